@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/transcript"
+)
+
+// watchInterval is how often a watched game file is checked for changes
+const watchInterval = 500 * time.Millisecond
+
+// analysisLine is the JSON form of an analysis update
+type analysisLine struct {
+	Position string   `json:"position"`
+	Depth    int      `json:"depth"`
+	Score    int      `json:"score"`
+	Side     string   `json:"side"`
+	Best     string   `json:"best"`
+	PV       []string `json:"pv"`
+	Nodes    int64    `json:"nodes"`
+	TimeMs   int64    `json:"time_ms"`
+}
+
+// resultLine is the JSON form of the result of analyzing one position
+type resultLine struct {
+	analysisLine
+	WinProbability float64 `json:"win_probability"` // For the side to move
+	NodesPerSecond float64 `json:"nps"`
+	TTHitRate      float64 `json:"tt_hit_rate"`
+}
+
+// defaultPositionTime limits the search of a position given on the command
+// line when no depth, node or time limit is
+const defaultPositionTime = 10 * time.Second
+
+// proofLine is the JSON form of a win/draw/loss proof
+type proofLine struct {
+	Position string `json:"position"`
+	Winner   string `json:"winner"` // "Black", "White" or "Empty" for a draw
+	TimeMs   int64  `json:"time_ms"`
+}
+
+// runAnalyze evaluates the current position of a saved game, printing a line
+// for every completed search depth, or a position given on the command line,
+// printing the result of the search
+func runAnalyze(args []string) error {
+	fs := flag.NewFlagSet("analyze", flag.ExitOnError)
+	watch := fs.Bool("watch", false, "Keep analyzing and restart whenever the game file changes")
+	depth := fs.Int("depth", 0, "Maximum search depth (0 searches to the end of the game)")
+	limit := fs.Duration("time", 0, "Stop searching after this long (0 for no limit); not used with -watch")
+	nodes := fs.Int64("nodes", 0, "Stop searching after this many nodes (0 for no limit), for results that repeat on any machine")
+	pos := fs.String("pos", "", "Analyze this position instead of a game: 64 squares (X, O, -) and the side to move")
+	asJSON := fs.Bool("json", false, "Print updates as JSON lines")
+	wdl := fs.Bool("wdl", false, "First prove whether the side to move wins, draws or loses")
+	cacheFile := fs.String("cache", ai.DefaultCachePath(), "File remembering analyzed positions, empty to disable")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello analyze [options] (game.json | -pos POSITION)")
+		fmt.Fprintln(fs.Output(), "A game is analyzed with a line per search depth. A position prints only the")
+		fmt.Fprintf(fs.Output(), "result: best move, line, score, win chance and search statistics; it is searched\n")
+		fmt.Fprintf(fs.Output(), "for %v unless -depth, -nodes or -time is given. The search stops at the first\n", defaultPositionTime)
+		fmt.Fprintln(fs.Output(), "limit reached.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *pos != "" {
+		if fs.NArg() != 0 || *watch {
+			fs.Usage()
+			return errors.New("-pos takes no game file and cannot be watched")
+		}
+	} else if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one game file")
+	}
+
+	if *cacheFile != "" {
+		c, err := ai.OpenCache(*cacheFile)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		ai.DefaultCache = c
+	}
+
+	limits := ai.SearchLimits{Depth: *depth, Nodes: *nodes}
+	if *pos != "" && *limit == 0 && *depth == 0 && *nodes == 0 {
+		*limit = defaultPositionTime
+	}
+	ctx := context.Background()
+	if *limit > 0 && !*watch {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *limit)
+		defer cancel()
+	}
+
+	if *pos != "" {
+		board, err := model.ParsePosition(*pos)
+		if err != nil {
+			return err
+		}
+		if *wdl {
+			proveBoard(ctx, board, *asJSON)
+		}
+		return printResult(ctx, board, limits, *asJSON)
+	}
+
+	path := fs.Arg(0)
+	if !*watch {
+		game, err := loadGameFile(path)
+		if err != nil {
+			return err
+		}
+		if *wdl {
+			proveBoard(ctx, game.Board, *asJSON)
+		}
+		analyzeBoard(ctx, game.Board, limits, printInfo(game.Board, *asJSON))
+		return nil
+	}
+
+	return watchGameFile(path, limits, *asJSON, *wdl)
+}
+
+// printResult searches a position and prints the deepest result
+func printResult(ctx context.Context, board *model.Board, limits ai.SearchLimits, asJSON bool) error {
+	var result ai.Info
+	found := false
+	analyzeBoard(ctx, board, limits, func(info ai.Info) {
+		result, found = info, true
+	})
+	if !found {
+		return errors.New("the search was stopped before its first result")
+	}
+
+	line := newAnalysisLine(board, result)
+	if asJSON {
+		data, _ := json.Marshal(resultLine{
+			analysisLine:   line,
+			WinProbability: ai.WinProbability(result.Score),
+			NodesPerSecond: result.NodesPerSecond(),
+			TTHitRate:      result.TTHitRate(),
+		})
+		fmt.Println(string(data))
+		return nil
+	}
+
+	side := model.GetPieceName(result.Side)
+	fmt.Printf("Best move   %s\n", line.Best)
+	fmt.Printf("Score       %+d (%+.1f discs for %s)\n", result.Score, ai.Discs(result.Score), side)
+	fmt.Printf("Win chance  %.0f%% for %s\n", ai.WinProbability(result.Score)*100, side)
+	fmt.Printf("Line        %s\n", strings.Join(line.PV, " "))
+	fmt.Printf("Search      depth %d, %d nodes in %v, %.0fk nodes/s, TT hits %.0f%%\n",
+		result.Depth, result.Nodes, result.Elapsed.Round(time.Millisecond),
+		result.NodesPerSecond()/1000, result.TTHitRate()*100)
+	return nil
+}
+
+// printInfo returns a function printing analysis updates for a position
+func printInfo(board *model.Board, asJSON bool) func(ai.Info) {
+	return func(info ai.Info) {
+		if asJSON {
+			data, _ := json.Marshal(newAnalysisLine(board, info))
+			fmt.Println(string(data))
+			return
+		}
+		fmt.Println(formatInfo(info))
+	}
+}
+
+// watchGameFile re-runs the analysis whenever the file is modified
+func watchGameFile(path string, limits ai.SearchLimits, asJSON, wdl bool) error {
+	var lastMod, failedMod time.Time
+	cancel := func() {}
+	done := make(chan struct{})
+	close(done)
+
+	for {
+		info, err := os.Stat(path)
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		if info.ModTime() != lastMod {
+			game, err := loadGameFile(path)
+			if err != nil {
+				// The file may be mid-write; try again on the next tick,
+				// reporting the error once per change
+				if info.ModTime() != failedMod {
+					failedMod = info.ModTime()
+					fmt.Fprintln(os.Stderr, "Error:", err)
+				}
+			} else {
+				lastMod = info.ModTime()
+				cancel()
+				<-done
+
+				var ctx context.Context
+				ctx, cancel = context.WithCancel(context.Background())
+				done = make(chan struct{})
+				go func(board *model.Board, done chan struct{}) {
+					defer close(done)
+					if !asJSON {
+						fmt.Printf("\n%s to move after %d moves\n", model.GetPieceName(board.CurrentPlayer), len(game.History))
+					}
+					if wdl {
+						proveBoard(ctx, board, asJSON)
+					}
+					analyzeBoard(ctx, board, limits, printInfo(board, asJSON))
+				}(game.Board.Clone(), done)
+			}
+		}
+
+		time.Sleep(watchInterval)
+	}
+}
+
+// analyzeBoard passes analysis updates for a position to show
+// A cached result is shown first; the search then only reports depths
+// beyond it. A node limited search skips the cache, whose results may come
+// from longer searches, so that it repeats exactly.
+func analyzeBoard(ctx context.Context, board *model.Board, limits ai.SearchLimits, show func(ai.Info)) {
+	cache := ai.DefaultCache
+	var cached ai.Info
+	if cache != nil && limits.Nodes == 0 {
+		if info, ok := cache.Get(board); ok {
+			cached = info
+			show(info)
+			if info.IsMate() || (limits.Depth > 0 && info.Depth >= limits.Depth) {
+				return
+			}
+		}
+	}
+
+	analyzer := ai.NewPlayer(ai.Hard, board.CurrentPlayer)
+	for info := range analyzer.SearchStream(ctx, board, limits) {
+		if info.Depth <= cached.Depth && !info.IsMate() {
+			continue
+		}
+		if cache != nil {
+			cache.Put(board, info)
+		}
+		show(info)
+	}
+}
+
+// proveBoard solves a position for its win/draw/loss result and prints it
+func proveBoard(ctx context.Context, board *model.Board, asJSON bool) {
+	start := time.Now()
+	solver := ai.NewPlayer(ai.Hard, board.CurrentPlayer)
+	outcome, ok := solver.SolveWDL(ctx, board)
+	if !ok {
+		return
+	}
+	if asJSON {
+		data, _ := json.Marshal(proofLine{
+			Position: board.PositionString(),
+			Winner:   model.GetPieceName(outcome.Winner(board.CurrentPlayer)),
+			TimeMs:   time.Since(start).Milliseconds(),
+		})
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Printf("%s (%dms)\n", outcome.Describe(board.CurrentPlayer), time.Since(start).Milliseconds())
+}
+
+// newAnalysisLine converts an update to its JSON form
+func newAnalysisLine(board *model.Board, info ai.Info) analysisLine {
+	return analysisLine{
+		Position: board.PositionString(),
+		Depth:    info.Depth,
+		Score:    info.Score,
+		Side:     model.GetPieceName(info.Side),
+		Best:     model.FormatMove(info.Best.Row, info.Best.Col),
+		PV:       formatPV(info.PV),
+		Nodes:    info.Nodes,
+		TimeMs:   info.Elapsed.Milliseconds(),
+	}
+}
+
+// formatInfo renders an update as a single human-readable line
+func formatInfo(info ai.Info) string {
+	return fmt.Sprintf("depth %2d  score %+6d  nodes %9d  time %6dms  pv %s",
+		info.Depth, info.Score, info.Nodes, info.Elapsed.Milliseconds(), strings.Join(formatPV(info.PV), " "))
+}
+
+// formatPV converts a principal variation to move notation
+func formatPV(pv []model.Position) []string {
+	moves := make([]string, len(pv))
+	for i, move := range pv {
+		moves[i] = model.FormatMove(move.Row, move.Col)
+	}
+	return moves
+}
+
+// loadGameFile reads a saved game, or the first game of a transcript
+func loadGameFile(path string) (*model.Game, error) {
+	games, err := loadGames(path)
+	if err != nil {
+		return nil, err
+	}
+	return games[0], nil
+}
+
+// loadGames reads the games in a file: one from a saved game, every game
+// from a .txt transcript copied from an online app
+func loadGames(path string) ([]*model.Game, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if !strings.EqualFold(filepath.Ext(path), ".txt") {
+		game, err := engine.LoadGame(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return []*model.Game{game}, nil
+	}
+
+	parsed, err := transcript.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	games := make([]*model.Game, 0, len(parsed))
+	for i, p := range parsed {
+		game, err := p.Replay()
+		if err != nil {
+			return nil, fmt.Errorf("%s: game %d: %w", path, i+1, err)
+		}
+		games = append(games, game)
+	}
+	return games, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/arena"
+	"github.com/amirhossein-jamali/othello/pkg/book"
+	"github.com/amirhossein-jamali/othello/pkg/script"
+)
+
+// runArena plays a tournament between engine configurations and reports
+// their ratings
+func runArena(args []string) error {
+	fs := flag.NewFlagSet("arena", flag.ExitOnError)
+	var engines []arena.Engine
+	fs.Func("engine", "Engine as name=difficulty[:book file[:weights file]] or name=script.star[:difficulty], repeat for each engine", func(spec string) error {
+		e, err := parseEngineSpec(spec)
+		if err == nil {
+			engines = append(engines, e)
+		}
+		return err
+	})
+	format := fs.String("format", arena.RoundRobin, "Tournament format: round-robin or swiss")
+	rounds := fs.Int("rounds", 1, "Number of rounds")
+	pairs := fs.Int("pairs", 2, "Game pairs per pairing and round")
+	plies := fs.Int("opening-plies", 4, "Random opening moves before the engines play")
+	concurrency := fs.Int("concurrency", 4, "Games played at once")
+	base := fs.Duration("time", 0, "Time each engine has per game, e.g. 30s (0 for untimed games)")
+	increment := fs.Duration("increment", 0, "Time added after every move in timed games")
+	seed := fs.Int64("seed", 1, "Seed for the random openings and Swiss tie breaks")
+	markdown := fs.String("md", "", "Write a Markdown report to this file")
+	html := fs.String("html", "", "Write an HTML report to this file")
+	fs.Parse(args)
+
+	if *format != arena.RoundRobin && *format != arena.Swiss {
+		return fmt.Errorf("unknown tournament format %q", *format)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	t := &arena.Tournament{
+		Engines:      engines,
+		Format:       *format,
+		Rounds:       *rounds,
+		Pairs:        *pairs,
+		OpeningPlies: *plies,
+		Concurrency:  *concurrency,
+		TimeControl:  arena.TimeControl{Base: *base, Increment: *increment},
+		Seed:         *seed,
+		Progress: func(done, total int, r arena.GameResult) {
+			fmt.Fprintf(os.Stderr, "\r%d/%d games", done, total)
+		},
+	}
+	results, err := t.Run(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr)
+
+	title := fmt.Sprintf("Othello %s tournament", *format)
+	if err := results.WriteMarkdown(os.Stdout, title); err != nil {
+		return err
+	}
+	if *markdown != "" {
+		if err := writeReport(*markdown, func(f *os.File) error { return results.WriteMarkdown(f, title) }); err != nil {
+			return err
+		}
+	}
+	if *html != "" {
+		if err := writeReport(*html, func(f *os.File) error { return results.WriteHTML(f, title) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseEngineSpec parses name=difficulty[:book file[:weights file]]
+func parseEngineSpec(spec string) (arena.Engine, error) {
+	name, config, ok := strings.Cut(spec, "=")
+	if !ok || name == "" {
+		return arena.Engine{}, fmt.Errorf("engine %q: expected name=difficulty[:book file[:weights file]]", spec)
+	}
+
+	difficulty, files, _ := strings.Cut(config, ":")
+	if strings.HasSuffix(difficulty, ".star") {
+		return parseScriptSpec(name, difficulty, files)
+	}
+	bookFile, weightsFile, _ := strings.Cut(files, ":")
+	switch difficulty {
+	case ai.Easy, ai.Medium, ai.Hard:
+	default:
+		return arena.Engine{}, fmt.Errorf("engine %q: unknown difficulty %q", spec, difficulty)
+	}
+
+	var b *book.Book
+	if bookFile != "" {
+		var err error
+		if b, err = book.Load(bookFile); err != nil {
+			return arena.Engine{}, err
+		}
+	}
+	var w *ai.Weights
+	if weightsFile != "" {
+		var err error
+		if w, err = ai.LoadWeights(weightsFile); err != nil {
+			return arena.Engine{}, err
+		}
+	}
+	return arena.AIEngine(name, difficulty, b, w), nil
+}
+
+// parseScriptSpec loads the bot script of an arena engine, searched at
+// difficulty (medium when empty) if it only evaluates positions
+func parseScriptSpec(name, path, difficulty string) (arena.Engine, error) {
+	switch difficulty {
+	case "":
+		difficulty = ai.Medium
+	case ai.Easy, ai.Medium, ai.Hard:
+	default:
+		return arena.Engine{}, fmt.Errorf("engine %q: unknown difficulty %q", name, difficulty)
+	}
+	s, err := script.Load(path)
+	if err != nil {
+		return arena.Engine{}, err
+	}
+	return arena.ScriptEngine(name, s, difficulty), nil
+}
+
+// writeReport creates a report file and fills it
+func writeReport(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/amirhossein-jamali/othello/pkg/backup"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+)
+
+// webdavPasswordEnv names the environment variable holding the WebDAV
+// password, kept out of the command line and shell history
+const webdavPasswordEnv = "OTHELLO_WEBDAV_PASSWORD"
+
+// runBackup saves or restores the user's data as one archive, locally or on
+// a WebDAV server
+func runBackup(args []string) error {
+	defaults := backup.DefaultLocations()
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	configFile := fs.String("config", config.DefaultPath(), "Settings file; its directory is backed up")
+	libraryDir := fs.String("library", defaults.LibraryDir, "Game library directory")
+	cacheFile := fs.String("analysis-cache", defaults.CachePath, "Analysis cache file")
+	user := fs.String("user", "", "WebDAV user name; the password is read from "+webdavPasswordEnv)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello backup [options] (export FILE | import FILE | push URL | pull URL)")
+		fmt.Fprintln(fs.Output(), "Saves settings, skins, the game library, books and the analysis cache as one zip")
+		fmt.Fprintln(fs.Output(), "archive, or restores one; push and pull keep it on a WebDAV server.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("expected a command and a file or URL")
+	}
+	loc := backup.Locations{ConfigDir: filepath.Dir(*configFile), LibraryDir: *libraryDir, CachePath: *cacheFile}
+	target := fs.Arg(1)
+	ctx := context.Background()
+
+	switch fs.Arg(0) {
+	case "export":
+		f, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		n, err := backup.Write(f, loc)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("Saved %d files to %s\n", n, target)
+	case "import":
+		f, err := os.Open(target)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		n, err := backup.Restore(f, info.Size(), loc)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Restored %d files from %s\n", n, target)
+	case "push":
+		var buf bytes.Buffer
+		n, err := backup.Write(&buf, loc)
+		if err != nil {
+			return err
+		}
+		if err := backup.NewWebDAV(target, *user, os.Getenv(webdavPasswordEnv)).Push(ctx, buf.Bytes()); err != nil {
+			return err
+		}
+		fmt.Printf("Uploaded %d files to %s\n", n, target)
+	case "pull":
+		data, err := backup.NewWebDAV(target, *user, os.Getenv(webdavPasswordEnv)).Pull(ctx)
+		if err != nil {
+			return err
+		}
+		n, err := backup.Restore(bytes.NewReader(data), int64(len(data)), loc)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Restored %d files from %s\n", n, target)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown backup command %q", fs.Arg(0))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/amirhossein-jamali/othello/pkg/book"
+	"github.com/amirhossein-jamali/othello/pkg/wthor"
+)
+
+// runBook builds an opening book from WTHOR databases
+func runBook(args []string) error {
+	fs := flag.NewFlagSet("book", flag.ExitOnError)
+	out := fs.String("o", "book.bin", "Output book file")
+	depth := fs.Int("depth", 20, "Number of moves of each game to include")
+	minGames := fs.Uint("min-games", 2, "Drop positions seen in fewer games")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello book [options] file.wtb...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no WTHOR files given")
+	}
+
+	b := book.New()
+	total, skipped := 0, 0
+	for _, path := range fs.Args() {
+		games, err := readWthorFile(path)
+		if err != nil {
+			return err
+		}
+
+		for i, g := range games {
+			// WTHOR stores the final black disc count; empties go to the winner
+			result := 2*g.BlackScore - 64
+			if err := b.AddGame(g.Moves, result, *depth); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: skipping game %d: %v\n", path, i+1, err)
+				skipped++
+				continue
+			}
+			total++
+		}
+	}
+
+	b.Prune(uint32(*minGames))
+	if err := b.Save(*out); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d positions from %d games to %s (%d games skipped)\n", b.Len(), total, *out, skipped)
+	return nil
+}
+
+// readWthorFile reads all games from a WTHOR database file
+func readWthorFile(path string) ([]*wthor.Game, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := wthor.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	games, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return games, nil
+}
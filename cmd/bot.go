@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+
+	"github.com/amirhossein-jamali/othello/pkg/bot"
+)
+
+// runBot serves the chat bot's Slack and Discord webhooks
+func runBot(args []string) error {
+	fs := flag.NewFlagSet("bot", flag.ExitOnError)
+	addr := fs.String("addr", ":8081", "Address to listen on")
+	publicURL := fs.String("public-url", "", "Public base URL of this server, enables board images")
+	discordKey := fs.String("discord-key", "", "Discord application public key (hex)")
+	slackSecret := fs.String("slack-secret", "", "Slack app signing secret")
+	style := fs.String("style", "emoji", "Board style: ascii, unicode or emoji")
+	fs.Parse(args)
+
+	b := bot.New()
+	var err error
+	if b.Style, err = parseTextStyle(*style); err != nil {
+		return err
+	}
+	b.Images = *publicURL != ""
+
+	server, err := bot.NewServer(b, *publicURL, *discordKey, *slackSecret)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("serving chat bot", "addr", *addr, "images", b.Images, "discord", *discordKey != "", "slack", *slackSecret != "")
+	return http.ListenAndServe(*addr, server)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/network"
+	"github.com/amirhossein-jamali/othello/pkg/notify"
+	"github.com/amirhossein-jamali/othello/pkg/ui/console"
+)
+
+// runConsole plays the console game, on its own or over the network against
+// another console or client speaking the game protocol
+func runConsole(args []string) error {
+	fs := flag.NewFlagSet("console", flag.ExitOnError)
+	host := fs.Bool("host", false, "Host a network game and wait for a player to join")
+	addr := fs.String("addr", ":"+network.DefaultPort, "Address to listen on with -host")
+	join := fs.String("join", "", "Join the network game hosted at this address, e.g. 192.168.1.20 or host:port")
+	color := fs.String("color", "black", "Your color when hosting: black or white")
+	name := fs.String("name", "", "Your player name, shown to the other player")
+	boardView := fs.String("board-view", "normal", "Board view: normal, flipped (from White's side), rotated or auto (from your side)")
+	configFile := fs.String("config", config.DefaultPath(), "Settings file, for turn notifications in network games")
+	clock := fs.String("clock", "", "Time control as minutes+increment seconds, e.g. 5+0; not for network games")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello console [-host [-addr ADDR] [-color COLOR] | -join ADDR] [flags]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *host && *join != "" {
+		return errors.New("-host and -join cannot be used together")
+	}
+	view, err := console.ParseView(*boardView)
+	if err != nil {
+		return err
+	}
+	base, increment, err := config.ParseTimeControl(*clock)
+	if err != nil {
+		return err
+	}
+	if base > 0 && (*host || *join != "") {
+		return errors.New("network games are played without a clock")
+	}
+
+	settings, err := config.Load(*configFile)
+	if err != nil {
+		return err
+	}
+
+	game := console.NewConsoleGame()
+	game.SetView(view)
+	game.SetNotifier(notify.New(settings.Notify.Desktop, settings.Notify.Webhook))
+	switch {
+	case *host:
+		mine, err := network.ParseColor(*color)
+		if err != nil {
+			return err
+		}
+		ln, err := net.Listen("tcp", *addr)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Waiting for a player to join on %s...\n", ln.Addr())
+		conn, err := network.AcceptClient(ln, *name, mine.Opponent())
+		ln.Close()
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		game.RunHost(conn, mine.Opponent())
+	case *join != "":
+		target := *join
+		if _, _, err := net.SplitHostPort(target); err != nil {
+			target = net.JoinHostPort(target, network.DefaultPort)
+		}
+		client, err := network.Dial(target, *name)
+		if err != nil {
+			return err
+		}
+		defer client.Close()
+		game.RunClient(client)
+	default:
+		game.SetClock(base, increment)
+		game.Run()
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/correspondence"
+)
+
+// runCorrServer serves correspondence games over HTTP
+func runCorrServer(args []string) error {
+	fs := flag.NewFlagSet("corr-server", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "Address to listen on")
+	dir := fs.String("dir", "correspondence", "Directory the games are stored in")
+	limits := correspondence.DefaultLimits()
+	fs.IntVar(&limits.MaxConnsPerIP, "max-conns-per-ip", limits.MaxConnsPerIP, "Open connections allowed per client address, 0 for no limit")
+	fs.Float64Var(&limits.RequestsPerSec, "rate", limits.RequestsPerSec, "Requests per second allowed per client address, 0 for no limit")
+	fs.IntVar(&limits.RequestBurst, "burst", limits.RequestBurst, "Requests a client may send at once before -rate applies")
+	fs.Int64Var(&limits.MaxRequestBytes, "max-body", limits.MaxRequestBytes, "Largest request body in bytes, 0 for no limit")
+	fs.Parse(args)
+
+	store, err := correspondence.OpenStore(*dir)
+	if err != nil {
+		return err
+	}
+
+	handler := correspondence.NewServerLimited(store, limits)
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           handler,
+		ConnState:         handler.ConnState,
+		ReadHeaderTimeout: 10 * time.Second,
+		MaxHeaderBytes:    16 << 10,
+	}
+	slog.Info("serving correspondence games", "addr", *addr, "dir", *dir,
+		"max_conns_per_ip", limits.MaxConnsPerIP, "rate", limits.RequestsPerSec, "max_body", limits.MaxRequestBytes)
+	return server.ListenAndServe()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/broadcast"
+	"github.com/amirhossein-jamali/othello/pkg/crowd"
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/render"
+)
+
+// runCrowd plays one game in which a chat audience votes on the moves of
+// one side against the computer
+func runCrowd(args []string) error {
+	fs := flag.NewFlagSet("crowd", flag.ExitOnError)
+	color := fs.String("color", "black", "Side the chat plays: black or white")
+	difficulty := fs.String("difficulty", ai.Medium, "Strength of the computer: easy, medium or hard")
+	window := fs.Duration("window", crowd.DefaultWindow, "How long each vote stays open")
+	prefix := fs.String("prefix", "", "Text votes must start with, e.g. !")
+	ircAddr := fs.String("irc", "", "IRC server to read votes from, e.g. "+crowd.TwitchAddr)
+	useTLS := fs.Bool("tls", false, "Connect to the IRC server over TLS")
+	channel := fs.String("channel", "", "IRC channel to read votes from")
+	nick := fs.String("nick", "othello", "IRC nick")
+	pass := fs.String("pass", os.Getenv("OTHELLO_IRC_PASS"), "IRC server password, defaults to $OTHELLO_IRC_PASS")
+	webhook := fs.String("webhook", "", "Address to take votes posted to a webhook on, e.g. :8082")
+	webhookToken := fs.String("webhook-token", os.Getenv("OTHELLO_WEBHOOK_TOKEN"), "Secret webhook posts must carry, defaults to $OTHELLO_WEBHOOK_TOKEN")
+	broadcastAddr := fs.String("broadcast", "", "Address to serve the live board and stream overlay on, e.g. :8090")
+	style := fs.String("style", "unicode", "Board style: ascii, unicode or emoji")
+	fs.Parse(args)
+
+	side := model.Black
+	switch *color {
+	case "black":
+	case "white":
+		side = model.White
+	default:
+		return fmt.Errorf("unknown color %q", *color)
+	}
+	textStyle, err := parseTextStyle(*style)
+	if err != nil {
+		return err
+	}
+	if *ircAddr == "" && *webhook == "" {
+		return fmt.Errorf("no vote source: give -irc or -webhook")
+	}
+	if *webhook != "" && *webhookToken == "" {
+		return fmt.Errorf("-webhook needs -webhook-token so only your service can post votes")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	errs := make(chan error, 3)
+
+	chat := crowd.NewPlayer(*window)
+	chat.Prefix = *prefix
+	chat.Token = *webhookToken
+	if *ircAddr != "" {
+		irc := &crowd.IRC{Addr: *ircAddr, TLS: *useTLS, Nick: *nick, Pass: *pass, Channel: *channel}
+		chat.OnOpen = func(board *model.Board, closes time.Time) {
+			irc.Say(fmt.Sprintf("Vote for the next move, e.g. %sd3 - %s to choose", *prefix, time.Until(closes).Round(time.Second)))
+		}
+		chat.OnResult = func(r crowd.Result) {
+			irc.Say(fmt.Sprintf("Chat plays %s with %d of %d votes", r.Move, r.Votes[r.Move], r.Total))
+		}
+		go func() { errs <- irc.Run(ctx, chat) }()
+	}
+	if *webhook != "" {
+		slog.Info("taking votes on webhook", "addr", *webhook)
+		go func() { errs <- http.ListenAndServe(*webhook, chat) }()
+	}
+
+	var live *broadcast.Server
+	if *broadcastAddr != "" {
+		live = broadcast.NewServer()
+		slog.Info("broadcasting game", "addr", *broadcastAddr)
+		go func() { errs <- http.ListenAndServe(*broadcastAddr, live) }()
+	}
+
+	players := map[model.Piece]engine.Player{
+		side:            chat,
+		side.Opponent(): engine.NewAI(*difficulty, side.Opponent()),
+	}
+	names := map[model.Piece]string{side: "Chat", side.Opponent(): "Computer (" + *difficulty + ")"}
+
+	game := engine.NewGame()
+	go func() {
+		for !game.GameOver {
+			if live != nil {
+				live.Publish(game, names[model.Black], names[model.White])
+			}
+			fmt.Println(render.Text(game.Board, textStyle, false))
+			if err := engine.Play(game, players[game.Board.CurrentPlayer]); err != nil {
+				errs <- err
+				return
+			}
+			engine.Observe(game, players[model.Black], players[model.White])
+		}
+		errs <- nil
+	}()
+
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if err != nil {
+		return err
+	}
+
+	if live != nil {
+		live.Publish(game, names[model.Black], names[model.White])
+	}
+	black, white := game.GetScore()
+	fmt.Println(render.Text(game.Board, textStyle, false))
+	fmt.Printf("Game over: black %d, white %d\n", black, white)
+	return nil
+}
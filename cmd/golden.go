@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/amirhossein-jamali/othello/pkg/golden"
+)
+
+// runGolden records games into a golden corpus or replays a corpus to check
+// the rules still give every game the recorded outcome
+func runGolden(args []string) error {
+	fs := flag.NewFlagSet("golden", flag.ExitOnError)
+	verbose := fs.Bool("v", false, "List every game checked, not just the failures")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello golden [options] (verify CORPUS | add CORPUS GAME...)")
+		fmt.Fprintln(fs.Output(), "verify replays every game of the corpus and reports those whose final position,")
+		fmt.Fprintln(fs.Output(), "disc counts or winner changed. add appends saved games (JSON) or transcript")
+		fmt.Fprintln(fs.Output(), "files (.txt) to the corpus with the outcome the rules give them now.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		fs.Usage()
+		return errors.New("expected a command and a corpus file")
+	}
+	corpus := fs.Arg(1)
+
+	switch fs.Arg(0) {
+	case "verify":
+		games, err := golden.Load(corpus)
+		if err != nil {
+			return err
+		}
+		failed := golden.Verify(games)
+		if *verbose {
+			bad := make(map[int]bool, len(failed))
+			for _, m := range failed {
+				bad[m.Game.Line] = true
+			}
+			for _, g := range games {
+				if !bad[g.Line] {
+					fmt.Printf("ok    %s\n", g.ID)
+				}
+			}
+		}
+		for _, m := range failed {
+			fmt.Printf("FAIL  %v\n", m)
+		}
+		fmt.Printf("%d/%d games replay as recorded\n", len(games)-len(failed), len(games))
+		if len(failed) > 0 {
+			return fmt.Errorf("%d games no longer replay as recorded", len(failed))
+		}
+	case "add":
+		if fs.NArg() < 3 {
+			fs.Usage()
+			return errors.New("expected game files to add")
+		}
+		return addGolden(corpus, fs.Args()[2:])
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+	return nil
+}
+
+// addGolden appends the games in files to the corpus, named after the file
+// and their place in it
+func addGolden(corpus string, files []string) error {
+	var records []golden.Game
+	for _, path := range files {
+		games, err := loadGames(path)
+		if err != nil {
+			return err
+		}
+		for i, game := range games {
+			if len(game.History) == 0 {
+				continue
+			}
+			id := filepath.Base(path)
+			if len(games) > 1 {
+				id = fmt.Sprintf("%s #%d", id, i+1)
+			}
+			records = append(records, golden.Record(id, game))
+		}
+	}
+
+	f, err := os.OpenFile(corpus, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if _, err := fmt.Fprintln(f, r); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Added %d games to %s\n", len(records), corpus)
+	return nil
+}
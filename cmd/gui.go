@@ -0,0 +1,16 @@
+//go:build !nogui
+
+package main
+
+import "github.com/amirhossein-jamali/othello/pkg/ui/gui"
+
+// guiAvailable reports whether this binary was built with the GUI
+const guiAvailable = true
+
+// guiOptions mirrors gui.Options so headless builds need not import the GUI
+type guiOptions = gui.Options
+
+// runGUI starts the graphical interface
+func runGUI(opts guiOptions) {
+	gui.Run(opts)
+}
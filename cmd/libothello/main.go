@@ -0,0 +1,247 @@
+// Command libothello exports the rules engine as a C library, so frontends
+// written in other languages reuse the engine instead of reimplementing the
+// rules. Build it with
+//
+//	go build -buildmode=c-shared -o libothello.so ./cmd/libothello
+//
+// which also writes libothello.h. Games are referred to by the positive
+// handles create_game returns. Functions report failures with the negative
+// codes below; squares are numbered from 0, row first, and pieces are 0 for
+// empty, 1 for Black and 2 for White. Different games may be used from
+// different threads, but one game only from one thread at a time.
+package main
+
+/*
+#include <stdlib.h>
+
+enum {
+	OTHELLO_OK = 0,
+	OTHELLO_UNKNOWN_GAME = -1,
+	OTHELLO_ILLEGAL_MOVE = -2,
+	OTHELLO_GAME_OVER = -3,
+	OTHELLO_BAD_ARGUMENT = -4
+};
+*/
+import "C"
+
+import (
+	"errors"
+	"sync"
+	"unsafe"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+)
+
+// games are the games created through the library, by handle
+var (
+	mu       sync.Mutex
+	games    = make(map[C.int]*engine.Game)
+	nextGame C.int
+)
+
+// game looks up a handle
+func game(handle C.int) (*engine.Game, bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	g, ok := games[handle]
+	return g, ok
+}
+
+// code translates an error from the engine to a result code
+func code(err error) C.int {
+	switch {
+	case err == nil:
+		return C.OTHELLO_OK
+	case errors.Is(err, engine.ErrGameOver):
+		return C.OTHELLO_GAME_OVER
+	default:
+		return C.OTHELLO_ILLEGAL_MOVE
+	}
+}
+
+// create_game starts a game in the standard starting position and returns
+// its handle
+//
+//export create_game
+func create_game() C.int {
+	return add(engine.NewGame())
+}
+
+// create_game_at starts a game from a position string, such as get_position
+// returns, and returns its handle or OTHELLO_BAD_ARGUMENT
+//
+//export create_game_at
+func create_game_at(position *C.char) C.int {
+	board, err := engine.ParsePosition(C.GoString(position))
+	if err != nil {
+		return C.OTHELLO_BAD_ARGUMENT
+	}
+	return add(engine.NewGameFrom(board))
+}
+
+// add registers a game and returns its handle
+func add(g *engine.Game) C.int {
+	mu.Lock()
+	defer mu.Unlock()
+	nextGame++
+	games[nextGame] = g
+	return nextGame
+}
+
+// free_game forgets a game; its handle must not be used again
+//
+//export free_game
+func free_game(handle C.int) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(games, handle)
+}
+
+// make_move plays a move for the side to move
+//
+//export make_move
+func make_move(handle C.int, row, col C.int) C.int {
+	g, ok := game(handle)
+	if !ok {
+		return C.OTHELLO_UNKNOWN_GAME
+	}
+	return code(g.MakeMove(int(row), int(col)))
+}
+
+// pass_turn passes for the side to move, which must have no legal move
+//
+//export pass_turn
+func pass_turn(handle C.int) C.int {
+	g, ok := game(handle)
+	if !ok {
+		return C.OTHELLO_UNKNOWN_GAME
+	}
+	return code(g.Pass())
+}
+
+// get_best_move asks the computer player of a difficulty ("easy", "medium"
+// or "hard") for the side to move's best move, without playing it. The
+// move is stored in row and col, both -1 for a pass.
+//
+//export get_best_move
+func get_best_move(handle C.int, difficulty *C.char, row, col *C.int) C.int {
+	g, ok := game(handle)
+	if !ok {
+		return C.OTHELLO_UNKNOWN_GAME
+	}
+	level := C.GoString(difficulty)
+	if level != ai.Easy && level != ai.Medium && level != ai.Hard || row == nil || col == nil {
+		return C.OTHELLO_BAD_ARGUMENT
+	}
+	if g.GameOver {
+		return C.OTHELLO_GAME_OVER
+	}
+
+	r, c := -1, -1
+	if g.HasValidMove() {
+		var err error
+		r, c, err = engine.NewAI(level, g.GetCurrentPlayer()).GetMove(g.Board.Clone())
+		if err != nil {
+			return C.OTHELLO_ILLEGAL_MOVE
+		}
+	}
+	*row, *col = C.int(r), C.int(c)
+	return C.OTHELLO_OK
+}
+
+// current_player returns the side to move, 0 once the game is over
+//
+//export current_player
+func current_player(handle C.int) C.int {
+	g, ok := game(handle)
+	if !ok {
+		return C.OTHELLO_UNKNOWN_GAME
+	}
+	if g.GameOver {
+		return 0
+	}
+	return C.int(g.GetCurrentPlayer())
+}
+
+// is_game_over returns 1 once neither side can move, otherwise 0
+//
+//export is_game_over
+func is_game_over(handle C.int) C.int {
+	g, ok := game(handle)
+	if !ok {
+		return C.OTHELLO_UNKNOWN_GAME
+	}
+	if g.GameOver {
+		return 1
+	}
+	return 0
+}
+
+// is_valid_move returns 1 when the side to move may play a square,
+// otherwise 0
+//
+//export is_valid_move
+func is_valid_move(handle C.int, row, col C.int) C.int {
+	g, ok := game(handle)
+	if !ok {
+		return C.OTHELLO_UNKNOWN_GAME
+	}
+	if !g.GameOver && g.Board.IsValidMove(int(row), int(col)) {
+		return 1
+	}
+	return 0
+}
+
+// get_piece returns the piece on a square, or OTHELLO_BAD_ARGUMENT off the
+// board
+//
+//export get_piece
+func get_piece(handle C.int, row, col C.int) C.int {
+	g, ok := game(handle)
+	if !ok {
+		return C.OTHELLO_UNKNOWN_GAME
+	}
+	if !g.Board.IsValidPosition(int(row), int(col)) {
+		return C.OTHELLO_BAD_ARGUMENT
+	}
+	return C.int(g.Board.GetPiece(int(row), int(col)))
+}
+
+// get_score stores the disc counts in black and white
+//
+//export get_score
+func get_score(handle C.int, black, white *C.int) C.int {
+	g, ok := game(handle)
+	if !ok {
+		return C.OTHELLO_UNKNOWN_GAME
+	}
+	if black == nil || white == nil {
+		return C.OTHELLO_BAD_ARGUMENT
+	}
+	b, w := g.GetScore()
+	*black, *white = C.int(b), C.int(w)
+	return C.OTHELLO_OK
+}
+
+// get_position returns the position string of a game, or NULL for an
+// unknown handle. Release it with free_string.
+//
+//export get_position
+func get_position(handle C.int) *C.char {
+	g, ok := game(handle)
+	if !ok {
+		return nil
+	}
+	return C.CString(g.Board.PositionString())
+}
+
+// free_string releases a string returned by the library
+//
+//export free_string
+func free_string(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// main is required by -buildmode=c-shared but never runs
+func main() {}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// runLibrary adds games to the game library, analyzes them and lists them
+func runLibrary(args []string) error {
+	fs := flag.NewFlagSet("library", flag.ExitOnError)
+	dir := fs.String("dir", library.DefaultDir(), "Library directory")
+	analyze := fs.Bool("analyze", false, "Analyze games not yet analyzed to -depth, using every CPU")
+	stats := fs.Bool("stats", false, "Summarize each player's accuracy instead of listing games")
+	depth := fs.Int("depth", config.Default().Library.AnalysisDepth, "Analysis depth")
+	search := fs.String("search", "", "List only matching games, e.g. \"player:alice result:black opening:f5d6c3 after:2024-01-01\"")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello library [options] [game.json|transcript.txt...]")
+		fmt.Fprintln(fs.Output(), "Adds the given games, then lists the library.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	lib, err := library.Open(*dir)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range fs.Args() {
+		games, err := loadGames(path)
+		if err != nil {
+			return err
+		}
+		records := make([]*model.GameRecord, len(games))
+		for i, game := range games {
+			records[i] = model.NewGameRecord(game)
+		}
+		stats, err := lib.Import(records)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s: %s\n", path, stats)
+	}
+
+	if *analyze {
+		analyzer := library.NewAnalyzer(lib, *depth)
+		for _, g := range lib.List() {
+			if !analyzer.Pending(g) {
+				continue
+			}
+			a, err := library.AnalyzeRecord(context.Background(), g.Record, *depth, 0)
+			if err != nil {
+				return fmt.Errorf("game %s: %w", g.ID, err)
+			}
+			if err := lib.SetAnalysis(g.ID, a); err != nil {
+				return err
+			}
+			fmt.Println("Analyzed", g.ID)
+		}
+	}
+
+	games := lib.List()
+	var position *model.Board
+	if *search != "" {
+		q, err := library.ParseQuery(*search)
+		if err != nil {
+			return err
+		}
+		games, position = lib.Search(q), q.Board
+	}
+
+	if *stats {
+		printLibraryStats(library.Stats(games))
+		return nil
+	}
+
+	for _, g := range games {
+		accuracy := "not analyzed"
+		if g.Analysis != nil {
+			var parts []string
+			for _, side := range []model.Piece{model.Black, model.White} {
+				if a, ok := g.Analysis.Accuracy(side); ok {
+					loss, _ := g.Analysis.AverageLoss(side)
+					parts = append(parts, fmt.Sprintf("%s %.0f%% (%.1f discs lost per move)", model.GetPieceName(side), a, loss))
+				}
+			}
+			accuracy = "accuracy " + strings.Join(parts, ", ")
+		}
+		if position != nil {
+			accuracy = fmt.Sprintf("position at move %d, %s", lib.PositionPly(g, position), accuracy)
+		}
+		for _, side := range []model.Piece{model.Black, model.White} {
+			if help := library.DescribeAssistance(g.Record.Metadata, side); help != "" {
+				accuracy += fmt.Sprintf(", %s assisted with %s", model.GetPieceName(side), help)
+			}
+		}
+		fmt.Printf("%s  %s  %s vs %s  %-5s  %s\n", g.ID, g.Saved.Format("2006-01-02 15:04"),
+			g.PlayerName(model.Black), g.PlayerName(model.White), g.Record.Result, accuracy)
+	}
+	return nil
+}
+
+// printLibraryStats prints each player's accuracy over their analyzed games
+// and how the last five games compare with the five before
+func printLibraryStats(stats []library.PlayerStats) {
+	if len(stats) == 0 {
+		fmt.Println("No analyzed games")
+		return
+	}
+	fmt.Printf("%-16s %6s %-16s %9s %10s %13s\n", "Player", "Games", "Wins", "Accuracy", "Loss/move", "Last 5 games")
+	for _, s := range stats {
+		trend := "-"
+		if change, ok := s.Trend(5); ok {
+			trend = fmt.Sprintf("%+.1f discs", change)
+		}
+		fmt.Printf("%-16s %6d %-16s %8.0f%% %10.1f %13s\n", s.Player, len(s.Games), winsText(s), s.Accuracy(), s.AverageLoss(), trend)
+	}
+}
+
+// winsText describes a player's wins, marking those won with hints or undos,
+// e.g. "7 (2 assisted)"
+func winsText(s library.PlayerStats) string {
+	wins, assisted := s.Wins()
+	if assisted == 0 {
+		return fmt.Sprint(wins)
+	}
+	return fmt.Sprintf("%d (%d assisted)", wins, assisted)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/lineproto"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// runLineServer referees bot games over the line protocol
+func runLineServer(args []string) error {
+	fs := flag.NewFlagSet("line-server", flag.ExitOnError)
+	addr := fs.String("addr", ":4000", "Address to listen on")
+	difficulty := fs.String("difficulty", ai.Medium, "AI level bots play against: easy, medium or hard")
+	pair := fs.Bool("pair", false, "Match bots against each other instead of the AI")
+	moveTimeout := fs.Duration("move-timeout", 5*time.Second, "Time a bot has for each move")
+	maxIllegal := fs.Int("max-illegal", 3, "Illegal moves allowed before a bot forfeits")
+	fs.Parse(args)
+
+	server := lineproto.NewServer()
+	server.Difficulty = *difficulty
+	server.Pair = *pair
+	server.MoveTimeout = *moveTimeout
+	server.MaxIllegal = *maxIllegal
+
+	slog.Info("serving line protocol games", "addr", *addr, "pair", *pair, "difficulty", *difficulty)
+	return server.ListenAndServe(*addr)
+}
+
+// runLineBot plays one game as the reference line protocol bot
+func runLineBot(args []string) error {
+	fs := flag.NewFlagSet("line-bot", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:4000", "Server address")
+	name := fs.String("name", "refbot", "Bot name")
+	difficulty := fs.String("difficulty", ai.Easy, "Strength of the bot: easy, medium or hard")
+	fs.Parse(args)
+
+	client, err := lineproto.Dial(*addr, *name)
+	if err != nil {
+		return err
+	}
+
+	result, err := client.Play(func(board *model.Board) (int, int) {
+		row, col, _ := ai.NewPlayer(*difficulty, board.CurrentPlayer).GetMove(board)
+		return row, col
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Played %s against %s: black %d, white %d, winner %s",
+		lineproto.ColorName(client.Color), client.Opponent, result.Black, result.White, result.Winner)
+	if result.Reason != "" {
+		fmt.Printf(" (%s)", result.Reason)
+	}
+	fmt.Println()
+	return nil
+}
@@ -1,29 +1,224 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/book"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/correspondence"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/plugin"
+	"github.com/amirhossein-jamali/othello/pkg/profile"
 	"github.com/amirhossein-jamali/othello/pkg/ui/console"
-	"github.com/amirhossein-jamali/othello/pkg/ui/gui"
 )
 
 func main() {
+	// Subcommands take their own flags
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "book":
+			exitOnError(runBook(os.Args[2:]))
+			return
+		case "analyze":
+			exitOnError(runAnalyze(os.Args[2:]))
+			return
+		case "corr-server":
+			exitOnError(runCorrServer(os.Args[2:]))
+			return
+		case "bot":
+			exitOnError(runBot(os.Args[2:]))
+			return
+		case "crowd":
+			exitOnError(runCrowd(os.Args[2:]))
+			return
+		case "console":
+			exitOnError(runConsole(os.Args[2:]))
+			return
+		case "serve":
+			exitOnError(runServe(os.Args[2:]))
+			return
+		case "line-server":
+			exitOnError(runLineServer(os.Args[2:]))
+			return
+		case "line-bot":
+			exitOnError(runLineBot(os.Args[2:]))
+			return
+		case "arena":
+			exitOnError(runArena(os.Args[2:]))
+			return
+		case "suite":
+			exitOnError(runSuite(os.Args[2:]))
+			return
+		case "golden":
+			exitOnError(runGolden(os.Args[2:]))
+			return
+		case "replay":
+			exitOnError(runReplay(os.Args[2:]))
+			return
+		case "tune":
+			exitOnError(runTune(os.Args[2:]))
+			return
+		case "library":
+			exitOnError(runLibrary(os.Args[2:]))
+			return
+		case "render":
+			exitOnError(runRender(os.Args[2:]))
+			return
+		case "ownership":
+			exitOnError(runOwnership(os.Args[2:]))
+			return
+		case "print":
+			exitOnError(runPrint(os.Args[2:]))
+			return
+		case "backup":
+			exitOnError(runBackup(os.Args[2:]))
+			return
+		case "migrate":
+			exitOnError(runMigrate(os.Args[2:]))
+			return
+		case "profile":
+			exitOnError(runProfile(os.Args[2:]))
+			return
+		}
+	}
+
 	// Parse command line flags
-	useConsole := flag.Bool("console", false, "Run in console mode")
+	useConsole := flag.Bool("console", !guiAvailable, "Run in console mode")
+	configFile := flag.String("config", config.DefaultPath(), "Settings file, reloaded when it changes or on SIGHUP")
+	bookFile := flag.String("book", "", "Opening book file for the AI, overriding the settings file")
+	weightsFile := flag.String("weights", "", "Evaluation weights file for the AI, overriding the settings file")
+	telemetryFile := flag.String("telemetry", "", "Append AI search records to this file")
+	cacheFile := flag.String("analysis-cache", ai.DefaultCachePath(), "File remembering analyzed positions, empty to disable")
+	libraryDir := flag.String("library", library.DefaultDir(), "Directory finished GUI games are saved to, empty to disable")
+	logLevel := flag.String("log-level", "info", "Log level: debug, info, warn or error")
+	logFile := flag.String("log-file", "", "Write logs to this file instead of stderr")
+	corrURL := flag.String("corr-url", "", "Correspondence server URL for the My Games screen")
+	corrSecret := flag.String("corr-secret", os.Getenv("OTHELLO_CORR_SECRET"), "Your secret on the correspondence server, defaults to $OTHELLO_CORR_SECRET or one saved beside the settings file")
+	playerName := flag.String("name", "", "Your player name on network and correspondence servers")
+	profileName := flag.String("profile", "", "Play as this profile instead of choosing one at startup")
+	enginesDir := flag.String("engines", plugin.DefaultDir(), "Directory of installed engines offered on the mode menu")
+	touch := flag.Bool("touch", false, "Lay the GUI out for a touch screen, with buttons for the keyboard shortcuts")
+	presetName := flag.String("preset", "", "Start a game with this preset from the settings file")
+	clock := flag.String("clock", "", "Console time control as minutes+increment seconds, e.g. 5+0")
+	boardView := flag.String("board-view", "normal", "Console board view: normal, flipped (from White's side), rotated or auto (from your side)")
 	flag.Parse()
 
+	closeLog, err := logging.Setup(logging.Options{Level: *logLevel, File: *logFile})
+	exitOnError(err)
+	defer closeLog()
+
+	if *telemetryFile != "" {
+		t, err := ai.OpenTelemetry(*telemetryFile, 1000)
+		exitOnError(err)
+		defer t.Close()
+		ai.DefaultTelemetry = t
+		slog.Info("recording AI telemetry", "file", *telemetryFile)
+	}
+
+	settings, err := config.Watch(*configFile, 2*time.Second)
+	exitOnError(err)
+	defer settings.Close()
+
+	if *bookFile == "" {
+		*bookFile = settings.Current().AI.Book
+	}
+	if *bookFile != "" {
+		b, err := book.Load(*bookFile)
+		exitOnError(err)
+		ai.DefaultBook = b
+		slog.Info("loaded opening book", "file", *bookFile, "positions", b.Len())
+	}
+
+	if *weightsFile == "" {
+		*weightsFile = settings.Current().AI.Weights
+	}
+	if *weightsFile != "" {
+		w, err := ai.LoadWeights(*weightsFile)
+		exitOnError(err)
+		ai.DefaultWeights = w
+		slog.Info("loaded evaluation weights", "file", *weightsFile)
+	}
+
+	aiSettings := settings.Current().AI
+	model.DefaultPhaseLimits = model.PhaseLimits{Midgame: aiSettings.MidgameEmpties, Endgame: aiSettings.EndgameEmpties}
+
+	if *cacheFile != "" {
+		c, err := ai.OpenCache(*cacheFile)
+		if err != nil {
+			// Analysis works without the cache, just more slowly
+			slog.Warn("analysis cache unavailable", "file", *cacheFile, "err", err)
+		} else {
+			defer c.Close()
+			ai.DefaultCache = c
+			slog.Info("loaded analysis cache", "file", *cacheFile, "positions", c.Len())
+		}
+	}
+
+	if *presetName != "" {
+		if *useConsole {
+			exitOnError(errors.New("presets are only available in the GUI"))
+		}
+		if _, ok := settings.Current().Preset(*presetName); !ok {
+			exitOnError(fmt.Errorf("no preset named %q in %s", *presetName, *configFile))
+		}
+	}
+	if *clock != "" && !*useConsole {
+		exitOnError(errors.New("-clock is only available in the console; use a preset in the GUI"))
+	}
+
 	if *useConsole {
-		fmt.Println("Starting Othello in console mode...")
+		slog.Info("starting Othello", "mode", "console")
+		view, err := console.ParseView(*boardView)
+		exitOnError(err)
 		game := console.NewConsoleGame()
+		game.SetView(view)
+		base, increment, err := config.ParseTimeControl(*clock)
+		exitOnError(err)
+		game.SetClock(base, increment)
 		game.Run()
 	} else {
-		fmt.Println("Starting Othello in GUI mode...")
-		gui.RunGame()
+		slog.Info("starting Othello", "mode", "gui")
+		opts := guiOptions{CorrespondenceURL: *corrURL, PlayerName: *playerName, Settings: settings, Touch: *touch, Preset: *presetName}
+		if *corrURL != "" {
+			opts.CorrespondenceSecret = *corrSecret
+			if opts.CorrespondenceSecret == "" {
+				opts.CorrespondenceSecret, err = correspondence.LoadSecret(filepath.Join(filepath.Dir(*configFile), "corr-secret"))
+				exitOnError(err)
+			}
+		}
+		opts.Profiles = profile.NewStore(profile.DefaultDir())
+		if *profileName != "" {
+			p, err := opts.Profiles.Get(*profileName)
+			exitOnError(err)
+			opts.Profile = &p
+		}
+		if *libraryDir != "" {
+			lib, err := library.Open(*libraryDir)
+			exitOnError(err)
+			opts.Library = lib
+		}
+		if opts.Engines, err = plugin.Discover(*enginesDir); err != nil {
+			slog.Warn("installed engines unavailable", "dir", *enginesDir, "err", err)
+		}
+		runGUI(opts)
 	}
+}
 
-	os.Exit(0)
+// exitOnError prints the error and exits with a failure status
+func exitOnError(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
 }
 
 func showHelp() {
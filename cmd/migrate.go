@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/amirhossein-jamali/othello/pkg/migrate"
+)
+
+// runMigrate upgrades saved games, library games and opening books in
+// directories to the current formats
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "List the files that would be upgraded without changing them")
+	backupDir := fs.String("backup", "", "Copy the original files here (default: a migrate-backup-TIME directory in each DIR)")
+	verbose := fs.Bool("v", false, "List the files already up to date too")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello migrate [options] DIR...")
+		fmt.Fprintln(fs.Output(), "Upgrades the saved games, library games and opening books under each")
+		fmt.Fprintln(fs.Output(), "directory to the formats this version writes, backing up every file it changes.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("expected a directory")
+	}
+
+	failures := 0
+	for _, dir := range fs.Args() {
+		report, err := migrate.Dir(dir, migrate.Options{DryRun: *dryRun, BackupDir: *backupDir})
+		for _, f := range report.Files {
+			switch {
+			case f.Err != nil:
+				fmt.Printf("%s: %s: %v\n", f.Path, f.Kind, f.Err)
+			case f.Upgraded():
+				fmt.Printf("%s: %s, version %d -> %d\n", f.Path, f.Kind, f.From, f.To)
+			case *verbose:
+				fmt.Printf("%s: %s, version %d, up to date\n", f.Path, f.Kind, f.From)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		upgraded, current, newer, failed := report.Count()
+		failures += failed
+		verb := "upgraded"
+		if *dryRun {
+			verb = "to upgrade"
+		}
+		fmt.Printf("%s: %d %s, %d up to date, %d newer, %d failed\n", dir, upgraded, verb, current, newer, failed)
+		if report.BackupDir != "" {
+			fmt.Printf("Originals backed up to %s\n", report.BackupDir)
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d files could not be upgraded", failures)
+	}
+	return nil
+}
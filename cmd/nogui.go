@@ -0,0 +1,37 @@
+//go:build nogui
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/plugin"
+	"github.com/amirhossein-jamali/othello/pkg/profile"
+)
+
+// guiAvailable reports whether this binary was built with the GUI
+const guiAvailable = false
+
+// guiOptions mirrors gui.Options so headless builds need not import the GUI
+type guiOptions struct {
+	CorrespondenceURL    string
+	CorrespondenceSecret string
+	PlayerName           string
+	Settings             *config.Watcher
+	Library              *library.Library
+	Profiles             *profile.Store
+	Profile              *profile.Profile
+	Touch                bool
+	Engines              []plugin.Engine
+	ResumeFile           string
+	Preset               string
+}
+
+// runGUI explains that this headless build has no graphical interface
+func runGUI(opts guiOptions) {
+	fmt.Fprintln(os.Stderr, "This build of Othello has no GUI (built with -tags nogui); use -console instead.")
+	os.Exit(1)
+}
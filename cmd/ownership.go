@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/sim"
+)
+
+// runOwnership plays a position out many times and prints how often each
+// square ended up black
+func runOwnership(args []string) error {
+	fs := flag.NewFlagSet("ownership", flag.ExitOnError)
+	pos := fs.String("pos", "", "Position: 64 squares (X, O, -) and the side to move")
+	moves := fs.String("moves", "", "Moves played from the start, e.g. F5D6C3, instead of -pos")
+	playouts := fs.Int("playouts", sim.DefaultPlayouts, "Games played out from the position")
+	policy := fs.String("policy", "random", "How playouts choose moves: random or heuristic")
+	seed := fs.Int64("seed", 1, "Random seed")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello ownership [options] (-pos POSITION | -moves MOVES)")
+		fmt.Fprintln(fs.Output(), "Plays the position out many times and prints the percentage of playouts in")
+		fmt.Fprintln(fs.Output(), "which each square ended up black; the rest ended up white or empty.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	board, _, err := renderedBoard(*pos, *moves)
+	if err != nil {
+		fs.Usage()
+		return err
+	}
+	opts := sim.OwnershipOptions{Playouts: *playouts, Seed: *seed}
+	switch *policy {
+	case "random":
+	case "heuristic":
+		opts.Policy = sim.PolicyHeuristic
+	default:
+		return fmt.Errorf("unknown policy %q", *policy)
+	}
+
+	own := sim.PlayoutOwnership(context.Background(), board, opts)
+	var sb strings.Builder
+	sb.WriteString("     A   B   C   D   E   F   G   H\n")
+	for row := 0; row < 8; row++ {
+		fmt.Fprintf(&sb, "%d", row+1)
+		for col := 0; col < 8; col++ {
+			black, _ := own.Share(row, col)
+			fmt.Fprintf(&sb, " %3.0f", black*100)
+		}
+		sb.WriteByte('\n')
+	}
+	fmt.Print(sb.String())
+	n := float64(own.Playouts)
+	fmt.Printf("%d playouts: black wins %.1f%%, white %.1f%%, draws %.1f%%\n", own.Playouts,
+		float64(own.BlackWins)/n*100, float64(own.WhiteWins)/n*100, float64(own.Draws)/n*100)
+	return nil
+}
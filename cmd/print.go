@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// runPrint writes a game as a printable HTML record
+func runPrint(args []string) error {
+	fs := flag.NewFlagSet("print", flag.ExitOnError)
+	id := fs.String("id", "", "Print this library game instead of a game file")
+	dir := fs.String("dir", library.DefaultDir(), "Library directory for -id")
+	depth := fs.Int("depth", config.Default().Library.AnalysisDepth, "Analysis depth for the evaluation graph when the game has no analysis; 0 leaves it out")
+	out := fs.String("out", "-", "Output HTML file; - prints to standard output")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello print [options] (game.json | -id ID)")
+		fmt.Fprintln(fs.Output(), "Writes a game record to print or save as PDF from a browser.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var g *library.Game
+	switch {
+	case *id != "" && fs.NArg() == 0:
+		lib, err := library.Open(*dir)
+		if err != nil {
+			return err
+		}
+		if g, err = lib.Get(*id); err != nil {
+			return err
+		}
+	case *id == "" && fs.NArg() == 1:
+		game, err := loadGameFile(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		g = &library.Game{Record: model.NewGameRecord(game)}
+	default:
+		fs.Usage()
+		return errors.New("expected a game file or -id")
+	}
+
+	if g.Analysis == nil && *depth > 0 {
+		a, err := library.AnalyzeRecord(context.Background(), g.Record, *depth, 0)
+		if err != nil {
+			return err
+		}
+		g.Analysis = a
+	}
+
+	if *out == "-" {
+		return g.WriteHTML(os.Stdout)
+	}
+	return writeImage(*out, func(f *os.File) error { return g.WriteHTML(f) })
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/amirhossein-jamali/othello/pkg/profile"
+)
+
+// runProfile lists, adds and removes the local user profiles and sets their
+// avatars
+func runProfile(args []string) error {
+	fs := flag.NewFlagSet("profile", flag.ExitOnError)
+	dir := fs.String("dir", profile.DefaultDir(), "Profiles directory")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello profile [options] (list | add NAME | avatar NAME IMAGE | remove NAME)")
+		fmt.Fprintln(fs.Output(), "Each profile has its own settings, game library and statistics, and an avatar")
+		fmt.Fprintln(fs.Output(), "shown on the player cards; the GUI asks which one to use at startup.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	store := profile.NewStore(*dir)
+	want := map[string]int{"list": 1, "add": 2, "avatar": 3, "remove": 2}
+	if n, ok := want[fs.Arg(0)]; !ok || fs.NArg() != n {
+		fs.Usage()
+		return errors.New("expected a profile command")
+	}
+
+	switch fs.Arg(0) {
+	case "list":
+		profiles, err := store.List()
+		if err != nil {
+			return err
+		}
+		if len(profiles) == 0 {
+			fmt.Println("No profiles yet; add one with: othello profile add NAME")
+		}
+		for _, p := range profiles {
+			fmt.Printf("%-20s %s\n", p.Name, p.Dir)
+		}
+	case "add":
+		p, err := store.Create(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Added profile %s in %s\n", p.Name, p.Dir)
+	case "avatar":
+		if err := store.SetAvatar(fs.Arg(1), fs.Arg(2)); err != nil {
+			return err
+		}
+		fmt.Printf("Set the avatar of %s\n", fs.Arg(1))
+	case "remove":
+		if err := store.Remove(fs.Arg(1)); err != nil {
+			return err
+		}
+		fmt.Printf("Removed profile %s with its settings and games\n", fs.Arg(1))
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/render"
+)
+
+// runRender draws a position as a PNG image or a text diagram
+func runRender(args []string) error {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	pos := fs.String("pos", "", "Position to draw: 64 squares (X, O, -) and the side to move")
+	moves := fs.String("moves", "", "Moves played from the start, e.g. F5D6C3, instead of -pos")
+	out := fs.String("out", "-", "Output file: .png or .svg for an image, anything else for text; - prints text")
+	style := fs.String("style", "unicode", "Text style: ascii, unicode or emoji")
+	cell := fs.Int("cell", 48, "Pixels per square in images")
+	coords := fs.Bool("coords", true, "Draw coordinates around images")
+	legal := fs.Bool("legal", false, "Mark the legal moves of the side to move")
+	labels := fs.String("labels", "", "SVG only: text on squares, e.g. C4=a,F5=b")
+	arrows := fs.String("arrows", "", "SVG only: arrows between squares, e.g. D3-F5,C4-C6")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello render [options] (-pos POSITION | -moves MOVES)")
+		fmt.Fprintln(fs.Output(), "Draws a position without starting the GUI.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	board, last, err := renderedBoard(*pos, *moves)
+	if err != nil {
+		fs.Usage()
+		return err
+	}
+
+	notes, err := parseAnnotations(*labels, *arrows)
+	if err != nil {
+		return err
+	}
+
+	opts := render.Options{CellSize: *cell, Coordinates: *coords, ShowMoves: *legal, LastMove: last}
+	switch strings.ToLower(filepath.Ext(*out)) {
+	case ".png":
+		return writeImage(*out, func(f *os.File) error { return render.PNG(f, board, opts) })
+	case ".svg":
+		return writeImage(*out, func(f *os.File) error { return render.SVG(f, board, opts, notes) })
+	}
+
+	textStyle, err := parseTextStyle(*style)
+	if err != nil {
+		return err
+	}
+	diagram := render.Text(board, textStyle, *legal)
+	if *out == "-" {
+		fmt.Print(diagram)
+		return nil
+	}
+	return os.WriteFile(*out, []byte(diagram), 0o644)
+}
+
+// renderedBoard returns the position given by -pos or reached by -moves,
+// with the last move played when moves were given
+func renderedBoard(pos, moves string) (*model.Board, *model.Position, error) {
+	switch {
+	case pos != "" && moves != "":
+		return nil, nil, errors.New("give either -pos or -moves, not both")
+	case pos != "":
+		board, err := model.ParsePosition(pos)
+		return board, nil, err
+	case moves != "":
+		game := model.NewGame()
+		if _, err := game.ReplayTranscript(moves); err != nil {
+			return nil, nil, err
+		}
+		var last *model.Position
+		for i := len(game.History) - 1; i >= 0 && last == nil; i-- {
+			if p := game.History[i].Position; p.Row >= 0 {
+				last = &p
+			}
+		}
+		return game.Board, last, nil
+	}
+	return nil, nil, errors.New("a position is required: give -pos or -moves")
+}
+
+// writeImage creates a file and fills it with draw
+func writeImage(path string, draw func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := draw(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// parseAnnotations reads the -labels and -arrows lists
+func parseAnnotations(labels, arrows string) (render.Annotations, error) {
+	var notes render.Annotations
+	square := func(s string) (model.Position, error) {
+		row, col, err := model.ParseMove(s)
+		if err == nil && row < 0 {
+			err = fmt.Errorf("%q is not a square", s)
+		}
+		return model.Position{Row: row, Col: col}, err
+	}
+
+	for _, item := range strings.FieldsFunc(labels, isComma) {
+		at, text, ok := strings.Cut(item, "=")
+		if !ok {
+			return notes, fmt.Errorf("label %q: expected SQUARE=TEXT", item)
+		}
+		p, err := square(at)
+		if err != nil {
+			return notes, fmt.Errorf("label %q: %w", item, err)
+		}
+		if notes.Labels == nil {
+			notes.Labels = make(map[model.Position]string)
+		}
+		notes.Labels[p] = text
+	}
+
+	for _, item := range strings.FieldsFunc(arrows, isComma) {
+		from, to, ok := strings.Cut(item, "-")
+		if !ok {
+			return notes, fmt.Errorf("arrow %q: expected FROM-TO", item)
+		}
+		a, err := square(from)
+		if err != nil {
+			return notes, fmt.Errorf("arrow %q: %w", item, err)
+		}
+		b, err := square(to)
+		if err != nil {
+			return notes, fmt.Errorf("arrow %q: %w", item, err)
+		}
+		notes.Arrows = append(notes.Arrows, render.Arrow{From: a, To: b})
+	}
+	return notes, nil
+}
+
+// isComma separates list items
+func isComma(r rune) bool {
+	return r == ','
+}
+
+// parseTextStyle returns the text diagram style of the given name
+func parseTextStyle(name string) (render.TextStyle, error) {
+	switch name {
+	case "ascii":
+		return render.ASCII, nil
+	case "unicode":
+		return render.Unicode, nil
+	case "emoji":
+		return render.Emoji, nil
+	}
+	return render.TextStyle{}, fmt.Errorf("unknown board style %q", name)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/book"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// runReplay replays a saved game, asking the recorded AI players for their
+// moves again and reporting the first move that differs
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	bookFile := fs.String("book", "", "Opening book the AI used, if any")
+	weightsFile := fs.String("weights", "", "Evaluation weights the AI used, if not the built-in ones")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: othello replay [flags] <game file>")
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	record, err := model.ReadGameRecord(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	var b *book.Book
+	if *bookFile != "" {
+		if b, err = book.Load(*bookFile); err != nil {
+			return err
+		}
+	}
+
+	if *weightsFile != "" {
+		if ai.DefaultWeights, err = ai.LoadWeights(*weightsFile); err != nil {
+			return err
+		}
+	}
+
+	players := make(map[model.Piece]*ai.Player)
+	for _, piece := range []model.Piece{model.Black, model.White} {
+		p, err := ai.ReplayPlayer(record.Metadata, piece)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			continue
+		}
+		if err := ai.CheckBook(record.Metadata, piece, b); err != nil {
+			return err
+		}
+		p.Book = b
+		players[piece] = p
+		fmt.Printf("%s: %s AI, seed %d\n", model.GetPieceName(piece), p.Difficulty, p.Seed)
+	}
+	if len(players) == 0 {
+		return fmt.Errorf("the game has no AI players to replay")
+	}
+
+	game := model.NewGame()
+	for i, move := range record.Moves {
+		row, col, err := model.ParseMove(move)
+		if err != nil {
+			return fmt.Errorf("move %d (%q): %w", i+1, move, err)
+		}
+
+		side := game.GetCurrentPlayer()
+		if p, ok := players[side]; ok && game.HasValidMove() {
+			aiRow, aiCol, err := p.GetMove(game.Board)
+			if err != nil {
+				return fmt.Errorf("move %d: %w", i+1, err)
+			}
+			if aiRow != row || aiCol != col {
+				fmt.Printf("Move %d differs: the game has %s, the AI now plays %s\n", i+1, move, model.FormatMove(aiRow, aiCol))
+				return fmt.Errorf("replay diverged")
+			}
+		}
+
+		if row < 0 {
+			err = game.Pass()
+		} else {
+			err = game.MakeMove(row, col)
+		}
+		if err != nil {
+			return fmt.Errorf("move %d (%q): %w", i+1, move, err)
+		}
+	}
+
+	fmt.Printf("All %d moves replayed identically.\n", len(record.Moves))
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"log/slog"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ui/console"
+)
+
+// runServe serves the console game to telnet clients
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", ":2323", "Address to listen on")
+	maxSessions := fs.Int("max-sessions", 64, "Maximum concurrent sessions, 0 for no limit")
+	idle := fs.Duration("idle-timeout", 30*time.Minute, "Disconnect players idle for this long, 0 to never")
+	fs.Parse(args)
+
+	server := console.NewServer()
+	server.MaxSessions = *maxSessions
+	server.IdleTimeout = *idle
+
+	slog.Info("serving console games over telnet", "addr", *addr, "max_sessions", *maxSessions)
+	return server.ListenAndServe(*addr)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/suite"
+)
+
+// runSuite scores the engine on a position test suite
+func runSuite(args []string) error {
+	fs := flag.NewFlagSet("suite", flag.ExitOnError)
+	timeLimit := fs.Duration("time", time.Second, "Search time per position, 0 for no limit")
+	depth := fs.Int("depth", 0, "Search depth limit, 0 for none")
+	nodes := fs.Int64("nodes", 0, "Nodes searched per position, 0 for no limit")
+	deterministic := fs.Bool("deterministic", false, "Search by node count alone, -nodes or 1000000, and print no times, so runs repeat exactly")
+	verbose := fs.Bool("v", false, "Print every position, not only the failures")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: othello suite [flags] <suite file>")
+	}
+	player := &ai.Player{Difficulty: ai.Hard}
+	if *deterministic {
+		player.Deterministic(*nodes, 0)
+		*timeLimit, *nodes = 0, player.Nodes
+	}
+	if *timeLimit == 0 && *depth == 0 && *nodes == 0 {
+		return fmt.Errorf("set a time, depth or node limit")
+	}
+
+	tests, err := suite.Load(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	runner := &suite.Runner{
+		Player:    player,
+		TimeLimit: *timeLimit,
+		MaxDepth:  *depth,
+		NodeLimit: *nodes,
+	}
+	_, summary := runner.Run(ctx, tests, func(o suite.Outcome) {
+		if o.Solved && !*verbose {
+			return
+		}
+		status := "FAIL"
+		switch {
+		case o.Solved && *deterministic:
+			status = fmt.Sprintf("ok (%d nodes)", o.SolvedAtNodes)
+		case o.Solved:
+			status = fmt.Sprintf("ok (%v)", o.SolvedAt.Round(time.Millisecond))
+		}
+		fmt.Printf("%-20s %-4s expected %-12s depth %2d score %+6d  %s\n",
+			o.Test.ID, model.FormatMove(o.Move.Row, o.Move.Col), expectedMoves(o.Test), o.Depth, o.Score, status)
+	})
+
+	fmt.Printf("\nSolved %d of %d (%.1f%%), %d nodes", summary.Solved, summary.Total,
+		100*float64(summary.Solved)/float64(max(summary.Total, 1)), summary.Nodes)
+	if !*deterministic {
+		fmt.Printf(" in %v", summary.Elapsed.Round(time.Millisecond))
+	}
+	fmt.Println()
+	return nil
+}
+
+// expectedMoves describes what a test accepts, e.g. "C3,E3" or "not D3"
+func expectedMoves(t suite.Test) string {
+	format := func(moves []model.Position) string {
+		names := make([]string, len(moves))
+		for i, m := range moves {
+			names[i] = model.FormatMove(m.Row, m.Col)
+		}
+		return strings.Join(names, ",")
+	}
+	if len(t.Best) > 0 {
+		return format(t.Best)
+	}
+	return "not " + format(t.Avoid)
+}
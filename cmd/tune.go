@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/tune"
+)
+
+// runTune fits the evaluation weights to the results of WTHOR and self-play
+// games
+func runTune(args []string) error {
+	fs := flag.NewFlagSet("tune", flag.ExitOnError)
+	out := fs.String("o", "weights.json", "Output weights file")
+	selfPlay := fs.Int("selfplay", 0, "Number of self-play games to add to the dataset")
+	level := fs.String("level", ai.Medium, "AI difficulty of the self-play games")
+	plies := fs.Int("opening-plies", 8, "Random opening moves of each self-play game")
+	skip := fs.Int("skip", 8, "Opening moves of each game left out of the dataset")
+	iterations := fs.Int("iterations", 500, "Gradient descent iterations")
+	rate := fs.Float64("rate", 0.5, "Gradient descent step size in evaluation points")
+	seed := fs.Int64("seed", 1, "Seed for the self-play openings")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: othello tune [options] [file.wtb...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 && *selfPlay == 0 {
+		fs.Usage()
+		return errors.New("no WTHOR files or self-play games given")
+	}
+
+	var samples []tune.Sample
+	games := 0
+	for _, path := range fs.Args() {
+		wthorGames, err := readWthorFile(path)
+		if err != nil {
+			return err
+		}
+		for _, g := range wthorGames {
+			// WTHOR stores the final black disc count; empties go to the winner
+			result := 0.5
+			switch {
+			case g.BlackScore > 32:
+				result = 1
+			case g.BlackScore < 32:
+				result = 0
+			}
+			samples = append(samples, tune.GameSamples(g.Moves, result, *skip)...)
+			games++
+		}
+	}
+
+	if *selfPlay > 0 {
+		fmt.Fprintf(os.Stderr, "Playing %d self-play games on %s...\n", *selfPlay, *level)
+		for _, g := range tune.SelfPlay(*selfPlay, *level, *plies, *seed) {
+			moves := make([]model.Position, len(g.History))
+			for i, m := range g.History {
+				moves[i] = m.Position
+			}
+			samples = append(samples, tune.GameSamples(moves, tune.ResultFor(g.Winner), *skip)...)
+			games++
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Tuning on %d positions from %d games\n", len(samples), games)
+	res, err := tune.Tune(samples, ai.BuiltinWeights(), tune.Options{
+		Iterations: *iterations,
+		Rate:       *rate,
+		Progress: func(it int, loss float64) {
+			if it%50 == 0 {
+				fmt.Fprintf(os.Stderr, "iteration %d: loss %.5f\n", it, loss)
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := res.Weights.Save(*out); err != nil {
+		return err
+	}
+
+	fmt.Printf("Loss %.5f -> %.5f (sigmoid scale %.1f); wrote %s\n", res.InitialLoss, res.FinalLoss, res.Scale, *out)
+	return nil
+}
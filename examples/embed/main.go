@@ -0,0 +1,77 @@
+//go:build !nogui
+
+// Command embed shows an Othello board inside another Ebitengine game: you
+// play Black against the computer, and the moves are listed beside the board.
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+const screenW, screenH = 640, 440
+
+// demo is the host game the board is embedded in
+type demo struct {
+	board *widget.Board
+	moves []string
+}
+
+func (d *demo) Update() error {
+	return d.board.Update()
+}
+
+func (d *demo) Draw(screen *ebiten.Image) {
+	screen.Fill(color.RGBA{30, 30, 46, 255})
+	d.board.Draw(screen)
+
+	game := d.board.Game()
+	black, white := game.GetScore()
+	status := fmt.Sprintf("Black %d - White %d\n\n", black, white)
+	switch {
+	case game.GameOver:
+		status += "Game over\n"
+	case d.board.Thinking():
+		status += "Computer is thinking...\n"
+	default:
+		status += "Your move\n"
+	}
+	// The last moves, in columns of two plies
+	recent := d.moves[max(len(d.moves)-40, 0):]
+	for i := 0; i < len(recent); i += 2 {
+		status += "\n" + strings.Join(recent[i:min(i+2, len(recent))], "  ")
+	}
+	ebitenutil.DebugPrintAt(screen, status, 440, 20)
+}
+
+func (d *demo) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return screenW, screenH
+}
+
+func main() {
+	driver := widget.NewDriver(nil)
+	driver.SetPlayer(engine.White, engine.NewAI(engine.Medium, engine.White))
+
+	d := &demo{board: widget.NewBoard(driver, image.Rect(20, 20, 420, 420))}
+	driver.Observe(func(e widget.Event) {
+		move := "pass"
+		if p := e.Move.Position; p.Row >= 0 {
+			move = engine.FormatMove(p.Row, p.Col)
+		}
+		d.moves = append(d.moves, move)
+	})
+
+	ebiten.SetWindowSize(screenW, screenH)
+	ebiten.SetWindowTitle("Othello widget example")
+	if err := ebiten.RunGame(d); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,9 +1,10 @@
 module github.com/amirhossein-jamali/othello
 
-go 1.20
+go 1.21
 
 require (
 	github.com/hajimehoshi/ebiten/v2 v2.6.3
+	go.starlark.net v0.0.0-20231121155337-90ade8b19d09
 	golang.org/x/image v0.15.0
 )
 
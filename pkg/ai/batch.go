@@ -0,0 +1,64 @@
+package ai
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// BatchOptions configure EvaluatePositions
+type BatchOptions struct {
+	Depth   int           // Plies searched per position, HardDepth when 0
+	Nodes   int64         // Nodes searched per position at most, 0 for no limit
+	Time    time.Duration // Time per position at most, 0 for no limit
+	Weights *Weights      // Evaluation weights, DefaultWeights when nil
+	Workers int           // Searches run at once, one per CPU when 0
+}
+
+// EvaluatePositions searches every board as the Hard AI would and returns
+// the results in the same order, scored for each board's side to move
+// The boards are not modified. Searches cut short by ctx report the deepest
+// depth they finished, 0 for boards they never reached. Limited by nodes
+// rather than time, the results do not depend on the machine or its load.
+func EvaluatePositions(ctx context.Context, boards []*model.Board, opts BatchOptions) []Info {
+	limits := SearchLimits{Depth: opts.Depth, Nodes: opts.Nodes, Time: opts.Time}
+	if limits.Depth <= 0 {
+		limits.Depth = HardDepth
+	}
+	weights := opts.Weights
+	if weights == nil {
+		weights = DefaultWeights
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	results := make([]Info, len(boards))
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := &Player{Difficulty: Hard, Weights: weights, ProbCut: ProbCutThresholds[Hard]}
+			for i := range queue {
+				board := boards[i]
+				results[i] = Info{Side: board.CurrentPlayer, Best: model.PassPosition}
+				if ctx.Err() == nil {
+					results[i] = p.Search(ctx, board.Clone(), limits, nil)
+				}
+			}
+		}()
+	}
+
+	for i := range boards {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,212 @@
+package ai
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// DefaultCache is the analysis cache used by analysis views, if any
+var DefaultCache *Cache
+
+// cacheEntry is one line of the cache file: the deepest result known for a
+// position, its moves stored as row*8+col with -1 for a pass
+type cacheEntry struct {
+	Hash  uint64 `json:"hash"`
+	Depth int    `json:"depth"`
+	Score int    `json:"score"`
+	PV    []int  `json:"pv"`
+	Nodes int64  `json:"nodes"`
+}
+
+// Cache remembers the deepest analysis of every position, optionally
+// appending each improvement to a JSON lines file so it survives restarts
+type Cache struct {
+	mu      sync.Mutex
+	entries map[uint64]cacheEntry
+	file    *os.File
+	out     *bufio.Writer
+}
+
+// NewCache creates an in-memory analysis cache
+func NewCache() *Cache {
+	return &Cache{entries: make(map[uint64]cacheEntry)}
+}
+
+// DefaultCachePath returns the cache file in the user's cache directory
+func DefaultCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "analysis-cache.jsonl"
+	}
+	return filepath.Join(dir, "othello", "analysis-cache.jsonl")
+}
+
+// OpenCache loads the cache file, creating it if needed, and appends new
+// results to it
+// A file mostly made of superseded lines is rewritten with one line per
+// position first.
+func OpenCache(path string) (*Cache, error) {
+	c := NewCache()
+	lines, err := c.load(path)
+	if err != nil {
+		return nil, err
+	}
+	if lines > 2*len(c.entries) {
+		if err := c.rewrite(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	c.file = f
+	c.out = bufio.NewWriter(f)
+	return c, nil
+}
+
+// load reads the entries of a cache file, returning how many lines it has
+func (c *Cache) load(path string) (int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines++
+		var e cacheEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Skip lines truncated by a crash
+		}
+		if old, ok := c.entries[e.Hash]; !ok || better(e, old) {
+			c.entries[e.Hash] = e
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// rewrite replaces the cache file with one line per position
+func (c *Cache) rewrite(path string) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(f)
+	enc := json.NewEncoder(out)
+	for _, e := range c.entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := out.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// better reports whether a result should replace old: proven results are
+// kept, otherwise the deeper search wins
+func better(e, old cacheEntry) bool {
+	if proven(old.Score) {
+		return false
+	}
+	return proven(e.Score) || e.Depth > old.Depth
+}
+
+// proven reports whether a score is a game result rather than an estimate
+func proven(score int) bool {
+	return score >= winScore || score <= -winScore
+}
+
+// Get returns the deepest analysis cached for the position
+func (c *Cache) Get(board *model.Board) (Info, bool) {
+	c.mu.Lock()
+	e, ok := c.entries[board.Hash()]
+	c.mu.Unlock()
+	if !ok {
+		return Info{}, false
+	}
+
+	info := Info{Depth: e.Depth, Score: e.Score, Side: board.CurrentPlayer, Best: model.PassPosition, Nodes: e.Nodes}
+	for _, m := range e.PV {
+		move := model.PassPosition
+		if m >= 0 {
+			move = model.Position{Row: m / 8, Col: m % 8}
+		}
+		info.PV = append(info.PV, move)
+	}
+	if len(info.PV) > 0 {
+		info.Best = info.PV[0]
+	}
+	return info, true
+}
+
+// Put stores the analysis of the position unless a deeper one is cached
+func (c *Cache) Put(board *model.Board, info Info) {
+	e := cacheEntry{Hash: board.Hash(), Depth: info.Depth, Score: info.Score, Nodes: info.Nodes}
+	for _, m := range info.PV {
+		if m.Row < 0 {
+			e.PV = append(e.PV, -1)
+		} else {
+			e.PV = append(e.PV, m.Row*8+m.Col)
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[e.Hash]; ok && !better(e, old) {
+		return
+	}
+	c.entries[e.Hash] = e
+
+	if c.out != nil {
+		data, err := json.Marshal(e)
+		if err == nil {
+			c.out.Write(data)
+			c.out.WriteByte('\n')
+			c.out.Flush()
+		}
+	}
+}
+
+// Len returns the number of positions in the cache
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Close flushes and closes the cache file, if any
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.file == nil {
+		return nil
+	}
+	c.out.Flush()
+	err := c.file.Close()
+	c.file, c.out = nil, nil
+	return err
+}
@@ -0,0 +1,140 @@
+package ai
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/book"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Metadata keys describing an AI player in a game record, prefixed with
+// the color it played, e.g. "white.ai.seed"
+const (
+	MetaDifficulty = "ai"
+	MetaSeed       = "ai.seed"
+	MetaDepth      = "ai.depth"
+	MetaBook       = "ai.book"
+	MetaBookPrint  = "ai.book.fingerprint"
+	MetaProbCut    = "ai.probcut"
+	MetaWeights    = "ai.weights" // Fingerprint of the evaluation weights
+	MetaNodes      = "ai.nodes"
+	MetaEvaluator  = "ai.evaluator"
+)
+
+// customEvaluator marks a player whose evaluation replaced the weights
+const customEvaluator = "custom"
+
+// metaKey returns the metadata key for the given color
+func metaKey(piece model.Piece, key string) string {
+	return strings.ToLower(model.GetPieceName(piece)) + "." + key
+}
+
+// WriteMetadata records the player's configuration and seed in the game's
+// metadata so the game can be replayed with ReplayPlayer
+func (p *Player) WriteMetadata(game *model.Game) {
+	game.SetMetadata(metaKey(p.Piece, MetaDifficulty), p.Difficulty)
+	game.SetMetadata(metaKey(p.Piece, MetaSeed), strconv.FormatInt(p.Seed, 10))
+	if p.Difficulty == Hard {
+		game.SetMetadata(metaKey(p.Piece, MetaDepth), strconv.Itoa(HardDepth))
+	}
+	if p.ProbCut != 0 {
+		game.SetMetadata(metaKey(p.Piece, MetaProbCut), strconv.FormatFloat(p.ProbCut, 'g', -1, 64))
+	}
+	if p.Book != nil && p.Difficulty != Easy {
+		game.SetMetadata(metaKey(p.Piece, MetaBook), strconv.Itoa(p.Book.Len()))
+		game.SetMetadata(metaKey(p.Piece, MetaBookPrint), p.Book.Fingerprint())
+	}
+	game.SetMetadata(metaKey(p.Piece, MetaWeights), p.weights().Fingerprint())
+	if p.Nodes > 0 {
+		game.SetMetadata(metaKey(p.Piece, MetaNodes), strconv.FormatInt(p.Nodes, 10))
+	}
+	if p.Evaluator != nil {
+		game.SetMetadata(metaKey(p.Piece, MetaEvaluator), customEvaluator)
+	}
+}
+
+// RecordedAI returns the difficulty and seed of the AI recorded for the
+// given color, or an empty difficulty if that color was not played by the
+// AI. Unlike ReplayPlayer it does not check the rest of the configuration,
+// for continuing a game rather than reproducing it.
+func RecordedAI(md map[string]string, piece model.Piece) (difficulty string, seed int64, err error) {
+	difficulty, ok := md[metaKey(piece, MetaDifficulty)]
+	if !ok {
+		return "", 0, nil
+	}
+	switch difficulty {
+	case Easy, Medium, Hard:
+	default:
+		return "", 0, fmt.Errorf("unknown AI difficulty %q", difficulty)
+	}
+
+	seed, err = strconv.ParseInt(md[metaKey(piece, MetaSeed)], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s: %w", metaKey(piece, MetaSeed), err)
+	}
+	return difficulty, seed, nil
+}
+
+// ReplayPlayer rebuilds the AI player recorded for the given color, or
+// returns nil if that color was not played by the AI. Recorded weights must
+// be the built-in ones or DefaultWeights, and the caller must supply the same
+// opening book, if one was used; CheckBook verifies it. A setting that cannot
+// be restored exactly is an error.
+func ReplayPlayer(md map[string]string, piece model.Piece) (*Player, error) {
+	difficulty, seed, err := RecordedAI(md, piece)
+	if difficulty == "" || err != nil {
+		return nil, err
+	}
+	name := model.GetPieceName(piece)
+	if depth, ok := md[metaKey(piece, MetaDepth)]; ok && depth != strconv.Itoa(HardDepth) {
+		return nil, fmt.Errorf("game was played with search depth %s, this build searches %d", depth, HardDepth)
+	}
+	if _, ok := md[metaKey(piece, MetaEvaluator)]; ok {
+		return nil, fmt.Errorf("%s played with a custom evaluator, which cannot be restored", name)
+	}
+	p := &Player{Difficulty: difficulty, Piece: piece, Seed: seed}
+
+	// Games recorded without ProbCut searched every move
+	if v, ok := md[metaKey(piece, MetaProbCut)]; ok {
+		if p.ProbCut, err = strconv.ParseFloat(v, 64); err != nil {
+			return nil, fmt.Errorf("%s: %w", metaKey(piece, MetaProbCut), err)
+		}
+	}
+	if v, ok := md[metaKey(piece, MetaNodes)]; ok {
+		if p.Nodes, err = strconv.ParseInt(v, 10, 64); err != nil || p.Nodes <= 0 {
+			return nil, fmt.Errorf("%s: invalid node limit %q", metaKey(piece, MetaNodes), v)
+		}
+	}
+
+	// Games recorded without a fingerprint used the built-in weights
+	if recorded, ok := md[metaKey(piece, MetaWeights)]; ok && recorded != builtinWeights.Fingerprint() {
+		if DefaultWeights == nil || recorded != DefaultWeights.Fingerprint() {
+			return nil, fmt.Errorf("%s played with evaluation weights %s; load the same weights to replay", name, recorded)
+		}
+		p.Weights = DefaultWeights
+	}
+	return p, nil
+}
+
+// CheckBook reports whether b matches the opening book recorded for the
+// given color, comparing the fingerprint or, in older records, the number
+// of positions
+func CheckBook(md map[string]string, piece model.Piece, b *book.Book) error {
+	recorded, used := md[metaKey(piece, MetaBook)]
+	switch {
+	case !used && b == nil:
+		return nil
+	case !used:
+		return fmt.Errorf("%s played without an opening book", model.GetPieceName(piece))
+	case b == nil:
+		return fmt.Errorf("%s played with an opening book of %s positions", model.GetPieceName(piece), recorded)
+	case recorded != strconv.Itoa(b.Len()):
+		return fmt.Errorf("%s played with an opening book of %s positions, not %d", model.GetPieceName(piece), recorded, b.Len())
+	}
+	if fingerprint, ok := md[metaKey(piece, MetaBookPrint)]; ok && fingerprint != b.Fingerprint() {
+		return fmt.Errorf("%s played with a different opening book of %s positions", model.GetPieceName(piece), recorded)
+	}
+	return nil
+}
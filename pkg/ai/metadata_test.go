@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// TestReplayPlayerHard checks a seeded Hard game replays move for move
+// ProbCut first changes a move after about forty plies, so the game is
+// played that far.
+func TestReplayPlayerHard(t *testing.T) {
+	if testing.Short() {
+		t.Skip("plays a long Hard game")
+	}
+	const plies = 44
+	game := model.NewGame()
+	players := map[model.Piece]*Player{}
+	for _, piece := range []model.Piece{model.Black, model.White} {
+		p := NewPlayer(Hard, piece)
+		p.Book, p.Telemetry, p.Seed = nil, nil, 42
+		p.WriteMetadata(game)
+		players[piece] = p
+	}
+	for len(game.History) < plies && !game.GameOver {
+		if !game.HasValidMove() {
+			game.Pass()
+			continue
+		}
+		row, col, err := players[game.GetCurrentPlayer()].GetMove(game.Board.Clone())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := game.MakeMove(row, col); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replayed := map[model.Piece]*Player{}
+	for _, piece := range []model.Piece{model.Black, model.White} {
+		p, err := ReplayPlayer(game.Metadata, piece)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.ProbCut != players[piece].ProbCut {
+			t.Fatalf("%s ProbCut = %v, played with %v", model.GetPieceName(piece), p.ProbCut, players[piece].ProbCut)
+		}
+		replayed[piece] = p
+	}
+	for i, move := range game.History {
+		board := game.BoardAt(i)
+		if move.IsPass() {
+			continue
+		}
+		row, col, err := replayed[move.Piece].GetMove(board)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if row != move.Position.Row || col != move.Position.Col {
+			t.Fatalf("ply %d: replayed %s, played %s", i+1, model.FormatMove(row, col), model.FormatMove(move.Position.Row, move.Position.Col))
+		}
+	}
+}
+
+// TestReplayPlayerSettings checks the weights and node limit are restored,
+// and that weights which cannot be restored are an error
+func TestReplayPlayerSettings(t *testing.T) {
+	custom := BuiltinWeights()
+	custom.Mobility = 7
+	p := NewPlayer(Medium, model.White)
+	p.Weights = &custom
+	p.Deterministic(5000, 1)
+
+	game := model.NewGame()
+	p.WriteMetadata(game)
+
+	saved := DefaultWeights
+	defer func() { DefaultWeights = saved }()
+	DefaultWeights = nil
+	if _, err := ReplayPlayer(game.Metadata, model.White); err == nil {
+		t.Fatal("replayed without the weights the game was played with")
+	}
+
+	DefaultWeights = &custom
+	replayed, err := ReplayPlayer(game.Metadata, model.White)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.Weights != &custom || replayed.Nodes != 5000 {
+		t.Fatalf("replayed with weights %v and %d nodes", replayed.Weights, replayed.Nodes)
+	}
+}
@@ -0,0 +1,54 @@
+package ai
+
+import "github.com/amirhossein-jamali/othello/pkg/model"
+
+// phaseCount is the number of game phases, as given by model.Phase
+const phaseCount = int(model.Endgame) + 1
+
+// PhaseEval summarizes the evaluation over one phase of a game
+type PhaseEval struct {
+	Name    string // "Opening", "Midgame" or "Endgame"
+	Moves   int    // Moves played in the phase
+	Average int    // Mean positional evaluation after the moves, from Black's view
+	Final   int    // Evaluation when the phase ended, from Black's view
+}
+
+// PhaseEvals replays the game and evaluates the position after every move,
+// summarized per phase; phases without moves are left out
+func PhaseEvals(game *model.Game) []PhaseEval {
+	phases := make([]PhaseEval, phaseCount)
+	for i := range phases {
+		phases[i].Name = model.GamePhase(i).String()
+	}
+	totals := make([]int, len(phases))
+
+	replay := model.NewGame()
+	p := Player{Weights: DefaultWeights}
+	for _, move := range game.History {
+		var err error
+		if move.Position.Row < 0 {
+			err = replay.Pass()
+		} else {
+			err = replay.MakeMove(move.Position.Row, move.Position.Col)
+		}
+		if err != nil {
+			break
+		}
+
+		i := model.Phase(replay.Board)
+		eval := p.evaluate(replay.Board, model.Black)
+		phases[i].Moves++
+		phases[i].Final = eval
+		totals[i] += eval
+	}
+
+	var played []PhaseEval
+	for i, phase := range phases {
+		if phase.Moves == 0 {
+			continue
+		}
+		phase.Average = totals[i] / phase.Moves
+		played = append(played, phase)
+	}
+	return played
+}
@@ -1,10 +1,12 @@
 package ai
 
 import (
+	"context"
 	"math"
 	"math/rand"
 	"time"
 
+	"github.com/amirhossein-jamali/othello/pkg/book"
 	"github.com/amirhossein-jamali/othello/pkg/model"
 )
 
@@ -15,10 +17,52 @@ const (
 	Hard   = "hard"
 )
 
+// HardDepth is the number of plies searched on Hard
+const HardDepth = 7
+
+// MinBookGames is the number of games a book move needs before it is trusted
+const MinBookGames = 3
+
+// DefaultBook is the opening book given to new players, if any
+var DefaultBook *book.Book
+
+// DefaultWeights are the evaluation weights given to new players, if any
+var DefaultWeights *Weights
+
 // Player represents an AI player
 type Player struct {
 	Difficulty string
 	Piece      model.Piece
+	Book       *book.Book // Optional opening book, ignored on Easy
+	Telemetry  *Telemetry // Optional log of every move decision
+	Weights    *Weights   // Evaluation weights, the defaults when nil
+	ProbCut    float64    // ProbCut confidence in standard deviations, 0 to search every move
+	Evaluator  Evaluator  // Optional evaluation used in place of the weights
+
+	// Seed drives every random choice, so a player with the same seed and
+	// configuration replays a game move for move
+	Seed int64
+	rng  *rand.Rand
+
+	// Nodes limits every search to this many nodes, 0 for no limit; a
+	// player with a node limit ignores clocks
+	Nodes int64
+}
+
+// DeterministicNodes is the node limit of Deterministic when none is given
+const DeterministicNodes = 1_000_000
+
+// Deterministic makes the player repeat its moves exactly on any machine and
+// under any load, to reproduce and bisect engine changes: searches stop at
+// the node limit (DeterministicNodes when 0) and never on time, and random
+// choices restart from seed
+func (p *Player) Deterministic(nodes, seed int64) {
+	if nodes <= 0 {
+		nodes = DeterministicNodes
+	}
+	p.Nodes = nodes
+	p.Seed = seed
+	p.rng = nil
 }
 
 // NewPlayer creates a new AI player with the specified difficulty
@@ -26,21 +70,58 @@ func NewPlayer(difficulty string, piece model.Piece) *Player {
 	return &Player{
 		Difficulty: difficulty,
 		Piece:      piece,
+		Book:       DefaultBook,
+		Telemetry:  DefaultTelemetry,
+		Weights:    DefaultWeights,
+		ProbCut:    ProbCutThresholds[difficulty],
+		Seed:       time.Now().UnixNano(),
+	}
+}
+
+// random returns the player's random source, seeding it on first use
+func (p *Player) random() *rand.Rand {
+	if p.rng == nil {
+		p.rng = rand.New(rand.NewSource(p.Seed))
 	}
+	return p.rng
 }
 
 // GetMove returns the AI's chosen move
 func (p *Player) GetMove(board *model.Board) (int, int, error) {
+	started := time.Now()
+
+	if move, ok := p.bookMove(board, started); ok {
+		return move.Row, move.Col, nil
+	}
+
 	switch p.Difficulty {
-	case Easy:
-		return p.getRandomMove(board)
 	case Medium:
-		return p.getMediumMove(board)
+		row, col, err := p.getMediumMove(board)
+		if err == nil {
+			p.record(board, SourceHeuristic, row, col, Info{Depth: 1}, started)
+		}
+		return row, col, err
 	case Hard:
-		return p.getHardMove(board)
+		return p.getHardMove(board, started)
 	default:
-		return p.getRandomMove(board)
+		row, col, err := p.getRandomMove(board)
+		if err == nil {
+			p.record(board, SourceRandom, row, col, Info{}, started)
+		}
+		return row, col, err
+	}
+}
+
+// bookMove plays from the opening book while the position is known
+func (p *Player) bookMove(board *model.Board, started time.Time) (model.Position, bool) {
+	if p.Book == nil || p.Difficulty == Easy {
+		return model.Position{}, false
+	}
+	move, ok := p.Book.BestMove(board, MinBookGames)
+	if ok {
+		p.record(board, SourceBook, move.Row, move.Col, Info{}, started)
 	}
+	return move, ok
 }
 
 // getRandomMove returns a random valid move
@@ -50,8 +131,7 @@ func (p *Player) getRandomMove(board *model.Board) (int, int, error) {
 		return -1, -1, nil
 	}
 
-	rand.Seed(time.Now().UnixNano())
-	move := moves[rand.Intn(len(moves))]
+	move := moves[p.random().Intn(len(moves))]
 	return move.Row, move.Col, nil
 }
 
@@ -80,96 +160,105 @@ func (p *Player) getMediumMove(board *model.Board) (int, int, error) {
 	return bestMove.Row, bestMove.Col, nil
 }
 
-// getHardMove uses minimax algorithm with alpha-beta pruning
-func (p *Player) getHardMove(board *model.Board) (int, int, error) {
-	moves := board.GetValidMoves()
-	if len(moves) == 0 {
+// getHardMove searches a few moves ahead with alpha-beta pruning
+func (p *Player) getHardMove(board *model.Board, started time.Time) (int, int, error) {
+	if !board.HasValidMove() {
 		return -1, -1, nil
 	}
 
-	bestScore := math.MinInt32
-	var bestMove model.Position
-
-	for _, move := range moves {
-		boardCopy := board.Clone()
-		boardCopy.MakeMove(move.Row, move.Col)
-		score := p.minimax(boardCopy, 4, math.MinInt32, math.MaxInt32, false)
-
-		if score > bestScore {
-			bestScore = score
-			bestMove = move
-		}
+	info := p.Search(context.Background(), board, SearchLimits{Depth: HardDepth, Nodes: p.Nodes}, nil)
+	if info.Depth == 0 {
+		// The node budget ran out before one ply finished: fall back to the
+		// heuristic
+		row, col, err := p.getMediumMove(board)
+		p.record(board, SourceHeuristic, row, col, Info{Depth: 1}, started)
+		return row, col, err
 	}
-
-	return bestMove.Row, bestMove.Col, nil
+	p.record(board, SourceSearch, info.Best.Row, info.Best.Col, info, started)
+	return info.Best.Row, info.Best.Col, nil
 }
 
-// minimax implements the minimax algorithm with alpha-beta pruning
-func (p *Player) minimax(board *model.Board, depth int, alpha, beta int, maximizing bool) int {
-	if depth == 0 || board.IsGameOver() {
-		return p.evaluatePosition(board)
-	}
-
-	moves := board.GetValidMoves()
-	if len(moves) == 0 {
-		return p.evaluatePosition(board)
-	}
-
-	if maximizing {
-		maxScore := math.MinInt32
-		for _, move := range moves {
-			boardCopy := board.Clone()
-			boardCopy.MakeMove(move.Row, move.Col)
-			score := p.minimax(boardCopy, depth-1, alpha, beta, false)
-			maxScore = max(maxScore, score)
-			alpha = max(alpha, score)
-			if beta <= alpha {
-				break
-			}
-		}
-		return maxScore
-	} else {
-		minScore := math.MaxInt32
-		for _, move := range moves {
-			boardCopy := board.Clone()
-			boardCopy.MakeMove(move.Row, move.Col)
-			score := p.minimax(boardCopy, depth-1, alpha, beta, true)
-			minScore = min(minScore, score)
-			beta = min(beta, score)
-			if beta <= alpha {
-				break
-			}
-		}
-		return minScore
-	}
+// positionWeights rates each square; corners are valuable, the squares
+// next to them are dangerous
+var positionWeights = [8][8]int{
+	{100, -20, 10, 5, 5, 10, -20, 100},
+	{-20, -50, -2, -2, -2, -2, -50, -20},
+	{10, -2, -1, -1, -1, -1, -2, 10},
+	{5, -2, -1, -1, -1, -1, -2, 5},
+	{5, -2, -1, -1, -1, -1, -2, 5},
+	{10, -2, -1, -1, -1, -1, -2, 10},
+	{-20, -50, -2, -2, -2, -2, -50, -20},
+	{100, -20, 10, 5, 5, 10, -20, 100},
 }
 
+// Parity tuning
+const (
+	parityEmpties = 20 // Parity counts once this few squares are empty
+	parityWeight  = 10 // Score per empty region, gained for odd ones and lost for even ones
+)
+
 // evaluatePosition returns a score for the current board position
 func (p *Player) evaluatePosition(board *model.Board) int {
-	// Simple evaluation: count pieces with weights
-	var score int
-	weights := [8][8]int{
-		{100, -20, 10, 5, 5, 10, -20, 100},
-		{-20, -50, -2, -2, -2, -2, -50, -20},
-		{10, -2, -1, -1, -1, -1, -2, 10},
-		{5, -2, -1, -1, -1, -1, -2, 5},
-		{5, -2, -1, -1, -1, -1, -2, 5},
-		{10, -2, -1, -1, -1, -1, -2, 10},
-		{-20, -50, -2, -2, -2, -2, -50, -20},
-		{100, -20, 10, 5, 5, 10, -20, 100},
+	return p.evaluate(board, p.Piece)
+}
+
+// Evaluator scores positions for the search in place of the built-in
+// evaluation, e.g. one written as a script
+type Evaluator interface {
+	Evaluate(board *model.Board, side model.Piece) int
+}
+
+// evaluate scores the board from the given side's point of view
+func (p *Player) evaluate(board *model.Board, side model.Piece) int {
+	if p.Evaluator != nil {
+		return p.Evaluator.Evaluate(board, side)
 	}
 
+	// Count pieces with weights
+	w := p.weights()
+	var table int
 	for i := 0; i < 8; i++ {
 		for j := 0; j < 8; j++ {
 			piece := board.GetPiece(i, j)
-			if piece == p.Piece {
-				score += weights[i][j]
+			if piece == side {
+				table += w.Table[i][j]
 			} else if piece != model.Empty {
-				score -= weights[i][j]
+				table -= w.Table[i][j]
 			}
 		}
 	}
+	var mobility int
+	if w.Mobility != 0 {
+		mobility = w.Mobility * (board.Mobility(side) - board.Mobility(side.Opponent()))
+	}
 
+	score := table + mobility
+	if len(w.Phases) > 0 {
+		scale := w.Phases[model.Phase(board)]
+		score = int(math.Round(scale.Table*float64(table) + scale.Mobility*float64(mobility)))
+	}
+
+	return score + parity(board, side)
+}
+
+// parity scores who moves last in each empty region late in the game: the
+// side to move can take the last square of every odd region, while the
+// opponent gets the last square of every even one
+func parity(board *model.Board, side model.Piece) int {
+	if emptySquares(board) > parityEmpties {
+		return 0
+	}
+	score := 0
+	for _, region := range board.Regions() {
+		if region.Odd() {
+			score += parityWeight
+		} else {
+			score -= parityWeight
+		}
+	}
+	if board.CurrentPlayer != side {
+		score = -score
+	}
 	return score
 }
 
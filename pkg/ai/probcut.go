@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"context"
+	"math"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// ProbCut tuning
+// The linear model and its error were fitted with FitProbCut on 300 positions
+// 12 to 42 random moves into the game, relating searches probCutReduction
+// plies shallower to depths 4 to 7; the fits barely differed by depth.
+const (
+	probCutMinDepth  = 4 // Shallower nodes are searched in full
+	probCutReduction = 3 // Depth saved by the shallow search
+	probCutA         = 1.02
+	probCutB         = 0.0
+	probCutSigma     = 22.0
+)
+
+// ProbCutThresholds is the confidence, in standard deviations of the
+// shallow search's error, ProbCut needs before pruning at each difficulty
+// Lower values prune more and search deeper in the same time, but miss more
+// moves; difficulties without an entry search every move.
+var ProbCutThresholds = map[string]float64{
+	Hard: 1.5,
+}
+
+// ProbCutFit relates shallow search scores to deep ones:
+// deep ≈ A*shallow + B, with errors of standard deviation Sigma
+type ProbCutFit struct {
+	A, B, Sigma float64
+	Samples     int
+}
+
+// FitProbCut searches every board to both depths and fits the linear model
+// by least squares; proven results are left out
+func FitProbCut(boards []*model.Board, shallow, deep int) ProbCutFit {
+	p := &Player{Difficulty: Hard}
+	var xs, ys []float64
+	for _, board := range boards {
+		if !board.HasValidMove() {
+			continue
+		}
+		var x, y float64
+		ok := 0
+		p.Analyze(context.Background(), board, deep, func(info Info) {
+			if info.IsMate() {
+				return
+			}
+			switch info.Depth {
+			case shallow:
+				x = float64(info.Score)
+				ok++
+			case deep:
+				y = float64(info.Score)
+				ok++
+			}
+		})
+		if ok == 2 {
+			xs, ys = append(xs, x), append(ys, y)
+		}
+	}
+
+	n := float64(len(xs))
+	if n < 2 {
+		return ProbCutFit{A: 1, Samples: len(xs)}
+	}
+	var sx, sy, sxx, sxy float64
+	for i := range xs {
+		sx += xs[i]
+		sy += ys[i]
+		sxx += xs[i] * xs[i]
+		sxy += xs[i] * ys[i]
+	}
+	fit := ProbCutFit{Samples: len(xs)}
+	fit.A = (n*sxy - sx*sy) / (n*sxx - sx*sx)
+	fit.B = (sy - fit.A*sx) / n
+
+	var sse float64
+	for i := range xs {
+		e := ys[i] - fit.A*xs[i] - fit.B
+		sse += e * e
+	}
+	fit.Sigma = math.Sqrt(sse / (n - 2))
+	return fit
+}
+
+// probCut tries to prove with a shallow search that the node falls outside
+// the window, returning the bound it proved
+func (s *searcher) probCut(board *model.Board, depth, alpha, beta int) (int, bool) {
+	t := s.player.ProbCut
+	if t <= 0 || depth < probCutMinDepth || alpha <= -winScore || beta >= winScore {
+		return 0, false
+	}
+	shallow := depth - probCutReduction
+	margin := t * probCutSigma
+
+	// Shallow score needed to predict a deep score of at least beta
+	if bound := int(math.Ceil((float64(beta) + margin - probCutB) / probCutA)); bound < winScore {
+		if s.negamax(board, shallow, bound-1, bound, false) >= bound {
+			return beta, true
+		}
+	}
+	// Shallow score needed to predict a deep score of at most alpha
+	if bound := int(math.Floor((float64(alpha) - margin - probCutB) / probCutA)); bound > -winScore {
+		if s.negamax(board, shallow, bound, bound+1, false) <= bound {
+			return alpha, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,446 @@
+package ai
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Search tuning
+const (
+	winScore          = 10000   // Score of a won game before adding the disc difference
+	maxTTEntries      = 1 << 20 // Transposition table is cleared when it grows past this
+	nodeCheckInterval = 1024    // Nodes between cancellation checks
+	maxSearchDepth    = 60      // No game lasts longer than 60 moves
+	aspirationWindow  = 10      // Half width of the window around the previous iteration's score
+	aspirationDepth   = 3       // Shallower iterations search the full window
+	maxExtensions     = 6       // Forced moves searched without using up depth, per line
+)
+
+// Info reports the result of a completed search iteration
+type Info struct {
+	Depth   int
+	Score   int              // From the point of view of the side to move
+	Side    model.Piece      // Side to move at the root
+	Best    model.Position   // Row and Col are -1 for a pass
+	PV      []model.Position // Principal variation, passes included
+	Nodes   int64
+	Elapsed time.Duration
+
+	// Transposition table lookups and how many found an entry
+	TTProbes int64
+	TTHits   int64
+}
+
+// IsMate reports whether the score is a proven game result
+func (i Info) IsMate() bool {
+	return i.Score >= winScore || i.Score <= -winScore
+}
+
+// pointsPerDisc converts evaluation points to discs, fitted by regressing
+// the final disc margins of self-play games on their midgame scores
+const pointsPerDisc = 10
+
+// Discs converts a score to the final disc margin it predicts: exactly for
+// proven results, estimated otherwise
+func Discs(score int) float64 {
+	switch {
+	case score >= winScore:
+		return float64(score - winScore)
+	case score <= -winScore:
+		return float64(score + winScore)
+	}
+	return float64(max(min(score, 64*pointsPerDisc), -64*pointsPerDisc)) / pointsPerDisc
+}
+
+// winChanceDiscs is the predicted disc margin at which the side ahead is
+// given about an 88% chance to win
+const winChanceDiscs = 20
+
+// WinProbability estimates the chance that the side a score is for wins:
+// certain for proven results, growing with the predicted margin otherwise
+func WinProbability(score int) float64 {
+	switch {
+	case score >= winScore:
+		return 1
+	case score <= -winScore:
+		return 0
+	}
+	return 0.5 + 0.5*math.Tanh(Discs(score)/winChanceDiscs)
+}
+
+// NodesPerSecond returns the search speed
+func (i Info) NodesPerSecond() float64 {
+	if i.Elapsed <= 0 {
+		return 0
+	}
+	return float64(i.Nodes) / i.Elapsed.Seconds()
+}
+
+// TTHitRate returns the fraction of transposition table lookups that found
+// an entry
+func (i Info) TTHitRate() float64 {
+	if i.TTProbes == 0 {
+		return 0
+	}
+	return float64(i.TTHits) / float64(i.TTProbes)
+}
+
+// ttFlag describes how a stored score bounds the true score
+type ttFlag int8
+
+const (
+	ttExact ttFlag = iota
+	ttLower
+	ttUpper
+)
+
+// ttEntry is a transposition table slot
+type ttEntry struct {
+	depth int8
+	flag  ttFlag
+	move  int8 // Square index of the best move, -1 if unknown or pass
+	score int32
+}
+
+// searcher holds the state of one search
+type searcher struct {
+	player   *Player
+	ctx      context.Context
+	tt       map[uint64]ttEntry
+	nodes    int64
+	maxNodes int64 // 0 for no limit
+	aborted  bool
+
+	ttProbes int64
+	ttHits   int64
+
+	// deadline is ctx's deadline, checked directly since the timer that
+	// cancels ctx can fire late when the search has the only CPU
+	deadline time.Time
+
+	extensions int // Extensions on the line being searched
+}
+
+// newSearcher creates a searcher with an empty transposition table
+func newSearcher(ctx context.Context, p *Player) *searcher {
+	deadline, _ := ctx.Deadline()
+	return &searcher{
+		player:   p,
+		ctx:      ctx,
+		tt:       make(map[uint64]ttEntry),
+		deadline: deadline,
+	}
+}
+
+// SearchLimits bound a search by depth, nodes and wall time together; it
+// stops at whichever is reached first. A zero field sets no limit.
+// Node limits make a search repeat exactly on any machine, while time
+// limits suit interactive play.
+type SearchLimits struct {
+	Depth int
+	Nodes int64
+	Time  time.Duration
+}
+
+// Analyze runs an iterative deepening search on the board, calling report
+// after every completed depth. The search stops at maxDepth (0 searches to
+// the end of the game) or when ctx is cancelled, returning the deepest
+// completed result.
+func (p *Player) Analyze(ctx context.Context, board *model.Board, maxDepth int, report func(Info)) Info {
+	return p.Search(ctx, board, SearchLimits{Depth: maxDepth}, report)
+}
+
+// Search is Analyze under limits: it also stops once the limits are
+// reached, returning the deepest iteration completed within them
+func (p *Player) Search(ctx context.Context, board *model.Board, limits SearchLimits, report func(Info)) Info {
+	maxDepth := limits.Depth
+	if maxDepth <= 0 || maxDepth > maxSearchDepth {
+		maxDepth = maxSearchDepth
+	}
+	if limits.Time > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Time)
+		defer cancel()
+	}
+
+	s := newSearcher(ctx, p)
+	s.maxNodes = limits.Nodes
+	start := time.Now()
+	result := Info{Side: board.CurrentPlayer, Best: model.PassPosition}
+
+	for depth := 1; depth <= maxDepth; depth++ {
+		score := s.aspirate(board, depth, result)
+		if s.aborted {
+			break
+		}
+
+		result = Info{
+			Depth:    depth,
+			Score:    score,
+			Side:     board.CurrentPlayer,
+			PV:       s.principalVariation(board, depth),
+			Nodes:    s.nodes,
+			Elapsed:  time.Since(start),
+			TTProbes: s.ttProbes,
+			TTHits:   s.ttHits,
+		}
+		result.Best = model.PassPosition
+		if len(result.PV) > 0 {
+			result.Best = result.PV[0]
+		}
+
+		if report != nil {
+			report(result)
+		}
+
+		// A proven result cannot change with more depth
+		if result.IsMate() || depth >= emptySquares(board) {
+			break
+		}
+	}
+
+	return result
+}
+
+// aspirate searches the root in a narrow window around the previous
+// iteration's score, which cuts more nodes when the score barely moves, and
+// searches again with the full window when the score falls outside it
+func (s *searcher) aspirate(board *model.Board, depth int, prev Info) int {
+	if depth < aspirationDepth || prev.IsMate() {
+		return s.negamax(board, depth, -math.MaxInt32, math.MaxInt32, false)
+	}
+
+	alpha, beta := prev.Score-aspirationWindow, prev.Score+aspirationWindow
+	score := s.negamax(board, depth, alpha, beta, false)
+	if !s.aborted && (score <= alpha || score >= beta) {
+		// Outside the window the score is only a bound
+		score = s.negamax(board, depth, -math.MaxInt32, math.MaxInt32, false)
+	}
+	return score
+}
+
+// expired reports whether the search should stop
+func (s *searcher) expired() bool {
+	if !s.deadline.IsZero() && time.Now().After(s.deadline) {
+		return true
+	}
+	return s.ctx.Err() != nil
+}
+
+// negamax is a principal variation search returning the score for the side
+// to move: the first, best ordered move is searched with the full window and
+// the others with a null window that only proves them worse, searching again
+// the rare move that turns out better
+func (s *searcher) negamax(board *model.Board, depth, alpha, beta int, passed bool) int {
+	s.nodes++
+	if s.maxNodes > 0 && s.nodes > s.maxNodes {
+		s.aborted = true
+	} else if s.nodes%nodeCheckInterval == 0 && s.expired() {
+		s.aborted = true
+	}
+	if s.aborted {
+		return 0
+	}
+
+	moves := board.GetValidMoves()
+	if len(moves) == 0 {
+		if passed {
+			// Neither side can move: the game is over
+			return finalScore(board)
+		}
+		child := board.Clone()
+		child.PassTurn()
+		return -s.negamax(child, depth, -beta, -alpha, true)
+	}
+
+	if depth == 0 {
+		return s.player.evaluate(board, board.CurrentPlayer)
+	}
+
+	hash := board.Hash()
+	alphaOrig, betaOrig := alpha, beta
+	bestMove := int8(-1)
+	s.ttProbes++
+	if entry, ok := s.tt[hash]; ok {
+		s.ttHits++
+		bestMove = entry.move
+		if int(entry.depth) >= depth {
+			score := int(entry.score)
+			switch entry.flag {
+			case ttExact:
+				return score
+			case ttLower:
+				alpha = max(alpha, score)
+			case ttUpper:
+				beta = min(beta, score)
+			}
+			if alpha >= beta {
+				return score
+			}
+		}
+	}
+
+	// Skip the node when a shallow search is confident about its score
+	if score, ok := s.probCut(board, depth, alpha, beta); ok || s.aborted {
+		return score
+	}
+
+	orderMoves(moves, bestMove)
+
+	// A forced move does not use up depth, so forcing lines are seen to their
+	// end instead of stopping at the horizon; passes never use up depth
+	childDepth := depth - 1
+	if len(moves) == 1 && s.extensions < maxExtensions {
+		childDepth = depth
+		s.extensions++
+		defer func() { s.extensions-- }()
+	}
+
+	bestScore := -math.MaxInt32
+	for i, move := range moves {
+		child := board.Clone()
+		child.MakeMove(move.Row, move.Col)
+		var score int
+		if i == 0 {
+			score = -s.negamax(child, childDepth, -beta, -alpha, false)
+		} else {
+			score = -s.negamax(child, childDepth, -alpha-1, -alpha, false)
+			if score > alpha && score < beta && !s.aborted {
+				score = -s.negamax(child, childDepth, -beta, -alpha, false)
+			}
+		}
+		if s.aborted {
+			return 0
+		}
+
+		if score > bestScore {
+			bestScore = score
+			bestMove = squareIndex(move)
+		}
+		alpha = max(alpha, score)
+		if alpha >= beta {
+			break
+		}
+	}
+
+	s.store(hash, depth, bestScore, alphaOrig, betaOrig, bestMove)
+	return bestScore
+}
+
+// store records a search result in the transposition table
+func (s *searcher) store(hash uint64, depth, score, alpha, beta int, move int8) {
+	if len(s.tt) >= maxTTEntries {
+		s.tt = make(map[uint64]ttEntry)
+	}
+
+	flag := ttExact
+	if score <= alpha {
+		flag = ttUpper
+	} else if score >= beta {
+		flag = ttLower
+	}
+
+	s.tt[hash] = ttEntry{depth: int8(depth), flag: flag, move: move, score: int32(score)}
+}
+
+// principalVariation follows best moves through the transposition table
+func (s *searcher) principalVariation(board *model.Board, depth int) []model.Position {
+	var pv []model.Position
+	b := board.Clone()
+
+	for len(pv) < depth {
+		if !b.HasValidMove() {
+			b.PassTurn()
+			if !b.HasValidMove() {
+				break
+			}
+			pv = append(pv, model.PassPosition)
+			continue
+		}
+
+		entry, ok := s.tt[b.Hash()]
+		if !ok || entry.move < 0 {
+			break
+		}
+		move := model.Position{Row: int(entry.move) / 8, Col: int(entry.move) % 8}
+		if !b.MakeMove(move.Row, move.Col) {
+			break
+		}
+		pv = append(pv, move)
+	}
+
+	return pv
+}
+
+// finalScore scores a finished game for the side to move
+func finalScore(board *model.Board) int {
+	diff := board.BlackCnt - board.WhiteCnt
+	if board.CurrentPlayer == model.White {
+		diff = -diff
+	}
+
+	switch {
+	case diff > 0:
+		return winScore + diff
+	case diff < 0:
+		return -winScore + diff
+	default:
+		return 0
+	}
+}
+
+// orderMoves puts the hash move first, then sorts by square weight so
+// corners are tried before squares next to them
+func orderMoves(moves []model.Position, hashMove int8) {
+	key := func(m model.Position) int {
+		if squareIndex(m) == hashMove {
+			return math.MaxInt32
+		}
+		return positionWeights[m.Row][m.Col]
+	}
+
+	// Insertion sort: move lists are short
+	for i := 1; i < len(moves); i++ {
+		for j := i; j > 0 && key(moves[j]) > key(moves[j-1]); j-- {
+			moves[j], moves[j-1] = moves[j-1], moves[j]
+		}
+	}
+}
+
+// squareIndex converts a position to a 0-63 index
+func squareIndex(p model.Position) int8 {
+	return int8(p.Row*8 + p.Col)
+}
+
+// emptySquares counts the empty squares left on the board
+func emptySquares(board *model.Board) int {
+	return board.Size*board.Size - board.BlackCnt - board.WhiteCnt
+}
+
+// AnalyzeStream runs Analyze in the background, delivering every completed
+// iteration on the returned channel. The channel is closed when the search
+// finishes or ctx is cancelled.
+func (p *Player) AnalyzeStream(ctx context.Context, board *model.Board, maxDepth int) <-chan Info {
+	return p.SearchStream(ctx, board, SearchLimits{Depth: maxDepth})
+}
+
+// SearchStream is AnalyzeStream under limits
+func (p *Player) SearchStream(ctx context.Context, board *model.Board, limits SearchLimits) <-chan Info {
+	updates := make(chan Info)
+	board = board.Clone()
+
+	go func() {
+		defer close(updates)
+		p.Search(ctx, board, limits, func(info Info) {
+			select {
+			case updates <- info:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return updates
+}
@@ -0,0 +1,195 @@
+package ai
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// DefaultTelemetry is the telemetry log given to new players, if any
+var DefaultTelemetry *Telemetry
+
+// Move sources recorded in telemetry
+const (
+	SourceBook      = "book"
+	SourceSearch    = "search"
+	SourceHeuristic = "heuristic"
+	SourceRandom    = "random"
+	SourceForced    = "forced"
+)
+
+// SearchRecord describes one move decision made by an AI player
+type SearchRecord struct {
+	Time       time.Time     `json:"time"`
+	Hash       uint64        `json:"hash"`
+	Position   string        `json:"position"`
+	Difficulty string        `json:"difficulty"`
+	Source     string        `json:"source"`
+	Depth      int           `json:"depth"`
+	Nodes      int64         `json:"nodes"`
+	Duration   time.Duration `json:"duration"`
+	Move       string        `json:"move"`
+	Eval       int           `json:"eval"`
+	TTProbes   int64         `json:"tt_probes,omitempty"`
+	TTHits     int64         `json:"tt_hits,omitempty"`
+}
+
+// Info returns the search statistics of the record
+func (r SearchRecord) Info() Info {
+	return Info{Depth: r.Depth, Score: r.Eval, Nodes: r.Nodes, Elapsed: r.Duration, TTProbes: r.TTProbes, TTHits: r.TTHits}
+}
+
+// Query selects telemetry records; zero fields match everything
+type Query struct {
+	Hash     uint64
+	MinDepth int
+	Source   string
+	Since    time.Time
+	Limit    int // Return at most this many of the newest matches
+}
+
+// Telemetry keeps the most recent search records in memory and optionally
+// appends every record to a JSON lines log file
+type Telemetry struct {
+	mu      sync.Mutex
+	records []SearchRecord
+	limit   int
+	file    *os.File
+	out     *bufio.Writer
+}
+
+// NewTelemetry creates an in-memory telemetry log holding up to limit records
+func NewTelemetry(limit int) *Telemetry {
+	return &Telemetry{limit: limit}
+}
+
+// OpenTelemetry creates a telemetry log that also appends to the given file
+func OpenTelemetry(path string, limit int) (*Telemetry, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewTelemetry(limit)
+	t.file = f
+	t.out = bufio.NewWriter(f)
+	return t, nil
+}
+
+// Record stores a search record
+func (t *Telemetry) Record(r SearchRecord) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.records = append(t.records, r)
+	if t.limit > 0 && len(t.records) > t.limit {
+		t.records = t.records[len(t.records)-t.limit:]
+	}
+
+	if t.out != nil {
+		data, err := json.Marshal(r)
+		if err == nil {
+			t.out.Write(data)
+			t.out.WriteByte('\n')
+			t.out.Flush()
+		}
+	}
+}
+
+// Records returns the stored records matching the query, oldest first
+func (t *Telemetry) Records(q Query) []SearchRecord {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var matches []SearchRecord
+	for _, r := range t.records {
+		if q.Hash != 0 && r.Hash != q.Hash {
+			continue
+		}
+		if r.Depth < q.MinDepth {
+			continue
+		}
+		if q.Source != "" && r.Source != q.Source {
+			continue
+		}
+		if !q.Since.IsZero() && r.Time.Before(q.Since) {
+			continue
+		}
+		matches = append(matches, r)
+	}
+
+	if q.Limit > 0 && len(matches) > q.Limit {
+		matches = matches[len(matches)-q.Limit:]
+	}
+	return matches
+}
+
+// Close flushes and closes the log file, if any
+func (t *Telemetry) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+	t.out.Flush()
+	err := t.file.Close()
+	t.file, t.out = nil, nil
+	return err
+}
+
+// ReadTelemetryLog loads every record from a telemetry log file
+func ReadTelemetryLog(path string) ([]SearchRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []SearchRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r SearchRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			continue // Skip lines truncated by a crash
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// record logs a move decision if the player has telemetry enabled
+func (p *Player) record(board *model.Board, source string, row, col int, info Info, started time.Time) {
+	logging.For("ai").Debug("move chosen",
+		"difficulty", p.Difficulty,
+		"source", source,
+		"move", model.FormatMove(row, col),
+		"depth", info.Depth,
+		"nodes", info.Nodes,
+		"eval", info.Score,
+		"duration", time.Since(started))
+
+	if p.Telemetry == nil {
+		return
+	}
+
+	p.Telemetry.Record(SearchRecord{
+		Time:       started,
+		Hash:       board.Hash(),
+		Position:   board.PositionString(),
+		Difficulty: p.Difficulty,
+		Source:     source,
+		Depth:      info.Depth,
+		Nodes:      info.Nodes,
+		Duration:   time.Since(started),
+		Move:       model.FormatMove(row, col),
+		Eval:       info.Score,
+		TTProbes:   info.TTProbes,
+		TTHits:     info.TTHits,
+	})
+}
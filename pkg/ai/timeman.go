@@ -0,0 +1,92 @@
+package ai
+
+import (
+	"context"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Time management
+const (
+	solveEmpties   = 14                    // From here on the search tries to solve the game
+	endgameReserve = 0.25                  // Share of the clock kept back for the solve
+	solveShare     = 0.5                   // Share of the remaining clock the first solve may use
+	maxMoveShare   = 0.3                   // No move before the solve uses more of the clock
+	safetyMargin   = 50 * time.Millisecond // Kept back for overhead outside the search
+)
+
+// phaseWeights share the time before the solve between the phases: the
+// opening is mostly known or shallow, the midgame decides most games
+var phaseWeights = [phaseCount]float64{0.6, 1.5, 1.0}
+
+// Clock is the time a player has left for the rest of the game
+type Clock struct {
+	Remaining time.Duration
+	Increment time.Duration // Added after every move
+}
+
+// Budget returns how long to think about the move on board
+// Time before the solve is spread over the remaining moves, weighted by the
+// phase of the game, with a reserve kept back so the endgame can be solved.
+func (c Clock) Budget(board *model.Board) time.Duration {
+	available := c.Remaining - safetyMargin
+	if available <= 0 {
+		return 0
+	}
+	bonus := c.Increment * 4 / 5
+
+	empties := emptySquares(board)
+	if empties <= solveEmpties {
+		// Later solves reuse little, but the positions shrink fast
+		// The increment only comes after the move, so it must not push the
+		// budget past the clock
+		if budget := time.Duration(float64(available)*solveShare) + bonus; budget < available {
+			return budget
+		}
+		return available
+	}
+
+	ourMoves := (empties - solveEmpties + 1) / 2
+	planned := float64(available) * (1 - endgameReserve) / float64(ourMoves)
+	budget := time.Duration(planned*phaseWeights[model.Phase(board)]) + bonus
+	if limit := time.Duration(float64(available) * maxMoveShare); budget > limit {
+		return limit
+	}
+	return budget
+}
+
+// GetMoveTimed chooses a move within the time the clock allows, playing
+// book and forced moves at once and searching as deep as the budget lets
+// Easy and Medium are fast enough to ignore the clock, and a player with a
+// node limit ignores it to stay deterministic.
+func (p *Player) GetMoveTimed(board *model.Board, clock Clock) (int, int, error) {
+	if p.Difficulty != Hard || p.Nodes > 0 {
+		return p.GetMove(board)
+	}
+	started := time.Now()
+
+	moves := board.GetValidMoves()
+	switch len(moves) {
+	case 0:
+		return -1, -1, nil
+	case 1:
+		p.record(board, SourceForced, moves[0].Row, moves[0].Col, Info{}, started)
+		return moves[0].Row, moves[0].Col, nil
+	}
+	if move, ok := p.bookMove(board, started); ok {
+		return move.Row, move.Col, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clock.Budget(board))
+	defer cancel()
+	info := p.Analyze(ctx, board, 0, nil)
+	if info.Depth == 0 {
+		// Not even one ply finished: fall back to the heuristic
+		row, col, err := p.getMediumMove(board)
+		p.record(board, SourceHeuristic, row, col, Info{Depth: 1}, started)
+		return row, col, err
+	}
+	p.record(board, SourceSearch, info.Best.Row, info.Best.Col, info, started)
+	return info.Best.Row, info.Best.Col, nil
+}
@@ -0,0 +1,27 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// TestBudgetWithinClock checks a large increment never gives a move more
+// time than the clock has left
+func TestBudgetWithinClock(t *testing.T) {
+	opening := model.NewBoard()
+	// Ten empty squares, so the search would try to solve the game
+	solve, err := model.ParsePosition(strings.Repeat("XO", 27) + strings.Repeat("-", 10) + " X")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock := Clock{Remaining: 200 * time.Millisecond, Increment: 10 * time.Second}
+	for name, board := range map[string]*model.Board{"opening": opening, "solve": solve} {
+		if budget := clock.Budget(board); budget > clock.Remaining-safetyMargin {
+			t.Errorf("%s: budget %v with %v left", name, budget, clock.Remaining)
+		}
+	}
+}
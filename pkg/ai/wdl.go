@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"context"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// WDLEmpties is the number of empty squares from which a win/draw/loss
+// solve usually finishes within a few seconds
+const WDLEmpties = 16
+
+// Outcome is a proven game result for the side to move
+type Outcome int
+
+// Game outcomes
+const (
+	Loss Outcome = -1
+	Draw Outcome = 0
+	Win  Outcome = 1
+)
+
+// SolveWDL proves whether the side to move wins, draws or loses with perfect
+// play. It searches to the end of the game like an exact solve but only asks
+// which side wins, not by how much, which cuts far more of the tree. ok is
+// false when ctx was cancelled first.
+func (p *Player) SolveWDL(ctx context.Context, board *model.Board) (outcome Outcome, ok bool) {
+	// Forward pruning would make the proof unsound
+	s := newSearcher(ctx, &Player{Difficulty: p.Difficulty, Weights: p.Weights})
+
+	// Moves use up depth and passes do not, so every line reaches the end
+	score := s.negamax(board, emptySquares(board), -1, 1, false)
+	switch {
+	case s.aborted:
+		return Draw, false
+	case score > 0:
+		return Win, true
+	case score < 0:
+		return Loss, true
+	}
+	return Draw, true
+}
+
+// Describe states the outcome for the side to move, e.g. "Proven win for
+// White"
+func (o Outcome) Describe(side model.Piece) string {
+	switch o {
+	case Win:
+		return "Proven win for " + model.GetPieceName(side)
+	case Loss:
+		return "Proven win for " + model.GetPieceName(side.Opponent())
+	}
+	return "Proven draw"
+}
+
+// Winner returns the winning side, Empty for a draw
+func (o Outcome) Winner(side model.Piece) model.Piece {
+	switch o {
+	case Win:
+		return side
+	case Loss:
+		return side.Opponent()
+	}
+	return model.Empty
+}
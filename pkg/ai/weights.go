@@ -0,0 +1,95 @@
+package ai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ErrBadPhases is returned for weights whose phase list is not one entry per
+// game phase
+var ErrBadPhases = errors.New("weights need no phases or exactly opening, midgame and endgame")
+
+// Weights are the parameters of the static evaluation
+type Weights struct {
+	Table    [8][8]int `json:"table"`    // Score per square held, lost per square the opponent holds
+	Mobility int       `json:"mobility"` // Score per legal move more than the opponent has
+
+	// Phases scale the terms in the opening, midgame and endgame; the terms
+	// count fully in every phase when there are none
+	Phases []PhaseScale `json:"phases,omitempty"`
+}
+
+// PhaseScale scales the evaluation terms in one phase of the game
+type PhaseScale struct {
+	Table    float64 `json:"table"`
+	Mobility float64 `json:"mobility"`
+}
+
+// builtinWeights are the hand-picked weights used unless others are given
+var builtinWeights = Weights{Table: positionWeights}
+
+// BuiltinWeights returns the built-in evaluation weights
+func BuiltinWeights() Weights {
+	return builtinWeights
+}
+
+// LoadWeights reads weights from a JSON file, such as one written by Save
+func LoadWeights(path string) (*Weights, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var w Weights
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := w.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &w, nil
+}
+
+// Validate checks that the weights can be used
+func (w *Weights) Validate() error {
+	if len(w.Phases) != 0 && len(w.Phases) != phaseCount {
+		return ErrBadPhases
+	}
+	return nil
+}
+
+// Fingerprint identifies the weights, so a game record can tell whether it
+// was played with them
+func (w *Weights) Fingerprint() string {
+	data, _ := json.Marshal(w)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// numberList matches an indented JSON array of numbers
+var numberList = regexp.MustCompile(`\[\s*(-?[\d.]+(?:,\s*-?[\d.]+)*)\s*\]`)
+
+// Save writes the weights to a JSON file, one table row per line
+func (w Weights) Save(path string) error {
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = numberList.ReplaceAllFunc(data, func(list []byte) []byte {
+		return []byte("[" + strings.Join(strings.Fields(strings.Trim(string(list), "[]")), " ") + "]")
+	})
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// weights returns the player's evaluation weights
+func (p *Player) weights() *Weights {
+	if p.Weights != nil {
+		return p.Weights
+	}
+	return &builtinWeights
+}
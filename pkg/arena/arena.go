@@ -0,0 +1,142 @@
+// Package arena plays engine configurations against each other in
+// tournaments and rates them, to track engine strength across versions.
+package arena
+
+import (
+	"math/rand"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/book"
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/script"
+	"github.com/amirhossein-jamali/othello/pkg/sim"
+)
+
+// Engine is a named engine configuration taking part in a tournament
+type Engine struct {
+	Name string
+	// New creates a player for one game; players are never shared between
+	// games, so they may keep state
+	New func(piece model.Piece) engine.Player
+}
+
+// AIEngine returns an engine using the built-in AI at the given difficulty,
+// with an optional opening book and evaluation weights (nil for the built-in
+// ones)
+func AIEngine(name, difficulty string, b *book.Book, w *ai.Weights) Engine {
+	return Engine{
+		Name: name,
+		New: func(piece model.Piece) engine.Player {
+			p := ai.NewPlayer(difficulty, piece)
+			p.Book = b
+			p.Weights = w
+			p.Telemetry = nil
+			return p
+		},
+	}
+}
+
+// ScriptEngine returns an engine playing a bot script; scripts that only
+// evaluate positions are searched at the given difficulty
+func ScriptEngine(name string, s *script.Script, difficulty string) Engine {
+	return Engine{
+		Name: name,
+		New: func(piece model.Piece) engine.Player {
+			p, err := s.Player(difficulty, piece)
+			if err != nil {
+				return failedPlayer{err}
+			}
+			return p
+		},
+	}
+}
+
+// failedPlayer is an engine that could not be started; it forfeits
+type failedPlayer struct {
+	err error
+}
+
+// GetMove returns the reason the engine could not start
+func (p failedPlayer) GetMove(*model.Board) (int, int, error) {
+	return -1, -1, p.err
+}
+
+// GameResult is the outcome of one arena game
+type GameResult struct {
+	Black, White           int // Engine indexes
+	BlackDiscs, WhiteDiscs int
+	Winner                 model.Piece // Empty for a draw
+	Transcript             string
+	Forfeit                string // Why the loser forfeited, if it did
+}
+
+// Score returns the points engine i earned in the game
+func (r GameResult) Score(i int) float64 {
+	switch {
+	case r.Winner == model.Empty:
+		return 0.5
+	case r.Winner == model.Black && r.Black == i, r.Winner == model.White && r.White == i:
+		return 1
+	}
+	return 0
+}
+
+// Opponent returns the engine i played against, if it played the game
+func (r GameResult) Opponent(i int) (int, bool) {
+	switch i {
+	case r.Black:
+		return r.White, true
+	case r.White:
+		return r.Black, true
+	}
+	return -1, false
+}
+
+// RandomOpening returns a sequence of plies random legal moves from the
+// starting position, used to vary games between deterministic engines
+func RandomOpening(rng *rand.Rand, plies int) []model.Position {
+	game := model.NewGame()
+	var moves []model.Position
+	for len(moves) < plies && !game.GameOver {
+		legal := game.GetValidMoves()
+		if len(legal) == 0 {
+			game.Pass()
+			moves = append(moves, model.PassPosition)
+			continue
+		}
+		move := legal[rng.Intn(len(legal))]
+		game.MakeMove(move.Row, move.Col)
+		moves = append(moves, move)
+	}
+	return moves
+}
+
+// ErrLostOnTime is the forfeit reason of an engine that ran out of time
+var ErrLostOnTime = sim.ErrLostOnTime
+
+// TimeControl is the time each engine has for a game; a zero Base means
+// the game is untimed
+type TimeControl = sim.TimeControl
+
+// PlayGame plays one game between two engines from the given opening
+// An engine that returns an error or an illegal move forfeits the game, as
+// does one that runs out of time in a timed game.
+func PlayGame(engines []Engine, black, white int, opening []model.Position, tc TimeControl) GameResult {
+	r := sim.RunGame(engines[black].New(model.Black), engines[white].New(model.White), sim.Options{
+		Opening:     opening,
+		TimeControl: tc,
+	})
+	result := GameResult{
+		Black:      black,
+		White:      white,
+		BlackDiscs: r.BlackDiscs,
+		WhiteDiscs: r.WhiteDiscs,
+		Winner:     r.Winner,
+		Transcript: r.Transcript,
+	}
+	if r.Forfeit != nil {
+		result.Forfeit = r.Forfeit.Error()
+	}
+	return result
+}
@@ -0,0 +1,141 @@
+package arena
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// formatElo formats a rating with its error bar, e.g. "+35 ± 20"
+func formatElo(s Standing) string {
+	return fmt.Sprintf("%+.0f ± %.0f", s.Elo, s.Error)
+}
+
+// crossCell formats the points one engine scored against another
+func crossCell(points [][]float64, games [][]int, i, j int) string {
+	if i == j {
+		return "—"
+	}
+	if games[i][j] == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%g/%d", points[i][j], games[i][j])
+}
+
+// WriteMarkdown writes the standings and cross table as Markdown
+func (r *Results) WriteMarkdown(w io.Writer, title string) error {
+	var sb strings.Builder
+	standings := r.Standings()
+	points, games := r.CrossTable()
+
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	fmt.Fprintf(&sb, "%d games between %d engines.\n\n", len(r.Games), len(r.Engines))
+
+	sb.WriteString("| Rank | Engine | Elo | Games | W | D | L | Score |\n")
+	sb.WriteString("|---:|---|---:|---:|---:|---:|---:|---:|\n")
+	for rank, s := range standings {
+		fmt.Fprintf(&sb, "| %d | %s | %s | %d | %d | %d | %d | %.1f%% |\n",
+			rank+1, s.Name, formatElo(s), s.Games, s.Wins, s.Draws, s.Losses, 100*s.Score())
+	}
+
+	sb.WriteString("\n## Cross Table\n\n|  |")
+	for _, s := range standings {
+		fmt.Fprintf(&sb, " %s |", s.Name)
+	}
+	sb.WriteString("\n|---|" + strings.Repeat("---:|", len(standings)) + "\n")
+	for _, a := range standings {
+		fmt.Fprintf(&sb, "| %s |", a.Name)
+		for _, b := range standings {
+			fmt.Fprintf(&sb, " %s |", crossCell(points, games, a.Engine, b.Engine))
+		}
+		sb.WriteString("\n")
+	}
+
+	if forfeits := r.forfeits(); len(forfeits) > 0 {
+		sb.WriteString("\n## Forfeits\n\n")
+		for _, f := range forfeits {
+			fmt.Fprintf(&sb, "- %s\n", f)
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// htmlReport is the template of the HTML report
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th:nth-child(2), td:nth-child(2) { text-align: left; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Games}} games between {{len .Standings}} engines.</p>
+<table>
+<tr><th>Rank</th><th>Engine</th><th>Elo</th><th>Games</th><th>W</th><th>D</th><th>L</th><th>Score</th></tr>
+{{range .Standings}}<tr><td>{{.Rank}}</td><td>{{.Name}}</td><td>{{.Elo}}</td><td>{{.Games}}</td><td>{{.Wins}}</td><td>{{.Draws}}</td><td>{{.Losses}}</td><td>{{.Score}}</td></tr>
+{{end}}</table>
+<h2>Cross Table</h2>
+<table>
+<tr><th></th>{{range .Standings}}<th>{{.Name}}</th>{{end}}</tr>
+{{range .Cross}}<tr>{{range $i, $cell := .}}{{if eq $i 0}}<th>{{$cell}}</th>{{else}}<td>{{$cell}}</td>{{end}}{{end}}</tr>
+{{end}}</table>
+{{if .Forfeits}}<h2>Forfeits</h2>
+<ul>{{range .Forfeits}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the standings and cross table as a standalone HTML page
+func (r *Results) WriteHTML(w io.Writer, title string) error {
+	type row struct {
+		Rank                int
+		Name, Elo, Score    string
+		Games               int
+		Wins, Draws, Losses int
+	}
+
+	standings := r.Standings()
+	points, games := r.CrossTable()
+
+	data := struct {
+		Title     string
+		Games     int
+		Standings []row
+		Cross     [][]string
+		Forfeits  []string
+	}{Title: title, Games: len(r.Games), Forfeits: r.forfeits()}
+
+	for rank, s := range standings {
+		data.Standings = append(data.Standings, row{
+			Rank: rank + 1, Name: s.Name, Elo: formatElo(s), Score: fmt.Sprintf("%.1f%%", 100*s.Score()),
+			Games: s.Games, Wins: s.Wins, Draws: s.Draws, Losses: s.Losses,
+		})
+		cells := []string{s.Name}
+		for _, b := range standings {
+			cells = append(cells, crossCell(points, games, s.Engine, b.Engine))
+		}
+		data.Cross = append(data.Cross, cells)
+	}
+
+	return htmlReport.Execute(w, data)
+}
+
+// forfeits describes every forfeited game
+func (r *Results) forfeits() []string {
+	var lines []string
+	for _, g := range r.Games {
+		if g.Forfeit != "" {
+			lines = append(lines, fmt.Sprintf("%s vs %s: %s", r.Engines[g.Black], r.Engines[g.White], g.Forfeit))
+		}
+	}
+	return lines
+}
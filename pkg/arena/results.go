@@ -0,0 +1,167 @@
+package arena
+
+import (
+	"math"
+	"sort"
+)
+
+// Results holds the games of a tournament
+type Results struct {
+	Engines []string
+	Games   []GameResult
+}
+
+// Standing is one engine's line in the final table
+type Standing struct {
+	Engine              int
+	Name                string
+	Games               int
+	Wins, Draws, Losses int
+	Points              float64
+	Elo                 float64 // Relative to the field's average
+	Error               float64 // 95% confidence half-width of Elo
+	Forfeits            int
+}
+
+// Score returns the fraction of the available points the engine scored
+func (s Standing) Score() float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	return s.Points / float64(s.Games)
+}
+
+// Standings returns the table ordered by Elo, best first
+func (r *Results) Standings() []Standing {
+	standings := make([]Standing, len(r.Engines))
+	for i, name := range r.Engines {
+		standings[i] = Standing{Engine: i, Name: name}
+	}
+
+	for _, g := range r.Games {
+		for _, i := range []int{g.Black, g.White} {
+			s := &standings[i]
+			s.Games++
+			score := g.Score(i)
+			s.Points += score
+			switch score {
+			case 1:
+				s.Wins++
+			case 0:
+				s.Losses++
+				if g.Forfeit != "" {
+					s.Forfeits++
+				}
+			default:
+				s.Draws++
+			}
+		}
+	}
+
+	elo, errs := r.Ratings()
+	for i := range standings {
+		standings[i].Elo, standings[i].Error = elo[i], errs[i]
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Elo != standings[j].Elo {
+			return standings[i].Elo > standings[j].Elo
+		}
+		return standings[i].Points > standings[j].Points
+	})
+	return standings
+}
+
+// CrossTable returns the points engine i scored against engine j
+func (r *Results) CrossTable() (points [][]float64, games [][]int) {
+	n := len(r.Engines)
+	points = make([][]float64, n)
+	games = make([][]int, n)
+	for i := range points {
+		points[i] = make([]float64, n)
+		games[i] = make([]int, n)
+	}
+	for _, g := range r.Games {
+		points[g.Black][g.White] += g.Score(g.Black)
+		points[g.White][g.Black] += g.Score(g.White)
+		games[g.Black][g.White]++
+		games[g.White][g.Black]++
+	}
+	return points, games
+}
+
+// maxElo bounds ratings as a safeguard against divergence
+const maxElo = 1500
+
+// priorDraws is the number of virtual draws each engine plays against a
+// zero-rated opponent; without them an engine that won or lost every game
+// would have an infinite rating
+const priorDraws = 1
+
+// Ratings computes maximum likelihood Elo ratings from all games, centered
+// on the field's average, together with 95% confidence half-widths derived
+// from the Fisher information of each rating. Draws count as half a win.
+func (r *Results) Ratings() (elo, errs []float64) {
+	n := len(r.Engines)
+	elo = make([]float64, n)
+	errs = make([]float64, n)
+
+	// Expected score of a rating against another under the logistic model
+	expected := func(a, b float64) float64 {
+		return 1 / (1 + math.Pow(10, (b-a)/400))
+	}
+	scale := math.Ln10 / 400
+
+	// Newton's method, one rating at a time
+	for iter := 0; iter < 500; iter++ {
+		largest := 0.0
+		for i := 0; i < n; i++ {
+			// Start from the virtual draws
+			p := expected(elo[i], 0)
+			score := 0.5 * priorDraws
+			exp := p * priorDraws
+			info := p * (1 - p) * scale * priorDraws
+			for _, g := range r.Games {
+				opponent, ok := g.Opponent(i)
+				if !ok {
+					continue
+				}
+				p := expected(elo[i], elo[opponent])
+				score += g.Score(i)
+				exp += p
+				info += p * (1 - p) * scale
+			}
+			delta := math.Max(-100, math.Min(100, (score-exp)/info))
+			elo[i] = math.Max(-maxElo, math.Min(maxElo, elo[i]+delta))
+			largest = math.Max(largest, math.Abs(delta))
+		}
+		if largest < 0.01 {
+			break
+		}
+	}
+
+	// Center on the average so the numbers are comparable between runs
+	mean := 0.0
+	for _, e := range elo {
+		mean += e
+	}
+	mean /= float64(n)
+	for i := range elo {
+		elo[i] -= mean
+	}
+
+	for i := 0; i < n; i++ {
+		p := expected(elo[i], 0)
+		info := p * (1 - p) * scale * scale * priorDraws
+		for _, g := range r.Games {
+			opponent, ok := g.Opponent(i)
+			if !ok {
+				continue
+			}
+			p := expected(elo[i], elo[opponent])
+			info += p * (1 - p) * scale * scale
+		}
+		errs[i] = 1.96 / math.Sqrt(info)
+	}
+	return elo, errs
+}
@@ -0,0 +1,223 @@
+package arena
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Tournament formats
+const (
+	RoundRobin = "round-robin"
+	Swiss      = "swiss"
+)
+
+// Tournament configures a set of games between engines
+// Every pairing plays game pairs: the same random opening twice with colors
+// swapped, which cancels out most of the luck of the opening.
+type Tournament struct {
+	Engines      []Engine
+	Format       string // RoundRobin or Swiss
+	Rounds       int    // Swiss rounds; round-robin plays every pairing once per round
+	Pairs        int    // Game pairs per pairing and round
+	OpeningPlies int    // Random moves played before the engines take over
+	TimeControl  TimeControl
+	Concurrency  int // Games played at once, at most one per CPU when timed
+	Seed         int64
+
+	// Progress, if set, is called after every finished game
+	Progress func(done, total int, result GameResult)
+}
+
+// ErrTooFewEngines is returned for tournaments with fewer than two engines
+var ErrTooFewEngines = errors.New("a tournament needs at least two engines")
+
+// pairing is one match between two engines within a round
+type pairing struct {
+	a, b int
+}
+
+// Run plays the tournament and returns its results
+// Cancelling ctx stops scheduling new games; the games played so far are
+// still returned.
+func (t *Tournament) Run(ctx context.Context) (*Results, error) {
+	if len(t.Engines) < 2 {
+		return nil, ErrTooFewEngines
+	}
+
+	rng := rand.New(rand.NewSource(t.Seed))
+	results := &Results{Engines: engineNames(t.Engines)}
+
+	rounds := max(t.Rounds, 1)
+	byes := make([]int, len(t.Engines))
+	for round := 0; round < rounds && ctx.Err() == nil; round++ {
+		var pairings []pairing
+		if t.Format == Swiss {
+			pairings = swissPairings(results, byes, rng)
+		} else {
+			pairings = roundRobinPairings(len(t.Engines))
+		}
+		t.playRound(ctx, results, pairings, rng, rounds)
+	}
+	return results, nil
+}
+
+// playRound plays every pairing of a round, using the same openings for
+// all pairings so the round is fair
+func (t *Tournament) playRound(ctx context.Context, results *Results, pairings []pairing, rng *rand.Rand, rounds int) {
+	type job struct {
+		black, white int
+		opening      []model.Position
+	}
+
+	pairs := max(t.Pairs, 1)
+	var jobs []job
+	for i := 0; i < pairs; i++ {
+		opening := RandomOpening(rng, t.OpeningPlies)
+		for _, p := range pairings {
+			jobs = append(jobs, job{p.a, p.b, opening}, job{p.b, p.a, opening})
+		}
+	}
+
+	total := len(jobs) * rounds
+	played := make([]GameResult, len(jobs))
+	done := make([]bool, len(jobs))
+
+	workers := max(t.Concurrency, 1)
+	if t.TimeControl.Base > 0 {
+		// Clocks measure wall time, so games sharing a CPU would be charged
+		// for each other's thinking
+		workers = min(workers, runtime.GOMAXPROCS(0))
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				r := PlayGame(t.Engines, jobs[i].black, jobs[i].white, jobs[i].opening, t.TimeControl)
+
+				mu.Lock()
+				played[i], done[i] = r, true
+				if t.Progress != nil {
+					t.Progress(len(results.Games)+countTrue(done), total, r)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+schedule:
+	for i := range jobs {
+		select {
+		case queue <- i:
+		case <-ctx.Done():
+			break schedule
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	// Keep games in schedule order so reports are reproducible
+	for i, r := range played {
+		if done[i] {
+			results.Games = append(results.Games, r)
+		}
+	}
+}
+
+// roundRobinPairings pairs every engine with every other
+func roundRobinPairings(n int) []pairing {
+	var pairings []pairing
+	for a := 0; a < n; a++ {
+		for b := a + 1; b < n; b++ {
+			pairings = append(pairings, pairing{a, b})
+		}
+	}
+	return pairings
+}
+
+// swissPairings pairs engines with similar scores, avoiding rematches where
+// possible. With an odd number of engines the lowest ranked one among those
+// with the fewest byes sits out, and its bye is counted.
+func swissPairings(results *Results, byes []int, rng *rand.Rand) []pairing {
+	n := len(results.Engines)
+	points := make([]float64, n)
+	met := make(map[pairing]bool)
+	for _, g := range results.Games {
+		points[g.Black] += g.Score(g.Black)
+		points[g.White] += g.Score(g.White)
+		met[pairing{min(g.Black, g.White), max(g.Black, g.White)}] = true
+	}
+
+	// Shuffle first so ties are broken randomly
+	order := rng.Perm(n)
+	sort.SliceStable(order, func(i, j int) bool { return points[order[i]] > points[order[j]] })
+
+	used := make([]bool, n)
+	if n%2 == 1 {
+		bye := -1
+		for i := n - 1; i >= 0; i-- {
+			if bye < 0 || byes[order[i]] < byes[bye] {
+				bye = order[i]
+			}
+		}
+		used[bye] = true
+		byes[bye]++
+	}
+
+	var pairings []pairing
+	for i, a := range order {
+		if used[a] {
+			continue
+		}
+		// The closest unpaired engine not met yet, or else the closest one
+		partner := -1
+		for _, b := range order[i+1:] {
+			if used[b] {
+				continue
+			}
+			if partner < 0 {
+				partner = b
+			}
+			if !met[pairing{min(a, b), max(a, b)}] {
+				partner = b
+				break
+			}
+		}
+		if partner < 0 {
+			break
+		}
+		used[a], used[partner] = true, true
+		pairings = append(pairings, pairing{a, partner})
+	}
+	return pairings
+}
+
+// engineNames lists the engines' names
+func engineNames(engines []Engine) []string {
+	names := make([]string, len(engines))
+	for i, e := range engines {
+		names[i] = e.Name
+	}
+	return names
+}
+
+// countTrue counts the set flags
+func countTrue(flags []bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,306 @@
+// Package backup saves everything the game keeps for a user - settings,
+// skins, the game library with its analysis and statistics, the opening book
+// and evaluation weights named in the settings, and the analysis cache - as
+// one zip archive, and restores it, on the same machine or another one.
+//
+// The archive holds a manifest and the files under fixed names:
+//
+//	manifest.json
+//	config/...          the settings directory
+//	library/...         the game library, when kept elsewhere
+//	cache/analysis-cache.jsonl
+//	books/...           book and weights files kept outside the settings directory
+//
+// Book and weights files are restored to a books directory in the settings
+// directory, and the restored settings are pointed at them.
+package backup
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+)
+
+// Version is the archive format written by Write
+const Version = 1
+
+// manifestName is the archive entry describing the backup
+const manifestName = "manifest.json"
+
+// ErrNotBackup is returned for archives without a backup manifest or of a
+// newer format
+var ErrNotBackup = errors.New("not an Othello backup")
+
+// Locations are where a user's data is kept on this machine
+type Locations struct {
+	ConfigDir  string // Settings file and skins
+	LibraryDir string
+	CachePath  string // Analysis cache file
+}
+
+// DefaultLocations returns the standard locations
+func DefaultLocations() Locations {
+	return Locations{
+		ConfigDir:  filepath.Dir(config.DefaultPath()),
+		LibraryDir: library.DefaultDir(),
+		CachePath:  ai.DefaultCachePath(),
+	}
+}
+
+// manifest describes a backup
+type manifest struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+
+	// Archive names of the book and weights files the settings use
+	Book    string `json:"book,omitempty"`
+	Weights string `json:"weights,omitempty"`
+}
+
+// Write archives the data found at the locations, returning the number of
+// files written. Missing locations are skipped.
+func Write(w io.Writer, loc Locations) (int, error) {
+	zw := zip.NewWriter(w)
+	files := 0
+	m := manifest{Version: Version, Created: time.Now().UTC()}
+
+	add := func(name, src string) error {
+		f, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name, header.Method = name, zip.Deflate
+		dst, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, f); err != nil {
+			return fmt.Errorf("%s: %w", src, err)
+		}
+		files++
+		return nil
+	}
+
+	// The settings directory, which usually holds the library too
+	err := walkFiles(loc.ConfigDir, func(rel, src string) error {
+		return add(path.Join("config", rel), src)
+	})
+	if err != nil {
+		return files, err
+	}
+	if !within(loc.ConfigDir, loc.LibraryDir) {
+		err := walkFiles(loc.LibraryDir, func(rel, src string) error {
+			return add(path.Join("library", rel), src)
+		})
+		if err != nil {
+			return files, err
+		}
+	}
+	if isFile(loc.CachePath) {
+		if err := add("cache/analysis-cache.jsonl", loc.CachePath); err != nil {
+			return files, err
+		}
+	}
+
+	// Books and weights the settings name outside the settings directory
+	if cfg, err := config.Load(filepath.Join(loc.ConfigDir, "config.json")); err == nil {
+		for _, f := range []struct {
+			path string
+			name *string
+		}{{cfg.AI.Book, &m.Book}, {cfg.AI.Weights, &m.Weights}} {
+			if f.path == "" || !isFile(f.path) || within(loc.ConfigDir, f.path) {
+				continue
+			}
+			*f.name = "books/" + filepath.Base(f.path)
+			if err := add(*f.name, f.path); err != nil {
+				return files, err
+			}
+		}
+	}
+
+	dst, err := zw.Create(manifestName)
+	if err != nil {
+		return files, err
+	}
+	if err := json.NewEncoder(dst).Encode(m); err != nil {
+		return files, err
+	}
+	return files, zw.Close()
+}
+
+// Restore unpacks a backup into the locations, replacing files of the same
+// name and keeping any others, and returns the number of files restored
+func Restore(r io.ReaderAt, size int64, loc Locations) (int, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrNotBackup, err)
+	}
+
+	var m manifest
+	for _, f := range zr.File {
+		if f.Name != manifestName {
+			continue
+		}
+		if err := readJSON(f, &m); err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrNotBackup, err)
+		}
+	}
+	if m.Version == 0 || m.Version > Version {
+		return 0, fmt.Errorf("%w: format %d", ErrNotBackup, m.Version)
+	}
+
+	books := filepath.Join(loc.ConfigDir, "books")
+	files := 0
+	for _, f := range zr.File {
+		if f.Name == manifestName || strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		if !fs.ValidPath(f.Name) {
+			return files, fmt.Errorf("%w: unsafe file name %q", ErrNotBackup, f.Name)
+		}
+		root, rel, _ := strings.Cut(f.Name, "/")
+		var dst string
+		switch root {
+		case "config":
+			dst = filepath.Join(loc.ConfigDir, filepath.FromSlash(rel))
+		case "library":
+			dst = filepath.Join(loc.LibraryDir, filepath.FromSlash(rel))
+		case "cache":
+			dst = loc.CachePath
+		case "books":
+			dst = filepath.Join(books, filepath.FromSlash(rel))
+		default:
+			continue
+		}
+		if err := extract(f, dst); err != nil {
+			return files, err
+		}
+		files++
+	}
+
+	// Point the restored settings at the restored books
+	if m.Book != "" || m.Weights != "" {
+		settings := filepath.Join(loc.ConfigDir, "config.json")
+		cfg, err := config.Load(settings)
+		if err != nil {
+			return files, err
+		}
+		if m.Book != "" {
+			cfg.AI.Book = filepath.Join(books, path.Base(m.Book))
+		}
+		if m.Weights != "" {
+			cfg.AI.Weights = filepath.Join(books, path.Base(m.Weights))
+		}
+		if err := config.Save(settings, cfg); err != nil {
+			return files, err
+		}
+	}
+	return files, nil
+}
+
+// extract writes an archive entry to dst through a temporary file, so an
+// interrupted restore never leaves a half-written file
+func extract(f *zip.File, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	src, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := dst + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+// readJSON decodes an archive entry
+func readJSON(f *zip.File, v any) error {
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return json.NewDecoder(r).Decode(v)
+}
+
+// walkFiles calls fn for every regular file under dir with its slash
+// separated path relative to dir; a missing dir has no files
+func walkFiles(dir string, fn func(rel, src string) error) error {
+	if dir == "" {
+		return nil
+	}
+	err := filepath.WalkDir(dir, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || strings.HasSuffix(src, ".tmp") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, src)
+		if err != nil {
+			return err
+		}
+		return fn(filepath.ToSlash(rel), src)
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// within reports whether path is dir or inside it
+func within(dir, path string) bool {
+	if dir == "" || path == "" {
+		return false
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// isFile reports whether path names an existing regular file
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
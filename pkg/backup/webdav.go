@@ -0,0 +1,67 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebDAV keeps a backup as one file on a WebDAV server, such as Nextcloud
+// or ownCloud, so it can be pushed from one machine and pulled on another
+type WebDAV struct {
+	URL      string // Of the backup file, e.g. https://cloud.example.com/remote.php/dav/files/alice/othello.zip
+	User     string // Empty for servers without authentication
+	Password string
+	HTTP     *http.Client
+}
+
+// NewWebDAV creates a client for the backup file at url
+func NewWebDAV(url, user, password string) *WebDAV {
+	return &WebDAV{URL: url, User: user, Password: password, HTTP: &http.Client{Timeout: 5 * time.Minute}}
+}
+
+// Push uploads a backup, replacing the one on the server
+func (d *WebDAV) Push(ctx context.Context, data []byte) error {
+	resp, err := d.do(ctx, http.MethodPut, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Pull downloads the backup on the server
+func (d *WebDAV) Pull(ctx context.Context) ([]byte, error) {
+	resp, err := d.do(ctx, http.MethodGet, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// do sends a request, turning error statuses into errors
+func (d *WebDAV) do(ctx context.Context, method string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, d.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	if d.User != "" {
+		req.SetBasicAuth(d.User, d.Password)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/zip")
+	}
+	resp, err := d.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s %s: %s", method, d.URL, resp.Status)
+	}
+	return resp, nil
+}
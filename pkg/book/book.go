@@ -0,0 +1,283 @@
+package book
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// magic starts every book file
+const magic = "OTHBOOK"
+
+// Version is the book file format written by WriteTo
+const Version = 1
+
+// ErrNotBook is returned for files that are not opening books
+var ErrNotBook = errors.New("not an opening book file")
+
+// Stats aggregates the results of all games that reached a position
+// Results are kept from Black's point of view
+type Stats struct {
+	Games     uint32
+	BlackWins uint32
+	WhiteWins uint32
+	DiscSum   int32 // Sum of final black minus white disc counts
+}
+
+// Draws returns the number of drawn games
+func (s Stats) Draws() uint32 {
+	return s.Games - s.BlackWins - s.WhiteWins
+}
+
+// Score returns the expected result for the given side between 0 and 1,
+// counting a draw as half a win
+func (s Stats) Score(side model.Piece) float64 {
+	if s.Games == 0 {
+		return 0.5
+	}
+	wins := s.BlackWins
+	if side == model.White {
+		wins = s.WhiteWins
+	}
+	return (float64(wins) + float64(s.Draws())/2) / float64(s.Games)
+}
+
+// AverageDiscs returns the mean final disc differential for the given side
+func (s Stats) AverageDiscs(side model.Piece) float64 {
+	if s.Games == 0 {
+		return 0
+	}
+	avg := float64(s.DiscSum) / float64(s.Games)
+	if side == model.White {
+		return -avg
+	}
+	return avg
+}
+
+// Candidate is a legal move together with the statistics of its position
+type Candidate struct {
+	Move  model.Position
+	Stats Stats
+}
+
+// Book maps canonical position hashes to game statistics
+// Positions are canonicalized under the board symmetries, so transpositions
+// and rotated or mirrored lines share a single entry.
+type Book struct {
+	entries map[uint64]Stats
+}
+
+// New creates an empty book
+func New() *Book {
+	return &Book{entries: make(map[uint64]Stats)}
+}
+
+// Len returns the number of positions in the book
+func (b *Book) Len() int {
+	return len(b.entries)
+}
+
+// Get returns the statistics for the position, if present
+func (b *Book) Get(board *model.Board) (Stats, bool) {
+	s, ok := b.entries[model.CanonicalHash(board)]
+	return s, ok
+}
+
+// AddGame records every position reached in the first maxPly moves of a game
+// result is the final black minus white disc count. The whole game is
+// checked first, so a game with an illegal move adds nothing.
+func (b *Book) AddGame(moves []model.Position, result int, maxPly int) error {
+	game := model.NewGame()
+	var hashes []uint64
+	for i, move := range moves {
+		if _, err := game.ReplayMoves([]model.Position{move}); err != nil {
+			return fmt.Errorf("move %d: %w", i+1, err)
+		}
+		if maxPly <= 0 || i < maxPly {
+			hashes = append(hashes, model.CanonicalHash(game.Board))
+		}
+	}
+	for _, hash := range hashes {
+		b.add(hash, result)
+	}
+	return nil
+}
+
+// add accumulates one game result for a position hash
+func (b *Book) add(hash uint64, result int) {
+	s := b.entries[hash]
+	s.Games++
+	switch {
+	case result > 0:
+		s.BlackWins++
+	case result < 0:
+		s.WhiteWins++
+	}
+	s.DiscSum += int32(result)
+	b.entries[hash] = s
+}
+
+// Prune removes positions reached by fewer than minGames games
+func (b *Book) Prune(minGames uint32) {
+	for hash, s := range b.entries {
+		if s.Games < minGames {
+			delete(b.entries, hash)
+		}
+	}
+}
+
+// Candidates returns the legal moves whose resulting position is in the book,
+// best first for the side to move
+func (b *Book) Candidates(board *model.Board) []Candidate {
+	side := board.CurrentPlayer
+	var candidates []Candidate
+
+	for _, move := range board.GetValidMoves() {
+		child := board.Clone()
+		child.MakeMove(move.Row, move.Col)
+		if s, ok := b.entries[model.CanonicalHash(child)]; ok {
+			candidates = append(candidates, Candidate{Move: move, Stats: s})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		si, sj := candidates[i].Stats.Score(side), candidates[j].Stats.Score(side)
+		if si != sj {
+			return si > sj
+		}
+		return candidates[i].Stats.Games > candidates[j].Stats.Games
+	})
+	return candidates
+}
+
+// BestMove returns the highest scoring book move played in at least minGames
+// games, or false when the position is out of book
+func (b *Book) BestMove(board *model.Board, minGames uint32) (model.Position, bool) {
+	for _, c := range b.Candidates(board) {
+		if c.Stats.Games >= minGames {
+			return c.Move, true
+		}
+	}
+	return model.Position{}, false
+}
+
+// Fingerprint identifies the book's contents, so a game record can tell
+// whether it was played with it
+func (b *Book) Fingerprint() string {
+	h := sha256.New()
+	b.WriteTo(h)
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// WriteTo encodes the book in its compact binary form
+func (b *Book) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	var written int64
+
+	header := make([]byte, len(magic)+1+4)
+	copy(header, magic)
+	header[len(magic)] = Version
+	binary.LittleEndian.PutUint32(header[len(magic)+1:], uint32(len(b.entries)))
+	n, err := bw.Write(header)
+	written += int64(n)
+	if err != nil {
+		return written, err
+	}
+
+	// Sort by hash so identical books produce identical files
+	hashes := make([]uint64, 0, len(b.entries))
+	for hash := range b.entries {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	var rec [24]byte
+	for _, hash := range hashes {
+		s := b.entries[hash]
+		binary.LittleEndian.PutUint64(rec[0:8], hash)
+		binary.LittleEndian.PutUint32(rec[8:12], s.Games)
+		binary.LittleEndian.PutUint32(rec[12:16], s.BlackWins)
+		binary.LittleEndian.PutUint32(rec[16:20], s.WhiteWins)
+		binary.LittleEndian.PutUint32(rec[20:24], uint32(s.DiscSum))
+		n, err = bw.Write(rec[:])
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, bw.Flush()
+}
+
+// Read decodes a book written by WriteTo
+func Read(r io.Reader) (*Book, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(magic)+1+4)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("reading book header: %w", err)
+	}
+	if string(header[:len(magic)]) != magic {
+		return nil, ErrNotBook
+	}
+	if header[len(magic)] != Version {
+		return nil, fmt.Errorf("unsupported book version %d", header[len(magic)])
+	}
+	count := binary.LittleEndian.Uint32(header[len(magic)+1:])
+
+	b := New()
+	var rec [24]byte
+	for i := uint32(0); i < count; i++ {
+		if _, err := io.ReadFull(br, rec[:]); err != nil {
+			return nil, fmt.Errorf("reading book entry %d: %w", i+1, err)
+		}
+		b.entries[binary.LittleEndian.Uint64(rec[0:8])] = Stats{
+			Games:     binary.LittleEndian.Uint32(rec[8:12]),
+			BlackWins: binary.LittleEndian.Uint32(rec[12:16]),
+			WhiteWins: binary.LittleEndian.Uint32(rec[16:20]),
+			DiscSum:   int32(binary.LittleEndian.Uint32(rec[20:24])),
+		}
+	}
+
+	return b, nil
+}
+
+// ReadVersion reads the format version of a book file from its header
+func ReadVersion(r io.Reader) (int, error) {
+	header := make([]byte, len(magic)+1)
+	if _, err := io.ReadFull(r, header); err != nil || string(header[:len(magic)]) != magic {
+		return 0, ErrNotBook
+	}
+	return int(header[len(magic)]), nil
+}
+
+// Load reads a book from a file
+func Load(path string) (*Book, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Save writes the book to a file
+func (b *Book) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := b.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
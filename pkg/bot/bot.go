@@ -0,0 +1,259 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/render"
+)
+
+// DefaultPrefix starts every bot command
+const DefaultPrefix = "!othello"
+
+// Reply is the bot's answer to a chat message
+type Reply struct {
+	Text  string // Markdown text, board diagrams in code blocks
+	Image []byte // Optional PNG of the board
+}
+
+// session is a single-player game running in one channel
+type session struct {
+	mu    sync.Mutex // Held while the game is played or read, AI searches included
+	game  *engine.Game
+	human engine.Piece
+	ai    *engine.AI
+}
+
+// Bot plays Othello in chat channels, one game per channel
+// It is transport agnostic: adapters for Slack, Discord and similar services
+// feed messages to Handle and post the replies.
+type Bot struct {
+	Prefix string
+	Style  render.TextStyle
+	Images bool // Attach a PNG of the board to replies
+
+	mu       sync.Mutex // Guards sessions only, so one channel's search does not stall the others
+	sessions map[string]*session
+}
+
+// New creates a bot using the default prefix and emoji boards
+func New() *Bot {
+	return &Bot{
+		Prefix:   DefaultPrefix,
+		Style:    render.Emoji,
+		sessions: make(map[string]*session),
+	}
+}
+
+// Handle processes a chat message and returns the reply, if the message was
+// a bot command
+func (b *Bot) Handle(channel, user, text string) (Reply, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], b.Prefix) {
+		return Reply{}, false
+	}
+	return b.Command(channel, user, fields[1:]), true
+}
+
+// Command runs a command given without the prefix, as slash commands deliver it
+func (b *Bot) Command(channel, user string, args []string) Reply {
+	if len(args) == 0 {
+		return Reply{Text: b.help()}
+	}
+
+	command := strings.ToLower(args[0])
+	switch command {
+	case "start", "new":
+		return b.start(channel, args[1:])
+	case "board", "show":
+		return b.withSession(channel, func(s *session) Reply { return b.boardReply(s, "") })
+	case "move", "play":
+		if len(args) < 2 {
+			return Reply{Text: "Usage: `" + b.Prefix + " move d3`"}
+		}
+		return b.withSession(channel, func(s *session) Reply { return b.move(s, args[1]) })
+	case "pass":
+		return b.withSession(channel, func(s *session) Reply { return b.move(s, "pass") })
+	case "resign", "stop":
+		b.mu.Lock()
+		delete(b.sessions, channel)
+		b.mu.Unlock()
+		return Reply{Text: fmt.Sprintf("%s resigned. Start a new game with `%s start`.", user, b.Prefix)}
+	case "help":
+		return Reply{Text: b.help()}
+	default:
+		// Allow "!othello d3" as a shortcut for moves
+		return b.withSession(channel, func(s *session) Reply { return b.move(s, args[0]) })
+	}
+}
+
+// withSession runs fn for the channel's game, or explains how to start one
+func (b *Bot) withSession(channel string, fn func(s *session) Reply) Reply {
+	s, ok := b.session(channel)
+	if !ok {
+		return Reply{Text: fmt.Sprintf("No game in this channel. Start one with `%s start [easy|medium|hard] [black|white]`.", b.Prefix)}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s)
+}
+
+// session returns the channel's game, if any
+func (b *Bot) session(channel string) (*session, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.sessions[channel]
+	return s, ok
+}
+
+// start begins a new game against the AI
+func (b *Bot) start(channel string, args []string) Reply {
+	difficulty := engine.Medium
+	human := engine.Black
+
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case engine.Easy, engine.Medium, engine.Hard:
+			difficulty = strings.ToLower(arg)
+		case "black", "b":
+			human = engine.Black
+		case "white", "w":
+			human = engine.White
+		default:
+			return Reply{Text: fmt.Sprintf("Unknown option %q. Usage: `%s start [easy|medium|hard] [black|white]`", arg, b.Prefix)}
+		}
+	}
+
+	s := &session{
+		game:  engine.NewGame(),
+		human: human,
+		ai:    engine.NewAI(difficulty, human.Opponent()),
+	}
+	s.ai.WriteMetadata(s.game)
+	// Commands arriving while the AI opens wait for it
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b.mu.Lock()
+	b.sessions[channel] = s
+	b.mu.Unlock()
+
+	intro := fmt.Sprintf("New game against the %s AI. You play %s.", difficulty, model.GetPieceName(human))
+	return b.boardReply(s, intro+"\n"+b.playAI(s))
+}
+
+// move applies the human's move and lets the AI answer
+func (b *Bot) move(s *session, notation string) Reply {
+	if s.game.GameOver {
+		return b.boardReply(s, "The game is over.")
+	}
+	if s.game.GetCurrentPlayer() != s.human {
+		return Reply{Text: "Please wait for the AI to move."}
+	}
+
+	row, col, err := engine.ParseMove(notation)
+	if err != nil {
+		return Reply{Text: fmt.Sprintf("I don't understand %q: %v", notation, err)}
+	}
+	if row < 0 {
+		err = s.game.Pass()
+	} else {
+		err = s.game.MakeMove(row, col)
+	}
+	if err != nil {
+		return Reply{Text: fmt.Sprintf("Illegal move: %v", err)}
+	}
+
+	return b.boardReply(s, fmt.Sprintf("You played %s.\n%s", model.FormatMove(row, col), b.playAI(s)))
+}
+
+// playAI lets the AI move until it is the human's turn again, describing
+// what happened. Forced passes on either side are handled automatically.
+func (b *Bot) playAI(s *session) string {
+	var notes []string
+	for !s.game.GameOver {
+		if s.game.GetCurrentPlayer() == s.human {
+			if s.game.HasValidMove() {
+				break
+			}
+			s.game.Pass()
+			notes = append(notes, "You have no legal move and pass.")
+			continue
+		}
+
+		if err := engine.Play(s.game, s.ai); err != nil {
+			notes = append(notes, fmt.Sprintf("The AI failed to move: %v", err))
+			break
+		}
+		last := s.game.History[len(s.game.History)-1].Position
+		if last.Row < 0 {
+			notes = append(notes, "The AI passes.")
+		} else {
+			notes = append(notes, "The AI played "+model.FormatMove(last.Row, last.Col)+".")
+		}
+	}
+	return strings.Join(notes, "\n")
+}
+
+// boardReply renders the position with a status line
+func (b *Bot) boardReply(s *session, note string) Reply {
+	black, white := s.game.GetScore()
+	var sb strings.Builder
+	if note != "" {
+		sb.WriteString(strings.TrimSpace(note) + "\n")
+	}
+	sb.WriteString("```\n")
+	sb.WriteString(render.Text(s.game.Board, b.Style, s.game.GetCurrentPlayer() == s.human))
+	sb.WriteString("```\n")
+	fmt.Fprintf(&sb, "Black %d - White %d. %s", black, white, s.game.GetGameStatus())
+
+	reply := Reply{Text: sb.String()}
+	if b.Images {
+		reply.Image = BoardPNG(s.game)
+	}
+	return reply
+}
+
+// BoardPNG renders the game's current position as a PNG
+func BoardPNG(game *engine.Game) []byte {
+	opts := render.Options{Coordinates: true, ShowMoves: !game.GameOver}
+	if n := len(game.History); n > 0 {
+		last := game.History[n-1].Position
+		opts.LastMove = &last
+	}
+
+	var buf bytes.Buffer
+	if err := render.PNG(&buf, game.Board, opts); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// Snapshot returns a copy of the channel's game, if any
+func (b *Bot) Snapshot(channel string) (*engine.Game, bool) {
+	s, ok := b.session(channel)
+	if !ok {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := model.NewGameRecord(s.game)
+	game, err := record.Replay()
+	return game, err == nil
+}
+
+// help lists the commands
+func (b *Bot) help() string {
+	p := b.Prefix
+	return strings.Join([]string{
+		"Othello commands:",
+		"`" + p + " start [easy|medium|hard] [black|white]` start a game against the AI",
+		"`" + p + " move d3` or `" + p + " d3` play a move",
+		"`" + p + " pass` pass when you have no legal move",
+		"`" + p + " board` show the board",
+		"`" + p + " resign` end the game",
+	}, "\n")
+}
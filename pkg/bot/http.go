@@ -0,0 +1,261 @@
+package bot
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+)
+
+// maxBodySize limits webhook request bodies
+const maxBodySize = 64 << 10
+
+// slackMaxAge is how old a signed Slack request may be, so captured requests
+// cannot be replayed later
+const slackMaxAge = 5 * time.Minute
+
+var errInvalidKey = errors.New("discord public key must be 32 hex-encoded bytes")
+
+// Server exposes a bot to chat services over HTTP webhooks
+//
+//	POST /slack          Slack slash command (e.g. /othello move d3)
+//	POST /discord        Discord interactions endpoint
+//	GET  /board/{id}.png PNG of a channel's board
+type Server struct {
+	Bot *Bot
+
+	// PublicURL is the externally reachable base URL of the server; when
+	// set, replies link to the PNG of the board
+	PublicURL string
+
+	// DiscordKey is the application's public key used to verify Discord
+	// interaction signatures; Discord requests are refused without it
+	DiscordKey ed25519.PublicKey
+
+	// SlackSecret is the Slack app's signing secret used to verify slash
+	// command requests; Slack requests are refused without it
+	SlackSecret string
+
+	log *slog.Logger
+}
+
+// NewServer creates the HTTP frontend for a bot
+func NewServer(b *Bot, publicURL, discordKey, slackSecret string) (*Server, error) {
+	s := &Server{Bot: b, PublicURL: strings.TrimSuffix(publicURL, "/"), SlackSecret: slackSecret, log: logging.For("bot")}
+	if discordKey != "" {
+		key, err := hex.DecodeString(discordKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, errInvalidKey
+		}
+		s.DiscordKey = key
+	}
+	return s, nil
+}
+
+// ServeHTTP routes webhook requests
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.URL.Path == "/slack" && r.Method == http.MethodPost:
+		s.serveSlack(w, r)
+	case r.URL.Path == "/discord" && r.Method == http.MethodPost:
+		s.serveDiscord(w, r)
+	case strings.HasPrefix(r.URL.Path, "/board/") && r.Method == http.MethodGet:
+		s.serveBoard(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveSlack answers a slash command after verifying its signature; the
+// command text holds the arguments
+func (s *Server) serveSlack(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !s.verifySlack(r.Header, body, time.Now()) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	channel := form.Get("channel_id")
+	user := form.Get("user_name")
+	reply := s.Bot.Command(channel, user, strings.Fields(form.Get("text")))
+	s.log.Info("slack command", "channel", channel, "user", user, "text", form.Get("text"))
+
+	response := map[string]any{
+		"response_type": "in_channel",
+		"text":          reply.Text,
+	}
+	if imageURL := s.boardURL(channel); imageURL != "" {
+		response["attachments"] = []map[string]string{{"image_url": imageURL, "fallback": "Othello board"}}
+	}
+	writeJSON(w, response)
+}
+
+// Discord interaction and response types
+const (
+	discordPing                  = 1
+	discordCommand               = 2
+	discordPong                  = 1
+	discordChannelMessageWithSrc = 4
+)
+
+// discordInteraction holds the fields of an interaction the bot uses
+type discordInteraction struct {
+	Type      int    `json:"type"`
+	ChannelID string `json:"channel_id"`
+	Member    struct {
+		User discordUser `json:"user"`
+	} `json:"member"`
+	User discordUser `json:"user"`
+	Data struct {
+		Name    string `json:"name"`
+		Options []struct {
+			Name  string `json:"name"`
+			Value any    `json:"value"`
+		} `json:"options"`
+	} `json:"data"`
+}
+
+type discordUser struct {
+	Username string `json:"username"`
+}
+
+// serveDiscord answers a Discord interaction after verifying its signature
+func (s *Server) serveDiscord(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !s.verifyDiscord(r.Header, body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var in discordInteraction
+	if err := json.Unmarshal(body, &in); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	switch in.Type {
+	case discordPing:
+		writeJSON(w, map[string]any{"type": discordPong})
+	case discordCommand:
+		// Each option value is one argument: /othello command:move square:d3
+		var args []string
+		for _, opt := range in.Data.Options {
+			if v, ok := opt.Value.(string); ok {
+				args = append(args, strings.Fields(v)...)
+			}
+		}
+		user := in.Member.User.Username
+		if user == "" {
+			user = in.User.Username
+		}
+		reply := s.Bot.Command(in.ChannelID, user, args)
+		s.log.Info("discord command", "channel", in.ChannelID, "user", user, "args", args)
+
+		data := map[string]any{"content": reply.Text}
+		if imageURL := s.boardURL(in.ChannelID); imageURL != "" {
+			data["embeds"] = []map[string]any{{"image": map[string]string{"url": imageURL}}}
+		}
+		writeJSON(w, map[string]any{"type": discordChannelMessageWithSrc, "data": data})
+	default:
+		http.Error(w, "unsupported interaction type", http.StatusBadRequest)
+	}
+}
+
+// verifyDiscord checks the Ed25519 signature Discord puts on every request
+func (s *Server) verifyDiscord(h http.Header, body []byte) bool {
+	if s.DiscordKey == nil {
+		return false
+	}
+	sig, err := hex.DecodeString(h.Get("X-Signature-Ed25519"))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	message := append([]byte(h.Get("X-Signature-Timestamp")), body...)
+	return ed25519.Verify(s.DiscordKey, message, sig)
+}
+
+// verifySlack checks the HMAC-SHA256 signature Slack puts on every request
+// and that the request is recent
+func (s *Server) verifySlack(h http.Header, body []byte, now time.Time) bool {
+	if s.SlackSecret == "" {
+		return false
+	}
+	timestamp := h.Get("X-Slack-Request-Timestamp")
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(sec, 0)); age > slackMaxAge || age < -slackMaxAge {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(s.SlackSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(h.Get("X-Slack-Signature")), []byte(expected))
+}
+
+// serveBoard serves the PNG of a channel's current position
+func (s *Server) serveBoard(w http.ResponseWriter, r *http.Request) {
+	channel, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/board/"), ".png")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	game, ok := s.Bot.Snapshot(channel)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	image := BoardPNG(game)
+	if image == nil {
+		http.Error(w, "failed to render board", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(image)
+}
+
+// boardURL returns the public URL of a channel's board image, or ""
+func (s *Server) boardURL(channel string) string {
+	if s.PublicURL == "" {
+		return ""
+	}
+	// Chat clients cache images by URL, so vary it with the move count
+	game, ok := s.Bot.Snapshot(channel)
+	if !ok {
+		return ""
+	}
+	return s.PublicURL + "/board/" + url.PathEscape(channel) + ".png?ply=" + strconv.Itoa(len(game.History))
+}
+
+// writeJSON writes v as the JSON response
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
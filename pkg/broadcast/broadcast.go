@@ -0,0 +1,193 @@
+// Package broadcast serves a read-only live view of a game over HTTP, so a
+// friend can follow it in a browser or a stream show it as an overlay
+//
+//	GET /           Web page following the game
+//	GET /overlay    The board, names, score and clocks for stream overlays,
+//	                on a transparent background or ?bg=COLOR for chroma keys
+//	GET /state      The game as JSON
+//	GET /events     Server-sent events, one State per change
+//	GET /board.svg  The board as an SVG image
+//
+// Nothing can be changed through it; the program playing the game publishes
+// every change with Publish.
+package broadcast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/render"
+)
+
+// cssColor matches the background colors the overlay accepts: names and
+// hex codes, e.g. "magenta" or "#00ff00"
+var cssColor = regexp.MustCompile(`^(#[0-9a-fA-F]{3,8}|[a-zA-Z]+)$`)
+
+// keepAlive is how often an idle event stream gets a comment, so proxies
+// do not close it
+const keepAlive = 30 * time.Second
+
+// State is the game as broadcast
+type State struct {
+	Seq        int      `json:"seq"` // Counts the changes published
+	Black      string   `json:"black"`
+	White      string   `json:"white"`
+	Moves      []string `json:"moves"` // "pass" for passes
+	BlackDiscs int      `json:"black_discs"`
+	WhiteDiscs int      `json:"white_discs"`
+	ToMove     string   `json:"to_move,omitempty"` // Empty once the game is over
+	Status     string   `json:"status"`
+	BlackClock string   `json:"black_clock"` // As the program shows it, e.g. "1:05"
+	WhiteClock string   `json:"white_clock"`
+	Board      string   `json:"board"` // SVG image of the position
+}
+
+// Server holds the latest state of the broadcast game and serves it
+type Server struct {
+	mu      sync.Mutex
+	state   State
+	changed chan struct{} // Closed and replaced on every change
+}
+
+// NewServer creates a server broadcasting an empty board until the first
+// Publish
+func NewServer() *Server {
+	s := &Server{changed: make(chan struct{})}
+	s.Publish(model.NewGame(), "Black", "White")
+	return s
+}
+
+// Publish broadcasts the game as it is now, with the players' names
+func (s *Server) Publish(game *model.Game, black, white string) {
+	state := State{Black: black, White: white, Moves: []string{}}
+	var last *model.Position
+	for _, m := range game.History {
+		if m.Position.Row < 0 {
+			state.Moves = append(state.Moves, "pass")
+			continue
+		}
+		state.Moves = append(state.Moves, model.FormatMove(m.Position.Row, m.Position.Col))
+		pos := m.Position
+		last = &pos
+	}
+	state.BlackDiscs, state.WhiteDiscs = game.GetScore()
+	if !game.GameOver {
+		state.ToMove = model.GetPieceName(game.GetCurrentPlayer())
+	}
+	state.Status = game.GetGameStatus()
+
+	var svg bytes.Buffer
+	render.SVG(&svg, game.Board, render.Options{CellSize: 40, Coordinates: true, LastMove: last}, render.Annotations{})
+	state.Board = svg.String()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state.Seq, state.BlackClock, state.WhiteClock = s.state.Seq, s.state.BlackClock, s.state.WhiteClock
+	s.state = state
+	s.notify()
+}
+
+// SetClocks broadcasts the players' clocks, as shown by the program
+func (s *Server) SetClocks(black, white string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if black == s.state.BlackClock && white == s.state.WhiteClock {
+		return
+	}
+	s.state.BlackClock, s.state.WhiteClock = black, white
+	s.notify()
+}
+
+// notify tells the event streams about a change; s.mu must be held
+func (s *Server) notify() {
+	s.state.Seq++
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// current returns the latest state and a channel closed when it changes
+func (s *Server) current() (State, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state, s.changed
+}
+
+// ServeHTTP routes a request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "the broadcast is read-only", http.StatusMethodNotAllowed)
+		return
+	}
+	state, _ := s.current()
+	switch strings.Trim(r.URL.Path, "/") {
+	case "":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, page)
+	case "overlay":
+		bg := r.URL.Query().Get("bg")
+		if !cssColor.MatchString(bg) {
+			bg = "transparent"
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, strings.ReplaceAll(overlayPage, "{{bg}}", bg))
+	case "state":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(state)
+	case "board.svg":
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "no-store")
+		fmt.Fprint(w, state.Board)
+	case "events":
+		s.serveEvents(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveEvents streams the state as server-sent events until the client
+// goes away: the current state at once, then every change
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		state, changed := s.current()
+		data, err := json.Marshal(state)
+		if err != nil {
+			return
+		}
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+			return
+		}
+		flusher.Flush()
+
+	waiting:
+		for {
+			select {
+			case <-changed:
+				break waiting
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+}
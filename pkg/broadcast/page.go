@@ -0,0 +1,105 @@
+package broadcast
+
+// page follows the game through /events, falling back to polling /state
+// where server-sent events are unavailable
+const page = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Othello - live</title>
+<style>
+body { background: #282828; color: #fff; font-family: sans-serif; margin: 0; padding: 16px; }
+main { display: flex; flex-wrap: wrap; gap: 24px; align-items: flex-start; }
+#board svg { width: min(90vw, 480px); height: auto; display: block; }
+h1 { font-size: 1.4em; margin: 0 0 8px; }
+#score { font-size: 1.2em; margin-bottom: 8px; }
+#moves { max-width: 320px; line-height: 1.6; color: #ccc; }
+#live { color: #8f8; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<main>
+<div id="board"></div>
+<div>
+<h1 id="players">Black vs White</h1>
+<div id="score"></div>
+<div id="status"></div>
+<p id="moves"></p>
+<div id="live">Connecting...</div>
+</div>
+</main>
+<script>
+function show(s) {
+	document.getElementById("board").innerHTML = s.board;
+	document.getElementById("players").textContent = s.black + " vs " + s.white;
+	document.getElementById("score").textContent = "Black " + s.black_discs + " - " + s.white_discs + " White";
+	document.getElementById("status").textContent = s.status;
+	var moves = [];
+	for (var i = 0; i < s.moves.length; i += 2) {
+		moves.push((i / 2 + 1) + ". " + s.moves.slice(i, i + 2).join(" "));
+	}
+	document.getElementById("moves").textContent = moves.join("  ");
+}
+function poll() {
+	fetch("state").then(function (r) { return r.json(); }).then(show).catch(function () {});
+}
+if (window.EventSource) {
+	var events = new EventSource("events");
+	events.onmessage = function (e) { show(JSON.parse(e.data)); };
+	events.onopen = function () { document.getElementById("live").textContent = "Live"; };
+	events.onerror = function () { document.getElementById("live").textContent = "Reconnecting..."; };
+} else {
+	document.getElementById("live").textContent = "Live (updated every 2 seconds)";
+	poll();
+	setInterval(poll, 2000);
+}
+</script>
+</body>
+</html>
+`
+
+// overlayPage shows the board with a banner of names, scores and clocks on
+// a plain background, for a browser source in streaming software. {{bg}}
+// is replaced with the background color.
+const overlayPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Othello overlay</title>
+<style>
+html, body { background: {{bg}}; margin: 0; }
+body { color: #fff; font-family: sans-serif; font-weight: bold; display: inline-block; padding: 8px; }
+#board svg { width: 400px; height: auto; display: block; }
+#banner { display: flex; justify-content: space-between; gap: 12px; margin-top: 6px;
+	background: rgba(0, 0, 0, 0.75); border-radius: 6px; padding: 6px 10px; font-size: 18px; }
+.side { display: flex; gap: 8px; align-items: center; }
+.disc { width: 16px; height: 16px; border-radius: 50%; border: 1px solid #888; }
+.clock { font-family: monospace; color: #ddd; }
+.to-move { color: #ff0; }
+</style>
+</head>
+<body>
+<div id="board"></div>
+<div id="banner">
+<div class="side" id="black"><span class="disc" style="background:#000"></span><span class="name"></span><span class="discs"></span><span class="clock"></span></div>
+<div class="side" id="white"><span class="clock"></span><span class="discs"></span><span class="name"></span><span class="disc" style="background:#f0f0f0"></span></div>
+</div>
+<script>
+function side(id, name, discs, clock, toMove) {
+	var el = document.getElementById(id);
+	el.querySelector(".name").textContent = name;
+	el.querySelector(".discs").textContent = discs;
+	el.querySelector(".clock").textContent = clock;
+	el.className = toMove ? "side to-move" : "side";
+}
+function show(s) {
+	document.getElementById("board").innerHTML = s.board;
+	side("black", s.black, s.black_discs, s.black_clock, s.to_move === "Black");
+	side("white", s.white, s.white_discs, s.white_clock, s.to_move === "White");
+}
+new EventSource("events").onmessage = function (e) { show(JSON.parse(e.data)); };
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,317 @@
+// Package config loads the user's settings file and watches it for changes
+// so running frontends can apply new settings without a restart.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Themes known to the GUI
+const (
+	ThemeClassic  = "classic"
+	ThemeDark     = "dark"
+	ThemeContrast = "contrast"
+)
+
+// Themes lists the valid theme names
+var Themes = []string{ThemeClassic, ThemeDark, ThemeContrast}
+
+// LanguageEnglish is the language of the GUI's text
+const LanguageEnglish = "en"
+
+// Languages lists the languages the GUI has text in, by ISO 639-1 code
+var Languages = []string{LanguageEnglish}
+
+// Config holds the user's settings
+type Config struct {
+	Theme     string              `json:"theme"`
+	Language  string              `json:"language"`
+	Skin      string              `json:"skin,omitempty"` // Board and disc images, empty for plain colors
+	AI        AIConfig            `json:"ai"`
+	Layout    Layout              `json:"layout"`
+	Library   LibraryConfig       `json:"library"`
+	Training  TrainingConfig      `json:"training"`
+	Effects   EffectsConfig       `json:"effects"`
+	Menu      MenuConfig          `json:"menu"`
+	Broadcast BroadcastConfig     `json:"broadcast"`
+	Notify    NotifyConfig        `json:"notifications"`
+	Keys      map[string][]string `json:"keys"` // Key names bound to each action
+	Presets   []Preset            `json:"presets,omitempty"`
+}
+
+// Actions the GUI lets users bind to keys
+const (
+	ActionUndo           = "undo"
+	ActionHint           = "hint"
+	ActionPass           = "pass"
+	ActionFullscreen     = "fullscreen"
+	ActionScreenshot     = "screenshot"
+	ActionHelp           = "help"
+	ActionToggleHistory  = "toggle_history"
+	ActionToggleAnalysis = "toggle_analysis"
+	ActionToggleChat     = "toggle_chat"
+	ActionToggleEvalBar  = "toggle_eval_bar"
+	ActionDebugOverlay   = "debug_overlay"
+	ActionBlunderAlert   = "toggle_blunder_alert"
+	ActionHistoryBack    = "history_back"
+	ActionHistoryForward = "history_forward"
+	ActionNextTab        = "next_tab"
+	ActionPrevTab        = "prev_tab"
+	ActionSaveGame       = "save_game"
+	ActionLoadGame       = "load_game"
+	ActionFindPosition   = "find_position"
+	ActionPlayback       = "playback"
+	ActionPlaybackFaster = "playback_faster"
+	ActionPlaybackSlower = "playback_slower"
+	ActionPlaybackStep   = "playback_step"
+	ActionBroadcast      = "toggle_broadcast"
+	ActionOwnership      = "toggle_ownership"
+	ActionRegions        = "toggle_regions"
+)
+
+// Actions lists the bindable actions in the order settings show them
+var Actions = []string{
+	ActionUndo, ActionHint, ActionPass, ActionFullscreen, ActionScreenshot, ActionHelp,
+	ActionToggleHistory, ActionToggleAnalysis, ActionToggleChat, ActionToggleEvalBar,
+	ActionDebugOverlay, ActionBlunderAlert, ActionHistoryBack, ActionHistoryForward,
+	ActionNextTab, ActionPrevTab, ActionSaveGame, ActionLoadGame, ActionFindPosition,
+	ActionPlayback, ActionPlaybackFaster, ActionPlaybackSlower, ActionPlaybackStep,
+	ActionBroadcast, ActionOwnership, ActionRegions,
+}
+
+// DefaultKeys returns the default key bindings
+// Key names are Ebitengine's, e.g. "A", "Space", "F11" or "ArrowLeft".
+func DefaultKeys() map[string][]string {
+	return map[string][]string{
+		ActionUndo:           {"U"},
+		ActionHint:           {"T"},
+		ActionPass:           {"Space", "P"},
+		ActionFullscreen:     {"F11"},
+		ActionScreenshot:     {"F12"},
+		ActionHelp:           {"F1"},
+		ActionToggleHistory:  {"H"},
+		ActionToggleAnalysis: {"A"},
+		ActionToggleChat:     {"C"},
+		ActionToggleEvalBar:  {"E"},
+		ActionDebugOverlay:   {"F3"},
+		ActionBlunderAlert:   {"B"},
+		ActionHistoryBack:    {"ArrowLeft"},
+		ActionHistoryForward: {"ArrowRight"},
+		ActionNextTab:        {"PageDown"},
+		ActionPrevTab:        {"PageUp"},
+		ActionSaveGame:       {"S"},
+		ActionLoadGame:       {"O"},
+		ActionFindPosition:   {"F"},
+		ActionPlayback:       {"R"},
+		ActionPlaybackFaster: {"BracketRight"},
+		ActionPlaybackSlower: {"BracketLeft"},
+		ActionPlaybackStep:   {"Period"},
+		ActionBroadcast:      {"F9"},
+		ActionOwnership:      {"W"},
+		ActionRegions:        {"G"},
+	}
+}
+
+// AIConfig holds the computer player's settings
+type AIConfig struct {
+	Book        string `json:"book,omitempty"`    // Opening book file, empty for none
+	Weights     string `json:"weights,omitempty"` // Evaluation weights file, empty for the built-in weights
+	MoveDelayMS int    `json:"move_delay_ms"`     // Pause before the GUI's AI moves
+
+	// Empty squares at which the midgame and the endgame begin, used by the
+	// evaluation, time management and the GUI
+	MidgameEmpties int `json:"midgame_empties"`
+	EndgameEmpties int `json:"endgame_empties"`
+}
+
+// LibraryConfig holds the game library's settings
+type LibraryConfig struct {
+	AnalysisDepth int `json:"analysis_depth"` // Depth saved games are analyzed to, 0 for none
+}
+
+// TrainingConfig holds the settings of the practice aids
+type TrainingConfig struct {
+	BlunderAlert     bool    `json:"blunder_alert"`     // Pause the game when a move loses too much
+	BlunderThreshold float64 `json:"blunder_threshold"` // Discs a move may lose before it is a blunder
+
+	// Assisted mode limits the hints and undos a player gets in each game;
+	// games they used any in are marked in the statistics either way
+	Assisted   bool `json:"assisted"`
+	HintBudget int  `json:"hint_budget"` // Hints per game in assisted mode
+	UndoBudget int  `json:"undo_budget"` // Undos per game in assisted mode
+}
+
+// EffectsConfig selects the GUI's celebrations of notable moves
+type EffectsConfig struct {
+	Particles   bool `json:"particles"`    // Bursts of particles on corners, big flips and winning moves
+	ScreenShake bool `json:"screen_shake"` // Shake the screen on the same moves
+}
+
+// MenuConfig holds the settings of the GUI's main menu
+type MenuConfig struct {
+	DemoAfterSeconds int `json:"demo_after_seconds"` // Idle time before a computer game plays behind the menu, 0 for never
+}
+
+// BroadcastConfig holds the settings of the GUI's live game broadcast
+type BroadcastConfig struct {
+	Addr string `json:"addr"` // Address the web page is served on
+}
+
+// NotifyConfig selects how players are told it is their move in a
+// correspondence or network game while the GUI is minimized or in console mode
+type NotifyConfig struct {
+	Desktop bool   `json:"desktop"`           // Show a desktop notification
+	Webhook string `json:"webhook,omitempty"` // URL the turn is posted to as JSON, empty for none
+}
+
+// Layout selects the panels the GUI shows around the board
+type Layout struct {
+	History  bool `json:"history"`
+	Analysis bool `json:"analysis"`
+	Chat     bool `json:"chat"`
+	EvalBar  bool `json:"eval_bar"`
+}
+
+// Default returns the settings used when there is no settings file
+func Default() Config {
+	return Config{
+		Theme:     ThemeClassic,
+		Language:  LanguageEnglish,
+		AI:        AIConfig{MoveDelayMS: 800, MidgameEmpties: 39, EndgameEmpties: 19},
+		Layout:    Layout{History: true},
+		Library:   LibraryConfig{AnalysisDepth: 8},
+		Training:  TrainingConfig{BlunderThreshold: 6, HintBudget: 3, UndoBudget: 3},
+		Effects:   EffectsConfig{Particles: true},
+		Menu:      MenuConfig{DemoAfterSeconds: 60},
+		Broadcast: BroadcastConfig{Addr: ":8090"},
+		Keys:      DefaultKeys(),
+	}
+}
+
+// DefaultPath returns the settings file in the user's config directory
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "othello.json"
+	}
+	return filepath.Join(dir, "othello", "config.json")
+}
+
+// SkinsDir returns the directory user skins are loaded from, one
+// subdirectory per skin
+func SkinsDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "skins"
+	}
+	return filepath.Join(dir, "othello", "skins")
+}
+
+// Validate reports the first invalid setting
+func (c Config) Validate() error {
+	valid := false
+	for _, t := range Themes {
+		valid = valid || c.Theme == t
+	}
+	if !valid {
+		return fmt.Errorf("unknown theme %q", c.Theme)
+	}
+	if !slices.Contains(Languages, c.Language) {
+		return fmt.Errorf("unknown language %q", c.Language)
+	}
+	if c.AI.MoveDelayMS < 0 {
+		return errors.New("ai.move_delay_ms must not be negative")
+	}
+	if c.AI.EndgameEmpties < 0 || c.AI.EndgameEmpties >= c.AI.MidgameEmpties || c.AI.MidgameEmpties > 60 {
+		return errors.New("ai.endgame_empties must be below ai.midgame_empties, both between 0 and 60")
+	}
+	if c.Library.AnalysisDepth < 0 {
+		return errors.New("library.analysis_depth must not be negative")
+	}
+	if c.Training.BlunderThreshold <= 0 {
+		return errors.New("training.blunder_threshold must be positive")
+	}
+	if c.Training.HintBudget < 0 || c.Training.UndoBudget < 0 {
+		return errors.New("training.hint_budget and training.undo_budget must not be negative")
+	}
+	if c.Menu.DemoAfterSeconds < 0 {
+		return errors.New("menu.demo_after_seconds must not be negative")
+	}
+	if c.Broadcast.Addr == "" {
+		return errors.New("broadcast.addr must not be empty")
+	}
+	if c.Notify.Webhook != "" {
+		if u, err := url.Parse(c.Notify.Webhook); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("notifications.webhook must be an http or https URL")
+		}
+	}
+
+	names := make(map[string]bool)
+	for _, p := range c.Presets {
+		if err := p.validate(); err != nil {
+			return err
+		}
+		if names[strings.ToLower(p.Name)] {
+			return fmt.Errorf("presets: %s is defined twice", p.Name)
+		}
+		names[strings.ToLower(p.Name)] = true
+	}
+
+	// Each key may trigger only one action
+	boundTo := make(map[string]string)
+	for action, keys := range c.Keys {
+		if !slices.Contains(Actions, action) {
+			return fmt.Errorf("keys: unknown action %q", action)
+		}
+		for _, key := range keys {
+			name := strings.ToLower(key)
+			if other, ok := boundTo[name]; ok && other != action {
+				return fmt.Errorf("keys: %s is bound to both %s and %s", key, other, action)
+			}
+			boundTo[name] = action
+		}
+	}
+	return nil
+}
+
+// Load reads a settings file; settings missing from the file keep their
+// defaults and a missing file yields the defaults
+func Load(path string) (Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Default(), fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Save writes the settings file, creating its directory if needed
+func Save(path string, cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Preset is a named game setup started in one step from the main menu or
+// with --preset, e.g. "club blitz": Hard AI, 5+0 clock, dark theme
+type Preset struct {
+	Name string `json:"name"`
+	// Opponent is "human" or the computer's difficulty: "easy", "medium"
+	// or "hard"
+	Opponent string `json:"opponent"`
+	Color    string `json:"color,omitempty"` // The player's color against the computer, "black" or "white"; empty to ask
+	Clock    string `json:"clock,omitempty"` // Time control as minutes+increment seconds, e.g. "5+0"; empty for none
+	Theme    string `json:"theme,omitempty"` // Theme switched to; empty to keep the current one
+}
+
+// Opponents lists the valid preset opponents
+var Opponents = []string{"human", "easy", "medium", "hard"}
+
+// Preset returns the preset with the given name, ignoring case
+func (c Config) Preset(name string) (Preset, bool) {
+	for _, p := range c.Presets {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Preset{}, false
+}
+
+// TimeControl returns the preset's clock: the time each side starts with and
+// the time added after each of their moves; a zero base means no clock
+func (p Preset) TimeControl() (base, increment time.Duration, err error) {
+	return ParseTimeControl(p.Clock)
+}
+
+// ParseTimeControl reads a time control written as minutes+increment
+// seconds, e.g. "5+0" or "3+2"; an empty one is no clock
+func ParseTimeControl(clock string) (base, increment time.Duration, err error) {
+	if clock == "" {
+		return 0, 0, nil
+	}
+	minutes, seconds, _ := strings.Cut(clock, "+")
+	m, err := strconv.ParseFloat(strings.TrimSpace(minutes), 64)
+	if err != nil || m <= 0 {
+		return 0, 0, fmt.Errorf("clock %q: expected minutes+increment seconds, e.g. 5+0", clock)
+	}
+	s := 0.0
+	if seconds != "" {
+		if s, err = strconv.ParseFloat(strings.TrimSpace(seconds), 64); err != nil || s < 0 {
+			return 0, 0, fmt.Errorf("clock %q: expected minutes+increment seconds, e.g. 5+0", clock)
+		}
+	}
+	return time.Duration(m * float64(time.Minute)), time.Duration(s * float64(time.Second)), nil
+}
+
+// validate reports the first invalid setting of the preset
+func (p Preset) validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return errors.New("presets: every preset needs a name")
+	}
+	if !slices.Contains(Opponents, p.Opponent) {
+		return fmt.Errorf("presets: %s: unknown opponent %q", p.Name, p.Opponent)
+	}
+	switch p.Color {
+	case "", "black", "white":
+	default:
+		return fmt.Errorf("presets: %s: color must be black or white", p.Name)
+	}
+	if _, _, err := p.TimeControl(); err != nil {
+		return fmt.Errorf("presets: %s: %w", p.Name, err)
+	}
+	if p.Theme != "" && !slices.Contains(Themes, p.Theme) {
+		return fmt.Errorf("presets: %s: unknown theme %q", p.Name, p.Theme)
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+)
+
+// Watcher reloads the settings file when it changes or the process receives
+// SIGHUP, and notifies subscribers of every change
+// An invalid file is logged and ignored, keeping the previous settings.
+type Watcher struct {
+	path string
+
+	mu      sync.Mutex
+	current Config
+	modTime time.Time
+	subs    []chan Config
+
+	done chan struct{}
+	log  *slog.Logger
+}
+
+// Watch loads the settings file and starts watching it, checking for
+// changes every interval
+func Watch(path string, interval time.Duration) (*Watcher, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		path:    path,
+		current: cfg,
+		modTime: modTime(path),
+		done:    make(chan struct{}),
+		log:     logging.For("config"),
+	}
+	go w.run(interval)
+	return w, nil
+}
+
+// Path returns the watched settings file
+func (w *Watcher) Path() string {
+	return w.path
+}
+
+// Current returns the settings in effect
+func (w *Watcher) Current() Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Subscribe returns a channel receiving the settings after each change
+// Slow subscribers only see the latest settings, never a backlog.
+func (w *Watcher) Subscribe() <-chan Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ch := make(chan Config, 1)
+	w.subs = append(w.subs, ch)
+	return ch
+}
+
+// Reload reads the settings file now, e.g. after a settings screen saved
+// it, and notifies subscribers if the settings changed
+func (w *Watcher) Reload() error {
+	cfg, err := Load(w.path)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.modTime = modTime(w.path)
+	if reflect.DeepEqual(cfg, w.current) {
+		return nil
+	}
+	w.current = cfg
+	for _, ch := range w.subs {
+		// Replace an unread older value with the new one
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- cfg
+	}
+	w.log.Info("settings reloaded", "file", w.path, "theme", cfg.Theme)
+	return nil
+}
+
+// Close stops watching
+func (w *Watcher) Close() {
+	close(w.done)
+}
+
+// run polls the file and listens for SIGHUP until Close
+func (w *Watcher) run(interval time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-hup:
+			w.log.Info("SIGHUP received, reloading settings")
+		case <-ticker.C:
+			w.mu.Lock()
+			changed := !modTime(w.path).Equal(w.modTime)
+			w.mu.Unlock()
+			if !changed {
+				continue
+			}
+		}
+		if err := w.Reload(); err != nil {
+			w.log.Error("invalid settings file, keeping previous settings", "err", err)
+			// Do not report the same broken file on every tick
+			w.mu.Lock()
+			w.modTime = modTime(w.path)
+			w.mu.Unlock()
+		}
+	}
+}
+
+// modTime returns the file's modification time, or zero if it is missing
+func modTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
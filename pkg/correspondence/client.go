@@ -0,0 +1,131 @@
+package correspondence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client talks to a correspondence server on behalf of one player
+type Client struct {
+	BaseURL string
+	Player  string
+	Secret  string // Proves the player is who they say; see NewSecret
+	HTTP    *http.Client
+}
+
+// NewClient creates a client for the server at baseURL
+func NewClient(baseURL, player, secret string) *Client {
+	return &Client{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Player:  player,
+		Secret:  secret,
+		HTTP:    &http.Client{Timeout: MaxPollWait + 10*time.Second},
+	}
+}
+
+// ServerError is an error response from the server
+type ServerError struct {
+	Status int
+	Code   string
+	Msg    string
+}
+
+// Error implements the error interface
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("server error %d (%s): %s", e.Status, e.Code, e.Msg)
+}
+
+// List returns the player's games updated after since
+// With wait > 0 the server holds the request until something changes.
+func (c *Client) List(ctx context.Context, since time.Time, wait time.Duration) (*ListResponse, error) {
+	query := url.Values{}
+	query.Set("player", c.Player)
+	if !since.IsZero() {
+		query.Set("since", since.Format(time.RFC3339Nano))
+	}
+	if wait > 0 {
+		query.Set("wait", strconv.Itoa(int(wait.Seconds())))
+	}
+
+	var resp ListResponse
+	err := c.do(ctx, http.MethodGet, "/games?"+query.Encode(), nil, &resp)
+	return &resp, err
+}
+
+// Get returns one game
+func (c *Client) Get(ctx context.Context, id string) (*Game, error) {
+	var g Game
+	err := c.do(ctx, http.MethodGet, "/games/"+url.PathEscape(id), nil, &g)
+	return &g, err
+}
+
+// Challenge starts a game against an opponent, playing Black if asBlack
+func (c *Client) Challenge(ctx context.Context, opponent string, asBlack bool, daysPerMove int) (*Game, error) {
+	req := CreateRequest{Player: c.Player, Black: c.Player, White: opponent, DaysPerMove: daysPerMove}
+	if !asBlack {
+		req.Black, req.White = opponent, c.Player
+	}
+
+	var g Game
+	err := c.do(ctx, http.MethodPost, "/games", req, &g)
+	return &g, err
+}
+
+// Play submits a move in notation such as "E4" or "Pass"
+func (c *Client) Play(ctx context.Context, id, move string) (*Game, error) {
+	var g Game
+	err := c.do(ctx, http.MethodPost, "/games/"+url.PathEscape(id)+"/move", MoveRequest{Player: c.Player, Move: move}, &g)
+	return &g, err
+}
+
+// Resign gives up a game
+func (c *Client) Resign(ctx context.Context, id string) (*Game, error) {
+	var g Game
+	err := c.do(ctx, http.MethodPost, "/games/"+url.PathEscape(id)+"/resign", MoveRequest{Player: c.Player}, &g)
+	return &g, err
+}
+
+// do performs a JSON request
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Secret != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Secret)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&e)
+		return &ServerError{Status: resp.StatusCode, Code: e.Code, Msg: e.Error}
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
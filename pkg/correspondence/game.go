@@ -0,0 +1,172 @@
+package correspondence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/notation"
+)
+
+// Reasons a correspondence game ended
+const (
+	EndFinished = "finished"
+	EndTimeout  = model.EndTimeout
+	EndResigned = model.EndResigned
+)
+
+// Correspondence errors
+var (
+	ErrNotFound    = errors.New("game not found")
+	ErrNotAPlayer  = errors.New("not a player in this game")
+	ErrNotYourTurn = errors.New("it is not your turn")
+
+	ErrUnauthorized  = errors.New("missing or wrong player secret")
+	ErrUnknownPlayer = errors.New("player has not created a game on this server yet")
+)
+
+// Game is a correspondence game stored on the server
+type Game struct {
+	ID          string    `json:"id"`
+	Black       string    `json:"black"`
+	White       string    `json:"white"`
+	Moves       []string  `json:"moves"`
+	DaysPerMove int       `json:"days_per_move"`
+	Deadline    time.Time `json:"deadline"`
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+	Winner      string    `json:"winner,omitempty"` // "Black", "White" or "Empty" for a draw
+	EndReason   string    `json:"end_reason,omitempty"`
+}
+
+// Replay rebuilds the rules engine state from the stored moves
+func (g *Game) Replay() (*model.Game, error) {
+	record := &model.GameRecord{Moves: g.Moves}
+	return record.Replay()
+}
+
+// IsOver reports whether the game has ended
+func (g *Game) IsOver() bool {
+	return g.EndReason != ""
+}
+
+// ColorOf returns the color the named player has, or Empty
+func (g *Game) ColorOf(player string) model.Piece {
+	switch {
+	case strings.EqualFold(player, g.Black):
+		return model.Black
+	case strings.EqualFold(player, g.White):
+		return model.White
+	default:
+		return model.Empty
+	}
+}
+
+// Opponent returns the name of the other player
+func (g *Game) Opponent(player string) string {
+	if strings.EqualFold(player, g.Black) {
+		return g.White
+	}
+	return g.Black
+}
+
+// ToMove returns the color whose turn it is, or Empty once the game is over
+func (g *Game) ToMove() model.Piece {
+	if g.IsOver() {
+		return model.Empty
+	}
+	// Passes are stored explicitly, so turns simply alternate
+	if len(g.Moves)%2 == 0 {
+		return model.Black
+	}
+	return model.White
+}
+
+// TimeLeft returns the time remaining for the side to move
+func (g *Game) TimeLeft(now time.Time) time.Duration {
+	if g.IsOver() {
+		return 0
+	}
+	return g.Deadline.Sub(now)
+}
+
+// play validates and applies a move by the named player
+// Forced passes for the opponent are recorded automatically.
+func (g *Game) play(player, move string, now time.Time) error {
+	if g.IsOver() {
+		return model.NewMoveError(model.ErrGameOver, -1, -1)
+	}
+
+	color := g.ColorOf(player)
+	if color == model.Empty {
+		return ErrNotAPlayer
+	}
+	if color != g.ToMove() {
+		return ErrNotYourTurn
+	}
+
+	game, err := g.Replay()
+	if err != nil {
+		return fmt.Errorf("stored game is corrupt: %w", err)
+	}
+
+	row, col, err := notation.Default.Parse(move, notation.Strict)
+	if err != nil {
+		return err
+	}
+	if row < 0 {
+		err = game.Pass()
+	} else {
+		err = game.MakeMove(row, col)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The opponent cannot do anything about a forced pass, so record it now
+	if !game.GameOver && !game.HasValidMove() {
+		game.Pass()
+	}
+
+	g.Moves = model.NewGameRecord(game).Moves
+	g.Updated = now
+	g.Deadline = now.Add(time.Duration(g.DaysPerMove) * 24 * time.Hour)
+
+	if game.GameOver {
+		g.Winner = model.GetPieceName(game.Winner)
+		g.EndReason = EndFinished
+	}
+	return nil
+}
+
+// resign ends the game in the opponent's favour
+func (g *Game) resign(player string, now time.Time) error {
+	if g.IsOver() {
+		return model.NewMoveError(model.ErrGameOver, -1, -1)
+	}
+
+	color := g.ColorOf(player)
+	if color == model.Empty {
+		return ErrNotAPlayer
+	}
+
+	g.Winner = model.GetPieceName(color.Opponent())
+	g.EndReason = EndResigned
+	g.Updated = now
+	return nil
+}
+
+// expire ends the game on time if the side to move missed the deadline
+// It reports whether the game changed.
+func (g *Game) expire(now time.Time) bool {
+	if g.IsOver() || now.Before(g.Deadline) {
+		return false
+	}
+
+	g.Winner = model.GetPieceName(g.ToMove().Opponent())
+	g.EndReason = EndTimeout
+	g.Updated = now
+	return true
+}
@@ -0,0 +1,201 @@
+package correspondence
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// idleClient is how long a client's request budget is kept after its last
+// request
+const idleClient = 10 * time.Minute
+
+// Limits protect a public server from clients that send too much, whether
+// by mistake or on purpose
+type Limits struct {
+	MaxConnsPerIP   int     // Open connections per client address; 0 for no limit
+	RequestsPerSec  float64 // Sustained requests per client address; 0 for no limit
+	RequestBurst    int     // Requests a client may send at once before the rate applies
+	MaxRequestBytes int64   // Largest request body; 0 for no limit
+}
+
+// DefaultLimits returns limits that ordinary clients, including the GUI's
+// long polls, never reach
+func DefaultLimits() Limits {
+	return Limits{MaxConnsPerIP: 16, RequestsPerSec: 5, RequestBurst: 20, MaxRequestBytes: 16 << 10}
+}
+
+// Metrics count the server's traffic and what the limits turned away
+type Metrics struct {
+	Requests      int64 `json:"requests"`
+	Connections   int   `json:"connections"` // Open now
+	Clients       int   `json:"clients"`     // Addresses with open connections or a recent request
+	RejectedConns int64 `json:"rejected_connections"`
+	RateLimited   int64 `json:"rate_limited"`
+	TooLarge      int64 `json:"too_large"`
+}
+
+// client is what the limits track for one address
+type client struct {
+	conns  int
+	tokens float64 // Requests the client may send now
+	last   time.Time
+}
+
+// guard applies the limits and counts the traffic
+type guard struct {
+	mu        sync.Mutex
+	limits    Limits
+	clients   map[string]*client
+	metrics   Metrics
+	lastPrune time.Time
+}
+
+// newGuard creates a guard applying limits
+func newGuard(limits Limits) *guard {
+	return &guard{limits: limits, clients: make(map[string]*client)}
+}
+
+// client returns the entry for an address, creating it with a full budget
+func (g *guard) client(addr string, now time.Time) *client {
+	c, ok := g.clients[addr]
+	if !ok {
+		c = &client{tokens: float64(g.limits.RequestBurst), last: now}
+		g.clients[addr] = c
+	}
+	return c
+}
+
+// connOpened counts a new connection, reporting whether it is allowed
+func (g *guard) connOpened(addr string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c := g.client(addr, time.Now())
+	if g.limits.MaxConnsPerIP > 0 && c.conns >= g.limits.MaxConnsPerIP {
+		g.metrics.RejectedConns++
+		return false
+	}
+	c.conns++
+	g.metrics.Connections++
+	return true
+}
+
+// connClosed counts a connection that closed
+func (g *guard) connClosed(addr string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if c, ok := g.clients[addr]; ok && c.conns > 0 {
+		c.conns--
+		g.metrics.Connections--
+	}
+}
+
+// allow spends one request of an address's budget, returning how long to
+// wait before retrying when it has none left
+func (g *guard) allow(addr string) (ok bool, retry time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := time.Now()
+	g.prune(now)
+	g.metrics.Requests++
+	c := g.client(addr, now)
+
+	rate := g.limits.RequestsPerSec
+	if rate <= 0 {
+		c.last = now
+		return true, 0
+	}
+	c.tokens = min(c.tokens+now.Sub(c.last).Seconds()*rate, float64(max(g.limits.RequestBurst, 1)))
+	c.last = now
+	if c.tokens < 1 {
+		g.metrics.RateLimited++
+		return false, time.Duration((1 - c.tokens) / rate * float64(time.Second))
+	}
+	c.tokens--
+	return true, 0
+}
+
+// prune forgets addresses without connections that have been idle a while,
+// at most once a minute
+func (g *guard) prune(now time.Time) {
+	if now.Sub(g.lastPrune) < time.Minute {
+		return
+	}
+	g.lastPrune = now
+	for addr, c := range g.clients {
+		if c.conns == 0 && now.Sub(c.last) > idleClient {
+			delete(g.clients, addr)
+		}
+	}
+}
+
+// tooLarge counts a request body over the limit
+func (g *guard) tooLarge() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.metrics.TooLarge++
+}
+
+// snapshot returns the metrics so far
+func (g *guard) snapshot() Metrics {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	m := g.metrics
+	m.Clients = len(g.clients)
+	return m
+}
+
+// ConnState applies the per-address connection limit; set it as the
+// http.Server's ConnState. Connections over the limit are closed at once.
+func (s *Server) ConnState(conn net.Conn, state http.ConnState) {
+	addr := clientAddr(conn.RemoteAddr().String())
+	switch state {
+	case http.StateNew:
+		if !s.guard.connOpened(addr) {
+			s.rejected.Store(conn, true)
+			conn.Close()
+		}
+	case http.StateClosed, http.StateHijacked:
+		// Rejected connections were never counted
+		if _, rejected := s.rejected.LoadAndDelete(conn); !rejected {
+			s.guard.connClosed(addr)
+		}
+	}
+}
+
+// Metrics returns the server's traffic so far
+func (s *Server) Metrics() Metrics {
+	return s.guard.snapshot()
+}
+
+// limit applies the request limits, answering requests they refuse
+func (s *Server) limit(w http.ResponseWriter, r *http.Request) bool {
+	if ok, retry := s.guard.allow(clientAddr(r.RemoteAddr)); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(retry/time.Second)+1))
+		writeError(w, http.StatusTooManyRequests, "rate_limited", "too many requests, slow down")
+		return false
+	}
+	if s.guard.limits.MaxRequestBytes > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, s.guard.limits.MaxRequestBytes)
+	}
+	return true
+}
+
+// isTooLarge reports whether reading a body failed on the size limit
+func isTooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge)
+}
+
+// clientAddr returns the address limits are applied to: the IP of a
+// host:port address
+func clientAddr(remote string) string {
+	host, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		return remote
+	}
+	return host
+}
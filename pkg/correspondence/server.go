@@ -0,0 +1,300 @@
+package correspondence
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/network"
+)
+
+// MaxPollWait bounds how long a list request may wait for changes
+const MaxPollWait = 60 * time.Second
+
+// ListResponse is returned by GET /games
+type ListResponse struct {
+	Games []*Game   `json:"games"`
+	Now   time.Time `json:"now"` // Pass back as "since" to poll for changes
+}
+
+// CreateRequest is the body of POST /games
+type CreateRequest struct {
+	Player      string `json:"player"` // Who creates the game, Black or White
+	Black       string `json:"black"`
+	White       string `json:"white"`
+	DaysPerMove int    `json:"days_per_move"`
+}
+
+// MoveRequest is the body of POST /games/{id}/move and /resign
+type MoveRequest struct {
+	Player string `json:"player"`
+	Move   string `json:"move,omitempty"`
+}
+
+// ErrorResponse is returned with every failed request
+type ErrorResponse struct {
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+// Server exposes a store over HTTP
+//
+//	GET  /games?player=NAME&since=RFC3339&wait=SECONDS
+//	POST /games
+//	GET  /games/{id}
+//	POST /games/{id}/move
+//	POST /games/{id}/resign
+//	GET  /metrics
+//
+// List requests with wait set are long polls: they return as soon as one of
+// the player's games changes after since, or when the wait expires.
+//
+// Listing a player's games, creating a game, moving and resigning need the
+// player's secret as "Authorization: Bearer <secret>". A name is claimed by
+// the first game its player creates, and games are only created against
+// players who have claimed theirs.
+type Server struct {
+	store    *Store
+	guard    *guard
+	rejected sync.Map // Connections closed by ConnState
+}
+
+// NewServer creates an HTTP handler for the store with the default limits
+func NewServer(store *Store) *Server {
+	return NewServerLimited(store, DefaultLimits())
+}
+
+// NewServerLimited creates an HTTP handler for the store with the given
+// limits
+func NewServerLimited(store *Store, limits Limits) *Server {
+	return &Server{store: store, guard: newGuard(limits)}
+}
+
+// ServeHTTP routes a request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.limit(w, r) {
+		return
+	}
+	path := strings.Trim(r.URL.Path, "/")
+	parts := strings.Split(path, "/")
+	if path == "metrics" && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, s.Metrics())
+		return
+	}
+	if parts[0] != "games" {
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.handleList(w, r)
+	case len(parts) == 1 && r.Method == http.MethodPost:
+		s.handleCreate(w, r)
+	case len(parts) == 2 && r.Method == http.MethodGet:
+		s.handleGet(w, parts[1])
+	case len(parts) == 3 && r.Method == http.MethodPost && parts[2] == "move":
+		s.handleMove(w, r, parts[1])
+	case len(parts) == 3 && r.Method == http.MethodPost && parts[2] == "resign":
+		s.handleResign(w, r, parts[1])
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "unknown endpoint")
+	}
+}
+
+// handleList lists a player's games, optionally waiting for changes
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	player := query.Get("player")
+	if player == "" {
+		writeError(w, http.StatusBadRequest, "bad_request", "player is required")
+		return
+	}
+	// No game can name a player who has not claimed their name, so there
+	// is nothing to protect until they do
+	if err := s.store.Authenticate(player, bearerSecret(r)); err != nil && !errors.Is(err, ErrUnknownPlayer) {
+		writeStoreError(w, err)
+		return
+	}
+
+	var since time.Time
+	if v := query.Get("since"); v != "" {
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid since time")
+			return
+		}
+		since = t
+	}
+
+	var wait time.Duration
+	if v := query.Get("wait"); v != "" {
+		secs, err := strconv.Atoi(v)
+		if err != nil || secs < 0 {
+			writeError(w, http.StatusBadRequest, "bad_request", "invalid wait")
+			return
+		}
+		wait = min(time.Duration(secs)*time.Second, MaxPollWait)
+	}
+
+	deadline := time.After(wait)
+	for {
+		// Grab the change channel before listing so no update is missed
+		changed := s.store.Changed()
+		now := s.store.now()
+		games := s.store.List(player, since)
+		if len(games) > 0 || wait == 0 {
+			writeJSON(w, http.StatusOK, ListResponse{Games: games, Now: now})
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-deadline:
+			writeJSON(w, http.StatusOK, ListResponse{Games: []*Game{}, Now: now})
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleCreate starts a new game
+func (s *Server) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req CreateRequest
+	if !s.readJSON(w, r, &req) {
+		return
+	}
+	if !strings.EqualFold(req.Player, req.Black) && !strings.EqualFold(req.Player, req.White) {
+		writeStoreError(w, ErrNotAPlayer)
+		return
+	}
+	if err := s.store.Claim(req.Player, bearerSecret(r)); err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	g, err := s.store.Create(req.Black, req.White, req.DaysPerMove)
+	if errors.Is(err, ErrUnknownPlayer) {
+		writeStoreError(w, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	writeJSON(w, http.StatusCreated, g)
+}
+
+// handleGet returns one game
+func (s *Server) handleGet(w http.ResponseWriter, id string) {
+	g, err := s.store.Get(id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+// handleMove applies a move
+func (s *Server) handleMove(w http.ResponseWriter, r *http.Request, id string) {
+	var req MoveRequest
+	if !s.readJSON(w, r, &req) {
+		return
+	}
+	if !s.authenticate(w, r, req.Player) {
+		return
+	}
+
+	g, err := s.store.Play(id, req.Player, req.Move)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+// handleResign resigns a game
+func (s *Server) handleResign(w http.ResponseWriter, r *http.Request, id string) {
+	var req MoveRequest
+	if !s.readJSON(w, r, &req) {
+		return
+	}
+	if !s.authenticate(w, r, req.Player) {
+		return
+	}
+
+	g, err := s.store.Resign(id, req.Player)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+// authenticate checks the request carries the player's secret, answering
+// with an error if it does not
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request, player string) bool {
+	if err := s.store.Authenticate(player, bearerSecret(r)); err != nil {
+		writeStoreError(w, err)
+		return false
+	}
+	return true
+}
+
+// bearerSecret returns the player secret a request carries
+func bearerSecret(r *http.Request) string {
+	secret, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return secret
+}
+
+// writeStoreError maps store and rules errors to HTTP responses
+func writeStoreError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		writeError(w, http.StatusNotFound, "not_found", err.Error())
+	case errors.Is(err, ErrUnauthorized):
+		writeError(w, http.StatusUnauthorized, "unauthorized", err.Error())
+	case errors.Is(err, ErrUnknownPlayer):
+		writeError(w, http.StatusNotFound, "unknown_player", err.Error())
+	case errors.Is(err, ErrNotAPlayer):
+		writeError(w, http.StatusForbidden, "not_a_player", err.Error())
+	case errors.Is(err, ErrNotYourTurn):
+		writeError(w, http.StatusConflict, network.CodeNotYourTurn, err.Error())
+	case errors.As(err, new(*model.MoveError)):
+		writeError(w, http.StatusUnprocessableEntity, network.ErrorCode(err), err.Error())
+	default:
+		writeError(w, http.StatusBadRequest, network.CodeBadNotation, err.Error())
+	}
+}
+
+// readJSON decodes a request body, answering with an error if it fails
+func (s *Server) readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		if isTooLarge(err) {
+			s.guard.tooLarge()
+			writeError(w, http.StatusRequestEntityTooLarge, "too_large", "request body too large")
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "bad_request", "invalid JSON body")
+		return false
+	}
+	return true
+}
+
+// writeJSON sends a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError sends a JSON error response
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, ErrorResponse{Code: code, Error: message})
+}
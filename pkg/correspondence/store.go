@@ -0,0 +1,345 @@
+package correspondence
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// maxPlayers bounds the names a store lets players claim
+const maxPlayers = 100_000
+
+// errTooManyPlayers is returned when a name is claimed on a full store
+var errTooManyPlayers = errors.New("the server takes no new players")
+
+// playersFile holds the hashes of the players' secrets, beside the games
+const playersFile = "players.secrets"
+
+// Store keeps correspondence games as JSON files in a directory
+type Store struct {
+	dir     string
+	mu      sync.Mutex
+	games   map[string]*Game
+	players map[string]string // Hash of each player's secret, by lower case name
+	changed chan struct{}     // Closed and replaced whenever a game changes
+
+	// now is replaceable for deadline handling
+	now func() time.Time
+}
+
+// OpenStore loads every game from the directory, creating it if needed
+func OpenStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		dir:     dir,
+		games:   make(map[string]*Game),
+		players: make(map[string]string),
+		changed: make(chan struct{}),
+		now:     time.Now,
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, playersFile))
+	if err == nil {
+		err = json.Unmarshal(data, &s.players)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var g Game
+		if err := json.Unmarshal(data, &g); err != nil {
+			return nil, err
+		}
+		s.games[g.ID] = &g
+	}
+
+	return s, nil
+}
+
+// Create starts a new game between two players, who must both have claimed
+// their names, so neither seat can be taken by someone else
+func (s *Store) Create(black, white string, daysPerMove int) (*Game, error) {
+	if black == "" || white == "" || strings.EqualFold(black, white) {
+		return nil, errors.New("a game needs two different players")
+	}
+	if daysPerMove <= 0 {
+		return nil, errors.New("days per move must be positive")
+	}
+
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, player := range []string{black, white} {
+		if _, ok := s.players[strings.ToLower(player)]; !ok {
+			return nil, fmt.Errorf("%s: %w", player, ErrUnknownPlayer)
+		}
+	}
+
+	now := s.now()
+	g := &Game{
+		ID:          id,
+		Black:       black,
+		White:       white,
+		Moves:       []string{},
+		DaysPerMove: daysPerMove,
+		Deadline:    now.Add(time.Duration(daysPerMove) * 24 * time.Hour),
+		Created:     now,
+		Updated:     now,
+	}
+	if err := s.save(g); err != nil {
+		return nil, err
+	}
+	s.games[id] = g
+	s.notify()
+
+	snapshot := *g
+	return &snapshot, nil
+}
+
+// Authenticate checks the secret a player sent with a request, returning
+// ErrUnknownPlayer for a name no one has claimed
+func (s *Store) Authenticate(player, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.authenticate(player, secret, false)
+}
+
+// Claim checks the secret a player sent like Authenticate, but claims the
+// name with it if no one has yet; every later request must send the same
+func (s *Store) Claim(player, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.authenticate(player, secret, true)
+}
+
+// authenticate checks a player's secret, claiming an unknown name if claim
+// is set; the caller holds the lock
+func (s *Store) authenticate(player, secret string, claim bool) error {
+	if player == "" || secret == "" {
+		return ErrUnauthorized
+	}
+	sum := sha256.Sum256([]byte(secret))
+	hash := hex.EncodeToString(sum[:])
+	name := strings.ToLower(player)
+
+	known, ok := s.players[name]
+	if ok {
+		if subtle.ConstantTimeCompare([]byte(known), []byte(hash)) != 1 {
+			return ErrUnauthorized
+		}
+		return nil
+	}
+	if !claim {
+		return fmt.Errorf("%s: %w", player, ErrUnknownPlayer)
+	}
+	if len(s.players) >= maxPlayers {
+		return errTooManyPlayers
+	}
+	s.players[name] = hash
+	if err := s.savePlayers(); err != nil {
+		delete(s.players, name)
+		return err
+	}
+	return nil
+}
+
+// Get returns a copy of a game
+func (s *Store) Get(id string) (*Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	s.expire(g)
+
+	snapshot := *g
+	return &snapshot, nil
+}
+
+// List returns copies of the player's games updated after since, most
+// recently updated first. An empty player lists every game.
+func (s *Store) List(player string, since time.Time) []*Game {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var games []*Game
+	for _, g := range s.games {
+		s.expire(g)
+		if player != "" && g.ColorOf(player) == model.Empty {
+			continue
+		}
+		if !g.Updated.After(since) {
+			continue
+		}
+		snapshot := *g
+		games = append(games, &snapshot)
+	}
+
+	sort.Slice(games, func(i, j int) bool { return games[i].Updated.After(games[j].Updated) })
+	return games
+}
+
+// Changed returns a channel that is closed at the next change to any game
+func (s *Store) Changed() <-chan struct{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.changed
+}
+
+// Play applies a move by the named player
+func (s *Store) Play(id, player, move string) (*Game, error) {
+	return s.update(id, func(g *Game, now time.Time) error {
+		return g.play(player, move, now)
+	})
+}
+
+// Resign ends a game in the opponent's favour
+func (s *Store) Resign(id, player string) (*Game, error) {
+	return s.update(id, func(g *Game, now time.Time) error {
+		return g.resign(player, now)
+	})
+}
+
+// update applies a change to a game and persists it
+func (s *Store) update(id string, change func(g *Game, now time.Time) error) (*Game, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.games[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	s.expire(g)
+
+	// Work on a copy so a failed save leaves the game untouched
+	updated := *g
+	updated.Moves = append([]string(nil), g.Moves...)
+	if err := change(&updated, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.save(&updated); err != nil {
+		return nil, err
+	}
+	s.games[id] = &updated
+	s.notify()
+
+	snapshot := updated
+	return &snapshot, nil
+}
+
+// expire ends an overdue game; the caller holds the lock
+func (s *Store) expire(g *Game) {
+	if g.expire(s.now()) {
+		s.save(g)
+		s.notify()
+	}
+}
+
+// notify wakes everyone waiting on Changed; the caller holds the lock
+func (s *Store) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// save writes a game file atomically
+func (s *Store) save(g *Game) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, g.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// savePlayers writes the players' secrets atomically; the caller holds the
+// lock
+func (s *Store) savePlayers() error {
+	data, err := json.MarshalIndent(s.players, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.dir, playersFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// newID returns a random game identifier
+func newID() (string, error) {
+	return randomHex(8)
+}
+
+// NewSecret returns a random player secret
+func NewSecret() (string, error) {
+	return randomHex(16)
+}
+
+// LoadSecret returns the player secret saved at path, creating and saving
+// a new one the first time
+func LoadSecret(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	secret, err := NewSecret()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(secret+"\n"), 0o600); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// randomHex returns n random bytes, hex encoded
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
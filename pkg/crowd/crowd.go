@@ -0,0 +1,150 @@
+// Package crowd lets a chat audience play one side of a game together, in
+// the style of "Twitch plays": every move is put to a vote, and when the
+// voting window closes the legal move with the most votes is played.
+//
+// Votes reach a Player through Vote, which chat sources call for every
+// message: IRC reads a channel (Twitch chat speaks IRC) and the Player is
+// itself an http.Handler for services that post messages to a webhook.
+package crowd
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/notation"
+)
+
+// DefaultWindow is how long a vote stays open unless set otherwise
+const DefaultWindow = 20 * time.Second
+
+// Result is the outcome of a vote
+type Result struct {
+	Move  string         // The move played, e.g. "D3"
+	Votes map[string]int // Votes cast for each legal move
+	Total int            // Voters in the window
+}
+
+// Player is an engine.Player whose moves are voted on by a chat audience.
+// Each chatter has one vote per move and may change it until the window
+// closes; messages that are not a legal move are ignored. A window that
+// closes without votes is opened again.
+type Player struct {
+	Window time.Duration // How long each vote stays open
+	Prefix string        // Text votes must start with, e.g. "!"; empty takes bare moves
+
+	// Token is the shared secret webhook posts must carry; posts are
+	// refused without one
+	Token string
+
+	// OnOpen and OnResult, when set, are told when a vote opens and how it
+	// went, e.g. to announce it in the chat
+	OnOpen   func(board *model.Board, closes time.Time)
+	OnResult func(Result)
+
+	mu    sync.Mutex
+	open  bool
+	board *model.Board
+	votes map[string]model.Position // Each voter's current vote
+	order []model.Position          // Moves in the order they were first voted for
+}
+
+var _ engine.Player = (*Player)(nil)
+
+// NewPlayer returns a player voting for window on every move
+func NewPlayer(window time.Duration) *Player {
+	return &Player{Window: window}
+}
+
+// Vote counts a chat message from user towards the open vote, reporting
+// whether it was taken as a vote. Sources may call it from any goroutine.
+func (p *Player) Vote(user, text string) bool {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, p.Prefix) {
+		return false
+	}
+	row, col, err := notation.Parse(strings.TrimPrefix(text, p.Prefix))
+	if err != nil || user == "" {
+		return false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.open || !p.board.IsValidMove(row, col) {
+		return false
+	}
+	pos := model.Position{Row: row, Col: col}
+	if !slices.Contains(p.order, pos) {
+		p.order = append(p.order, pos)
+	}
+	p.votes[user] = pos
+	return true
+}
+
+// GetMove opens a vote on board and returns the winning move once a window
+// closes with votes in it. Ties go to the move voted for first.
+func (p *Player) GetMove(board *model.Board) (int, int, error) {
+	window := p.Window
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	log := logging.For("crowd")
+
+	p.mu.Lock()
+	p.open, p.board = true, board
+	p.votes, p.order = make(map[string]model.Position), nil
+	p.mu.Unlock()
+
+	for {
+		closes := time.Now().Add(window)
+		if p.OnOpen != nil {
+			p.OnOpen(board, closes)
+		}
+		time.Sleep(time.Until(closes))
+
+		p.mu.Lock()
+		result, best, ok := p.tally()
+		if ok {
+			p.open = false
+		}
+		p.mu.Unlock()
+
+		if !ok {
+			log.Debug("vote closed without votes, reopening")
+			continue
+		}
+		log.Info("vote closed", "move", result.Move, "votes", result.Votes[result.Move], "voters", result.Total)
+		if p.OnResult != nil {
+			p.OnResult(result)
+		}
+		return best.Row, best.Col, nil
+	}
+}
+
+// tally counts the open vote; it reports false when nobody voted
+func (p *Player) tally() (Result, model.Position, bool) {
+	counts := make(map[model.Position]int)
+	for _, pos := range p.votes {
+		counts[pos]++
+	}
+	if len(counts) == 0 {
+		return Result{}, model.Position{}, false
+	}
+
+	result := Result{Votes: make(map[string]int), Total: len(p.votes)}
+	best := p.order[0]
+	for _, pos := range p.order {
+		if counts[pos] > counts[best] {
+			best = pos
+		}
+		if counts[pos] > 0 {
+			result.Votes[model.FormatMove(pos.Row, pos.Col)] = counts[pos]
+		}
+	}
+	result.Move = model.FormatMove(best.Row, best.Col)
+	return result, best, true
+}
@@ -0,0 +1,131 @@
+package crowd
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+)
+
+// TwitchAddr is the address of Twitch's chat server
+const TwitchAddr = "irc.chat.twitch.tv:6697"
+
+// ErrNoChannel is returned when an IRC source has no channel to join
+var ErrNoChannel = errors.New("no IRC channel to join")
+
+// IRC reads votes from the messages in an IRC channel
+type IRC struct {
+	Addr    string // Server address, e.g. TwitchAddr
+	TLS     bool
+	Nick    string
+	Pass    string // Server password, for Twitch "oauth:" and a token
+	Channel string // Channel to join, with or without the leading '#'
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// Run joins the channel and passes its messages to p.Vote until ctx is
+// done or the connection drops
+func (c *IRC) Run(ctx context.Context, p *Player) error {
+	if c.Channel == "" {
+		return ErrNoChannel
+	}
+	channel := "#" + strings.TrimPrefix(c.Channel, "#")
+
+	var conn net.Conn
+	var err error
+	if c.TLS {
+		conn, err = (&tls.Dialer{}).DialContext(ctx, "tcp", c.Addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", c.Addr)
+	}
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if c.Pass != "" {
+		c.send("PASS " + c.Pass)
+	}
+	c.send("NICK " + c.Nick)
+	c.send("JOIN " + channel)
+
+	log := logging.For("crowd")
+	log.Info("reading votes from IRC", "addr", c.Addr, "channel", channel)
+	lines := bufio.NewScanner(conn)
+	for lines.Scan() {
+		command, params := parseLine(lines.Text())
+		switch {
+		case command.name == "PING" && len(params) > 0:
+			c.send("PONG :" + params[len(params)-1])
+		case command.name == "PRIVMSG" && len(params) == 2 && strings.EqualFold(params[0], channel):
+			p.Vote(command.nick, params[1])
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := lines.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// Say sends text to the channel, e.g. to announce a vote; it does nothing
+// until Run has connected
+func (c *IRC) Say(text string) {
+	c.send(fmt.Sprintf("PRIVMSG #%s :%s", strings.TrimPrefix(c.Channel, "#"), text))
+}
+
+// send writes one line to the server
+func (c *IRC) send(line string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		fmt.Fprintf(c.conn, "%s\r\n", line)
+	}
+}
+
+// ircCommand is the command of an IRC line and the nick that sent it
+type ircCommand struct {
+	name, nick string
+}
+
+// parseLine splits an IRC line into its command and parameters, the last
+// of which may contain spaces. Message tags, as Twitch sends, are skipped.
+func parseLine(line string) (ircCommand, []string) {
+	var cmd ircCommand
+	if strings.HasPrefix(line, "@") {
+		_, line, _ = strings.Cut(line, " ")
+	}
+	if strings.HasPrefix(line, ":") {
+		var prefix string
+		prefix, line, _ = strings.Cut(line[1:], " ")
+		cmd.nick, _, _ = strings.Cut(prefix, "!")
+	}
+
+	line, trailing, hasTrailing := strings.Cut(line, " :")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return cmd, nil
+	}
+	cmd.name = strings.ToUpper(fields[0])
+	params := fields[1:]
+	if hasTrailing {
+		params = append(params, trailing)
+	}
+	return cmd, params
+}
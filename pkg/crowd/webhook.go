@@ -0,0 +1,60 @@
+package crowd
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// webhookMessage is a chat message posted to the webhook
+type webhookMessage struct {
+	User string `json:"user"`
+	Text string `json:"text"`
+}
+
+// ServeHTTP takes chat messages posted by a webhook, as JSON such as
+// {"user": "ann", "text": "d3"} or as the form values user and text. The
+// post carries the token as "Authorization: Bearer <token>" or the token
+// query parameter. It answers 202 Accepted when the message counted as a
+// vote and 204 No Content when it did not.
+func (p *Player) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !p.authorized(r) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	var msg webhookMessage
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "application/json" {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&msg); err != nil {
+			http.Error(w, "invalid message: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else {
+		msg.User, msg.Text = r.FormValue("user"), r.FormValue("text")
+	}
+
+	if p.Vote(msg.User, msg.Text) {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorized reports whether a post carries the webhook token
+func (p *Player) authorized(r *http.Request) bool {
+	if p.Token == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token = r.URL.Query().Get("token")
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(p.Token)) == 1
+}
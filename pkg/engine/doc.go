@@ -0,0 +1,21 @@
+// Package engine is the stable entry point to the Othello rules engine.
+//
+// It re-exports the game and board types from pkg/model, AI construction from
+// pkg/ai and game record serialization, so programs embedding the engine only
+// need a single import and never depend on the user interface packages.
+//
+// A minimal game between two computer players looks like:
+//
+//	game := engine.NewGame()
+//	black := engine.NewAI(engine.Medium, engine.Black)
+//	white := engine.NewAI(engine.Hard, engine.White)
+//	for !game.GameOver {
+//		player := engine.Player(black)
+//		if game.GetCurrentPlayer() == engine.White {
+//			player = white
+//		}
+//		if err := engine.Play(game, player); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+package engine
@@ -0,0 +1,193 @@
+package engine
+
+import (
+	"context"
+	"io"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Core rules types
+type (
+	Game         = model.Game
+	Board        = model.Board
+	Piece        = model.Piece
+	Position     = model.Position
+	Move         = model.Move
+	MoveError    = model.MoveError
+	GameRecord   = model.GameRecord
+	Annotations  = model.Annotations
+	GamePhase    = model.GamePhase
+	Region       = model.Region
+	AI           = ai.Player
+	Clock        = ai.Clock
+	Evaluator    = ai.Evaluator
+	Analysis     = ai.Info
+	SearchLimits = ai.SearchLimits
+	BatchOpts    = ai.BatchOptions
+)
+
+// Pieces
+const (
+	Empty = model.Empty
+	Black = model.Black
+	White = model.White
+)
+
+// Game phases
+const (
+	Opening = model.Opening
+	Midgame = model.Midgame
+	Endgame = model.Endgame
+)
+
+// AI difficulty levels
+const (
+	Easy   = ai.Easy
+	Medium = ai.Medium
+	Hard   = ai.Hard
+)
+
+// Rule violations returned by Game.MakeMove and Game.Pass
+var (
+	ErrGameOver    = model.ErrGameOver
+	ErrOccupied    = model.ErrOccupied
+	ErrNoFlips     = model.ErrNoFlips
+	ErrOutOfBounds = model.ErrOutOfBounds
+	ErrCannotPass  = model.ErrCannotPass
+)
+
+// Player chooses moves for one side of a game
+// Implementations include AI players, humans behind a user interface and
+// remote peers. A negative row or column means the player passes.
+type Player interface {
+	GetMove(board *model.Board) (int, int, error)
+}
+
+// TimedPlayer is a player that can pace itself to a game clock
+type TimedPlayer interface {
+	Player
+	GetMoveTimed(board *model.Board, clock Clock) (int, int, error)
+}
+
+// NewGame creates a game in the standard starting position
+func NewGame() *Game {
+	return model.NewGame()
+}
+
+// NewGameFrom creates a game starting from a copy of board
+func NewGameFrom(board *Board) *Game {
+	return model.NewGameFrom(board)
+}
+
+// NewBoard creates a board in the standard starting position
+func NewBoard() *Board {
+	return model.NewBoard()
+}
+
+// NewAI creates a computer player of the given difficulty
+func NewAI(difficulty string, piece Piece) *AI {
+	return ai.NewPlayer(difficulty, piece)
+}
+
+// EvaluatePositions scores many positions at once, searching them in
+// parallel; the results are in the order of boards
+func EvaluatePositions(ctx context.Context, boards []*Board, opts BatchOpts) []Analysis {
+	return ai.EvaluatePositions(ctx, boards, opts)
+}
+
+// Play asks the player for a move on the current position and applies it
+// The player's turn is passed automatically when it has no legal move
+func Play(game *Game, player Player) error {
+	if game.GameOver {
+		return model.NewMoveError(model.ErrGameOver, -1, -1)
+	}
+
+	if !game.HasValidMove() {
+		return game.Pass()
+	}
+
+	row, col, err := player.GetMove(game.Board.Clone())
+	if err != nil {
+		return err
+	}
+	if row < 0 || col < 0 {
+		return game.Pass()
+	}
+	return game.MakeMove(row, col)
+}
+
+// PlayTimed is Play for a player on the clock: players that can pace
+// themselves are told how much time they have left
+func PlayTimed(game *Game, player Player, clock Clock) error {
+	timed, ok := player.(TimedPlayer)
+	if !ok || game.GameOver || !game.HasValidMove() {
+		return Play(game, player)
+	}
+
+	row, col, err := timed.GetMoveTimed(game.Board.Clone(), clock)
+	if err != nil {
+		return err
+	}
+	if row < 0 || col < 0 {
+		return game.Pass()
+	}
+	return game.MakeMove(row, col)
+}
+
+// MoveObserver is a player told of every move in its game, its opponent's
+// included, e.g. to keep state of its own between moves
+type MoveObserver interface {
+	Player
+	ObserveMove(board *model.Board, move Move)
+}
+
+// Observe tells the players that are move observers of the last move
+// played in game
+func Observe(game *Game, players ...Player) {
+	if len(game.History) == 0 {
+		return
+	}
+	move := game.History[len(game.History)-1]
+	for _, p := range players {
+		if o, ok := p.(MoveObserver); ok {
+			o.ObserveMove(game.Board.Clone(), move)
+		}
+	}
+}
+
+// Phase returns the phase of the game on board
+func Phase(board *Board) GamePhase {
+	return model.Phase(board)
+}
+
+// FormatMove converts a position to notation such as "E4"
+func FormatMove(row, col int) string {
+	return model.FormatMove(row, col)
+}
+
+// ParseMove converts notation such as "E4" to board coordinates
+func ParseMove(move string) (int, int, error) {
+	return model.ParseMove(move)
+}
+
+// ParsePosition decodes a position string such as the one returned by
+// Board.PositionString
+func ParsePosition(s string) (*Board, error) {
+	return model.ParsePosition(s)
+}
+
+// SaveGame writes the game as a JSON record
+func SaveGame(w io.Writer, game *Game) error {
+	return model.WriteGameRecord(w, model.NewGameRecord(game))
+}
+
+// LoadGame reads a JSON record and replays it into a game
+func LoadGame(r io.Reader) (*Game, error) {
+	record, err := model.ReadGameRecord(r)
+	if err != nil {
+		return nil, err
+	}
+	return record.Replay()
+}
@@ -0,0 +1,57 @@
+package ggf
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// FuzzParse checks that any text either fails to parse or gives games whose
+// moves are on the board, and that the moves written back as a GGF game
+// parse to the same moves
+func FuzzParse(f *testing.F) {
+	f.Add("(;GM[Othello]PB[alice]PW[bob]RE[+4.000]B[f5//1.2]W[d6]B[pa];)")
+	f.Add("(;GM[Othello]BO[8 ---------------------------O*------*O--------------------------- *]B[d3];)")
+	f.Add("(;B[d3];)(;W[;)")
+	f.Add("(;[];)")
+	f.Fuzz(func(t *testing.T, s string) {
+		games, err := Parse(strings.NewReader(s))
+		if err != nil {
+			return
+		}
+		for _, g := range games {
+			if err := model.CheckMoves(g.Moves); err != nil {
+				t.Fatal(err)
+			}
+			g.Replay() // Illegal moves are reported, not a panic
+
+			written := writeMoves(g.Moves)
+			again, err := ParseGame(written)
+			if err != nil {
+				t.Fatalf("%q does not parse back: %v", written, err)
+			}
+			if !slices.Equal(again.Moves, g.Moves) {
+				t.Fatalf("%q parses back to %v, want %v", written, again.Moves, g.Moves)
+			}
+		}
+	})
+}
+
+// writeMoves writes moves as a GGF game, Black and White alternating
+func writeMoves(moves []model.Position) string {
+	var sb strings.Builder
+	sb.WriteString("(;GM[Othello]")
+	for i, m := range moves {
+		sb.WriteString([]string{"B[", "W["}[i%2])
+		if m == model.PassPosition {
+			sb.WriteString("pa")
+		} else {
+			sb.WriteString(model.FormatMove(m.Row, m.Col))
+		}
+		sb.WriteString("]")
+	}
+	sb.WriteString(";)")
+	return sb.String()
+}
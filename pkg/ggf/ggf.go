@@ -0,0 +1,214 @@
+// Package ggf reads Othello games in the Generic Game Format written by the
+// Generic Game Server and many Othello programs.
+//
+// A game is a list of properties between "(;" and ";)", e.g.
+//
+//	(;GM[Othello]PB[alice]PW[bob]RE[+4.000]BO[8 ---...--- *]B[d3//1.2]W[c5];)
+//
+// Moves are B and W properties holding a square, "pa" for a pass, optionally
+// followed by "/evaluation/time". Only games on the standard 8x8 board from
+// the standard starting position are supported.
+package ggf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Errors returned for games that cannot be read; use errors.Is to test for
+// them
+var (
+	ErrSyntax      = errors.New("malformed GGF game")
+	ErrUnsupported = errors.New("unsupported GGF game")
+)
+
+// Game is a single game record
+type Game struct {
+	Black  string // PB, the black player's name
+	White  string // PW
+	Date   string // DT
+	Result string // RE, Black's disc difference such as "+4.000"
+	Moves  []model.Position
+
+	// Props holds the first value of every property other than the moves
+	Props map[string]string
+}
+
+// Parse reads every game in r
+func Parse(r io.Reader) ([]*Game, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	s := string(data)
+
+	var games []*Game
+	for {
+		start := strings.Index(s, "(;")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start+2:], ";)") // Not the ';' of "(;"
+		if end < 0 {
+			return games, fmt.Errorf("game %d: %w: missing \";)\"", len(games)+1, ErrSyntax)
+		}
+		end += start + 4
+		game, err := ParseGame(s[start:end])
+		if err != nil {
+			return games, fmt.Errorf("game %d: %w", len(games)+1, err)
+		}
+		games = append(games, game)
+		s = s[end:]
+	}
+	if len(games) == 0 {
+		return nil, fmt.Errorf("%w: no game found", ErrSyntax)
+	}
+	return games, nil
+}
+
+// ParseGame reads one game, from "(;" to ";)"
+func ParseGame(s string) (*Game, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 4 || !strings.HasPrefix(s, "(;") || !strings.HasSuffix(s, ";)") {
+		return nil, fmt.Errorf("%w: a game starts with \"(;\" and ends with \";)\"", ErrSyntax)
+	}
+	body := s[2 : len(s)-2]
+
+	g := &Game{Props: make(map[string]string)}
+	for {
+		body = strings.TrimSpace(body)
+		if body == "" {
+			break
+		}
+		open := strings.IndexByte(body, '[')
+		if open <= 0 {
+			return nil, fmt.Errorf("%w: expected a property at %q", ErrSyntax, preview(body))
+		}
+		closing := strings.IndexByte(body[open:], ']')
+		if closing < 0 {
+			return nil, fmt.Errorf("%w: property %s is not closed", ErrSyntax, body[:open])
+		}
+		key := strings.ToUpper(strings.TrimSpace(body[:open]))
+		value := body[open+1 : open+closing]
+		body = body[open+closing+1:]
+
+		switch key {
+		case "B", "W":
+			move, err := parseMove(value)
+			if err != nil {
+				return nil, fmt.Errorf("move %d: %w", len(g.Moves)+1, err)
+			}
+			g.Moves = append(g.Moves, move)
+			continue
+		case "PB":
+			g.Black = value
+		case "PW":
+			g.White = value
+		case "DT":
+			g.Date = value
+		case "RE":
+			g.Result = value
+		}
+		if _, ok := g.Props[key]; !ok {
+			g.Props[key] = value
+		}
+	}
+
+	if err := g.checkSupported(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// parseMove reads a move such as "d3", "d3/-2.00/4.1" or "pa"
+func parseMove(value string) (model.Position, error) {
+	square, _, _ := strings.Cut(value, "/")
+	square = strings.TrimSpace(square)
+	if strings.EqualFold(square, "pa") {
+		return model.PassPosition, nil
+	}
+	row, col, err := model.ParseMove(square)
+	if err != nil {
+		return model.Position{}, fmt.Errorf("%w: %v", ErrSyntax, err)
+	}
+	if row < 0 {
+		return model.PassPosition, nil
+	}
+	return model.Position{Row: row, Col: col}, nil
+}
+
+// checkSupported rejects games of other kinds or starting elsewhere than
+// the standard position
+func (g *Game) checkSupported() error {
+	if gm, ok := g.Props["GM"]; ok && !strings.EqualFold(gm, "othello") {
+		return fmt.Errorf("%w: game type %q", ErrUnsupported, gm)
+	}
+	board, ok := g.Props["BO"]
+	if !ok {
+		return nil
+	}
+	fields := strings.Fields(board)
+	if len(fields) < 3 {
+		return fmt.Errorf("%w: board %q", ErrSyntax, board)
+	}
+	if fields[0] != "8" {
+		return fmt.Errorf("%w: board size %s", ErrUnsupported, fields[0])
+	}
+	squares := strings.Join(fields[1:len(fields)-1], "")
+	if squares+fields[len(fields)-1] != standardStart() {
+		return fmt.Errorf("%w: the game does not start from the standard position", ErrUnsupported)
+	}
+	return nil
+}
+
+// standardStart returns the standard starting position as written in the
+// BO property, Black to move
+func standardStart() string {
+	b := model.NewBoard()
+	var sb strings.Builder
+	for row := 0; row < b.Size; row++ {
+		for col := 0; col < b.Size; col++ {
+			sb.WriteByte(square(b.GetPiece(row, col)))
+		}
+	}
+	sb.WriteByte(square(b.CurrentPlayer))
+	return sb.String()
+}
+
+// square returns the character for a piece in a BO property
+func square(p model.Piece) byte {
+	switch p {
+	case model.Black:
+		return '*'
+	case model.White:
+		return 'O'
+	}
+	return '-'
+}
+
+// Replay plays the game's moves on a fresh game with full validation,
+// keeping the players' names and the date as metadata
+func (g *Game) Replay() (*model.Game, error) {
+	game := model.NewGame()
+	if ply, err := game.ReplayMoves(g.Moves); err != nil {
+		return game, fmt.Errorf("move %d: %w", ply+1, err)
+	}
+	for key, value := range map[string]string{"black.name": g.Black, "white.name": g.White, "date": g.Date} {
+		if value != "" {
+			game.SetMetadata(key, value)
+		}
+	}
+	return game, nil
+}
+
+// preview returns the start of s for error messages
+func preview(s string) string {
+	if len(s) > 20 {
+		return s[:20] + "..."
+	}
+	return s
+}
@@ -0,0 +1,197 @@
+// Package golden keeps a corpus of recorded games with the outcome the rules
+// gave them, and replays them to check the rules still agree, guarding
+// against subtle rule regressions.
+//
+// A corpus file has one game per line: its transcript, then operations
+// separated by semicolons, as in the position test suites. Blank lines and
+// lines starting with '#' are ignored.
+//
+//	F5D6C3D3C4F4F6F3E6E7... pos XXXXOOOO...XXXX O; discs 36 28; winner black; id "game 1";
+//
+// pos is the final position as written by Board.PositionString, discs the
+// final disc counts, winner "black", "white" or "draw", and id a name for the
+// game. A game is checked against every operation its line has.
+package golden
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Game is one game of a corpus and its expected outcome
+type Game struct {
+	ID         string
+	Line       int // Line number in the corpus file
+	Moves      []model.Position
+	Position   string // Final position; empty if not recorded
+	Black      int    // Final disc counts, -1 if not recorded
+	White      int
+	Winner     model.Piece // model.Empty for a draw
+	HasWinner  bool
+	transcript string
+}
+
+// Record describes a game for the corpus, with the outcome the rules gave
+// it. A game that ended early, e.g. by resignation, has no winner recorded.
+func Record(id string, game *model.Game) Game {
+	g := Game{
+		ID:         id,
+		Moves:      make([]model.Position, len(game.History)),
+		Position:   game.Board.PositionString(),
+		Winner:     game.Winner,
+		HasWinner:  game.GameOver && game.EndReason == "",
+		transcript: game.Transcript(),
+	}
+	g.Black, g.White = game.GetScore()
+	for i, m := range game.History {
+		g.Moves[i] = m.Position
+	}
+	return g
+}
+
+// String writes the game as a corpus line
+func (g Game) String() string {
+	var sb strings.Builder
+	sb.WriteString(g.transcript)
+	if g.Position != "" {
+		fmt.Fprintf(&sb, " pos %s;", g.Position)
+	}
+	if g.Black >= 0 {
+		fmt.Fprintf(&sb, " discs %d %d;", g.Black, g.White)
+	}
+	if g.HasWinner {
+		fmt.Fprintf(&sb, " winner %s;", winnerName(g.Winner))
+	}
+	if g.ID != "" {
+		fmt.Fprintf(&sb, " id %q;", g.ID)
+	}
+	return sb.String()
+}
+
+// ParseError reports a malformed corpus line
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Load reads a corpus file
+func Load(path string) ([]Game, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads a corpus
+func Parse(r io.Reader) ([]Game, error) {
+	var games []Game
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		game, err := parseLine(text)
+		if err != nil {
+			return nil, &ParseError{Line: line, Err: err}
+		}
+		game.Line = line
+		if game.ID == "" {
+			game.ID = fmt.Sprintf("line %d", line)
+		}
+		games = append(games, game)
+	}
+	return games, scanner.Err()
+}
+
+// parseLine parses the transcript and operations of one game
+func parseLine(text string) (Game, error) {
+	transcript, ops, _ := strings.Cut(text, " ")
+	moves, err := model.ParseTranscript(transcript)
+	if err != nil {
+		return Game{}, err
+	}
+	game := Game{Moves: moves, Black: -1, White: -1, transcript: transcript}
+
+	for _, op := range strings.Split(ops, ";") {
+		op = strings.TrimSpace(op)
+		if op == "" {
+			continue
+		}
+		name, args, _ := strings.Cut(op, " ")
+		args = strings.TrimSpace(args)
+		switch name {
+		case "pos":
+			board, err := model.ParsePosition(args)
+			if err != nil {
+				return Game{}, fmt.Errorf("pos: %w", err)
+			}
+			game.Position = board.PositionString()
+		case "discs":
+			fields := strings.Fields(args)
+			if len(fields) != 2 {
+				return Game{}, errors.New("discs: expected the black and white counts")
+			}
+			if game.Black, err = strconv.Atoi(fields[0]); err == nil {
+				game.White, err = strconv.Atoi(fields[1])
+			}
+			if err != nil {
+				return Game{}, fmt.Errorf("discs: %w", err)
+			}
+		case "winner":
+			if game.Winner, err = parseWinner(args); err != nil {
+				return Game{}, err
+			}
+			game.HasWinner = true
+		case "id":
+			game.ID = strings.Trim(args, `"`)
+		default:
+			// Unknown operations are kept by other tools; ignore them
+		}
+	}
+	return game, nil
+}
+
+// winnerName names a winner in a corpus line
+func winnerName(p model.Piece) string {
+	switch p {
+	case model.Black:
+		return "black"
+	case model.White:
+		return "white"
+	}
+	return "draw"
+}
+
+// parseWinner reads a winner written by winnerName
+func parseWinner(name string) (model.Piece, error) {
+	switch name {
+	case "black":
+		return model.Black, nil
+	case "white":
+		return model.White, nil
+	case "draw":
+		return model.Empty, nil
+	}
+	return model.Empty, fmt.Errorf("winner: unknown winner %q", name)
+}
@@ -0,0 +1,62 @@
+package golden
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Mismatch lists how a replayed game differs from its recorded outcome
+type Mismatch struct {
+	Game     Game
+	Problems []string
+}
+
+// Error implements the error interface
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("%s (line %d): %s", m.Game.ID, m.Game.Line, strings.Join(m.Problems, "; "))
+}
+
+// Check replays the game through the rules and compares the outcome with
+// the recorded one, returning a *Mismatch if they differ
+func Check(g Game) error {
+	m := &Mismatch{Game: g}
+	game := model.NewGame()
+	if ply, err := game.ReplayMoves(g.Moves); err != nil {
+		m.Problems = append(m.Problems, fmt.Sprintf("move %d: %v", ply+1, err))
+		return m
+	}
+
+	if g.Position != "" && game.Board.PositionString() != g.Position {
+		m.Problems = append(m.Problems, fmt.Sprintf("final position %s, expected %s", game.Board.PositionString(), g.Position))
+	}
+	if black, white := game.GetScore(); g.Black >= 0 && (black != g.Black || white != g.White) {
+		m.Problems = append(m.Problems, fmt.Sprintf("discs %d-%d, expected %d-%d", black, white, g.Black, g.White))
+	}
+	if g.HasWinner {
+		switch {
+		case !game.GameOver:
+			m.Problems = append(m.Problems, "the game is not over")
+		case game.Winner != g.Winner:
+			m.Problems = append(m.Problems, fmt.Sprintf("winner %s, expected %s", winnerName(game.Winner), winnerName(g.Winner)))
+		}
+	}
+
+	if len(m.Problems) > 0 {
+		return m
+	}
+	return nil
+}
+
+// Verify checks every game of a corpus, returning the games that no longer
+// replay as recorded
+func Verify(games []Game) []*Mismatch {
+	var failed []*Mismatch
+	for _, g := range games {
+		if err := Check(g); err != nil {
+			failed = append(failed, err.(*Mismatch))
+		}
+	}
+	return failed
+}
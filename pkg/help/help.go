@@ -0,0 +1,77 @@
+// Package help holds the rules summary and the controls reference shown by
+// the frontends, so every screen explains the game the same way.
+package help
+
+import "strings"
+
+// Section is a titled part of the rules
+type Section struct {
+	Title string
+	Lines []string // Paragraphs
+}
+
+// Binding describes what an input does
+type Binding struct {
+	Keys   string
+	Action string
+}
+
+// Rules summarizes how Othello is played
+var Rules = []Section{
+	{"Goal", []string{
+		"Finish with more discs of your color on the board than your opponent.",
+	}},
+	{"Setup", []string{
+		"The game starts with two black and two white discs crossed in the center. Black moves first.",
+	}},
+	{"Moves", []string{
+		"Place a disc so that one or more straight lines of opponent discs lie between it and another of your discs, horizontally, vertically or diagonally.",
+		"Every outflanked disc flips to your color. A move must flip at least one disc.",
+	}},
+	{"Passing", []string{
+		"A player without a legal move passes. Passing is not allowed while a legal move exists.",
+	}},
+	{"End of the game", []string{
+		"The game ends when neither player can move, usually when the board is full. The player with more discs wins; equal counts are a draw.",
+	}},
+}
+
+// GUIControls lists the graphical frontend's fixed controls; its keyboard
+// shortcuts can be remapped and are listed by the GUI itself
+var GUIControls = []Binding{
+	{"Click", "Place a disc on a highlighted square"},
+	{"Mouse wheel / drag", "Scroll lists and panels"},
+	{"Tab / Shift+Tab", "Move between buttons and fields"},
+	{"Enter", "Press the focused button"},
+	{"Esc", "Go back, or return to the main menu"},
+}
+
+// ConsoleControls lists the console frontend's commands
+var ConsoleControls = []Binding{
+	{"A1 ... H8", "Place a disc on that square"},
+	{"flip", "Turn the board around"},
+	{"help", "Show the rules and commands"},
+	{"quit", "Leave the game"},
+}
+
+// Text formats the rules and controls as plain text
+func Text(controls []Binding) string {
+	var sb strings.Builder
+	sb.WriteString("Rules\n")
+	for _, s := range Rules {
+		sb.WriteString("\n" + s.Title + "\n")
+		for _, line := range s.Lines {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+
+	width := 0
+	for _, b := range controls {
+		width = max(width, len(b.Keys))
+	}
+	sb.WriteString("\nControls\n\n")
+	for _, b := range controls {
+		sb.WriteString("  " + b.Keys + strings.Repeat(" ", width-len(b.Keys)+2) + b.Action + "\n")
+	}
+	return sb.String()
+}
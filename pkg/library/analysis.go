@@ -0,0 +1,227 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// winScale is the number of evaluation points per unit of the logistic
+// curve turning scores into expected results, as fitted by the tune command
+// on self-play games
+const winScale = 75
+
+// Analysis annotates every move of a game with the engine's opinion
+type Analysis struct {
+	Depth    int            `json:"depth"`
+	Analyzed time.Time      `json:"analyzed"`
+	Moves    []MoveAnalysis `json:"moves"` // Passes are left out
+}
+
+// MoveAnalysis compares a played move with the engine's choice, scored for
+// the side that moved
+type MoveAnalysis struct {
+	Ply    int    `json:"ply"` // 1-based index into the record's moves
+	Side   string `json:"side"`
+	Move   string `json:"move"`
+	Best   string `json:"best"`
+	Score  int    `json:"score"`  // Score of the best move
+	Played int    `json:"played"` // Score of the played move
+}
+
+// Loss returns how many discs the played move is expected to lose compared
+// with the best one
+func (m MoveAnalysis) Loss() float64 {
+	return max(ai.Discs(m.Score)-ai.Discs(m.Played), 0)
+}
+
+// Accuracy rates the move from 0 to 100 by the drop in expected result it
+// caused, on the curve chess sites use: no drop scores 100, a drop of ten
+// percentage points about 62
+func (m MoveAnalysis) Accuracy() float64 {
+	drop := 100 * (expectedResult(m.Score) - expectedResult(m.Played))
+	accuracy := 103.1668*math.Exp(-0.04354*max(drop, 0)) - 3.1669
+	return min(max(accuracy, 0), 100)
+}
+
+// expectedResult turns a score into the expected result between 0 and 1
+func expectedResult(score int) float64 {
+	return 1 / (1 + math.Exp(-float64(score)/winScale))
+}
+
+// Accuracy returns the mean accuracy of the given side's moves, false if it
+// made none
+func (a *Analysis) Accuracy(side model.Piece) (float64, bool) {
+	return a.mean(side, MoveAnalysis.Accuracy)
+}
+
+// AverageLoss returns the mean number of discs the given side's moves lost,
+// false if it made none
+func (a *Analysis) AverageLoss(side model.Piece) (float64, bool) {
+	return a.mean(side, MoveAnalysis.Loss)
+}
+
+// mean averages a measure over the given side's moves
+func (a *Analysis) mean(side model.Piece, measure func(MoveAnalysis) float64) (float64, bool) {
+	name := model.GetPieceName(side)
+	var sum float64
+	n := 0
+	for _, m := range a.Moves {
+		if m.Side == name {
+			sum += measure(m)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// AnalyzeRecord searches the position before every move of the game to
+// depth and compares each played move with the best one
+func AnalyzeRecord(ctx context.Context, record *model.GameRecord, depth, workers int) (*Analysis, error) {
+	game := model.NewGame()
+	boards := []*model.Board{game.Board.Clone()}
+	moves := make([]model.Position, len(record.Moves))
+	for i, move := range record.Moves {
+		row, col, err := model.ParseMove(move)
+		if err != nil {
+			return nil, fmt.Errorf("move %d (%q): %w", i+1, move, err)
+		}
+		moves[i] = model.Position{Row: row, Col: col}
+		if _, err := game.ReplayMoves(moves[i : i+1]); err != nil {
+			return nil, fmt.Errorf("move %d (%q): %w", i+1, move, err)
+		}
+		boards = append(boards, game.Board.Clone())
+	}
+
+	// The position after a move is searched one ply less, as it is within
+	// the search of the position before; equal depths would score the same
+	// move differently by whether the depth is odd or even
+	depth = max(depth, 2)
+	before := ai.EvaluatePositions(ctx, boards[:len(moves)], ai.BatchOptions{Depth: depth, Workers: workers})
+	after := ai.EvaluatePositions(ctx, boards[1:], ai.BatchOptions{Depth: depth - 1, Workers: workers})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	a := &Analysis{Depth: depth, Analyzed: time.Now()}
+	for i, move := range moves {
+		if move.Row < 0 {
+			continue
+		}
+		best, reply := before[i], after[i]
+		mover := boards[i].CurrentPlayer
+		played := reply.Score
+		if reply.Side != mover {
+			played = -played
+		}
+		a.Moves = append(a.Moves, MoveAnalysis{
+			Ply:    i + 1,
+			Side:   model.GetPieceName(mover),
+			Move:   record.Moves[i],
+			Best:   model.FormatMove(best.Best.Row, best.Best.Col),
+			Score:  best.Score,
+			Played: played,
+		})
+	}
+	return a, nil
+}
+
+// Analyzer analyzes the library's games in the background, newest first,
+// and again whenever the depth is raised
+type Analyzer struct {
+	lib *Library
+
+	mu      sync.Mutex
+	depth   int // 0 pauses the analyzer
+	current string
+	wake    chan struct{}
+}
+
+// NewAnalyzer creates an analyzer searching to the given depth
+func NewAnalyzer(lib *Library, depth int) *Analyzer {
+	return &Analyzer{lib: lib, depth: depth, wake: make(chan struct{}, 1)}
+}
+
+// SetDepth changes the depth games are analyzed to; 0 pauses the analyzer
+func (a *Analyzer) SetDepth(depth int) {
+	a.mu.Lock()
+	a.depth = depth
+	a.mu.Unlock()
+
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Current returns the ID of the game being analyzed, empty when idle
+func (a *Analyzer) Current() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.current
+}
+
+// Pending reports whether the game still needs analysis at the current depth
+func (a *Analyzer) Pending(g *Game) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.pending(g)
+}
+
+// pending is Pending for a caller holding the lock
+func (a *Analyzer) pending(g *Game) bool {
+	return a.depth > 0 && (g.Analysis == nil || g.Analysis.Depth < a.depth)
+}
+
+// next returns the newest game needing analysis and the depth to use
+func (a *Analyzer) next() (*Game, int) {
+	games := a.lib.List()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, g := range games {
+		if a.pending(g) {
+			a.current = g.ID
+			return g, a.depth
+		}
+	}
+	return nil, 0
+}
+
+// Run analyzes games until ctx is cancelled, one search at a time so the
+// rest of the program stays responsive
+func (a *Analyzer) Run(ctx context.Context) {
+	for ctx.Err() == nil {
+		changed := a.lib.Changed()
+		g, depth := a.next()
+		if g == nil {
+			select {
+			case <-ctx.Done():
+			case <-changed:
+			case <-a.wake:
+			}
+			continue
+		}
+
+		analysis, err := AnalyzeRecord(ctx, g.Record, depth, 1)
+		if err == nil {
+			err = a.lib.SetAnalysis(g.ID, analysis)
+		}
+		a.mu.Lock()
+		a.current = ""
+		a.mu.Unlock()
+		if err != nil && ctx.Err() == nil {
+			logging.For("library").Warn("game analysis failed", "game", g.ID, "err", err)
+			// Keep the game from being retried forever
+			a.lib.SetAnalysis(g.ID, &Analysis{Depth: depth, Analyzed: time.Now()})
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package library
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Metadata keys counting the help a side had during a game, prefixed with
+// its color, e.g. "black.hints"
+const (
+	MetaHints = "hints"
+	MetaUndos = "undos"
+)
+
+// assistKey returns the metadata key for the given color
+func assistKey(side model.Piece, key string) string {
+	return strings.ToLower(model.GetPieceName(side)) + "." + key
+}
+
+// Assistance returns the hints and undos a side used, as recorded in a
+// game's metadata
+func Assistance(md map[string]string, side model.Piece) (hints, undos int) {
+	hints, _ = strconv.Atoi(md[assistKey(side, MetaHints)])
+	undos, _ = strconv.Atoi(md[assistKey(side, MetaUndos)])
+	return hints, undos
+}
+
+// AddAssistance records that a side used one more hint or undo, key being
+// MetaHints or MetaUndos, and returns how many it has used
+func AddAssistance(game *model.Game, side model.Piece, key string) int {
+	n, _ := strconv.Atoi(game.Metadata[assistKey(side, key)])
+	n++
+	game.SetMetadata(assistKey(side, key), strconv.Itoa(n))
+	return n
+}
+
+// DescribeAssistance describes the help a side had, e.g. "2 hints, 1 undo",
+// or returns "" when it had none
+func DescribeAssistance(md map[string]string, side model.Piece) string {
+	hints, undos := Assistance(md, side)
+	var parts []string
+	if hints > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", hints, plural(hints, "hint")))
+	}
+	if undos > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", undos, plural(undos, "undo")))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Assisted reports whether a side used hints or undos in the game
+func (g *Game) Assisted(side model.Piece) bool {
+	hints, undos := Assistance(g.Record.Metadata, side)
+	return hints > 0 || undos > 0
+}
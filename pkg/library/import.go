@@ -0,0 +1,129 @@
+package library
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// ImportStats counts what became of the games of an import
+type ImportStats struct {
+	Added      int
+	Duplicates int // Games already in the library or earlier in the import
+	Symmetric  int // Duplicates played in a rotated or reflected orientation
+	Merged     int // Duplicates whose metadata filled in the stored game's
+}
+
+// String summarizes the import, e.g. "added 12 games, skipped 3 duplicates"
+func (s ImportStats) String() string {
+	summary := fmt.Sprintf("added %d %s", s.Added, plural(s.Added, "game"))
+	if s.Duplicates == 0 {
+		return summary
+	}
+	summary += fmt.Sprintf(", skipped %d %s", s.Duplicates, plural(s.Duplicates, "duplicate"))
+	if s.Symmetric > 0 {
+		summary += fmt.Sprintf(" (%d rotated or reflected)", s.Symmetric)
+	}
+	if s.Merged > 0 {
+		summary += fmt.Sprintf(", filled in the details of %d", s.Merged)
+	}
+	return summary
+}
+
+// plural returns the word with an s unless n is one
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// Import adds games, leaving out those with the same moves as a stored game
+// or one earlier in the import, also when rotated or reflected. Instead,
+// metadata a duplicate has and the stored game lacks, such as the players'
+// names from another database, is added to the stored game.
+func (l *Library) Import(records []*model.GameRecord) (ImportStats, error) {
+	var stats ImportStats
+	byKey := make(map[uint64]*Game)
+	for _, g := range l.List() {
+		byKey[l.indexOf(g).key] = g
+	}
+
+	for _, record := range records {
+		index := newGameIndex(&Game{Record: record})
+		if stored, ok := byKey[index.key]; ok {
+			stats.Duplicates++
+			if !slices.Equal(stored.Record.Moves, record.Moves) {
+				stats.Symmetric++
+			}
+			merged, err := l.merge(stored.ID, record)
+			if err != nil {
+				return stats, err
+			}
+			if merged != nil {
+				stats.Merged++
+				byKey[index.key] = merged
+			}
+			continue
+		}
+
+		g, err := l.Add(record)
+		if err != nil {
+			return stats, err
+		}
+		l.mu.Lock()
+		if l.index == nil {
+			l.index = make(map[string]*gameIndex)
+		}
+		l.index[g.ID] = index
+		l.mu.Unlock()
+		byKey[index.key] = g
+		stats.Added++
+	}
+	return stats, nil
+}
+
+// merge adds the result and metadata of a duplicate that the stored game
+// lacks. It returns the updated game, or nil when nothing was missing.
+func (l *Library) merge(id string, duplicate *model.GameRecord) (*Game, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	g, ok := l.games[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	record := *g.Record
+	record.Metadata = make(map[string]string, len(g.Record.Metadata))
+	for key, value := range g.Record.Metadata {
+		record.Metadata[key] = value
+	}
+	changed := false
+	if record.Result == "" && duplicate.Result != "" {
+		record.Result = duplicate.Result
+		changed = true
+	}
+	for key, value := range duplicate.Metadata {
+		if _, ok := record.Metadata[key]; !ok && value != "" {
+			record.Metadata[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil, nil
+	}
+
+	// Work on a copy so a failed save leaves the game untouched
+	updated := *g
+	updated.Record = &record
+	if err := l.save(&updated); err != nil {
+		return nil, err
+	}
+	l.games[id] = &updated
+	delete(l.index, id) // Its text now holds the new metadata
+	l.notify()
+	snapshot := updated
+	return &snapshot, nil
+}
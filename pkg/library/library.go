@@ -0,0 +1,199 @@
+// Package library keeps the player's finished games in a directory together
+// with their analysis, which an Analyzer fills in in the background.
+package library
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// ErrNotFound is returned for unknown game IDs
+var ErrNotFound = errors.New("game not found")
+
+// Game is a stored game
+type Game struct {
+	ID       string            `json:"id"`
+	Saved    time.Time         `json:"saved"`
+	Record   *model.GameRecord `json:"record"`
+	Analysis *Analysis         `json:"analysis,omitempty"` // nil until analyzed
+}
+
+// PlayerName describes who played the given color: the name recorded in an
+// imported game, or e.g. "AI (hard)"
+func (g *Game) PlayerName(side model.Piece) string {
+	prefix := strings.ToLower(model.GetPieceName(side)) + "."
+	if name := g.Record.Metadata[prefix+"name"]; name != "" {
+		return name
+	}
+	if difficulty, ok := g.Record.Metadata[prefix+ai.MetaDifficulty]; ok {
+		return "AI (" + difficulty + ")"
+	}
+	return "Human"
+}
+
+// Library keeps games as JSON files in a directory
+type Library struct {
+	dir     string
+	mu      sync.Mutex
+	games   map[string]*Game
+	index   map[string]*gameIndex // Built as games are searched
+	changed chan struct{}         // Closed and replaced whenever a game changes
+}
+
+// DefaultDir returns the library directory in the user's config directory
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "library"
+	}
+	return filepath.Join(dir, "othello", "library")
+}
+
+// Open loads every game from the directory, creating it if needed
+func Open(dir string) (*Library, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	l := &Library{
+		dir:     dir,
+		games:   make(map[string]*Game),
+		changed: make(chan struct{}),
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var g Game
+		if err := json.Unmarshal(data, &g); err != nil {
+			return nil, err
+		}
+		l.games[g.ID] = &g
+	}
+
+	return l, nil
+}
+
+// Add stores a game
+func (l *Library) Add(record *model.GameRecord) (*Game, error) {
+	id, err := newID()
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	g := &Game{ID: id, Saved: time.Now(), Record: record}
+	if err := l.save(g); err != nil {
+		return nil, err
+	}
+	l.games[id] = g
+	l.notify()
+
+	snapshot := *g
+	return &snapshot, nil
+}
+
+// Get returns a copy of a game
+func (l *Library) Get(id string) (*Game, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	g, ok := l.games[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	snapshot := *g
+	return &snapshot, nil
+}
+
+// List returns copies of every game, most recently saved first
+func (l *Library) List() []*Game {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	games := make([]*Game, 0, len(l.games))
+	for _, g := range l.games {
+		snapshot := *g
+		games = append(games, &snapshot)
+	}
+
+	sort.Slice(games, func(i, j int) bool { return games[i].Saved.After(games[j].Saved) })
+	return games
+}
+
+// SetAnalysis stores the analysis of a game
+func (l *Library) SetAnalysis(id string, a *Analysis) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	g, ok := l.games[id]
+	if !ok {
+		return ErrNotFound
+	}
+
+	// Work on a copy so a failed save leaves the game untouched
+	updated := *g
+	updated.Analysis = a
+	if err := l.save(&updated); err != nil {
+		return err
+	}
+	l.games[id] = &updated
+	l.notify()
+	return nil
+}
+
+// Changed returns a channel that is closed at the next change to any game
+func (l *Library) Changed() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.changed
+}
+
+// notify wakes everyone waiting on Changed; the caller holds the lock
+func (l *Library) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// save writes a game file atomically
+func (l *Library) save(g *Game) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(l.dir, g.ID+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// newID returns a random game identifier
+func newID() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
@@ -0,0 +1,251 @@
+package library
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/render"
+)
+
+// diagramEvery is the number of moves between the diagrams of a printed
+// record
+const diagramEvery = 10
+
+// blunderLoss is the number of discs a move must lose to be marked in a
+// printed record
+const blunderLoss = 6
+
+// printedRecord is the template of a printable game record. The style sheet
+// lays it out for A4 paper; printing it from a browser makes the PDF.
+var printedRecord = template.Must(template.New("record").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+@page { size: A4; margin: 15mm; }
+body { font-family: serif; margin: 2em; color: #000; }
+h1 { margin-bottom: 0.2em; }
+h2 { margin-top: 1.5em; border-bottom: 1px solid #999; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #bbb; padding: 2px 8px; text-align: left; }
+.header th { background: #eee; width: 8em; }
+.moves { column-count: 2; column-gap: 2em; }
+.moves table { width: 100%; break-inside: auto; }
+.moves tr { break-inside: avoid; }
+.moves td:first-child { text-align: right; }
+.loss { color: #b00; font-weight: bold; }
+.diagrams { display: flex; flex-wrap: wrap; gap: 1em; }
+figure { margin: 0; break-inside: avoid; text-align: center; }
+figure svg { width: 200px; height: 200px; }
+.graph svg { width: 100%; height: auto; }
+@media print { body { margin: 0; } h2 { break-after: avoid; } }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table class="header">
+{{range .Header}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+<h2>Moves</h2>
+<div class="moves">
+<table>
+<tr><th>#</th><th>Side</th><th>Move</th><th>Discs</th>{{if .Analyzed}}<th>Best</th><th>Lost</th>{{end}}</tr>
+{{range .Moves}}<tr><td>{{.Number}}</td><td>{{.Side}}</td><td>{{.Move}}</td><td>{{.Discs}}</td>{{if $.Analyzed}}<td>{{.Best}}</td><td{{if .Blunder}} class="loss"{{end}}>{{.Loss}}</td>{{end}}</tr>
+{{end}}</table>
+</div>
+<h2>Diagrams</h2>
+<div class="diagrams">
+{{range .Diagrams}}<figure>{{.SVG}}<figcaption>{{.Caption}}</figcaption></figure>
+{{end}}</div>
+<h2>Evaluation</h2>
+{{if .Graph}}<div class="graph">{{.Graph}}</div>
+<p>Black's expected final disc margin before each move, searched to depth {{.Depth}}; above the line Black is ahead.</p>
+{{else}}<p>The game has not been analyzed.</p>{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the game as a standalone page to print and archive: the
+// players and result, a numbered move table, a diagram every ten moves and,
+// once analyzed, the evaluation graph
+func (g *Game) WriteHTML(w io.Writer) error {
+	type field struct{ Label, Value string }
+	type moveRow struct {
+		Number, Side, Move, Discs, Best, Loss string
+		Blunder                               bool
+	}
+	type diagram struct {
+		Caption string
+		SVG     template.HTML
+	}
+
+	record := g.Record
+	black, white := g.PlayerName(model.Black), g.PlayerName(model.White)
+	data := struct {
+		Title    string
+		Header   []field
+		Analyzed bool
+		Depth    int
+		Moves    []moveRow
+		Diagrams []diagram
+		Graph    template.HTML
+	}{Title: black + " vs " + white, Analyzed: g.Analysis != nil}
+
+	date := record.Metadata["date"]
+	if date == "" && !g.Saved.IsZero() {
+		date = g.Saved.Format("2006-01-02 15:04")
+	}
+	result := record.Result
+	if result == "" {
+		result = "Unfinished"
+	}
+	data.Header = []field{{"Black", black}, {"White", white}, {"Date", date}, {"Result", result}}
+
+	analyzed := make(map[int]MoveAnalysis)
+	if a := g.Analysis; a != nil {
+		data.Depth = a.Depth
+		for _, m := range a.Moves {
+			analyzed[m.Ply] = m
+		}
+		var parts []string
+		for _, side := range []model.Piece{model.Black, model.White} {
+			if accuracy, ok := a.Accuracy(side); ok {
+				loss, _ := a.AverageLoss(side)
+				parts = append(parts, fmt.Sprintf("%s %.0f%% (%.1f discs lost per move)", model.GetPieceName(side), accuracy, loss))
+			}
+		}
+		data.Header = append(data.Header, field{"Accuracy", strings.Join(parts, ", ")})
+	}
+
+	opts := render.Options{CellSize: 32, Coordinates: true}
+	game := model.NewGame()
+	labels := make(map[model.Position]string)
+	number := 0
+	for i, move := range record.Moves {
+		row, col, err := model.ParseMove(move)
+		if err != nil {
+			return fmt.Errorf("move %d (%q): %w", i+1, move, err)
+		}
+		p := model.Position{Row: row, Col: col}
+		side := game.Board.CurrentPlayer
+		if _, err := game.ReplayMoves([]model.Position{p}); err != nil {
+			return fmt.Errorf("move %d (%q): %w", i+1, move, err)
+		}
+
+		blackDiscs, whiteDiscs := game.GetScore()
+		r := moveRow{Side: model.GetPieceName(side), Move: move, Discs: fmt.Sprintf("%d-%d", blackDiscs, whiteDiscs)}
+		if row < 0 {
+			r.Move = "pass"
+		} else {
+			number++
+			r.Number = fmt.Sprint(number)
+			labels[p] = fmt.Sprint(number)
+		}
+		if m, ok := analyzed[i+1]; ok {
+			r.Best = m.Best
+			r.Loss = fmt.Sprintf("%.1f", m.Loss())
+			r.Blunder = m.Loss() >= blunderLoss
+		}
+		data.Moves = append(data.Moves, r)
+
+		// A diagram closes every stretch of ten moves and the game, numbering
+		// the moves played in it
+		last := i == len(record.Moves)-1
+		if (row >= 0 && number%diagramEvery == 0) || (last && len(labels) > 0) {
+			first := (number-1)/diagramEvery*diagramEvery + 1
+			opts.LastMove = &p
+			if row < 0 {
+				opts.LastMove = nil
+			}
+			var svg bytes.Buffer
+			if err := render.SVG(&svg, game.Board, opts, render.Annotations{Labels: labels}); err != nil {
+				return err
+			}
+			data.Diagrams = append(data.Diagrams, diagram{
+				Caption: fmt.Sprintf("Moves %d-%d: Black %d, White %d", first, number, blackDiscs, whiteDiscs),
+				SVG:     template.HTML(svg.String()),
+			})
+			labels = make(map[model.Position]string)
+		}
+	}
+
+	if g.Analysis != nil && len(g.Analysis.Moves) > 0 {
+		data.Graph = template.HTML(evaluationGraph(g.Analysis, game))
+	}
+	return printedRecord.Execute(w, data)
+}
+
+// evaluationGraph draws Black's expected disc margin across the game as an
+// SVG line chart, ending at the final margin when the game is over
+func evaluationGraph(a *Analysis, end *model.Game) string {
+	const width, height, pad = 600, 200, 30
+	type point struct {
+		ply    int
+		margin float64
+	}
+	var points []point
+	for _, m := range a.Moves {
+		margin := ai.Discs(m.Score)
+		if m.Side != model.GetPieceName(model.Black) {
+			margin = -margin
+		}
+		points = append(points, point{m.Ply - 1, margin})
+	}
+	plies := len(end.History)
+	if end.GameOver {
+		b, w := end.GetScore()
+		points = append(points, point{plies, float64(b - w)})
+	}
+
+	// Scale to the largest margin, in steps of eight discs
+	top := 8.0
+	for _, p := range points {
+		top = max(top, math.Ceil(math.Abs(p.margin)/8)*8)
+	}
+	top = min(top, 64)
+	x := func(ply int) float64 { return pad + float64(ply)*(width-2*pad)/float64(max(plies, 1)) }
+	y := func(margin float64) float64 {
+		return height/2 - max(min(margin, top), -top)*(height/2-pad/2)/top
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" font-family="sans-serif" font-size="10">`+"\n", width, height)
+	fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="#999"/>`+"\n", pad, pad/2, width-2*pad, height-pad)
+	fmt.Fprintf(&sb, `<line x1="%d" y1="%g" x2="%d" y2="%g" stroke="#999" stroke-dasharray="4 3"/>`+"\n", pad, y(0), width-pad, y(0))
+	fmt.Fprintf(&sb, `<text x="%d" y="%g" text-anchor="end" dominant-baseline="central">+%g</text>`+"\n", pad-4, y(top), top)
+	fmt.Fprintf(&sb, `<text x="%d" y="%g" text-anchor="end" dominant-baseline="central">0</text>`+"\n", pad-4, y(0))
+	fmt.Fprintf(&sb, `<text x="%d" y="%g" text-anchor="end" dominant-baseline="central">-%g</text>`+"\n", pad-4, y(-top), top)
+	for ply := diagramEvery; ply < plies; ply += diagramEvery {
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%d" text-anchor="middle">%d</text>`+"\n", x(ply), height-2, ply)
+	}
+
+	sb.WriteString(`<polyline fill="none" stroke="#000" stroke-width="1.5" points="`)
+	for i, p := range points {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%.1f,%.1f", x(p.ply), y(p.margin))
+	}
+	sb.WriteString(`"/>` + "\n")
+
+	// Blunders are marked where they were played
+	for _, m := range a.Moves {
+		if m.Loss() < blunderLoss {
+			continue
+		}
+		margin := ai.Discs(m.Played)
+		if m.Side != model.GetPieceName(model.Black) {
+			margin = -margin
+		}
+		fmt.Fprintf(&sb, `<circle cx="%.1f" cy="%.1f" r="3" fill="#b00"><title>%s %s</title></circle>`+"\n", x(m.Ply), y(margin), m.Side, m.Move)
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
@@ -0,0 +1,283 @@
+package library
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Query selects games from the library; empty fields match every game
+type Query struct {
+	Text    string           // Words to find anywhere in the players, result, metadata or moves
+	Player  string           // Part of either player's name
+	Result  string           // "black", "white" or "draw" for who won, or the score such as "36-28"
+	Opening []model.Position // Moves the game starts with, up to symmetry
+	After   time.Time        // Played on or after this day
+	Before  time.Time        // Played before this day
+	Board   *model.Board     // A position the game passes through, up to symmetry
+}
+
+// gameIndex is what searches look at in a game, built the first time it is
+// searched
+type gameIndex struct {
+	text      string   // Lower-case players, result, metadata and moves
+	positions []uint64 // Canonical hash after each ply, the start first
+	key       uint64   // Same for games with the same moves up to symmetry
+}
+
+// dateLayouts are the layouts the date metadata of imported games is read
+// with
+var dateLayouts = []string{"2006-01-02", "2006.01.02", "2006/01/02", "2006-01-02 15:04:05"}
+
+// Date returns when the game was played: its recorded date when it was
+// imported with one, otherwise when it was saved
+func (g *Game) Date() time.Time {
+	if date := g.Record.Metadata["date"]; date != "" {
+		for _, layout := range dateLayouts {
+			if t, err := time.ParseInLocation(layout, date, time.Local); err == nil {
+				return t
+			}
+		}
+	}
+	return g.Saved
+}
+
+// Winner returns the side that won a finished game, model.Empty for a draw,
+// and false while the result is unknown
+func (g *Game) Winner() (model.Piece, bool) {
+	var black, white int
+	if _, err := fmt.Sscanf(g.Record.Result, "%d-%d", &black, &white); err != nil {
+		return model.Empty, false
+	}
+	switch {
+	case black > white:
+		return model.Black, true
+	case white > black:
+		return model.White, true
+	}
+	return model.Empty, true
+}
+
+// Search returns the games matching the query, most recently saved first
+func (l *Library) Search(q Query) []*Game {
+	var opening uint64
+	if len(q.Opening) > 0 {
+		game := model.NewGame()
+		if _, err := game.ReplayMoves(q.Opening); err != nil {
+			return nil
+		}
+		opening = model.CanonicalHash(game.Board)
+	}
+	var board uint64
+	if q.Board != nil {
+		board = model.CanonicalHash(q.Board)
+	}
+	words := strings.Fields(strings.ToLower(q.Text))
+	player := strings.ToLower(q.Player)
+
+	var found []*Game
+	for _, g := range l.List() {
+		if !q.After.IsZero() && g.Date().Before(q.After) {
+			continue
+		}
+		if !q.Before.IsZero() && !g.Date().Before(q.Before) {
+			continue
+		}
+		if player != "" && !strings.Contains(strings.ToLower(g.PlayerName(model.Black)), player) &&
+			!strings.Contains(strings.ToLower(g.PlayerName(model.White)), player) {
+			continue
+		}
+		if q.Result != "" && !g.hasResult(q.Result) {
+			continue
+		}
+
+		index := l.indexOf(g)
+		if !containsAll(index.text, words) {
+			continue
+		}
+		if len(q.Opening) > 0 && (len(index.positions) <= len(q.Opening) || index.positions[len(q.Opening)] != opening) {
+			continue
+		}
+		if q.Board != nil && index.ply(board) < 0 {
+			continue
+		}
+		found = append(found, g)
+	}
+	return found
+}
+
+// PositionPly returns the first ply at which a game passes through the
+// position or one of its rotations and reflections, or -1 if it never does
+func (l *Library) PositionPly(g *Game, b *model.Board) int {
+	return l.indexOf(g).ply(model.CanonicalHash(b))
+}
+
+// hasResult reports whether the game ended as described: "black", "white"
+// or "draw" for who won, or the exact score
+func (g *Game) hasResult(result string) bool {
+	winner, ok := g.Winner()
+	switch strings.ToLower(result) {
+	case "black":
+		return ok && winner == model.Black
+	case "white":
+		return ok && winner == model.White
+	case "draw":
+		return ok && winner == model.Empty
+	}
+	return g.Record.Result == result
+}
+
+// ply returns the first ply at which the game reaches the position with the
+// given canonical hash, or -1
+func (x *gameIndex) ply(hash uint64) int {
+	for i, h := range x.positions {
+		if h == hash {
+			return i
+		}
+	}
+	return -1
+}
+
+// indexOf returns the index of a game, building it on first use
+func (l *Library) indexOf(g *Game) *gameIndex {
+	l.mu.Lock()
+	index, ok := l.index[g.ID]
+	l.mu.Unlock()
+	if ok {
+		return index
+	}
+
+	index = newGameIndex(g)
+	l.mu.Lock()
+	if l.index == nil {
+		l.index = make(map[string]*gameIndex)
+	}
+	l.index[g.ID] = index
+	l.mu.Unlock()
+	return index
+}
+
+// newGameIndex indexes the text and positions of a game. The positions stop
+// at an illegal move, so a damaged record is still found by its start.
+func newGameIndex(g *Game) *gameIndex {
+	var sb strings.Builder
+	for _, side := range []model.Piece{model.Black, model.White} {
+		sb.WriteString(g.PlayerName(side) + " ")
+	}
+	sb.WriteString(g.Record.Result + " ")
+	for _, value := range g.Record.Metadata {
+		sb.WriteString(value + " ")
+	}
+	sb.WriteString(strings.Join(g.Record.Moves, ""))
+
+	index := &gameIndex{text: strings.ToLower(sb.String())}
+	game := model.NewGame()
+	index.positions = append(index.positions, model.CanonicalHash(game.Board))
+	for _, move := range g.Record.Moves {
+		row, col, err := model.ParseMove(move)
+		if err != nil {
+			break
+		}
+		if _, err := game.ReplayMoves([]model.Position{{Row: row, Col: col}}); err != nil {
+			break
+		}
+		index.positions = append(index.positions, model.CanonicalHash(game.Board))
+	}
+
+	// The positions of a damaged record only cover its start, so its other
+	// moves are part of the key as written
+	h := fnv.New64a()
+	var buf [8]byte
+	for _, p := range index.positions {
+		binary.LittleEndian.PutUint64(buf[:], p)
+		h.Write(buf[:])
+	}
+	if len(index.positions) <= len(g.Record.Moves) {
+		h.Write([]byte(strings.Join(g.Record.Moves[len(index.positions)-1:], "")))
+	}
+	index.key = h.Sum64()
+	return index
+}
+
+// containsAll reports whether the text holds every word
+func containsAll(text string, words []string) bool {
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseQuery reads a search typed as words and filters, e.g.
+//
+//	player:alice result:black opening:f5d6c3 after:2024-01-01 endgame
+//
+// Filters are player, result, opening, after, before and pos, a position as
+// written by Board.PositionString; values with spaces go in double quotes.
+// The remaining words are searched for as text.
+func ParseQuery(s string) (Query, error) {
+	var q Query
+	var text []string
+	for _, token := range splitQuery(s) {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || value == "" {
+			text = append(text, token)
+			continue
+		}
+		var err error
+		switch strings.ToLower(key) {
+		case "player":
+			q.Player = value
+		case "result":
+			q.Result = value
+		case "opening":
+			q.Opening, err = model.ParseTranscript(value)
+		case "after":
+			q.After, err = time.ParseInLocation("2006-01-02", value, time.Local)
+		case "before":
+			q.Before, err = time.ParseInLocation("2006-01-02", value, time.Local)
+		case "pos":
+			q.Board, err = model.ParsePosition(value)
+		default:
+			text = append(text, token)
+		}
+		if err != nil {
+			return q, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	q.Text = strings.Join(text, " ")
+	return q, nil
+}
+
+// splitQuery splits a search into words, keeping text in double quotes
+// together without the quotes
+func splitQuery(s string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted, started := false, false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted, started = !quoted, true
+		case unicode.IsSpace(r) && !quoted:
+			if started {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
@@ -0,0 +1,117 @@
+package library
+
+import (
+	"sort"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// GameStat is how one side played in one analyzed game
+type GameStat struct {
+	ID          string
+	Saved       time.Time
+	Side        model.Piece
+	Accuracy    float64
+	AverageLoss float64 // Discs lost per move
+	Won         bool
+	Assisted    bool // The side used hints or undos
+}
+
+// PlayerStats collects a player's analyzed games
+type PlayerStats struct {
+	Player string     // As named by Game.PlayerName
+	Games  []GameStat // Oldest first
+}
+
+// Stats groups the sides of the analyzed games by player, the player with
+// the most games first
+func Stats(games []*Game) []PlayerStats {
+	byPlayer := make(map[string]*PlayerStats)
+	for _, g := range games {
+		if g.Analysis == nil {
+			continue
+		}
+		for _, side := range []model.Piece{model.Black, model.White} {
+			accuracy, ok := g.Analysis.Accuracy(side)
+			if !ok {
+				continue
+			}
+			loss, _ := g.Analysis.AverageLoss(side)
+
+			name := g.PlayerName(side)
+			s, ok := byPlayer[name]
+			if !ok {
+				s = &PlayerStats{Player: name}
+				byPlayer[name] = s
+			}
+			winner, finished := g.Winner()
+			s.Games = append(s.Games, GameStat{
+				ID: g.ID, Saved: g.Saved, Side: side, Accuracy: accuracy, AverageLoss: loss,
+				Won: finished && winner == side, Assisted: g.Assisted(side),
+			})
+		}
+	}
+
+	stats := make([]PlayerStats, 0, len(byPlayer))
+	for _, s := range byPlayer {
+		sort.Slice(s.Games, func(i, j int) bool { return s.Games[i].Saved.Before(s.Games[j].Saved) })
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if len(stats[i].Games) != len(stats[j].Games) {
+			return len(stats[i].Games) > len(stats[j].Games)
+		}
+		return stats[i].Player < stats[j].Player
+	})
+	return stats
+}
+
+// Accuracy returns the mean accuracy over the player's games
+func (s PlayerStats) Accuracy() float64 {
+	return meanOf(s.Games, func(g GameStat) float64 { return g.Accuracy })
+}
+
+// AverageLoss returns the mean discs lost per move over the player's games
+func (s PlayerStats) AverageLoss() float64 {
+	return meanOf(s.Games, func(g GameStat) float64 { return g.AverageLoss })
+}
+
+// Wins counts the player's won games and how many of them were won with
+// hints or undos
+func (s PlayerStats) Wins() (wins, assisted int) {
+	for _, g := range s.Games {
+		if g.Won {
+			wins++
+			if g.Assisted {
+				assisted++
+			}
+		}
+	}
+	return wins, assisted
+}
+
+// Trend compares the average loss of the last n games with the n games
+// before them; a negative change means the player improved. ok is false
+// until the player has 2n games.
+func (s PlayerStats) Trend(n int) (change float64, ok bool) {
+	if n <= 0 || len(s.Games) < 2*n {
+		return 0, false
+	}
+	loss := func(g GameStat) float64 { return g.AverageLoss }
+	recent := s.Games[len(s.Games)-n:]
+	before := s.Games[len(s.Games)-2*n : len(s.Games)-n]
+	return meanOf(recent, loss) - meanOf(before, loss), true
+}
+
+// meanOf averages a measure over games
+func meanOf(games []GameStat, measure func(GameStat) float64) float64 {
+	if len(games) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, g := range games {
+		sum += measure(g)
+	}
+	return sum / float64(len(games))
+}
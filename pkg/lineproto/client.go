@@ -0,0 +1,154 @@
+package lineproto
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// ErrUnexpected is returned when the server sends something the client
+// does not understand
+var ErrUnexpected = errors.New("unexpected message from server")
+
+// Strategy chooses the move to play; board is the current position and
+// always has at least one legal move for the side to move
+type Strategy func(board *model.Board) (row, col int)
+
+// Result is the outcome of a game reported by the server
+type Result struct {
+	Black, White int
+	Winner       string // "black", "white" or "draw"
+	Reason       string // Empty for a normal finish
+}
+
+// Client is the reference bot implementation
+// It is deliberately simple so students can read it before writing their
+// own in any language.
+type Client struct {
+	Color    model.Piece
+	Opponent string
+
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+// Dial connects to a server and introduces the bot by name
+func Dial(addr, name string) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: conn, scanner: bufio.NewScanner(conn)}
+	greeting, err := c.read()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if greeting != Greeting {
+		conn.Close()
+		return nil, fmt.Errorf("%w: %q", ErrUnexpected, greeting)
+	}
+	if err := c.send("%s %s", CmdName, name); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Play plays one game with the given strategy and returns its result
+func (c *Client) Play(strategy Strategy) (Result, error) {
+	defer c.conn.Close()
+
+	for {
+		line, err := c.read()
+		if err != nil {
+			return Result{}, err
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case MsgGame:
+			if len(fields) != 3 {
+				return Result{}, fmt.Errorf("%w: %q", ErrUnexpected, line)
+			}
+			c.Color, _ = ParseColor(fields[1])
+			c.Opponent = fields[2]
+		case MsgYourMove:
+			if err := c.move(strategy); err != nil {
+				return Result{}, err
+			}
+		case MsgGameOver:
+			return parseResult(fields)
+		case MsgMoved, MsgOK:
+			// Moves are tracked by asking for the board when needed
+		case MsgErr:
+			return Result{}, fmt.Errorf("server: %s", strings.TrimPrefix(line, MsgErr+" "))
+		}
+	}
+}
+
+// move asks for the position, picks a move and sends it
+func (c *Client) move(strategy Strategy) error {
+	if err := c.send(CmdBoard); err != nil {
+		return err
+	}
+	line, err := c.read()
+	if err != nil {
+		return err
+	}
+	position, ok := strings.CutPrefix(line, MsgBoard+" ")
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnexpected, line)
+	}
+	board, err := model.ParsePosition(position)
+	if err != nil {
+		return err
+	}
+
+	row, col := strategy(board)
+	return c.send("%s %s", CmdMove, FormatSquare(row, col))
+}
+
+// parseResult parses a GAMEOVER message
+func parseResult(fields []string) (Result, error) {
+	if len(fields) < 4 {
+		return Result{}, fmt.Errorf("%w: %q", ErrUnexpected, strings.Join(fields, " "))
+	}
+	black, err1 := strconv.Atoi(fields[1])
+	white, err2 := strconv.Atoi(fields[2])
+	if err1 != nil || err2 != nil {
+		return Result{}, fmt.Errorf("%w: %q", ErrUnexpected, strings.Join(fields, " "))
+	}
+	result := Result{Black: black, White: white, Winner: fields[3]}
+	if len(fields) > 4 {
+		result.Reason = fields[4]
+	}
+	return result, nil
+}
+
+// read returns the next line from the server
+func (c *Client) read() (string, error) {
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", net.ErrClosed
+	}
+	return strings.TrimSpace(c.scanner.Text()), nil
+}
+
+// send writes one line to the server
+func (c *Client) send(format string, args ...any) error {
+	_, err := fmt.Fprintf(c.conn, format+"\n", args...)
+	return err
+}
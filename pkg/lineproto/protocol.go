@@ -0,0 +1,98 @@
+// Package lineproto implements a plain text, line based protocol for writing
+// Othello bots, meant for classrooms where parsing JSON would get in the way.
+//
+// Every message is one line of space separated words ending in "\n". Squares
+// are written in lower case ("d3"), colors as "black" or "white". After
+// connecting the server greets the bot and expects its name:
+//
+//	S: HELLO othello-line 1
+//	C: NAME randombot
+//	S: GAME black medium-ai
+//
+// When it is the bot's turn the server sends YOURMOVE with the time left in
+// milliseconds. The bot may ask for the position or its legal moves any
+// number of times and must answer with MOVE before the time runs out:
+//
+//	S: YOURMOVE 5000
+//	C: LEGAL
+//	S: LEGAL c4 d3 e6 f5
+//	C: BOARD
+//	S: BOARD ---------------------------OX------XO--------------------------- X
+//	C: MOVE d3
+//	S: OK
+//	S: MOVED white c3
+//
+// BOARD uses the 64 character position format of the model package: rows
+// from 1 to 8, 'X' for black, 'O' for white and '-' for empty, followed by
+// the side to move. Passes are made by the server, which announces them as
+// "MOVED <color> pass". Mistakes are answered with "ERR <reason>"; too many
+// illegal moves, a timeout or a disconnect forfeits the game. The game ends
+// with
+//
+//	S: GAMEOVER <black discs> <white discs> <black|white|draw> [reason]
+//
+// after which the server closes the connection. QUIT resigns at any time.
+package lineproto
+
+import (
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Greeting identifies the protocol and its version
+const Greeting = "HELLO othello-line 1"
+
+// Commands sent by bots
+const (
+	CmdName  = "NAME"
+	CmdMove  = "MOVE"
+	CmdBoard = "BOARD"
+	CmdLegal = "LEGAL"
+	CmdQuit  = "QUIT"
+)
+
+// Messages sent by the server
+const (
+	MsgHello    = "HELLO"
+	MsgGame     = "GAME"
+	MsgYourMove = "YOURMOVE"
+	MsgMoved    = "MOVED"
+	MsgBoard    = "BOARD"
+	MsgLegal    = "LEGAL"
+	MsgOK       = "OK"
+	MsgErr      = "ERR"
+	MsgGameOver = "GAMEOVER"
+)
+
+// Reasons given for the end of a game
+const (
+	ReasonTimeout    = "timeout"
+	ReasonIllegal    = "illegal"
+	ReasonDisconnect = "disconnect"
+	ReasonResign     = "resign"
+)
+
+// MaxLineLength is the longest line either side may send
+const MaxLineLength = 256
+
+// FormatSquare returns the protocol form of a square, e.g. "d3" or "pass"
+func FormatSquare(row, col int) string {
+	return strings.ToLower(model.FormatMove(row, col))
+}
+
+// ColorName returns "black" or "white"
+func ColorName(p model.Piece) string {
+	return strings.ToLower(model.GetPieceName(p))
+}
+
+// ParseColor parses a color name sent by the server
+func ParseColor(name string) (model.Piece, bool) {
+	switch name {
+	case "black":
+		return model.Black, true
+	case "white":
+		return model.White, true
+	}
+	return model.Empty, false
+}
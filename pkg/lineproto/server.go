@@ -0,0 +1,328 @@
+package lineproto
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/notation"
+)
+
+// Server referees games between bots and the built-in AI, or between pairs
+// of bots
+type Server struct {
+	Difficulty       string        // AI level bots play against
+	Pair             bool          // Match bots against each other instead of the AI
+	MoveTimeout      time.Duration // Time a bot has for each move
+	HandshakeTimeout time.Duration // Time a bot has to send its name
+	MaxIllegal       int           // Illegal moves allowed per game before forfeiting
+
+	mu      sync.Mutex
+	waiting *seat // Bot waiting for an opponent in pair mode
+	games   int
+	log     *slog.Logger
+}
+
+// NewServer creates a server with strict defaults suited to classroom bots
+func NewServer() *Server {
+	return &Server{
+		Difficulty:       ai.Medium,
+		MoveTimeout:      5 * time.Second,
+		HandshakeTimeout: 10 * time.Second,
+		MaxIllegal:       3,
+		log:              logging.For("lineproto"),
+	}
+}
+
+// ListenAndServe listens on addr and serves bots
+func (s *Server) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts bots on ln until it fails
+func (s *Server) Serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go s.accept(conn)
+	}
+}
+
+// seat is a bot taking part in a game
+type seat struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	name    string
+	color   model.Piece
+	lines   chan string   // Closed when the bot disconnects
+	gone    chan struct{} // Closed when the bot disconnects, for watchers not reading lines
+	done    chan struct{} // Closed when the game ends
+	illegal int
+}
+
+// send writes one line to the bot; write errors surface as a disconnect
+func (st *seat) send(format string, args ...any) {
+	st.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+	fmt.Fprintf(st.conn, format+"\n", args...)
+}
+
+// readLines feeds the bot's lines to the seat until it disconnects
+func (st *seat) readLines() {
+	defer close(st.gone)
+	defer close(st.lines)
+	scanner := bufio.NewScanner(st.reader)
+	scanner.Buffer(make([]byte, MaxLineLength), MaxLineLength)
+	for scanner.Scan() {
+		select {
+		case st.lines <- strings.TrimSpace(scanner.Text()):
+		case <-st.done:
+			return
+		}
+	}
+}
+
+// accept greets a bot and seats it in a game
+func (s *Server) accept(conn net.Conn) {
+	st := &seat{conn: conn, reader: bufio.NewReaderSize(conn, MaxLineLength), lines: make(chan string, 16), gone: make(chan struct{}), done: make(chan struct{})}
+	st.send(Greeting)
+
+	conn.SetReadDeadline(time.Now().Add(s.HandshakeTimeout))
+	line, err := st.reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return
+	}
+	fields := strings.Fields(line)
+	if len(fields) != 2 || strings.ToUpper(fields[0]) != CmdName {
+		st.send("%s expected NAME <name>", MsgErr)
+		conn.Close()
+		return
+	}
+	st.name = fields[1]
+	conn.SetReadDeadline(time.Time{})
+	go st.readLines()
+
+	s.mu.Lock()
+	s.games++
+	number := s.games
+	if !s.Pair {
+		s.mu.Unlock()
+		// Alternate colors so bots practice both sides
+		st.color = model.Black
+		if number%2 == 0 {
+			st.color = model.White
+		}
+		s.play(st, nil)
+		return
+	}
+	opponent := s.waiting
+	if opponent == nil {
+		s.waiting = st
+		s.mu.Unlock()
+		s.unseatWhenGone(st)
+		return
+	}
+	s.waiting = nil
+	s.mu.Unlock()
+
+	opponent.color, st.color = model.Black, model.White
+	s.play(opponent, st)
+}
+
+// unseatWhenGone stops a bot waiting for an opponent from being paired
+// once it disconnects; it returns when the bot leaves or its game ends
+func (s *Server) unseatWhenGone(st *seat) {
+	select {
+	case <-st.gone:
+		s.mu.Lock()
+		left := s.waiting == st
+		if left {
+			s.waiting = nil
+		}
+		s.mu.Unlock()
+		if left {
+			s.log.Info("waiting bot disconnected", "bot", st.name)
+			st.conn.Close()
+		}
+	case <-st.done:
+	}
+}
+
+// play runs a game between a and b, or between a and the AI when b is nil
+func (s *Server) play(a, b *seat) {
+	game := model.NewGame()
+	seats := map[model.Piece]*seat{a.color: a}
+	var computer *ai.Player
+	opponentName := s.Difficulty + "-ai"
+	if b != nil {
+		seats[b.color] = b
+		a.send("%s %s %s", MsgGame, ColorName(a.color), b.name)
+		b.send("%s %s %s", MsgGame, ColorName(b.color), a.name)
+	} else {
+		computer = ai.NewPlayer(s.Difficulty, a.color.Opponent())
+		a.send("%s %s %s", MsgGame, ColorName(a.color), opponentName)
+	}
+	defer func() {
+		for _, st := range seats {
+			close(st.done)
+			st.conn.Close()
+		}
+	}()
+
+	log := s.log.With("black", nameOf(seats[model.Black], opponentName), "white", nameOf(seats[model.White], opponentName))
+	log.Info("bot game started")
+
+	broadcast := func(format string, args ...any) {
+		for _, st := range seats {
+			st.send(format, args...)
+		}
+	}
+	finish := func(winner model.Piece, reason string) {
+		black, white := game.GetScore()
+		result := "draw"
+		if winner != model.Empty {
+			result = ColorName(winner)
+		}
+		message := fmt.Sprintf("%s %d %d %s", MsgGameOver, black, white, result)
+		if reason != "" {
+			message += " " + reason
+		}
+		broadcast("%s", message)
+		log.Info("bot game finished", "black_discs", black, "white_discs", white, "winner", result, "reason", reason)
+	}
+
+	for !game.GameOver {
+		side := game.GetCurrentPlayer()
+		if !game.HasValidMove() {
+			game.Pass()
+			broadcast("%s %s pass", MsgMoved, ColorName(side))
+			continue
+		}
+
+		st, ok := seats[side]
+		if !ok {
+			row, col, _ := computer.GetMove(game.Board)
+			if err := game.MakeMove(row, col); err != nil {
+				log.Error("AI move rejected", "err", err)
+				return
+			}
+			broadcast("%s %s %s", MsgMoved, ColorName(side), FormatSquare(row, col))
+			continue
+		}
+
+		row, col, loser, reason := s.awaitMove(game, st, seats)
+		if reason != "" {
+			finish(loser.Opponent(), reason)
+			return
+		}
+		broadcast("%s %s %s", MsgMoved, ColorName(side), FormatSquare(row, col))
+	}
+
+	finish(game.Winner, "")
+}
+
+// awaitMove answers the bots' queries until the bot to move plays a legal
+// move, returning it, or until a bot forfeits, returning its color and the
+// reason. The idle bot can forfeit too by quitting or disconnecting.
+func (s *Server) awaitMove(game *model.Game, mover *seat, seats map[model.Piece]*seat) (int, int, model.Piece, string) {
+	timer := time.NewTimer(s.MoveTimeout)
+	defer timer.Stop()
+
+	mover.send("%s %d", MsgYourMove, s.MoveTimeout.Milliseconds())
+
+	// Both bots may send queries while waiting; the idle one's channel is
+	// watched too so a disconnect is noticed promptly
+	other := seats[mover.color.Opponent()]
+	var otherLines chan string
+	if other != nil {
+		otherLines = other.lines
+	}
+
+	for {
+		var st *seat
+		var line string
+		var ok bool
+		select {
+		case <-timer.C:
+			mover.send("%s move timed out", MsgErr)
+			return -1, -1, mover.color, ReasonTimeout
+		case line, ok = <-mover.lines:
+			st = mover
+		case line, ok = <-otherLines:
+			st = other
+		}
+		if !ok {
+			return -1, -1, st.color, ReasonDisconnect
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case CmdBoard:
+			st.send("%s %s", MsgBoard, game.Board.PositionString())
+		case CmdLegal:
+			moves := game.GetValidMoves()
+			squares := make([]string, len(moves))
+			for i, m := range moves {
+				squares[i] = FormatSquare(m.Row, m.Col)
+			}
+			if st != mover {
+				squares = nil // Only the side to move has legal moves
+			}
+			st.send("%s %s", MsgLegal, strings.Join(squares, " "))
+		case CmdQuit:
+			return -1, -1, st.color, ReasonResign
+		case CmdMove:
+			if st != mover {
+				st.send("%s not your turn", MsgErr)
+				continue
+			}
+			if len(fields) != 2 {
+				st.send("%s usage: MOVE <square>", MsgErr)
+				continue
+			}
+			row, col, err := notation.Default.Parse(fields[1], notation.Strict)
+			if err == nil && row < 0 {
+				err = model.ErrCannotPass
+			}
+			if err == nil {
+				err = game.MakeMove(row, col)
+			}
+			if err != nil {
+				st.illegal++
+				st.send("%s %v", MsgErr, err)
+				if st.illegal >= s.MaxIllegal {
+					return -1, -1, st.color, ReasonIllegal
+				}
+				continue
+			}
+			st.send(MsgOK)
+			return row, col, model.Empty, ""
+		default:
+			st.send("%s unknown command %s", MsgErr, fields[0])
+		}
+	}
+}
+
+// nameOf returns the seat's bot name, or the AI's name for an empty seat
+func nameOf(st *seat, ai string) string {
+	if st == nil {
+		return ai
+	}
+	return st.name
+}
@@ -0,0 +1,238 @@
+// Package migrate upgrades the files the game keeps - saved games, the game
+// library and opening books - to the formats this build writes, so data
+// saved by older versions is not stranded as the formats evolve. Every file
+// it changes is first copied to a backup directory.
+package migrate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/book"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Kinds of file that are upgraded
+const (
+	KindGame    = "saved game"
+	KindLibrary = "library game"
+	KindBook    = "opening book"
+)
+
+// backupPrefix names backup directories, which are never migrated
+const backupPrefix = "migrate-backup-"
+
+// ErrNewer is why a file written by a newer version is left alone
+var ErrNewer = errors.New("written by a newer version")
+
+// File is what was found in one file
+type File struct {
+	Path     string
+	Kind     string
+	From, To int   // Format versions; equal when the file is current
+	Err      error // Why the file was not upgraded
+}
+
+// Upgraded reports whether the file was upgraded, or would be in a dry run
+func (f File) Upgraded() bool {
+	return f.Err == nil && f.From != f.To
+}
+
+// Options control Dir
+type Options struct {
+	DryRun bool // Report what would change without writing anything
+	// BackupDir receives a copy of every file before it is changed; when
+	// empty, a new directory named after the time is made in the migrated one
+	BackupDir string
+}
+
+// Report lists the files of a known kind that were found
+type Report struct {
+	Files     []File
+	BackupDir string // Empty when nothing was backed up
+}
+
+// Count returns how many files were upgraded, were already current, were
+// left alone as newer and could not be upgraded
+func (r *Report) Count() (upgraded, current, newer, failed int) {
+	for _, f := range r.Files {
+		switch {
+		case errors.Is(f.Err, ErrNewer):
+			newer++
+		case f.Err != nil:
+			failed++
+		case f.Upgraded():
+			upgraded++
+		default:
+			current++
+		}
+	}
+	return upgraded, current, newer, failed
+}
+
+// Dir upgrades every known file under dir, recursing into subdirectories
+// Files it does not recognize are left alone. A file that cannot be
+// upgraded is reported and skipped; only errors that stop the whole run,
+// like a failed backup, are returned.
+func Dir(dir string, opts Options) (*Report, error) {
+	backupDir := opts.BackupDir
+	if backupDir == "" {
+		backupDir = filepath.Join(dir, backupPrefix+time.Now().Format("20060102-150405"))
+	}
+	report := &Report{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && (strings.HasPrefix(d.Name(), backupPrefix) || path == backupDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f, upgraded, ok := upgrade(data)
+		if !ok {
+			return nil
+		}
+		f.Path = path
+		report.Files = append(report.Files, f)
+		if !f.Upgraded() || opts.DryRun {
+			return nil
+		}
+
+		if err := backup(dir, path, backupDir, data); err != nil {
+			return fmt.Errorf("backing up %s: %w", path, err)
+		}
+		report.BackupDir = backupDir
+		return writeFile(path, upgraded)
+	})
+	return report, err
+}
+
+// upgrade recognizes a file and returns it in the current format; ok is
+// false for files of no known kind
+func upgrade(data []byte) (f File, upgraded []byte, ok bool) {
+	if bytes.HasPrefix(data, []byte("OTHBOOK")) {
+		f = File{Kind: KindBook, To: book.Version}
+		f.From, f.Err = book.ReadVersion(bytes.NewReader(data))
+		switch {
+		case f.Err != nil:
+		case f.From > f.To:
+			f.Err = ErrNewer
+		case f.From < f.To:
+			f.Err = fmt.Errorf("no upgrade from book version %d", f.From)
+		}
+		return f, data, true
+	}
+
+	var sections map[string]json.RawMessage
+	if json.Unmarshal(data, &sections) != nil {
+		return File{}, nil, false
+	}
+	switch {
+	case sections["moves"] != nil:
+		f, upgraded = upgradeGame(data)
+	case sections["record"] != nil && sections["id"] != nil:
+		f, upgraded = upgradeLibraryGame(data)
+	default:
+		return File{}, nil, false
+	}
+	return f, upgraded, true
+}
+
+// upgradeGame upgrades a saved game record
+func upgradeGame(data []byte) (File, []byte) {
+	f := File{Kind: KindGame, To: model.RecordVersion}
+	record, err := model.ReadGameRecord(bytes.NewReader(data))
+	if err != nil {
+		f.Err = err
+		return f, nil
+	}
+	if f.From, f.Err = upgradeRecord(record); f.Err != nil || f.From == f.To {
+		return f, nil
+	}
+
+	var buf bytes.Buffer
+	f.Err = model.WriteGameRecord(&buf, record)
+	return f, buf.Bytes()
+}
+
+// upgradeLibraryGame upgrades the record of a game in the library
+func upgradeLibraryGame(data []byte) (File, []byte) {
+	f := File{Kind: KindLibrary, To: model.RecordVersion}
+	var g library.Game
+	if f.Err = json.Unmarshal(data, &g); f.Err != nil {
+		return f, nil
+	}
+	if g.Record == nil {
+		f.Err = errors.New("no game record")
+		return f, nil
+	}
+	if f.From, f.Err = upgradeRecord(g.Record); f.Err != nil || f.From == f.To {
+		return f, nil
+	}
+
+	// Written as the library writes its games
+	upgraded, err := json.MarshalIndent(&g, "", "  ")
+	f.Err = err
+	return f, upgraded
+}
+
+// upgradeRecord brings a game record to the current version, returning the
+// version it had. Versions only add sections, so the moves are checked
+// and the version raised.
+func upgradeRecord(record *model.GameRecord) (int, error) {
+	from := max(record.Version, 1)
+	switch {
+	case from > model.RecordVersion:
+		return from, ErrNewer
+	case from == model.RecordVersion:
+		return from, nil
+	}
+	if _, err := record.Replay(); err != nil {
+		return from, err
+	}
+	record.Version = model.RecordVersion
+	return from, nil
+}
+
+// backup copies a file's original contents to the same place under
+// backupDir as it has under dir
+func backup(dir, path, backupDir string, data []byte) error {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return err
+	}
+	dst := filepath.Join(backupDir, rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o644)
+}
+
+// writeFile replaces a file through a temporary file, so an interrupted
+// run never leaves it half written
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
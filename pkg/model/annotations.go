@@ -0,0 +1,101 @@
+package model
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Annotations are optional notes on the moves of a game, saved as sections
+// of its record. Plies count from 1 for the first move, passes included.
+type Annotations struct {
+	Comments   map[int]string `json:"comments,omitempty"`  // By ply
+	Clocks     []int64        `json:"clocks_ms,omitempty"` // Milliseconds the mover had left after each ply
+	Analysis   []PlyAnalysis  `json:"analysis,omitempty"`
+	Variations []Variation    `json:"variations,omitempty"`
+}
+
+// PlyAnalysis is the engine's view of the position before a ply
+type PlyAnalysis struct {
+	Ply   int    `json:"ply"`
+	Depth int    `json:"depth"`
+	Score int    `json:"score"` // For the side to move
+	Best  string `json:"best"`
+}
+
+// Variation is a line that could have been played instead of a ply
+type Variation struct {
+	Ply     int      `json:"ply"` // The ply the line replaces
+	Moves   []string `json:"moves"`
+	Comment string   `json:"comment,omitempty"`
+}
+
+// Empty reports whether there are no annotations
+func (a Annotations) Empty() bool {
+	return len(a.Comments) == 0 && len(a.Clocks) == 0 && len(a.Analysis) == 0 && len(a.Variations) == 0
+}
+
+// clone copies the annotations, keeping empty sections nil
+func (a Annotations) clone() Annotations {
+	c := Annotations{
+		Comments:   maps.Clone(a.Comments),
+		Clocks:     slices.Clone(a.Clocks),
+		Analysis:   slices.Clone(a.Analysis),
+		Variations: slices.Clone(a.Variations),
+	}
+	for i, v := range c.Variations {
+		c.Variations[i].Moves = slices.Clone(v.Moves)
+	}
+	return c
+}
+
+// trim drops the notes on plies after the first plies, e.g. after an undo
+func (a *Annotations) trim(plies int) {
+	maps.DeleteFunc(a.Comments, func(ply int, _ string) bool { return ply > plies })
+	if len(a.Clocks) > plies {
+		a.Clocks = a.Clocks[:plies]
+	}
+	a.Analysis = slices.DeleteFunc(a.Analysis, func(p PlyAnalysis) bool { return p.Ply > plies })
+	a.Variations = slices.DeleteFunc(a.Variations, func(v Variation) bool { return v.Ply > plies })
+}
+
+// check verifies that the annotations fit the game's moves and that every
+// variation is legal from the position it branches off, the moves being
+// played from start
+func (a Annotations) check(start *Board, moves []Position) error {
+	for ply := range a.Comments {
+		if ply < 1 || ply > len(moves) {
+			return fmt.Errorf("comment on ply %d of %d", ply, len(moves))
+		}
+	}
+	if len(a.Clocks) > len(moves) {
+		return fmt.Errorf("%d clock readings for %d plies", len(a.Clocks), len(moves))
+	}
+	for _, p := range a.Analysis {
+		if p.Ply < 1 || p.Ply > len(moves) {
+			return fmt.Errorf("analysis of ply %d of %d", p.Ply, len(moves))
+		}
+	}
+
+	for _, v := range a.Variations {
+		if v.Ply < 1 || v.Ply > len(moves) {
+			return fmt.Errorf("variation at ply %d of %d", v.Ply, len(moves))
+		}
+		game := newGameAt(start)
+		if _, err := game.ReplayMoves(moves[:v.Ply-1]); err != nil {
+			return err
+		}
+		line := make([]Position, len(v.Moves))
+		for i, move := range v.Moves {
+			row, col, err := ParseMove(move)
+			if err != nil {
+				return fmt.Errorf("variation at ply %d, move %d (%q): %w", v.Ply, i+1, move, err)
+			}
+			line[i] = Position{Row: row, Col: col}
+		}
+		if i, err := game.ReplayMoves(line); err != nil {
+			return fmt.Errorf("variation at ply %d, move %d (%q): %w", v.Ply, i+1, v.Moves[i], err)
+		}
+	}
+	return nil
+}
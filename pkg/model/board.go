@@ -21,6 +21,18 @@ const (
 	White
 )
 
+// Opponent returns the opposite color, or Empty for Empty
+func (p Piece) Opponent() Piece {
+	switch p {
+	case Black:
+		return White
+	case White:
+		return Black
+	default:
+		return Empty
+	}
+}
+
 // Position represents a board position with row and column
 type Position struct {
 	Row, Col int
@@ -111,6 +123,22 @@ func (b *Board) IsValidMove(row, col int) bool {
 	return false
 }
 
+// CheckMove explains why placing a piece at the given position is not allowed
+// It returns nil for a legal move, otherwise a *MoveError wrapping
+// ErrOutOfBounds, ErrOccupied or ErrNoFlips
+func (b *Board) CheckMove(row, col int) error {
+	if !b.IsValidPosition(row, col) {
+		return NewMoveError(ErrOutOfBounds, row, col)
+	}
+	if b.Cells[row][col] != Empty {
+		return NewMoveError(ErrOccupied, row, col)
+	}
+	if !b.IsValidMove(row, col) {
+		return NewMoveError(ErrNoFlips, row, col)
+	}
+	return nil
+}
+
 // GetValidMoves returns all valid moves for the current player
 func (b *Board) GetValidMoves() []Position {
 	var moves []Position
@@ -135,6 +163,9 @@ func (b *Board) MakeMove(row, col int) bool {
 	b.Cells[row][col] = b.CurrentPlayer
 	b.flipPieces(row, col)
 	b.CurrentPlayer = b.getOpponent()
+	if DebugChecks {
+		b.checkInvariants("move " + FormatMove(row, col))
+	}
 	return true
 }
 
@@ -198,11 +229,65 @@ func (b *Board) flipPieces(row, col int) {
 	}
 }
 
+// FlipLine is one line of discs a move flips
+type FlipLine struct {
+	Dir   Direction
+	Count int      // Discs flipped along the line
+	End   Position // The flipped disc farthest from the move
+}
+
+// FlipLines returns the lines of discs the side to move would flip by
+// playing at row, col, none if the move is not legal
+func (b *Board) FlipLines(row, col int) []FlipLine {
+	if !b.IsValidPosition(row, col) || b.Cells[row][col] != Empty {
+		return nil
+	}
+	opponent := b.getOpponent()
+	var lines []FlipLine
+	for _, dir := range Directions {
+		r, c := row+dir.DRow, col+dir.DCol
+		count := 0
+		for b.IsValidPosition(r, c) && b.Cells[r][c] == opponent {
+			count++
+			r, c = r+dir.DRow, c+dir.DCol
+		}
+		if count > 0 && b.IsValidPosition(r, c) && b.Cells[r][c] == b.CurrentPlayer {
+			lines = append(lines, FlipLine{Dir: dir, Count: count, End: Position{Row: r - dir.DRow, Col: c - dir.DCol}})
+		}
+	}
+	return lines
+}
+
+// Squares returns the discs flipped along the line, nearest the move first
+func (l FlipLine) Squares() []Position {
+	squares := make([]Position, l.Count)
+	for i := range squares {
+		back := l.Count - 1 - i
+		squares[i] = Position{Row: l.End.Row - back*l.Dir.DRow, Col: l.End.Col - back*l.Dir.DCol}
+	}
+	return squares
+}
+
+// PassTurn hands the move to the opponent without placing a piece
+func (b *Board) PassTurn() {
+	b.CurrentPlayer = b.getOpponent()
+	b.checkInvariants("pass")
+}
+
 // HasValidMove checks if the current player has any valid moves
 func (b *Board) HasValidMove() bool {
 	return len(b.GetValidMoves()) > 0
 }
 
+// Mobility counts the legal moves the given player would have
+func (b *Board) Mobility(p Piece) int {
+	current := b.CurrentPlayer
+	b.CurrentPlayer = p
+	n := len(b.GetValidMoves())
+	b.CurrentPlayer = current
+	return n
+}
+
 // IsGameOver checks if the game is over (no valid moves for either player)
 func (b *Board) IsGameOver() bool {
 	// Save current player
@@ -224,6 +309,14 @@ func (b *Board) IsGameOver() bool {
 	return !opponentHasMove
 }
 
+// count returns the number of discs of the given color
+func (b *Board) count(p Piece) int {
+	if p == Black {
+		return b.BlackCnt
+	}
+	return b.WhiteCnt
+}
+
 // GetWinner returns the winner (or Empty if tie)
 func (b *Board) GetWinner() Piece {
 	if b.BlackCnt > b.WhiteCnt {
@@ -252,5 +345,6 @@ func (b *Board) Clone() *Board {
 		}
 	}
 
+	newBoard.checkInvariants("clone")
 	return newBoard
 }
@@ -0,0 +1,7 @@
+//go:build othellodebug
+
+package model
+
+// DebugChecks is set in builds with the othellodebug tag, which check the
+// board's invariants after every change and panic when one is broken
+const DebugChecks = true
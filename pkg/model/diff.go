@@ -0,0 +1,75 @@
+package model
+
+// BoardDiff lists the squares that differ between two positions
+type BoardDiff struct {
+	Added   []Position // Empty in the first board, occupied in the second
+	Removed []Position // Occupied in the first board, empty in the second
+	Flipped []Position // Occupied in both boards by different colors
+
+	// SideChanged is set when the player to move differs
+	SideChanged bool
+}
+
+// Diff compares two boards of the same size square by square
+func Diff(a, b *Board) BoardDiff {
+	var diff BoardDiff
+
+	for i := 0; i < a.Size; i++ {
+		for j := 0; j < a.Size; j++ {
+			before := a.GetPiece(i, j)
+			after := b.GetPiece(i, j)
+			if before == after {
+				continue
+			}
+
+			pos := Position{Row: i, Col: j}
+			switch {
+			case before == Empty:
+				diff.Added = append(diff.Added, pos)
+			case after == Empty:
+				diff.Removed = append(diff.Removed, pos)
+			default:
+				diff.Flipped = append(diff.Flipped, pos)
+			}
+		}
+	}
+
+	diff.SideChanged = a.CurrentPlayer != b.CurrentPlayer
+	return diff
+}
+
+// IsEmpty reports whether both boards hold the same position
+func (d BoardDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Flipped) == 0 && !d.SideChanged
+}
+
+// SquareCount returns the number of squares that changed
+func (d BoardDiff) SquareCount() int {
+	return len(d.Added) + len(d.Removed) + len(d.Flipped)
+}
+
+// IsSingleMove reports whether the diff can be explained by exactly one
+// legal move played on the first board: one disc added, at least one flipped,
+// none removed, and the flips being exactly those the rules require
+func (d BoardDiff) IsSingleMove(from *Board) bool {
+	if len(d.Added) != 1 || len(d.Removed) != 0 || len(d.Flipped) == 0 {
+		return false
+	}
+
+	move := d.Added[0]
+	after := from.Clone()
+	if !after.MakeMove(move.Row, move.Col) {
+		return false
+	}
+
+	expected := Diff(from, after)
+	if len(expected.Flipped) != len(d.Flipped) {
+		return false
+	}
+	for i := range expected.Flipped {
+		if expected.Flipped[i] != d.Flipped[i] {
+			return false
+		}
+	}
+	return true
+}
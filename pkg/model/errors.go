@@ -0,0 +1,48 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/amirhossein-jamali/othello/pkg/notation"
+)
+
+// Rule violation errors; use errors.Is to test for them
+var (
+	ErrGameOver    = errors.New("game is already over")
+	ErrOccupied    = errors.New("square is already occupied")
+	ErrNoFlips     = errors.New("move does not flip any pieces")
+	ErrOutOfBounds = notation.ErrOutOfBounds
+	ErrCannotPass  = errors.New("cannot pass when valid moves are available")
+)
+
+// MoveError reports a rejected move together with the attempted position
+type MoveError struct {
+	Err      error
+	Position Position // Row and Col are -1 for a pass
+}
+
+// NewMoveError wraps a rule violation with the position it applies to
+func NewMoveError(err error, row, col int) *MoveError {
+	return &MoveError{Err: err, Position: Position{Row: row, Col: col}}
+}
+
+// Error implements the error interface
+func (e *MoveError) Error() string {
+	row, col := e.Position.Row, e.Position.Col
+	if !e.IsPass() && (row < 0 || row >= 8 || col < 0 || col >= 8) {
+		// FormatMove only makes sense for on-board squares
+		return fmt.Sprintf("%v: (%d,%d)", e.Err, row, col)
+	}
+	return fmt.Sprintf("%v: %s", e.Err, FormatMove(row, col))
+}
+
+// Unwrap returns the underlying rule violation
+func (e *MoveError) Unwrap() error {
+	return e.Err
+}
+
+// IsPass reports whether the rejected action was a pass
+func (e *MoveError) IsPass() bool {
+	return e.Position.Row == -1 && e.Position.Col == -1
+}
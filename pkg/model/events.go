@@ -0,0 +1,24 @@
+package model
+
+// MoveListener is told of every move and pass played in a game, once it is
+// in the history and the game state is up to date
+type MoveListener func(game *Game, move Move)
+
+// Listen adds a listener told of the moves and passes played from now on
+// Listeners are kept across Undo.
+func (g *Game) Listen(l MoveListener) {
+	g.listeners = append(g.listeners, l)
+}
+
+// IsPass reports whether the move is a pass
+func (m Move) IsPass() bool {
+	return m.Position == PassPosition
+}
+
+// notify tells the listeners of the last move
+func (g *Game) notify() {
+	move := g.History[len(g.History)-1]
+	for _, l := range g.listeners {
+		l(g, move)
+	}
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// FuzzParseMove checks that a parsed move is a square on the board or a pass
+func FuzzParseMove(f *testing.F) {
+	for _, s := range []string{"E4", "e4", "pass", "A1", "H8", "I1", "A9", "4E", "", "E"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		row, col, err := ParseMove(s)
+		if err != nil || row == -1 && col == -1 {
+			return
+		}
+		if !NewBoard().IsValidPosition(row, col) {
+			t.Fatalf("ParseMove(%q) = %d, %d: off the board", s, row, col)
+		}
+	})
+}
+
+// FuzzParsePosition checks that a parsed position is consistent and is
+// written back as an equivalent position
+func FuzzParsePosition(f *testing.F) {
+	f.Add(NewBoard().PositionString())
+	f.Add("---------------------------OX------XO--------------------------- O")
+	f.Add("........................... X")
+	f.Add("")
+	f.Fuzz(func(t *testing.T, s string) {
+		board, err := ParsePosition(s)
+		if err != nil {
+			return
+		}
+		checkCounts(t, board)
+		again, err := ParsePosition(board.PositionString())
+		if err != nil {
+			t.Fatalf("%q does not read back: %v", board.PositionString(), err)
+		}
+		if again.PositionString() != board.PositionString() {
+			t.Fatalf("%q reads back as %q", board.PositionString(), again.PositionString())
+		}
+	})
+}
+
+// FuzzParseTranscript checks that a parsed transcript holds only squares on
+// the board and passes
+func FuzzParseTranscript(f *testing.F) {
+	for _, s := range []string{"F5D6C3", "f5 d6 pass c3", "f5,d6,--", "F5D", "PAPASS", ""} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		moves, err := ParseTranscript(s)
+		if err != nil {
+			return
+		}
+		if err := CheckMoves(moves); err != nil {
+			t.Fatalf("ParseTranscript(%q): %v", s, err)
+		}
+	})
+}
+
+// FuzzPlayout plays random games, checking the rules' invariants after
+// every move: the disc counts match the board, a move adds exactly one disc
+// and flips at least one, occupied squares are refused, and the game ends
+// exactly when neither side can move
+func FuzzPlayout(f *testing.F) {
+	for seed := int64(0); seed < 8; seed++ {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		rng := rand.New(rand.NewSource(seed))
+		game := NewGame()
+		for ply := 0; !game.GameOver; ply++ {
+			if ply > 120 {
+				t.Fatal("the game did not end after 120 plies")
+			}
+			board := game.Board
+			moves := board.GetValidMoves()
+			if len(moves) == 0 {
+				if err := game.Pass(); err != nil {
+					t.Fatalf("ply %d: pass refused: %v", ply, err)
+				}
+				continue
+			}
+
+			occupied := occupiedSquare(board, rng)
+			before := board.PositionString()
+			if err := game.MakeMove(occupied.Row, occupied.Col); !errors.Is(err, ErrOccupied) {
+				t.Fatalf("ply %d: move to occupied %v gave %v", ply, occupied, err)
+			}
+			if board.PositionString() != before {
+				t.Fatalf("ply %d: a refused move changed the board", ply)
+			}
+
+			mover := board.CurrentPlayer
+			mine, discs := board.count(mover), board.BlackCnt+board.WhiteCnt
+			move := moves[rng.Intn(len(moves))]
+			if err := game.MakeMove(move.Row, move.Col); err != nil {
+				t.Fatalf("ply %d: legal move %v refused: %v", ply, move, err)
+			}
+			checkCounts(t, game.Board)
+			if got := game.Board.BlackCnt + game.Board.WhiteCnt; got != discs+1 {
+				t.Fatalf("ply %d: %d discs after a move on %d", ply, got, discs)
+			}
+			if game.Board.count(mover) < mine+2 {
+				t.Fatalf("ply %d: move %v flipped nothing", ply, move)
+			}
+		}
+
+		if game.Board.HasValidMove() {
+			t.Fatal("the game ended with a legal move left")
+		}
+		game.Board.PassTurn()
+		if game.Board.HasValidMove() {
+			t.Fatal("the game ended with a legal move left for the other side")
+		}
+	})
+}
+
+// checkCounts fails the test if the board's disc counts do not match its
+// squares
+func checkCounts(t *testing.T, b *Board) {
+	t.Helper()
+	black, white := 0, 0
+	for row := 0; row < b.Size; row++ {
+		for col := 0; col < b.Size; col++ {
+			switch b.Cells[row][col] {
+			case Black:
+				black++
+			case White:
+				white++
+			}
+		}
+	}
+	if black != b.BlackCnt || white != b.WhiteCnt {
+		t.Fatalf("counts %d/%d, board holds %d/%d", b.BlackCnt, b.WhiteCnt, black, white)
+	}
+}
+
+// occupiedSquare picks a random square holding a disc
+func occupiedSquare(b *Board, rng *rand.Rand) Position {
+	for {
+		row, col := rng.Intn(b.Size), rng.Intn(b.Size)
+		if b.Cells[row][col] != Empty {
+			return Position{Row: row, Col: col}
+		}
+	}
+}
@@ -1,10 +1,12 @@
 package model
 
 import (
+	"encoding/json"
 	"errors"
-	"fmt"
 	"math/rand"
 	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/notation"
 )
 
 // Game represents the core Othello game logic
@@ -14,12 +16,51 @@ type Game struct {
 	GameOver  bool
 	PassCount int // Track consecutive passes
 	Winner    Piece
+
+	// EndReason says how a game ended early, e.g. EndResigned; it is empty
+	// when the game was played out
+	EndReason string
+
+	// Metadata describes the game, e.g. the players; it is saved with the
+	// game record
+	Metadata map[string]string
+
+	// Annotations are notes on the moves, saved with the game record
+	Annotations Annotations
+
+	// The version and unknown sections of the record the game was loaded
+	// from, written back when it is saved again
+	recordVersion int
+	recordExtra   map[string]json.RawMessage
+
+	// start is the position the history is played from; nil for the
+	// standard opening
+	start *Board
+
+	listeners []MoveListener
 }
 
+// ErrNothingToUndo is returned by Undo before the first move
+var ErrNothingToUndo = errors.New("no move to undo")
+
+// Reasons a game ended before the board was played out
+const (
+	EndResigned = "resigned"
+	EndTimeout  = "timeout"
+)
+
 // Move represents a player's move
 type Move struct {
 	Position Position
 	Piece    Piece
+
+	// Disc counts after the move was played
+	BlackCount int
+	WhiteCount int
+
+	// Flipped is the number of discs the move flipped, along Lines
+	Flipped int
+	Lines   []FlipLine
 }
 
 // NewGame creates a new Othello game
@@ -33,22 +74,76 @@ func NewGame() *Game {
 	}
 }
 
+// NewGameFrom creates a game starting from a copy of board, with an empty
+// history; it is over at once if neither side can move
+func NewGameFrom(board *Board) *Game {
+	g := NewGame()
+	g.Board = board.Clone()
+	g.start = board.Clone()
+	g.updateGameState()
+	return g
+}
+
+// newGameAt creates a game starting from start, or from the standard
+// opening when start is nil
+func newGameAt(start *Board) *Game {
+	if start == nil {
+		return NewGame()
+	}
+	return NewGameFrom(start)
+}
+
+// StartBoard returns a copy of the position the game started from, which
+// the history is played from
+func (g *Game) StartBoard() *Board {
+	if g.start == nil {
+		return NewBoard()
+	}
+	return g.start.Clone()
+}
+
+// replayFromStart returns a new game at the start position with the given
+// moves of the history played
+func (g *Game) replayFromStart(history []Move) (*Game, error) {
+	replayed := newGameAt(g.start)
+	for _, m := range history {
+		var err error
+		if m.Position.Row < 0 {
+			err = replayed.Pass()
+		} else {
+			err = replayed.MakeMove(m.Position.Row, m.Position.Col)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return replayed, nil
+}
+
 // MakeMove attempts to place a piece at the given position
-// Returns an error if the move is invalid
+// Returns a *MoveError describing the violated rule if the move is invalid
 func (g *Game) MakeMove(row, col int) error {
 	if g.GameOver {
-		return errors.New("game is already over")
+		return NewMoveError(ErrGameOver, row, col)
 	}
 
-	// Try to make the move
-	if !g.Board.MakeMove(row, col) {
-		return errors.New("invalid move")
+	// Validate first so the caller learns why a move was rejected
+	if err := g.Board.CheckMove(row, col); err != nil {
+		return err
 	}
+	mover := g.Board.CurrentPlayer
+	before := g.Board.count(mover)
+	lines := g.Board.FlipLines(row, col)
+	g.Board.MakeMove(row, col)
 
 	// Record the move in history
 	g.History = append(g.History, Move{
-		Position: Position{Row: row, Col: col},
-		Piece:    g.Board.CurrentPlayer,
+		Position:   Position{Row: row, Col: col},
+		Piece:      mover,
+		BlackCount: g.Board.BlackCnt,
+		WhiteCount: g.Board.WhiteCnt,
+		Flipped:    g.Board.count(mover) - before - 1,
+		Lines:      lines,
 	})
 
 	// Reset pass count since a valid move was made
@@ -56,6 +151,10 @@ func (g *Game) MakeMove(row, col int) error {
 
 	// Check game state after the move
 	g.updateGameState()
+	if DebugChecks {
+		g.checkInvariants("move " + FormatMove(row, col))
+	}
+	g.notify()
 
 	return nil
 }
@@ -63,17 +162,19 @@ func (g *Game) MakeMove(row, col int) error {
 // Pass skips the current player's turn when they have no valid moves
 func (g *Game) Pass() error {
 	if g.GameOver {
-		return errors.New("game is already over")
+		return NewMoveError(ErrGameOver, -1, -1)
 	}
 
 	if g.Board.HasValidMove() {
-		return errors.New("cannot pass when valid moves are available")
+		return NewMoveError(ErrCannotPass, -1, -1)
 	}
 
 	// Record the pass in history
 	g.History = append(g.History, Move{
-		Position: Position{Row: -1, Col: -1}, // -1,-1 indicates a pass
-		Piece:    g.Board.CurrentPlayer,
+		Position:   Position{Row: -1, Col: -1}, // -1,-1 indicates a pass
+		Piece:      g.Board.CurrentPlayer,
+		BlackCount: g.Board.BlackCnt,
+		WhiteCount: g.Board.WhiteCnt,
 	})
 
 	// Increment pass count
@@ -84,10 +185,36 @@ func (g *Game) Pass() error {
 
 	// Check game state after the pass
 	g.updateGameState()
+	if DebugChecks {
+		g.checkInvariants("pass")
+	}
+	g.notify()
 
 	return nil
 }
 
+// Undo takes back the last move or pass, keeping the metadata, the
+// listeners and the annotations on the moves still played
+func (g *Game) Undo() error {
+	if len(g.History) == 0 {
+		return ErrNothingToUndo
+	}
+
+	replayed, err := g.replayFromStart(g.History[:len(g.History)-1])
+	if err != nil {
+		return err
+	}
+
+	kept := *g
+	*g = *replayed
+	g.Metadata = kept.Metadata
+	g.Annotations = kept.Annotations
+	g.Annotations.trim(len(g.History))
+	g.recordVersion, g.recordExtra = kept.recordVersion, kept.recordExtra
+	g.listeners = kept.listeners
+	return nil
+}
+
 // updateGameState checks if the game is over
 func (g *Game) updateGameState() {
 	if g.Board.IsGameOver() || g.PassCount >= 2 {
@@ -96,6 +223,14 @@ func (g *Game) updateGameState() {
 	}
 }
 
+// EndEarly ends the game before the board is played out, by resignation or
+// on time
+func (g *Game) EndEarly(winner Piece, reason string) {
+	g.GameOver = true
+	g.Winner = winner
+	g.EndReason = reason
+}
+
 // GetValidMoves returns all valid moves for the current player
 func (g *Game) GetValidMoves() []Position {
 	return g.Board.GetValidMoves()
@@ -106,6 +241,32 @@ func (g *Game) HasValidMove() bool {
 	return g.Board.HasValidMove()
 }
 
+// ScoreAt returns the score after the given ply (1-based) of the history
+// Ply 0 is the starting position
+func (g *Game) ScoreAt(ply int) (int, int) {
+	if ply <= 0 || len(g.History) == 0 {
+		start := g.StartBoard()
+		return start.BlackCnt, start.WhiteCnt
+	}
+	if ply > len(g.History) {
+		ply = len(g.History)
+	}
+	move := g.History[ply-1]
+	return move.BlackCount, move.WhiteCount
+}
+
+// BoardAt returns the position after the given ply (1-based) of the history
+// Ply 0 is the starting position.
+func (g *Game) BoardAt(ply int) *Board {
+	ply = max(0, min(ply, len(g.History)))
+	replayed, err := g.replayFromStart(g.History[:ply])
+	if err != nil {
+		// The history was legal when it was played
+		return g.StartBoard()
+	}
+	return replayed.Board
+}
+
 // GetScore returns the current score (black count, white count)
 func (g *Game) GetScore() (int, int) {
 	return g.Board.BlackCnt, g.Board.WhiteCnt
@@ -130,52 +291,38 @@ func (g *Game) GetGameStatus() string {
 	return "White's turn"
 }
 
-// Reset restarts the game with a new board
+// Reset restarts the game with a new board from the standard opening
 func (g *Game) Reset() {
 	g.Board = NewBoard()
+	g.start = nil
 	g.History = []Move{}
 	g.GameOver = false
 	g.PassCount = 0
 	g.Winner = Empty
+	g.EndReason = ""
+	g.Metadata = nil
+	g.Annotations = Annotations{}
+	g.recordVersion, g.recordExtra = 0, nil
+}
+
+// SetMetadata sets one metadata entry
+func (g *Game) SetMetadata(key, value string) {
+	if g.Metadata == nil {
+		g.Metadata = make(map[string]string)
+	}
+	g.Metadata[key] = value
 }
 
 // FormatMove converts a position to human-readable form (e.g., "E4")
 func FormatMove(row, col int) string {
-	if row < 0 || col < 0 {
-		return "Pass"
-	}
-	return fmt.Sprintf("%c%d", 'A'+col, row+1)
+	return notation.Format(row, col)
 }
 
 // ParseMove converts a string like "E4" to board coordinates
+// Parsing is lenient, as suits user input; protocols should use
+// notation.Strict. A pass is returned as -1, -1.
 func ParseMove(move string) (int, int, error) {
-	if len(move) < 2 {
-		return -1, -1, errors.New("invalid move format")
-	}
-
-	// Handle pass
-	if move == "pass" || move == "Pass" || move == "PASS" {
-		return -1, -1, nil
-	}
-
-	col := int(move[0])
-	if col >= 'a' && col <= 'h' {
-		col -= 'a'
-	} else if col >= 'A' && col <= 'H' {
-		col -= 'A'
-	} else {
-		return -1, -1, errors.New("invalid column")
-	}
-
-	row := -1
-	fmt.Sscanf(move[1:], "%d", &row)
-	row-- // Convert from 1-based to 0-based
-
-	if row < 0 || row >= 8 || col < 0 || col >= 8 {
-		return -1, -1, errors.New("position out of bounds")
-	}
-
-	return row, col, nil
+	return notation.Parse(move)
 }
 
 // GetPieceName returns a string representation of the piece
@@ -0,0 +1,48 @@
+package model
+
+// Zobrist keys, generated deterministically so hashes are stable across runs
+// and can be stored on disk
+var (
+	zobristSquares [2][8][8]uint64
+	zobristWhite   uint64
+)
+
+func init() {
+	// splitmix64 keeps the sequence independent of math/rand's implementation
+	seed := uint64(0x4f7468656c6c6f) // "Othello"
+	next := func() uint64 {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+
+	for color := 0; color < 2; color++ {
+		for i := 0; i < 8; i++ {
+			for j := 0; j < 8; j++ {
+				zobristSquares[color][i][j] = next()
+			}
+		}
+	}
+	zobristWhite = next()
+}
+
+// Hash returns a 64-bit Zobrist hash of the position and side to move
+func (b *Board) Hash() uint64 {
+	var h uint64
+	for i := 0; i < b.Size && i < 8; i++ {
+		for j := 0; j < b.Size && j < 8; j++ {
+			switch b.Cells[i][j] {
+			case Black:
+				h ^= zobristSquares[0][i][j]
+			case White:
+				h ^= zobristSquares[1][i][j]
+			}
+		}
+	}
+	if b.CurrentPlayer == White {
+		h ^= zobristWhite
+	}
+	return h
+}
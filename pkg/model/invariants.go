@@ -0,0 +1,145 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InvariantError is the panic value of a broken board invariant, caught in
+// builds with DebugChecks
+type InvariantError struct {
+	Op       string // What was done to the board, e.g. "move D3"
+	Problem  string
+	Position string // The board after op, drawn as text
+}
+
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("board invariant broken after %s: %s\n%s", e.Op, e.Problem, e.Position)
+}
+
+// checkInvariants panics with an *InvariantError if the board is not
+// consistent after op. Without DebugChecks it does nothing.
+func (b *Board) checkInvariants(op string) {
+	if !DebugChecks {
+		return
+	}
+	if problem := b.brokenInvariant(); problem != "" {
+		panic(&InvariantError{Op: op, Problem: problem, Position: b.dump()})
+	}
+}
+
+// checkInvariants checks the board and that the game's record agrees with
+// it after op. Without DebugChecks it does nothing.
+func (g *Game) checkInvariants(op string) {
+	if !DebugChecks {
+		return
+	}
+	g.Board.checkInvariants(op)
+	problem := ""
+	if n := len(g.History); n > 0 {
+		last := g.History[n-1]
+		if last.BlackCount != g.Board.BlackCnt || last.WhiteCount != g.Board.WhiteCnt {
+			problem = fmt.Sprintf("history ends at %d/%d discs, the board holds %d/%d",
+				last.BlackCount, last.WhiteCount, g.Board.BlackCnt, g.Board.WhiteCnt)
+		}
+	}
+	if g.EndReason == "" && g.GameOver != g.Board.IsGameOver() && g.PassCount < 2 {
+		problem = fmt.Sprintf("game over is %v, but the board says %v", g.GameOver, g.Board.IsGameOver())
+	}
+	if problem != "" {
+		panic(&InvariantError{Op: op, Problem: problem, Position: g.Board.dump()})
+	}
+}
+
+// brokenInvariant describes the first invariant the board breaks, or
+// returns ""
+func (b *Board) brokenInvariant() string {
+	if b.Size != 8 || len(b.Cells) != b.Size {
+		return fmt.Sprintf("size %d with %d rows", b.Size, len(b.Cells))
+	}
+	black, white := 0, 0
+	for i, row := range b.Cells {
+		if len(row) != b.Size {
+			return fmt.Sprintf("row %d has %d squares", i+1, len(row))
+		}
+		for j, p := range row {
+			switch p {
+			case Black:
+				black++
+			case White:
+				white++
+			case Empty:
+			default:
+				return fmt.Sprintf("square %s holds piece %d", FormatMove(i, j), p)
+			}
+		}
+	}
+	if black != b.BlackCnt || white != b.WhiteCnt {
+		return fmt.Sprintf("counts say %d/%d discs, the squares hold %d/%d", b.BlackCnt, b.WhiteCnt, black, white)
+	}
+	if b.CurrentPlayer != Black && b.CurrentPlayer != White {
+		return fmt.Sprintf("piece %d to move", b.CurrentPlayer)
+	}
+
+	for i := 0; i < b.Size; i++ {
+		for j := 0; j < b.Size; j++ {
+			if legal := b.Cells[i][j] == Empty && referenceFlips(b, i, j) > 0; legal != b.IsValidMove(i, j) {
+				return fmt.Sprintf("%s is legal by the rules (%v) but IsValidMove says %v", FormatMove(i, j), legal, !legal)
+			}
+		}
+	}
+
+	if again, err := parsePosition(b.PositionString()); err != nil {
+		return "the position does not read back: " + err.Error()
+	} else if again.Hash() != b.Hash() {
+		return "the hash changes when the position is read back"
+	}
+	return ""
+}
+
+// referenceFlips counts the discs a move would flip, walking each line the
+// plain way, as a check on the faster code
+func referenceFlips(b *Board, row, col int) int {
+	total := 0
+	for dr := -1; dr <= 1; dr++ {
+		for dc := -1; dc <= 1; dc++ {
+			if dr == 0 && dc == 0 {
+				continue
+			}
+			run := 0
+			r, c := row+dr, col+dc
+			for r >= 0 && r < 8 && c >= 0 && c < 8 && b.Cells[r][c] == b.CurrentPlayer.Opponent() {
+				run++
+				r, c = r+dr, c+dc
+			}
+			if run > 0 && r >= 0 && r < 8 && c >= 0 && c < 8 && b.Cells[r][c] == b.CurrentPlayer {
+				total += run
+			}
+		}
+	}
+	return total
+}
+
+// dump draws the board for a panic message, with its position string
+func (b *Board) dump() string {
+	var sb strings.Builder
+	sb.WriteString("  A B C D E F G H\n")
+	for i, row := range b.Cells {
+		fmt.Fprintf(&sb, "%d", i+1)
+		for _, p := range row {
+			switch p {
+			case Black:
+				sb.WriteString(" X")
+			case White:
+				sb.WriteString(" O")
+			case Empty:
+				sb.WriteString(" .")
+			default:
+				sb.WriteString(" ?")
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	fmt.Fprintf(&sb, "%s to move, %d/%d discs counted", GetPieceName(b.CurrentPlayer), b.BlackCnt, b.WhiteCnt)
+	return sb.String()
+}
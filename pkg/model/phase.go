@@ -0,0 +1,49 @@
+package model
+
+// GamePhase is the stage a game is in
+type GamePhase int
+
+// Game phases, in order
+const (
+	Opening GamePhase = iota
+	Midgame
+	Endgame
+)
+
+// String names the phase, e.g. "Midgame"
+func (p GamePhase) String() string {
+	switch p {
+	case Opening:
+		return "Opening"
+	case Midgame:
+		return "Midgame"
+	}
+	return "Endgame"
+}
+
+// PhaseLimits set where the phases begin, by the number of empty squares
+type PhaseLimits struct {
+	Midgame int // The midgame begins with this many empty squares
+	Endgame int // The endgame begins with this many empty squares
+}
+
+// DefaultPhaseLimits end the opening 20 moves in and begin the endgame
+// 40 moves in
+var DefaultPhaseLimits = PhaseLimits{Midgame: 39, Endgame: 19}
+
+// Phase returns the phase of board under DefaultPhaseLimits
+func Phase(board *Board) GamePhase {
+	return DefaultPhaseLimits.Phase(board)
+}
+
+// Phase returns the phase of board under the limits
+func (l PhaseLimits) Phase(board *Board) GamePhase {
+	empties := board.Size*board.Size - board.BlackCnt - board.WhiteCnt
+	switch {
+	case empties <= l.Endgame:
+		return Endgame
+	case empties <= l.Midgame:
+		return Midgame
+	}
+	return Opening
+}
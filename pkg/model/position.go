@@ -0,0 +1,106 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// Characters used in position strings
+const (
+	BlackChar = 'X'
+	WhiteChar = 'O'
+	EmptyChar = '-'
+)
+
+// PositionString encodes the board as 64 squares, row by row starting at A1,
+// followed by a space and the side to move, e.g. "---...XO--- X"
+func (b *Board) PositionString() string {
+	var sb strings.Builder
+	sb.Grow(b.Size*b.Size + 2)
+	for i := 0; i < b.Size; i++ {
+		for j := 0; j < b.Size; j++ {
+			sb.WriteByte(pieceChar(b.Cells[i][j]))
+		}
+	}
+	sb.WriteByte(' ')
+	sb.WriteByte(pieceChar(b.CurrentPlayer))
+	return sb.String()
+}
+
+// ParsePosition decodes a string produced by PositionString
+// Lowercase letters and '.' for empty squares are also accepted
+func ParsePosition(s string) (*Board, error) {
+	board, err := parsePosition(s)
+	if DebugChecks && err == nil {
+		board.checkInvariants("parsing " + s)
+	}
+	return board, err
+}
+
+// parsePosition is ParsePosition without the debug checks, which use it
+func parsePosition(s string) (*Board, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return nil, errors.New("position must have squares and side to move")
+	}
+
+	squares, side := fields[0], fields[1]
+	board := NewBoard()
+	if len(squares) != board.Size*board.Size {
+		return nil, errors.New("position must describe 64 squares")
+	}
+
+	board.BlackCnt, board.WhiteCnt = 0, 0
+	for i := 0; i < board.Size; i++ {
+		for j := 0; j < board.Size; j++ {
+			piece, ok := charPiece(squares[i*board.Size+j])
+			if !ok {
+				return nil, errors.New("invalid square character in position")
+			}
+			board.Cells[i][j] = piece
+			switch piece {
+			case Black:
+				board.BlackCnt++
+			case White:
+				board.WhiteCnt++
+			}
+		}
+	}
+
+	if len(side) != 1 {
+		return nil, errors.New("invalid side to move")
+	}
+	player, ok := charPiece(side[0])
+	if !ok || player == Empty {
+		return nil, errors.New("invalid side to move")
+	}
+	board.CurrentPlayer = player
+
+	return board, nil
+}
+
+// pieceChar returns the position string character for a piece
+func pieceChar(p Piece) byte {
+	switch p {
+	case Black:
+		return BlackChar
+	case White:
+		return WhiteChar
+	default:
+		return EmptyChar
+	}
+}
+
+// charPiece parses a position string character
+func charPiece(c byte) (Piece, bool) {
+	switch c {
+	case 'X', 'x', 'B', 'b', '*':
+		return Black, true
+	case 'O', 'o', 'W', 'w':
+		return White, true
+	case '-', '.':
+		return Empty, true
+	default:
+		return Empty, false
+	}
+}
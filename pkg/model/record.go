@@ -0,0 +1,200 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+)
+
+// RecordVersion is the version of the game record format written by this
+// build. Version 1, saved without a version, holds the moves, result and
+// metadata; version 2 adds the annotation sections and version 3 the start
+// position and how a game ended early. New versions only add
+// sections, so any version loads: sections a build does not know are kept
+// and written back unchanged.
+const RecordVersion = 3
+
+// GameRecord is the serializable form of a game
+// Moves are stored in human-readable notation ("E4", "Pass") so files stay
+// easy to inspect and edit by hand
+type GameRecord struct {
+	Version  int               `json:"version,omitempty"` // 0 for version 1 records
+	Moves    []string          `json:"moves"`
+	Result   string            `json:"result,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// Start is the position the moves are played from, as PositionString
+	// writes it; empty for the standard opening
+	Start string `json:"start,omitempty"`
+
+	// EndReason and Winner record a game that ended before the board was
+	// played out, e.g. by resignation; both are empty otherwise
+	EndReason string `json:"end_reason,omitempty"`
+	Winner    string `json:"winner,omitempty"` // "Black", "White" or "Empty" for a draw
+
+	Annotations
+
+	// Extra holds the sections of newer versions, by name
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// recordKeys are the sections GameRecord knows
+var recordKeys = map[string]bool{
+	"version": true, "moves": true, "result": true, "metadata": true,
+	"start": true, "end_reason": true, "winner": true,
+	"comments": true, "clocks_ms": true, "analysis": true, "variations": true,
+}
+
+// savedRecord is a GameRecord without its JSON methods
+type savedRecord GameRecord
+
+// UnmarshalJSON reads a record of any version, keeping unknown sections in
+// Extra
+func (r *GameRecord) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*savedRecord)(r)); err != nil {
+		return err
+	}
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return err
+	}
+	maps.DeleteFunc(sections, func(name string, _ json.RawMessage) bool { return recordKeys[name] })
+	r.Extra = nil
+	if len(sections) > 0 {
+		r.Extra = sections
+	}
+	return nil
+}
+
+// MarshalJSON writes the record with its unknown sections after the known
+// ones
+func (r GameRecord) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(savedRecord(r))
+	if err != nil || len(r.Extra) == 0 {
+		return data, err
+	}
+	names := make([]string, 0, len(r.Extra))
+	for name := range r.Extra {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	buf := bytes.NewBuffer(data[:len(data)-1])
+	for _, name := range names {
+		key, _ := json.Marshal(name)
+		fmt.Fprintf(buf, ",%s:%s", key, r.Extra[name])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// NewGameRecord captures the moves of a game
+func NewGameRecord(g *Game) *GameRecord {
+	record := &GameRecord{
+		Version: max(g.recordVersion, RecordVersion),
+		Moves:   make([]string, 0, len(g.History)),
+	}
+
+	for _, move := range g.History {
+		record.Moves = append(record.Moves, FormatMove(move.Position.Row, move.Position.Col))
+	}
+
+	if g.start != nil {
+		record.Start = g.start.PositionString()
+	}
+	if g.GameOver {
+		black, white := g.GetScore()
+		record.Result = fmt.Sprintf("%d-%d", black, white)
+	}
+	if g.EndReason != "" {
+		record.EndReason = g.EndReason
+		record.Winner = GetPieceName(g.Winner)
+	}
+
+	record.Metadata = copyMetadata(g.Metadata)
+	record.Annotations = g.Annotations.clone()
+	record.Extra = maps.Clone(g.recordExtra)
+	return record
+}
+
+// Replay rebuilds the game by applying every recorded move
+func (r *GameRecord) Replay() (*Game, error) {
+	moves := make([]Position, 0, len(r.Moves))
+	for i, move := range r.Moves {
+		row, col, err := ParseMove(move)
+		if err != nil {
+			return nil, fmt.Errorf("move %d (%q): %w", i+1, move, err)
+		}
+		moves = append(moves, Position{Row: row, Col: col})
+	}
+
+	game := NewGame()
+	if r.Start != "" {
+		start, err := ParsePosition(r.Start)
+		if err != nil {
+			return nil, fmt.Errorf("start position: %w", err)
+		}
+		game = NewGameFrom(start)
+	}
+	if ply, err := game.ReplayMoves(moves); err != nil {
+		return nil, fmt.Errorf("move %d (%q): %w", ply+1, r.Moves[ply], err)
+	}
+
+	if err := r.Annotations.check(game.start, moves); err != nil {
+		return nil, err
+	}
+	if r.EndReason != "" {
+		winner, err := parsePieceName(r.Winner)
+		if err != nil {
+			return nil, err
+		}
+		game.EndEarly(winner, r.EndReason)
+	}
+
+	game.Metadata = copyMetadata(r.Metadata)
+	game.Annotations = r.Annotations.clone()
+	game.recordVersion = r.Version
+	game.recordExtra = maps.Clone(r.Extra)
+	return game, nil
+}
+
+// parsePieceName reads a piece written by GetPieceName
+func parsePieceName(name string) (Piece, error) {
+	for _, p := range []Piece{Black, White, Empty} {
+		if name == GetPieceName(p) {
+			return p, nil
+		}
+	}
+	return Empty, fmt.Errorf("unknown winner %q", name)
+}
+
+// copyMetadata copies a metadata map, keeping nil maps nil
+func copyMetadata(md map[string]string) map[string]string {
+	if md == nil {
+		return nil
+	}
+	c := make(map[string]string, len(md))
+	for k, v := range md {
+		c[k] = v
+	}
+	return c
+}
+
+// WriteGameRecord encodes the record as indented JSON
+func WriteGameRecord(w io.Writer, r *GameRecord) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// ReadGameRecord decodes a JSON game record
+func ReadGameRecord(rd io.Reader) (*GameRecord, error) {
+	var r GameRecord
+	if err := json.NewDecoder(rd).Decode(&r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,91 @@
+package model
+
+// EmptyRegions splits the empty squares into regions of squares touching
+// horizontally, vertically or diagonally, in board order
+// Late in the game the regions are played out largely independently, so
+// whoever moves last in each one tends to gain there.
+func (b *Board) EmptyRegions() [][]Position {
+	seen := make([][]bool, b.Size)
+	for i := range seen {
+		seen[i] = make([]bool, b.Size)
+	}
+
+	var regions [][]Position
+	for i := 0; i < b.Size; i++ {
+		for j := 0; j < b.Size; j++ {
+			if seen[i][j] || b.Cells[i][j] != Empty {
+				continue
+			}
+
+			// Flood fill from the first square of a new region
+			seen[i][j] = true
+			region := []Position{{Row: i, Col: j}}
+			for next := 0; next < len(region); next++ {
+				p := region[next]
+				for dr := -1; dr <= 1; dr++ {
+					for dc := -1; dc <= 1; dc++ {
+						r, c := p.Row+dr, p.Col+dc
+						if !b.IsValidPosition(r, c) || seen[r][c] || b.Cells[r][c] != Empty {
+							continue
+						}
+						seen[r][c] = true
+						region = append(region, Position{Row: r, Col: c})
+					}
+				}
+			}
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// Region is a group of empty squares touching each other, with the parity
+// information the endgame turns on
+type Region struct {
+	Squares []Position
+	// Legal moves each side has in the region; a region only one side can
+	// play into is that side's to use for tempo
+	BlackMoves, WhiteMoves int
+}
+
+// Odd reports whether the region has an odd number of squares, so the side
+// that plays first in it also plays last if neither side passes there
+func (r Region) Odd() bool {
+	return len(r.Squares)%2 == 1
+}
+
+// Moves returns the legal moves the side has in the region
+func (r Region) Moves(side Piece) int {
+	if side == Black {
+		return r.BlackMoves
+	}
+	return r.WhiteMoves
+}
+
+// Regions splits the empty squares into regions, as EmptyRegions does, and
+// counts each side's legal moves in them
+func (b *Board) Regions() []Region {
+	empty := b.EmptyRegions()
+	regions := make([]Region, len(empty))
+	index := make(map[Position]int)
+	for i, squares := range empty {
+		regions[i].Squares = squares
+		for _, p := range squares {
+			index[p] = i
+		}
+	}
+
+	current := b.CurrentPlayer
+	for _, side := range []Piece{Black, White} {
+		b.CurrentPlayer = side
+		for _, m := range b.GetValidMoves() {
+			if side == Black {
+				regions[index[m]].BlackMoves++
+			} else {
+				regions[index[m]].WhiteMoves++
+			}
+		}
+	}
+	b.CurrentPlayer = current
+	return regions
+}
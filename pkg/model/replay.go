@@ -0,0 +1,109 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PassPosition is the position used to record a pass
+var PassPosition = Position{Row: -1, Col: -1}
+
+// CheckMoves verifies that every move is a pass or a square on the board,
+// as a parsed game record must hold
+func CheckMoves(moves []Position) error {
+	board := NewBoard()
+	for i, m := range moves {
+		if m != PassPosition && !board.IsValidPosition(m.Row, m.Col) {
+			return fmt.Errorf("move %d (%d, %d): %w", i+1, m.Row, m.Col, ErrOutOfBounds)
+		}
+	}
+	return nil
+}
+
+// ReplayMoves applies a list of moves to the game with full validation
+// A position with negative coordinates is an explicit pass. Passes may also be
+// left out: when the side to move has no legal move it passes automatically
+// before the next move is applied, as in standard transcripts.
+//
+// It returns the index of the first illegal move and the reason, or -1 and nil
+// when every move was legal. Moves before the illegal one remain applied.
+func (g *Game) ReplayMoves(moves []Position) (int, error) {
+	for i, move := range moves {
+		if move.Row < 0 || move.Col < 0 {
+			if err := g.Pass(); err != nil {
+				return i, err
+			}
+			continue
+		}
+
+		// Implicit pass for transcripts that omit them
+		if !g.GameOver && !g.HasValidMove() {
+			if err := g.Pass(); err != nil {
+				return i, err
+			}
+		}
+
+		if err := g.MakeMove(move.Row, move.Col); err != nil {
+			return i, err
+		}
+	}
+
+	return -1, nil
+}
+
+// ParseTranscript splits a transcript such as "F5D6C3" or "f5 d6 pass c3"
+// into positions. Moves may be separated by spaces, commas or nothing at all.
+// On error the moves before the unparsable one are returned with it.
+func ParseTranscript(transcript string) ([]Position, error) {
+	cleaned := strings.NewReplacer(" ", "", ",", "", "\t", "", "\n", "", "\r", "").Replace(transcript)
+	cleaned = strings.ToUpper(cleaned)
+
+	var moves []Position
+	for i := 0; i < len(cleaned); {
+		if strings.HasPrefix(cleaned[i:], "PASS") {
+			moves = append(moves, PassPosition)
+			i += 4
+			continue
+		}
+		if strings.HasPrefix(cleaned[i:], "PA") || strings.HasPrefix(cleaned[i:], "--") {
+			moves = append(moves, PassPosition)
+			i += 2
+			continue
+		}
+
+		if i+2 > len(cleaned) {
+			return moves, fmt.Errorf("move %d: incomplete move %q", len(moves)+1, cleaned[i:])
+		}
+		row, col, err := ParseMove(cleaned[i : i+2])
+		if err != nil {
+			return moves, fmt.Errorf("move %d (%q): %w", len(moves)+1, cleaned[i:i+2], err)
+		}
+		moves = append(moves, Position{Row: row, Col: col})
+		i += 2
+	}
+
+	return moves, nil
+}
+
+// ReplayTranscript parses a transcript and applies it with ReplayMoves
+// Parse errors are reported at the index of the unparsable move.
+func (g *Game) ReplayTranscript(transcript string) (int, error) {
+	moves, err := ParseTranscript(transcript)
+	if err != nil {
+		return len(moves), err
+	}
+	return g.ReplayMoves(moves)
+}
+
+// Transcript formats the game history as a compact transcript like "F5D6C3"
+// Passes are omitted since they are implied by the position
+func (g *Game) Transcript() string {
+	var sb strings.Builder
+	for _, move := range g.History {
+		if move.Position.Row < 0 {
+			continue
+		}
+		sb.WriteString(FormatMove(move.Position.Row, move.Position.Col))
+	}
+	return sb.String()
+}
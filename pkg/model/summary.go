@@ -0,0 +1,29 @@
+package model
+
+// Summary describes how a finished game went
+type Summary struct {
+	Empties int  // Squares left empty at the end
+	Wipeout bool // The loser has no discs left
+
+	// LongestCapture is the move that flipped the most discs, played at
+	// LongestCapturePly (1-based); the ply is 0 when no disc was flipped
+	LongestCapture    Move
+	LongestCapturePly int
+}
+
+// Summarize collects the summary from the board and the history
+func (g *Game) Summarize() Summary {
+	black, white := g.GetScore()
+	s := Summary{
+		Empties: g.Board.Size*g.Board.Size - black - white,
+		Wipeout: g.GameOver && (black == 0 || white == 0),
+	}
+
+	for i, move := range g.History {
+		if move.Flipped > s.LongestCapture.Flipped {
+			s.LongestCapture = move
+			s.LongestCapturePly = i + 1
+		}
+	}
+	return s
+}
@@ -0,0 +1,103 @@
+package model
+
+// Symmetry is one of the eight rotations and reflections of the square board
+type Symmetry int
+
+const (
+	Identity Symmetry = iota
+	Rotate90
+	Rotate180
+	Rotate270
+	FlipHorizontal   // Mirror left to right
+	FlipVertical     // Mirror top to bottom
+	FlipDiagonal     // Mirror along the A1-H8 diagonal
+	FlipAntiDiagonal // Mirror along the H1-A8 diagonal
+)
+
+// Symmetries lists all board symmetries, starting with Identity
+var Symmetries = []Symmetry{
+	Identity, Rotate90, Rotate180, Rotate270,
+	FlipHorizontal, FlipVertical, FlipDiagonal, FlipAntiDiagonal,
+}
+
+// Apply maps a position on a board of the given size through the symmetry
+// Pass positions (negative coordinates) are returned unchanged
+func (s Symmetry) Apply(p Position, size int) Position {
+	if p.Row < 0 || p.Col < 0 {
+		return p
+	}
+
+	last := size - 1
+	r, c := p.Row, p.Col
+	switch s {
+	case Rotate90:
+		return Position{Row: c, Col: last - r}
+	case Rotate180:
+		return Position{Row: last - r, Col: last - c}
+	case Rotate270:
+		return Position{Row: last - c, Col: r}
+	case FlipHorizontal:
+		return Position{Row: r, Col: last - c}
+	case FlipVertical:
+		return Position{Row: last - r, Col: c}
+	case FlipDiagonal:
+		return Position{Row: c, Col: r}
+	case FlipAntiDiagonal:
+		return Position{Row: last - c, Col: last - r}
+	default:
+		return p
+	}
+}
+
+// Inverse returns the symmetry that undoes s
+func (s Symmetry) Inverse() Symmetry {
+	switch s {
+	case Rotate90:
+		return Rotate270
+	case Rotate270:
+		return Rotate90
+	default:
+		// Every other symmetry is its own inverse
+		return s
+	}
+}
+
+// Transform returns a copy of the board mapped through the symmetry
+func (b *Board) Transform(s Symmetry) *Board {
+	out := b.Clone()
+	for i := 0; i < b.Size; i++ {
+		for j := 0; j < b.Size; j++ {
+			p := s.Apply(Position{Row: i, Col: j}, b.Size)
+			out.Cells[p.Row][p.Col] = b.Cells[i][j]
+		}
+	}
+	return out
+}
+
+// Canonical returns the representative of the board's symmetry class along
+// with the symmetry that maps the board onto it
+// Positions that are rotations or reflections of each other share the same
+// canonical form, so it can be used to deduplicate opening books and match
+// imported positions regardless of orientation. A move on the original board
+// maps to the canonical board with sym.Apply and back with sym.Inverse().
+func Canonical(b *Board) (*Board, Symmetry) {
+	best := b.Clone()
+	bestSym := Identity
+	bestKey := best.PositionString()
+
+	for _, s := range Symmetries[1:] {
+		candidate := b.Transform(s)
+		key := candidate.PositionString()
+		if key < bestKey {
+			best, bestSym, bestKey = candidate, s, key
+		}
+	}
+
+	return best, bestSym
+}
+
+// CanonicalHash returns the hash of the board's canonical form
+func CanonicalHash(b *Board) uint64 {
+	canonical, _ := Canonical(b)
+	return canonical.Hash()
+}
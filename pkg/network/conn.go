@@ -0,0 +1,90 @@
+package network
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// MaxMessageSize bounds a single protocol line
+const MaxMessageSize = 64 * 1024
+
+// Conn exchanges protocol messages over a network connection
+type Conn struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+	writeMu sync.Mutex
+
+	// Negotiated during the handshake
+	Version      int
+	Capabilities []string
+	PeerName     string
+}
+
+// NewConn wraps a network connection
+func NewConn(conn net.Conn) *Conn {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), MaxMessageSize)
+	return &Conn{conn: conn, scanner: scanner}
+}
+
+// Send writes one message
+func (c *Conn) Send(msg Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err = c.conn.Write(data)
+	return err
+}
+
+// Receive reads the next message
+func (c *Conn) Receive() (Message, error) {
+	for {
+		if !c.scanner.Scan() {
+			if err := c.scanner.Err(); err != nil {
+				return Message{}, err
+			}
+			return Message{}, ErrClosed
+		}
+
+		line := c.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg Message
+		if err := json.Unmarshal(line, &msg); err != nil {
+			return Message{}, fmt.Errorf("%w: %v", ErrBadMessage, err)
+		}
+		return msg, nil
+	}
+}
+
+// SetDeadline bounds the time for the next reads and writes
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.conn.SetDeadline(t)
+}
+
+// Has reports whether a capability was negotiated
+func (c *Conn) Has(capability string) bool {
+	return HasCapability(c.Capabilities, capability)
+}
+
+// RemoteAddr returns the peer's network address
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// Close sends a goodbye and closes the connection
+func (c *Conn) Close() error {
+	c.Send(Message{Type: TypeBye})
+	return c.conn.Close()
+}
@@ -0,0 +1,363 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/notation"
+)
+
+// Codes for moves refused by the host
+const (
+	CodeNotYourTurn = "not_your_turn"
+	CodeBadNotation = "bad_notation"
+	CodeGameOver    = "game_over"
+	CodeOccupied    = "occupied"
+	CodeNoFlips     = "no_flips"
+	CodeOutOfBounds = "out_of_bounds"
+	CodeCannotPass  = "cannot_pass"
+	CodeIllegalMove = "illegal_move"
+)
+
+// ProtocolError is a request refused by the peer
+type ProtocolError struct {
+	Code   string
+	Reason string
+	Move   string
+}
+
+// Error implements the error interface
+func (e *ProtocolError) Error() string {
+	if e.Move != "" {
+		return fmt.Sprintf("%s rejected (%s): %s", e.Move, e.Code, e.Reason)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Reason)
+}
+
+// ErrorCode maps a rules engine error to its protocol code
+func ErrorCode(err error) string {
+	switch {
+	case errors.Is(err, model.ErrGameOver):
+		return CodeGameOver
+	case errors.Is(err, model.ErrOccupied):
+		return CodeOccupied
+	case errors.Is(err, model.ErrNoFlips):
+		return CodeNoFlips
+	case errors.Is(err, model.ErrOutOfBounds):
+		return CodeOutOfBounds
+	case errors.Is(err, model.ErrCannotPass):
+		return CodeCannotPass
+	case errors.Is(err, notation.ErrSyntax):
+		return CodeBadNotation
+	default:
+		return CodeIllegalMove
+	}
+}
+
+// ColorName returns the protocol name of a color
+func ColorName(p model.Piece) string {
+	return model.GetPieceName(p)
+}
+
+// ParseColor parses a protocol color name
+func ParseColor(name string) (model.Piece, error) {
+	switch strings.ToLower(name) {
+	case "black":
+		return model.Black, nil
+	case "white":
+		return model.White, nil
+	default:
+		return model.Empty, fmt.Errorf("unknown color %q", name)
+	}
+}
+
+// stateMessage describes the authoritative position after a move
+func stateMessage(game *model.Game, move string) Message {
+	return Message{
+		Type:     TypeState,
+		Move:     move,
+		Position: game.Board.PositionString(),
+		Status:   game.GetGameStatus(),
+	}
+}
+
+// applyMove plays a move in notation on the game
+// Moves on the wire must be in canonical notation, so they are parsed
+// strictly.
+func applyMove(game *model.Game, move string) error {
+	row, col, err := notation.Default.Parse(move, notation.Strict)
+	if err != nil {
+		return err
+	}
+	if row < 0 {
+		return game.Pass()
+	}
+	return game.MakeMove(row, col)
+}
+
+// Host owns the authoritative game and validates every move a remote client
+// sends with the rules engine before accepting it
+type Host struct {
+	conn        *Conn
+	mu          sync.Mutex
+	game        *model.Game
+	RemoteColor model.Piece
+
+	// OnUpdate is called from Serve's goroutine after every accepted move
+	// and for every chat message
+	OnUpdate func(Message)
+}
+
+// AcceptClient waits for one client on the listener and performs the handshake,
+// telling the client which color it plays
+func AcceptClient(l net.Listener, name string, remoteColor model.Piece) (*Conn, error) {
+	for {
+		nc, err := l.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		conn := NewConn(nc)
+		if _, err := conn.ServerHandshake(name, Message{Color: ColorName(remoteColor)}); err != nil {
+			// Incompatible clients were already told why; wait for the next one
+			nc.Close()
+			continue
+		}
+		return conn, nil
+	}
+}
+
+// NewHost creates a host for a connected client
+func NewHost(conn *Conn, game *model.Game, remoteColor model.Piece) *Host {
+	return &Host{conn: conn, game: game, RemoteColor: remoteColor}
+}
+
+// Serve processes client messages until the connection closes
+func (h *Host) Serve() error {
+	for {
+		msg, err := h.conn.Receive()
+		if err != nil {
+			if errors.Is(err, ErrBadMessage) {
+				h.conn.Send(Message{Type: TypeError, Code: CodeBadMessage, Reason: err.Error()})
+				continue
+			}
+			return err
+		}
+
+		switch msg.Type {
+		case TypeMove:
+			h.handleRemoteMove(msg.Move)
+		case TypeChat:
+			if h.conn.Has(CapChat) {
+				h.notify(msg)
+			} else {
+				h.conn.Send(Message{Type: TypeError, Code: CodeUnsupported, Reason: "chat was not negotiated"})
+			}
+		case TypeBye:
+			return ErrClosed
+		}
+	}
+}
+
+// handleRemoteMove validates and applies a client move
+func (h *Host) handleRemoteMove(move string) {
+	h.mu.Lock()
+	if h.game.Board.CurrentPlayer != h.RemoteColor {
+		h.mu.Unlock()
+		h.conn.Send(Message{Type: TypeError, Code: CodeNotYourTurn, Move: move, Reason: "it is not your turn"})
+		return
+	}
+
+	if err := applyMove(h.game, move); err != nil {
+		h.mu.Unlock()
+		code := ErrorCode(err)
+		if !errors.As(err, new(*model.MoveError)) {
+			code = CodeBadNotation
+		}
+		h.conn.Send(Message{Type: TypeError, Code: code, Move: move, Reason: err.Error()})
+		return
+	}
+
+	state := stateMessage(h.game, move)
+	h.mu.Unlock()
+
+	h.conn.Send(state)
+	h.notify(state)
+}
+
+// PlayMove applies a move by the host's own player and informs the client
+func (h *Host) PlayMove(row, col int) error {
+	h.mu.Lock()
+	if h.game.Board.CurrentPlayer == h.RemoteColor {
+		h.mu.Unlock()
+		return &ProtocolError{Code: CodeNotYourTurn, Reason: "it is the remote player's turn"}
+	}
+
+	var err error
+	if row < 0 || col < 0 {
+		err = h.game.Pass()
+	} else {
+		err = h.game.MakeMove(row, col)
+	}
+	if err != nil {
+		h.mu.Unlock()
+		return err
+	}
+
+	state := stateMessage(h.game, model.FormatMove(row, col))
+	h.mu.Unlock()
+
+	return h.conn.Send(state)
+}
+
+// Chat sends a chat message if the client supports it
+func (h *Host) Chat(text string) error {
+	if !h.conn.Has(CapChat) {
+		return &ProtocolError{Code: CodeUnsupported, Reason: "chat was not negotiated"}
+	}
+	return h.conn.Send(Message{Type: TypeChat, Text: text})
+}
+
+// WithGame runs fn while holding the game lock
+func (h *Host) WithGame(fn func(game *model.Game)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fn(h.game)
+}
+
+// Close ends the session
+func (h *Host) Close() error {
+	return h.conn.Close()
+}
+
+// notify invokes the update callback, if set
+func (h *Host) notify(msg Message) {
+	if h.OnUpdate != nil {
+		h.OnUpdate(msg)
+	}
+}
+
+// Client plays against a host, mirroring the host's authoritative game
+type Client struct {
+	conn  *Conn
+	mu    sync.Mutex
+	game  *model.Game
+	Color model.Piece
+
+	// OnUpdate is called from Serve's goroutine for every state change, chat
+	// message and refused move
+	OnUpdate func(Message)
+}
+
+// Dial connects to a host and performs the handshake
+func Dial(addr, name string) (*Client, error) {
+	nc, err := net.DialTimeout("tcp", addr, HandshakeTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := NewConn(nc)
+	welcome, err := conn.ClientHandshake(name)
+	if err != nil {
+		nc.Close()
+		return nil, err
+	}
+
+	color, err := ParseColor(welcome.Color)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Client{conn: conn, game: model.NewGame(), Color: color}, nil
+}
+
+// PeerName returns the name the host gave in the handshake
+func (c *Client) PeerName() string {
+	return c.conn.PeerName
+}
+
+// SendMove asks the host to play a move; the result arrives through OnUpdate
+func (c *Client) SendMove(row, col int) error {
+	return c.conn.Send(Message{Type: TypeMove, Move: model.FormatMove(row, col)})
+}
+
+// Chat sends a chat message if the host supports it
+func (c *Client) Chat(text string) error {
+	if !c.conn.Has(CapChat) {
+		return &ProtocolError{Code: CodeUnsupported, Reason: "chat was not negotiated"}
+	}
+	return c.conn.Send(Message{Type: TypeChat, Text: text})
+}
+
+// Serve processes host messages until the connection closes
+func (c *Client) Serve() error {
+	for {
+		msg, err := c.conn.Receive()
+		if err != nil {
+			return err
+		}
+
+		switch msg.Type {
+		case TypeState:
+			c.sync(msg)
+			c.notify(msg)
+		case TypeChat, TypeError:
+			c.notify(msg)
+		case TypeBye:
+			return ErrClosed
+		}
+	}
+}
+
+// sync applies the host's move to the local game. If the two then
+// disagree, a position one move ahead is caught up by playing that move in
+// place; any other is adopted without the history before it.
+func (c *Client) sync(msg Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if msg.Move != "" {
+		applyMove(c.game, msg.Move)
+	}
+
+	if msg.Position == "" || c.game.Board.PositionString() == msg.Position {
+		return
+	}
+	board, err := model.ParsePosition(msg.Position)
+	if err != nil {
+		return
+	}
+	if diff := model.Diff(c.game.Board, board); diff.IsSingleMove(c.game.Board) {
+		move := diff.Added[0]
+		if c.game.MakeMove(move.Row, move.Col) == nil && c.game.Board.PositionString() == msg.Position {
+			return
+		}
+	}
+	// The local history no longer leads to the position, so the game
+	// restarts from it
+	c.game = model.NewGameFrom(board)
+}
+
+// WithGame runs fn while holding the game lock
+func (c *Client) WithGame(fn func(game *model.Game)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(c.game)
+}
+
+// Close ends the session
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// notify invokes the update callback, if set
+func (c *Client) notify(msg Message) {
+	if c.OnUpdate != nil {
+		c.OnUpdate(msg)
+	}
+}
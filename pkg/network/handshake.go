@@ -0,0 +1,122 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HandshakeTimeout bounds the whole handshake
+const HandshakeTimeout = 10 * time.Second
+
+// Connection errors
+var (
+	ErrClosed     = errors.New("connection closed")
+	ErrBadMessage = errors.New("malformed message")
+)
+
+// RejectedError is returned to a client whose handshake the host refused
+type RejectedError struct {
+	Code   string
+	Reason string
+}
+
+// Error implements the error interface
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("connection rejected (%s): %s", e.Code, e.Reason)
+}
+
+// ClientHandshake introduces the client and waits for the host's answer
+// On success the welcome message is returned and the connection records the
+// negotiated version and capabilities.
+func (c *Conn) ClientHandshake(name string) (Message, error) {
+	c.SetDeadline(time.Now().Add(HandshakeTimeout))
+	defer c.SetDeadline(time.Time{})
+
+	hello := Message{
+		Type:         TypeHello,
+		Version:      ProtocolVersion,
+		MinVersion:   MinProtocolVersion,
+		Capabilities: SupportedCapabilities,
+		Name:         name,
+	}
+	if err := c.Send(hello); err != nil {
+		return Message{}, err
+	}
+
+	reply, err := c.Receive()
+	if err != nil {
+		return Message{}, err
+	}
+
+	switch reply.Type {
+	case TypeWelcome:
+		if reply.Version < MinProtocolVersion || reply.Version > ProtocolVersion {
+			return Message{}, &RejectedError{Code: CodeVersionUnsupported,
+				Reason: fmt.Sprintf("host chose protocol version %d", reply.Version)}
+		}
+		c.Version = reply.Version
+		c.Capabilities = intersectCapabilities(SupportedCapabilities, reply.Capabilities)
+		c.PeerName = reply.Name
+		return reply, nil
+	case TypeReject:
+		return Message{}, &RejectedError{Code: reply.Code, Reason: reply.Reason}
+	default:
+		return Message{}, &RejectedError{Code: CodeBadHandshake,
+			Reason: fmt.Sprintf("unexpected %q message during handshake", reply.Type)}
+	}
+}
+
+// ServerHandshake waits for a client's hello and negotiates the version and
+// capabilities. Incompatible clients receive a reject message explaining why.
+// welcome supplies host-specific fields such as the client's color.
+func (c *Conn) ServerHandshake(name string, welcome Message) (Message, error) {
+	c.SetDeadline(time.Now().Add(HandshakeTimeout))
+	defer c.SetDeadline(time.Time{})
+
+	hello, err := c.Receive()
+	if err != nil {
+		return Message{}, err
+	}
+
+	if hello.Type != TypeHello {
+		return Message{}, c.reject(CodeBadHandshake, fmt.Sprintf("expected hello, got %q", hello.Type))
+	}
+
+	// Version 0 means the field was missing
+	clientMin := hello.MinVersion
+	if clientMin == 0 {
+		clientMin = hello.Version
+	}
+	version := min(hello.Version, ProtocolVersion)
+	if version < MinProtocolVersion || version < clientMin {
+		return Message{}, c.reject(CodeVersionUnsupported, fmt.Sprintf(
+			"client speaks protocol %d-%d, host speaks %d-%d",
+			clientMin, hello.Version, MinProtocolVersion, ProtocolVersion))
+	}
+
+	c.Version = version
+	c.Capabilities = intersectCapabilities(SupportedCapabilities, hello.Capabilities)
+	c.PeerName = hello.Name
+
+	welcome.Type = TypeWelcome
+	welcome.Version = version
+	welcome.Capabilities = c.Capabilities
+	welcome.Name = name
+	if err := c.Send(welcome); err != nil {
+		return Message{}, err
+	}
+	return hello, nil
+}
+
+// Reject refuses a connection with a reason and closes it
+func (c *Conn) Reject(code, reason string) error {
+	return c.reject(code, reason)
+}
+
+// reject sends a rejection, closes the connection and returns the error
+func (c *Conn) reject(code, reason string) error {
+	c.Send(Message{Type: TypeReject, Code: code, Reason: reason})
+	c.conn.Close()
+	return &RejectedError{Code: code, Reason: reason}
+}
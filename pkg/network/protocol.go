@@ -0,0 +1,94 @@
+package network
+
+// Protocol versions
+// ProtocolVersion is the newest version this build speaks; MinProtocolVersion
+// is the oldest peer version it still accepts. Bump ProtocolVersion for any
+// change an older peer could misinterpret, and raise MinProtocolVersion only
+// when support for old peers is dropped.
+const (
+	ProtocolVersion    = 1
+	MinProtocolVersion = 1
+)
+
+// DefaultPort is the TCP port games are hosted on unless another is given
+const DefaultPort = "7531"
+
+// Capability flags advertised during the handshake
+// A feature is only used when both sides advertise it; unknown capabilities
+// are ignored so new ones can be added without a version bump.
+const (
+	CapVariants = "variants"
+	CapClocks   = "clocks"
+	CapChat     = "chat"
+)
+
+// SupportedCapabilities lists the capabilities this build implements
+var SupportedCapabilities = []string{CapChat}
+
+// Message types
+// Receivers ignore message types they do not know.
+const (
+	TypeHello   = "hello"   // Client -> host: first message of a connection
+	TypeWelcome = "welcome" // Host -> client: handshake accepted
+	TypeReject  = "reject"  // Host -> client: handshake refused, connection closes
+	TypeMove    = "move"    // Either side: a move in notation, or "Pass"
+	TypeState   = "state"   // Host -> client: authoritative position after a move
+	TypeChat    = "chat"    // Either side: chat text, requires CapChat
+	TypeError   = "error"   // Either side: a request was refused
+	TypeBye     = "bye"     // Either side: orderly disconnect
+)
+
+// Error and rejection codes
+const (
+	CodeVersionUnsupported = "version_unsupported"
+	CodeBadHandshake       = "bad_handshake"
+	CodeGameFull           = "game_full"
+	CodeBadMessage         = "bad_message"
+	CodeUnsupported        = "unsupported"
+)
+
+// Message is a single protocol message, sent as one JSON object per line
+// Only the fields relevant to Type are set.
+type Message struct {
+	Type string `json:"type"`
+
+	// Handshake
+	Version      int      `json:"version,omitempty"`
+	MinVersion   int      `json:"min_version,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Color        string   `json:"color,omitempty"`
+
+	// Game play
+	Move     string `json:"move,omitempty"`
+	Position string `json:"position,omitempty"`
+	Status   string `json:"status,omitempty"`
+
+	// Chat
+	Text string `json:"text,omitempty"`
+
+	// Errors and rejections
+	Code   string `json:"code,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// HasCapability reports whether the list contains the capability
+func HasCapability(caps []string, capability string) bool {
+	for _, c := range caps {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// intersectCapabilities returns the capabilities present in both lists
+func intersectCapabilities(a, b []string) []string {
+	var common []string
+	for _, c := range a {
+		if HasCapability(b, c) {
+			common = append(common, c)
+		}
+	}
+	return common
+}
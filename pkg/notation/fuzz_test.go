@@ -0,0 +1,30 @@
+package notation
+
+import "testing"
+
+// FuzzParse checks that any text either fails to parse or names a square on
+// the board, and that every square reads back as written
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{"d3", "D3", " e6 ", "pass", "PA", "--", "i9", "a0", "a10", "44", "", "\x00"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, mode := range []Mode{Lenient, Strict} {
+			row, col, err := Default.Parse(s, mode)
+			if err != nil {
+				continue
+			}
+			if row == -1 && col == -1 {
+				continue
+			}
+			if row < 0 || row >= 8 || col < 0 || col >= 8 {
+				t.Fatalf("Parse(%q) = %d, %d: off the board", s, row, col)
+			}
+			formatted := Default.Format(row, col)
+			r, c, err := Default.Parse(formatted, Strict)
+			if err != nil || r != row || c != col {
+				t.Fatalf("Parse(%q) = %d, %d, but %q reads back as %d, %d, %v", s, row, col, formatted, r, c, err)
+			}
+		}
+	})
+}
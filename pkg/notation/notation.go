@@ -0,0 +1,252 @@
+// Package notation converts between board coordinates and move notation.
+//
+// Two styles are supported: algebraic notation names the column with a
+// letter and the row with a number ("E4"), numeric notation writes the row
+// and column as two digits counted from 1, the encoding used by WTHOR game
+// databases (E4 is "45"). Column letters can be localized. Coordinates are
+// zero-based; a negative row or column is a pass.
+package notation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Size is the number of rows and columns on the board
+const Size = 8
+
+// Style selects how squares are written
+type Style int
+
+const (
+	Algebraic Style = iota // Column letter and row number, e.g. "E4"
+	Numeric                // Row and column digits, e.g. "45"
+)
+
+// Mode controls how forgiving parsing is
+type Mode int
+
+const (
+	// Lenient accepts any letter case and surrounding whitespace
+	Lenient Mode = iota
+	// Strict accepts only what Format produces, apart from letter case
+	Strict
+)
+
+// Parsing errors; use errors.Is to test for them
+var (
+	ErrSyntax      = errors.New("invalid move format")
+	ErrOutOfBounds = errors.New("position out of bounds")
+)
+
+// LatinColumns are the standard column letters
+var LatinColumns = []string{"A", "B", "C", "D", "E", "F", "G", "H"}
+
+// Notation is a move notation
+type Notation struct {
+	Style   Style
+	Columns []string // Column labels for algebraic notation, LatinColumns if nil
+	Pass    string   // Written form of a pass
+}
+
+// Default is the notation used throughout the program: "E4" and "Pass"
+var Default = Notation{Style: Algebraic, Columns: LatinColumns, Pass: "Pass"}
+
+// WithColumns returns the notation with localized column labels
+func (n Notation) WithColumns(columns []string) Notation {
+	n.Columns = columns
+	return n
+}
+
+// columns returns the column labels in use
+func (n Notation) columns() []string {
+	if len(n.Columns) == Size {
+		return n.Columns
+	}
+	return LatinColumns
+}
+
+// pass returns the written form of a pass
+func (n Notation) pass() string {
+	if n.Pass == "" {
+		return Default.Pass
+	}
+	return n.Pass
+}
+
+// Format writes a square, or a pass for negative coordinates
+func (n Notation) Format(row, col int) string {
+	if row < 0 || col < 0 {
+		return n.pass()
+	}
+	if n.Style == Numeric {
+		return string([]byte{byte('1' + row), byte('1' + col)})
+	}
+	return n.columns()[col] + itoa(row+1)
+}
+
+// ParseError describes why a move could not be parsed
+type ParseError struct {
+	Input  string
+	Err    error  // ErrSyntax or ErrOutOfBounds
+	Detail string // What was wrong, for the user
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("%v: %q", e.Err, e.Input)
+	}
+	return fmt.Sprintf("%v: %q: %s", e.Err, e.Input, e.Detail)
+}
+
+// Unwrap returns ErrSyntax or ErrOutOfBounds
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// passWords are the spellings of a pass accepted in lenient mode, besides
+// the notation's own
+var passWords = []string{"pass", "pa", "--", "p"}
+
+// Parse reads a square or pass, returning -1, -1 for a pass
+// Strict mode accepts only the canonical form Format produces, ignoring
+// letter case. Lenient mode also accepts surrounding and inner whitespace
+// ("E 4"), leading zeros and the pass spellings "pass", "pa", "--" and "p".
+// Both modes reject trailing garbage such as "E4x".
+func (n Notation) Parse(s string, mode Mode) (int, int, error) {
+	input := s
+	fail := func(e *ParseError) (int, int, error) {
+		e.Input = input
+		return -1, -1, e
+	}
+
+	if mode == Lenient {
+		s = strings.Join(strings.Fields(s), "")
+	}
+	if s == "" {
+		return fail(&ParseError{Err: ErrSyntax, Detail: "empty move"})
+	}
+	if strings.EqualFold(s, n.pass()) {
+		return -1, -1, nil
+	}
+	if mode == Lenient {
+		for _, word := range passWords {
+			if strings.EqualFold(s, word) {
+				return -1, -1, nil
+			}
+		}
+	}
+
+	var row, col int
+	var perr *ParseError
+	if n.Style == Numeric {
+		row, col, perr = n.parseNumeric(s)
+	} else {
+		row, col, perr = n.parseAlgebraic(s)
+	}
+	if perr != nil {
+		return fail(perr)
+	}
+	if mode == Strict && !strings.EqualFold(s, n.Format(row, col)) {
+		return fail(&ParseError{Err: ErrSyntax, Detail: "expected " + n.Format(row, col)})
+	}
+	return row, col, nil
+}
+
+// parseAlgebraic reads a column label followed by a row number
+func (n Notation) parseAlgebraic(s string) (int, int, *ParseError) {
+	columns := n.columns()
+	expected := fmt.Sprintf("expected a column %s-%s and a row 1-%d, e.g. %s", columns[0], columns[Size-1], Size, n.Format(3, 4))
+
+	col := -1
+	rest := s
+	for i, label := range columns {
+		if len(s) >= len(label) && strings.EqualFold(s[:len(label)], label) {
+			col, rest = i, s[len(label):]
+			break
+		}
+	}
+	if col < 0 {
+		// A letter that is not a column is still well formed, just off the
+		// board, e.g. "J4"
+		r, size := utf8.DecodeRuneInString(s)
+		if !unicode.IsLetter(r) {
+			return -1, -1, &ParseError{Err: ErrSyntax, Detail: expected}
+		}
+		if _, ok := atoi(s[size:]); ok {
+			return -1, -1, &ParseError{Err: ErrOutOfBounds, Detail: fmt.Sprintf("column %s is off the board", s[:size])}
+		}
+		rest = s[size:]
+	}
+
+	digits := len(rest) - len(strings.TrimLeftFunc(rest, func(r rune) bool { return r < utf8.RuneSelf && isDigit(byte(r)) }))
+	if digits == 0 {
+		return -1, -1, &ParseError{Err: ErrSyntax, Detail: expected}
+	}
+	if digits < len(rest) {
+		return -1, -1, &ParseError{Err: ErrSyntax, Detail: fmt.Sprintf("unexpected %q after the square", rest[digits:])}
+	}
+
+	row, _ := atoi(rest)
+	if row < 1 || row > Size {
+		return -1, -1, &ParseError{Err: ErrOutOfBounds, Detail: fmt.Sprintf("row %s is off the board", rest)}
+	}
+	return row - 1, col, nil
+}
+
+// parseNumeric reads two digits, row then column
+func (n Notation) parseNumeric(s string) (int, int, *ParseError) {
+	if len(s) != 2 || !isDigit(s[0]) || !isDigit(s[1]) {
+		return -1, -1, &ParseError{Err: ErrSyntax, Detail: fmt.Sprintf("expected a row and a column digit, e.g. %s", n.Format(3, 4))}
+	}
+	row, col := int(s[0])-'1', int(s[1])-'1'
+	if row < 0 || row >= Size || col < 0 || col >= Size {
+		return -1, -1, &ParseError{Err: ErrOutOfBounds, Detail: fmt.Sprintf("digits must be 1-%d", Size)}
+	}
+	return row, col, nil
+}
+
+// atoi parses a positive decimal number of at most two digits; longer
+// numbers are reported as out of range rather than truncated
+func atoi(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if !isDigit(s[i]) {
+			return 0, false
+		}
+		if n <= Size {
+			n = n*10 + int(s[i]-'0')
+		}
+	}
+	return n, true
+}
+
+// itoa formats a row number
+func itoa(n int) string {
+	if n < 10 {
+		return string(rune('0' + n))
+	}
+	return string([]byte{byte('0' + n/10), byte('0' + n%10)})
+}
+
+// isDigit reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Format writes a square in the default notation
+func Format(row, col int) string {
+	return Default.Format(row, col)
+}
+
+// Parse reads a square in the default notation, leniently
+func Parse(s string) (int, int, error) {
+	return Default.Parse(s, Lenient)
+}
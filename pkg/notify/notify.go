@@ -0,0 +1,151 @@
+// Package notify tells players it is their move while they are away from the
+// game, with a desktop notification and by posting to a webhook, so
+// correspondence and network games do not stall unnoticed.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// errNoNotifier is returned when no desktop notification tool is available
+var errNoNotifier = errors.New("no desktop notification tool found")
+
+// webhookTimeout bounds a webhook request
+const webhookTimeout = 10 * time.Second
+
+// Kinds of game turns are sent for
+const (
+	KindCorrespondence = "correspondence"
+	KindNetwork        = "network"
+)
+
+// Turn is a game in which it has become the player's move
+type Turn struct {
+	Kind     string `json:"kind"` // KindCorrespondence or KindNetwork
+	Game     string `json:"game,omitempty"`
+	Player   string `json:"player,omitempty"`
+	Opponent string `json:"opponent"`
+	Color    string `json:"color"`          // The player's color
+	Move     string `json:"move,omitempty"` // The opponent's last move
+}
+
+// Text describes the turn in one line
+func (t Turn) Text() string {
+	if t.Move == "" {
+		return fmt.Sprintf("Your move as %s against %s", t.Color, t.Opponent)
+	}
+	return fmt.Sprintf("%s played %s. Your move as %s.", t.Opponent, t.Move, t.Color)
+}
+
+// webhookPayload is posted to the webhook, with a text field chat services
+// like Slack and Discord show as the message
+type webhookPayload struct {
+	Event   string `json:"event"`
+	Text    string `json:"text"`
+	Content string `json:"content"`
+	Turn    Turn   `json:"turn"`
+}
+
+// Notifier sends turn notifications
+type Notifier struct {
+	Desktop bool   // Show a desktop notification
+	Webhook string // URL turns are posted to as JSON, empty for none
+	HTTP    *http.Client
+}
+
+// New creates a notifier; it does nothing unless desktop notifications or a
+// webhook are enabled
+func New(desktop bool, webhook string) *Notifier {
+	return &Notifier{Desktop: desktop, Webhook: webhook, HTTP: &http.Client{Timeout: webhookTimeout}}
+}
+
+// Enabled reports whether the notifier sends anything
+func (n *Notifier) Enabled() bool {
+	return n != nil && (n.Desktop || n.Webhook != "")
+}
+
+// YourTurn tells the player of the turn in every way enabled; a way that
+// fails does not stop the others
+func (n *Notifier) YourTurn(t Turn) error {
+	if !n.Enabled() {
+		return nil
+	}
+	var errs []error
+	if n.Desktop {
+		errs = append(errs, showDesktop("Othello", t.Text()))
+	}
+	if n.Webhook != "" {
+		errs = append(errs, n.post(t))
+	}
+	return errors.Join(errs...)
+}
+
+// post sends the turn to the webhook
+func (n *Notifier) post(t Turn) error {
+	text := t.Text()
+	data, err := json.Marshal(webhookPayload{Event: "your_turn", Text: text, Content: text, Turn: t})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.Webhook, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+// Desktops have no common notification API, so the system's tools are used
+
+// desktopCommand returns the command showing a notification
+func desktopCommand(title, text string) []string {
+	switch runtime.GOOS {
+	case "windows":
+		script := `Add-Type -AssemblyName System.Windows.Forms; ` +
+			`$n = New-Object System.Windows.Forms.NotifyIcon; ` +
+			`$n.Icon = [System.Drawing.SystemIcons]::Information; $n.Visible = $true; ` +
+			`$n.ShowBalloonTip(10000, $env:OTHELLO_TITLE, $env:OTHELLO_TEXT, 'Info'); Start-Sleep 10; $n.Dispose()`
+		return []string{"powershell", "-NoProfile", "-Command", script}
+	case "darwin":
+		return []string{"osascript", "-e", `display notification (system attribute "OTHELLO_TEXT") with title (system attribute "OTHELLO_TITLE")`}
+	default:
+		return []string{"notify-send", title, text}
+	}
+}
+
+// showDesktop shows a desktop notification without waiting for it to close
+// The text is passed through the environment where a script shows it, so
+// it is never parsed as code.
+func showDesktop(title, text string) error {
+	args := desktopCommand(title, text)
+	if _, err := exec.LookPath(args[0]); err != nil {
+		return errNoNotifier
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), "OTHELLO_TITLE="+title, "OTHELLO_TEXT="+text)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
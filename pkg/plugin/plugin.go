@@ -0,0 +1,152 @@
+// Package plugin finds engines installed alongside the program so they can
+// be played like the built-in AI. Each engine is described by a JSON
+// manifest in the engines directory:
+//
+//	{"name": "Edax", "command": "edax-rpc", "args": ["--level", "10"]}
+//
+// A relative command is found next to the manifest. It is started for
+// every game and spoken to in JSON-RPC 2.0, one message per line on its
+// standard input and output; its standard error goes to the log. The one
+// method called is get_move, with the position in the format of
+// model.Board.PositionString:
+//
+//	--> {"jsonrpc":"2.0","id":1,"method":"get_move","params":{"position":"...","color":"black","moves":["D3","C4","F5","E6"]}}
+//	<-- {"jsonrpc":"2.0","id":1,"result":{"move":"D3"}}
+//
+// Bot scripts (see package script) in the directory are engines too, named
+// after their file.
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/script"
+)
+
+// ErrBadManifest is returned for a manifest without a name or command
+var ErrBadManifest = errors.New("engine manifest needs a name and a command")
+
+// Engine is an engine found in the engines directory
+type Engine struct {
+	Name    string   `json:"name"`
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+
+	dir    string         // Directory of the manifest
+	script *script.Script // Set for bot scripts
+}
+
+// Player is an engine playing one game; Close stops it
+type Player interface {
+	engine.Player
+	io.Closer
+}
+
+// DefaultDir returns the engines directory in the user's config directory
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "engines"
+	}
+	return filepath.Join(dir, "othello", "engines")
+}
+
+// Discover returns the engines in dir, sorted by name. Manifests and
+// scripts that cannot be read are logged and left out; a missing
+// directory has no engines.
+func Discover(dir string) ([]Engine, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	log := logging.For("plugin")
+	var engines []Engine
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		var e Engine
+		switch filepath.Ext(entry.Name()) {
+		case ".json":
+			e, err = readManifest(path)
+		case ".star":
+			e.Name = strings.TrimSuffix(entry.Name(), ".star")
+			e.script, err = script.Load(path)
+		default:
+			continue
+		}
+		if err != nil {
+			log.Warn("skipping engine", "file", path, "err", err)
+			continue
+		}
+		engines = append(engines, e)
+	}
+	sort.Slice(engines, func(i, j int) bool { return engines[i].Name < engines[j].Name })
+	return engines, nil
+}
+
+// readManifest reads an engine manifest
+func readManifest(path string) (Engine, error) {
+	var e Engine
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return e, err
+	}
+	if err := json.Unmarshal(data, &e); err != nil {
+		return e, fmt.Errorf("%s: %w", path, err)
+	}
+	if e.Name == "" || e.Command == "" {
+		return e, fmt.Errorf("%s: %w", path, ErrBadManifest)
+	}
+	e.dir = filepath.Dir(path)
+	return e, nil
+}
+
+// New starts the engine for one game, playing piece
+func (e Engine) New(piece model.Piece) (Player, error) {
+	if e.script != nil {
+		p, err := e.script.Player(ai.Medium, piece)
+		if err != nil {
+			return nil, err
+		}
+		return scriptPlayer{p}, nil
+	}
+	command := e.Command
+	if !filepath.IsAbs(command) && strings.ContainsAny(command, `/\`) {
+		command = filepath.Join(e.dir, command)
+	}
+	p, err := Start(e.Name, command, e.Args...)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// scriptPlayer is a bot script playing as an engine; there is nothing to
+// stop
+type scriptPlayer struct {
+	engine.Player
+}
+
+// Close does nothing
+func (scriptPlayer) Close() error { return nil }
+
+// ObserveMove passes moves on to scripts that want them
+func (p scriptPlayer) ObserveMove(board *model.Board, move model.Move) {
+	if o, ok := p.Player.(engine.MoveObserver); ok {
+		o.ObserveMove(board, move)
+	}
+}
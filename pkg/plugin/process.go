@@ -0,0 +1,199 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/notation"
+)
+
+// MoveTimeout is how long an engine may think about a move
+const MoveTimeout = time.Minute
+
+// Engine process errors
+var (
+	ErrTimeout = errors.New("engine did not answer in time")
+	ErrExited  = errors.New("engine exited")
+)
+
+// Process is an engine running as a child process
+type Process struct {
+	name  string
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	lines chan []byte // Lines the engine wrote, closed when it exits
+
+	mu sync.Mutex // One request at a time
+	id int
+}
+
+// request is a JSON-RPC request
+type request struct {
+	Version string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// response is a JSON-RPC response
+type response struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// moveParams are the parameters of get_move
+type moveParams struct {
+	Position string   `json:"position"`
+	Color    string   `json:"color"`
+	Moves    []string `json:"moves"`
+}
+
+// moveResult is the result of get_move
+type moveResult struct {
+	Move string `json:"move"`
+}
+
+// Start runs command as the engine called name
+func Start(name, command string, args ...string) (*Process, error) {
+	cmd := exec.Command(command, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	cmd.Stderr = &logWriter{name: name}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("engine %s: %w", name, err)
+	}
+
+	p := &Process{name: name, cmd: cmd, stdin: stdin, lines: make(chan []byte)}
+	go func() {
+		defer close(p.lines)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			p.lines <- append([]byte(nil), scanner.Bytes()...)
+		}
+	}()
+	logging.For("plugin").Info("engine started", "engine", name, "pid", cmd.Process.Pid)
+	return p, nil
+}
+
+// GetMove asks the engine for its move
+func (p *Process) GetMove(board *model.Board) (int, int, error) {
+	params := moveParams{Position: board.PositionString(), Color: "black", Moves: []string{}}
+	if board.CurrentPlayer == model.White {
+		params.Color = "white"
+	}
+	for _, m := range board.GetValidMoves() {
+		params.Moves = append(params.Moves, notation.Format(m.Row, m.Col))
+	}
+
+	var result moveResult
+	if err := p.call("get_move", params, &result); err != nil {
+		return -1, -1, err
+	}
+	row, col, err := notation.Parse(result.Move)
+	if err != nil {
+		return -1, -1, fmt.Errorf("engine %s: %w", p.name, err)
+	}
+	return row, col, nil
+}
+
+// call sends a request and waits for its response
+func (p *Process) call(method string, params, result any) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.id++
+	data, err := json.Marshal(request{Version: "2.0", ID: p.id, Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	if _, err := p.stdin.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("engine %s: %w", p.name, err)
+	}
+
+	timeout := time.NewTimer(MoveTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case line, ok := <-p.lines:
+			if !ok {
+				return fmt.Errorf("engine %s: %w", p.name, ErrExited)
+			}
+			var resp response
+			if json.Unmarshal(line, &resp) != nil || resp.ID != p.id {
+				continue // Not the answer, e.g. a stray print
+			}
+			if resp.Error != nil {
+				return fmt.Errorf("engine %s: %s (%d)", p.name, resp.Error.Message, resp.Error.Code)
+			}
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("engine %s: %w", p.name, err)
+			}
+			return nil
+		case <-timeout.C:
+			return fmt.Errorf("engine %s: %w", p.name, ErrTimeout)
+		}
+	}
+}
+
+// Close stops the engine: its input is closed, and it is killed if it has
+// not exited a moment later
+func (p *Process) Close() error {
+	p.stdin.Close()
+	done := make(chan struct{})
+	go func() {
+		for range p.lines {
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		p.cmd.Process.Kill()
+	}
+	err := p.cmd.Wait()
+	logging.For("plugin").Info("engine stopped", "engine", p.name)
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return nil // Killed, or exited unhappy with its input closing
+	}
+	return err
+}
+
+// logWriter logs what an engine writes to its standard error, a line at a
+// time
+type logWriter struct {
+	name string
+	buf  []byte
+}
+
+// Write logs every complete line in data
+func (w *logWriter) Write(data []byte) (int, error) {
+	w.buf = append(w.buf, data...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		logging.For("plugin").Info(string(w.buf[:i]), "engine", w.name)
+		w.buf = w.buf[i+1:]
+	}
+	return len(data), nil
+}
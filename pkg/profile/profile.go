@@ -0,0 +1,228 @@
+// Package profile keeps the local users of one machine apart. Each profile
+// is a directory of its own holding the user's settings, game library, and
+// with it their statistics, and an avatar shown on the player cards:
+//
+//	profiles/<name>/config.json
+//	profiles/<name>/library/...
+//	profiles/<name>/avatar.png
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // Avatars may be GIF, JPEG or PNG
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+)
+
+// avatarSize is the width and height avatars are stored at, in pixels
+const avatarSize = 128
+
+// Errors returned by Store
+var (
+	ErrNotFound    = errors.New("profile not found")
+	ErrExists      = errors.New("profile already exists")
+	ErrInvalidName = errors.New("invalid profile name")
+)
+
+// Profile is one local user
+type Profile struct {
+	Name string
+	Dir  string
+}
+
+// ConfigPath returns the profile's settings file
+func (p Profile) ConfigPath() string {
+	return filepath.Join(p.Dir, "config.json")
+}
+
+// LibraryDir returns the directory of the profile's game library
+func (p Profile) LibraryDir() string {
+	return filepath.Join(p.Dir, "library")
+}
+
+// AvatarPath returns the profile's avatar image file
+func (p Profile) AvatarPath() string {
+	return filepath.Join(p.Dir, "avatar.png")
+}
+
+// Avatar loads the profile's avatar, or returns nil when it has none
+func (p Profile) Avatar() (image.Image, error) {
+	f, err := os.Open(p.AvatarPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", p.AvatarPath(), err)
+	}
+	return img, nil
+}
+
+// Store keeps profiles as subdirectories of a directory
+type Store struct {
+	dir string
+}
+
+// DefaultDir returns the profiles directory in the user's config directory
+func DefaultDir() string {
+	return filepath.Join(filepath.Dir(config.DefaultPath()), "profiles")
+}
+
+// NewStore creates a store of the profiles in dir, which is created when
+// the first profile is
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// List returns the profiles sorted by name
+func (s *Store) List() ([]Profile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var profiles []Profile
+	for _, e := range entries {
+		if e.IsDir() && validName(e.Name()) {
+			profiles = append(profiles, s.profile(e.Name()))
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return strings.ToLower(profiles[i].Name) < strings.ToLower(profiles[j].Name)
+	})
+	return profiles, nil
+}
+
+// Get returns the profile with the given name
+func (s *Store) Get(name string) (Profile, error) {
+	if !validName(name) {
+		return Profile{}, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	p := s.profile(name)
+	if info, err := os.Stat(p.Dir); err != nil || !info.IsDir() {
+		return Profile{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return p, nil
+}
+
+// Create adds a profile with default settings and an empty library
+func (s *Store) Create(name string) (Profile, error) {
+	if !validName(name) {
+		return Profile{}, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	p := s.profile(name)
+	if _, err := os.Stat(p.Dir); err == nil {
+		return Profile{}, fmt.Errorf("%w: %s", ErrExists, name)
+	}
+	if err := os.MkdirAll(p.LibraryDir(), 0o755); err != nil {
+		return Profile{}, err
+	}
+	return p, nil
+}
+
+// Remove deletes a profile with its settings and games
+func (s *Store) Remove(name string) error {
+	p, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(p.Dir)
+}
+
+// SetAvatar gives a profile the picture in a GIF, JPEG or PNG file, cropped
+// to a square and scaled down to the size it is stored at
+func (s *Store) SetAvatar(name, path string) error {
+	p, err := s.Get(name)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	src, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	tmp := p.AvatarPath() + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(out, thumbnail(src, avatarSize)); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, p.AvatarPath())
+}
+
+// profile returns the profile of the given name in the store
+func (s *Store) profile(name string) Profile {
+	return Profile{Name: name, Dir: filepath.Join(s.dir, name)}
+}
+
+// validName reports whether a name can be used as a profile's directory
+// name on every platform
+func validName(name string) bool {
+	if name == "" || len(name) > 40 || name != strings.TrimSpace(name) || strings.HasPrefix(name, ".") {
+		return false
+	}
+	return !strings.ContainsAny(name, `/\:*?"<>|`)
+}
+
+// thumbnail crops the middle square of an image and scales it to size by
+// averaging the pixels that fall on each one of the thumbnail
+func thumbnail(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	side := min(b.Dx(), b.Dy())
+	crop := image.Rect(0, 0, side, side).Add(image.Pt(b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2))
+	if side < size {
+		size = max(side, 1)
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		y0, y1 := crop.Min.Y+y*side/size, crop.Min.Y+(y+1)*side/size
+		for x := 0; x < size; x++ {
+			x0, x1 := crop.Min.X+x*side/size, crop.Min.X+(x+1)*side/size
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < max(y1, y0+1); sy++ {
+				for sx := x0; sx < max(x1, x0+1); sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a, n = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca), n+1
+				}
+			}
+			// Premultiplied averages, stored as straight alpha
+			i := dst.PixOffset(x, y)
+			if a == 0 {
+				continue
+			}
+			dst.Pix[i+0] = uint8(r * 255 / a)
+			dst.Pix[i+1] = uint8(g * 255 / a)
+			dst.Pix[i+2] = uint8(bl * 255 / a)
+			dst.Pix[i+3] = uint8(a / n >> 8)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,166 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Colors used for rendered boards, matching the GUI's default theme
+var (
+	BackgroundColor = color.RGBA{40, 40, 40, 255}
+	BoardColor      = color.RGBA{34, 139, 34, 255}
+	GridColor       = color.RGBA{0, 70, 0, 255}
+	BlackColor      = color.RGBA{0, 0, 0, 255}
+	WhiteColor      = color.RGBA{240, 240, 240, 255}
+	MoveColor       = color.RGBA{50, 255, 50, 200}
+	LastMoveColor   = color.RGBA{255, 255, 0, 255}
+	LabelColor      = color.RGBA{255, 255, 255, 255}
+)
+
+// Options controls how a board image is drawn
+type Options struct {
+	CellSize    int             // Pixels per square; 0 means 48
+	Coordinates bool            // Draw A-H and 1-8 around the board
+	ShowMoves   bool            // Mark the legal moves of the side to move
+	LastMove    *model.Position // Highlight this square, if set
+}
+
+// margin returns the space reserved around the board for coordinates
+func (o Options) margin() int {
+	if o.Coordinates {
+		return 20
+	}
+	return 0
+}
+
+// cellSize returns the configured or default square size
+func (o Options) cellSize() int {
+	if o.CellSize <= 0 {
+		return 48
+	}
+	return o.CellSize
+}
+
+// Image draws the board without any GUI dependencies
+func Image(board *model.Board, opts Options) *image.RGBA {
+	cell := opts.cellSize()
+	margin := opts.margin()
+	boardPx := cell * board.Size
+	size := boardPx + 2*margin
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{BackgroundColor}, image.Point{}, draw.Src)
+	origin := image.Pt(margin, margin)
+	draw.Draw(img, image.Rect(margin, margin, margin+boardPx, margin+boardPx), &image.Uniform{BoardColor}, image.Point{}, draw.Src)
+
+	// Grid lines
+	for i := 0; i <= board.Size; i++ {
+		p := i * cell
+		fillRect(img, image.Rect(origin.X+p-1, origin.Y, origin.X+p+1, origin.Y+boardPx), GridColor)
+		fillRect(img, image.Rect(origin.X, origin.Y+p-1, origin.X+boardPx, origin.Y+p+1), GridColor)
+	}
+
+	if opts.LastMove != nil && opts.LastMove.Row >= 0 {
+		x := origin.X + opts.LastMove.Col*cell
+		y := origin.Y + opts.LastMove.Row*cell
+		marker := max(cell/10, 2)
+		fillRect(img, image.Rect(x+1, y+1, x+1+marker, y+1+marker), LastMoveColor)
+		fillRect(img, image.Rect(x+cell-1-marker, y+1, x+cell-1, y+1+marker), LastMoveColor)
+		fillRect(img, image.Rect(x+1, y+cell-1-marker, x+1+marker, y+cell-1), LastMoveColor)
+		fillRect(img, image.Rect(x+cell-1-marker, y+cell-1-marker, x+cell-1, y+cell-1), LastMoveColor)
+	}
+
+	// Discs
+	for row := 0; row < board.Size; row++ {
+		for col := 0; col < board.Size; col++ {
+			cx := float64(origin.X + col*cell + cell/2)
+			cy := float64(origin.Y + row*cell + cell/2)
+			switch board.GetPiece(row, col) {
+			case model.Black:
+				FillCircle(img, cx, cy, float64(cell)/2-4, BlackColor)
+			case model.White:
+				FillCircle(img, cx, cy, float64(cell)/2-4, WhiteColor)
+			}
+		}
+	}
+
+	if opts.ShowMoves {
+		for _, move := range board.GetValidMoves() {
+			cx := float64(origin.X + move.Col*cell + cell/2)
+			cy := float64(origin.Y + move.Row*cell + cell/2)
+			FillCircle(img, cx, cy, float64(cell)/8, MoveColor)
+		}
+	}
+
+	if opts.Coordinates {
+		face := basicfont.Face7x13
+		for i := 0; i < board.Size; i++ {
+			center := margin + i*cell + cell/2
+			col := string(rune('A' + i))
+			row := string(rune('1' + i))
+			drawLabel(img, face, col, center-3, margin-5)
+			drawLabel(img, face, col, center-3, size-5)
+			drawLabel(img, face, row, 6, center+5)
+			drawLabel(img, face, row, size-13, center+5)
+		}
+	}
+
+	return img
+}
+
+// PNG renders the board and encodes it as PNG
+func PNG(w io.Writer, board *model.Board, opts Options) error {
+	return png.Encode(w, Image(board, opts))
+}
+
+// fillRect fills a rectangle with a solid color
+func fillRect(img draw.Image, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Over)
+}
+
+// FillCircle draws an anti-aliased filled circle
+func FillCircle(img draw.Image, cx, cy, radius float64, c color.RGBA) {
+	bounds := image.Rect(int(cx-radius)-1, int(cy-radius)-1, int(cx+radius)+2, int(cy+radius)+2).Intersect(img.Bounds())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// Coverage from the distance of the pixel center to the edge
+			d := math.Hypot(float64(x)+0.5-cx, float64(y)+0.5-cy)
+			coverage := math.Max(0, math.Min(1, radius-d+0.5))
+			if coverage == 0 {
+				continue
+			}
+			blend(img, x, y, c, coverage)
+		}
+	}
+}
+
+// blend mixes a color into a pixel with the given opacity
+func blend(img draw.Image, x, y int, c color.RGBA, coverage float64) {
+	alpha := coverage * float64(c.A) / 255
+	r0, g0, b0, a0 := img.At(x, y).RGBA()
+	mix := func(src uint8, dst uint32) uint8 {
+		return uint8(float64(src)*alpha + float64(dst>>8)*(1-alpha))
+	}
+	a := uint8(math.Min(255, alpha*255+float64(a0>>8)*(1-alpha)))
+	img.Set(x, y, color.RGBA{mix(c.R, r0), mix(c.G, g0), mix(c.B, b0), a})
+}
+
+// drawLabel draws text with its baseline at (x, y)
+func drawLabel(img draw.Image, face font.Face, s string, x, y int) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{LabelColor},
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
@@ -0,0 +1,155 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Colors of annotations on SVG diagrams
+var (
+	ArrowColor      = color.RGBA{220, 40, 40, 220}
+	AnnotationColor = color.RGBA{255, 215, 0, 255}
+)
+
+// Arrow points from one square to another, e.g. to show a plan or a
+// threatened line
+type Arrow struct {
+	From, To model.Position
+}
+
+// Annotations are marks drawn over an SVG diagram
+type Annotations struct {
+	Labels map[model.Position]string // Short text on squares, e.g. "a" or a move number
+	Arrows []Arrow
+}
+
+// SVG draws the board as a scalable vector image, with the same layout and
+// colors as Image plus the annotations
+func SVG(w io.Writer, board *model.Board, opts Options, notes Annotations) error {
+	cell := opts.cellSize()
+	margin := opts.margin()
+	boardPx := cell * board.Size
+	size := boardPx + 2*margin
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">`+"\n", size, size, size, size)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d" fill="%s"/>`+"\n", size, size, svgColor(BackgroundColor))
+	fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n", margin, margin, boardPx, boardPx, svgColor(BoardColor))
+
+	// Grid lines
+	fmt.Fprintf(bw, `<g stroke="%s" stroke-width="2">`+"\n", svgColor(GridColor))
+	for i := 0; i <= board.Size; i++ {
+		p := margin + i*cell
+		fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`+"\n", p, margin, p, margin+boardPx)
+		fmt.Fprintf(bw, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`+"\n", margin, p, margin+boardPx, p)
+	}
+	bw.WriteString("</g>\n")
+
+	center := func(p model.Position) (float64, float64) {
+		return float64(margin + p.Col*cell + cell/2), float64(margin + p.Row*cell + cell/2)
+	}
+
+	if last := opts.LastMove; last != nil && last.Row >= 0 {
+		x, y := margin+last.Col*cell, margin+last.Row*cell
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="%s" stroke-width="%d"/>`+"\n",
+			x+3, y+3, cell-6, cell-6, svgColor(LastMoveColor), max(cell/16, 2))
+	}
+
+	// Discs
+	radius := float64(cell)/2 - 4
+	for row := 0; row < board.Size; row++ {
+		for col := 0; col < board.Size; col++ {
+			var fill color.RGBA
+			switch board.GetPiece(row, col) {
+			case model.Black:
+				fill = BlackColor
+			case model.White:
+				fill = WhiteColor
+			default:
+				continue
+			}
+			x, y := center(model.Position{Row: row, Col: col})
+			fmt.Fprintf(bw, `<circle cx="%g" cy="%g" r="%g" fill="%s"/>`+"\n", x, y, radius, svgColor(fill))
+		}
+	}
+
+	if opts.ShowMoves {
+		for _, move := range board.GetValidMoves() {
+			x, y := center(move)
+			fmt.Fprintf(bw, `<circle cx="%g" cy="%g" r="%g" %s/>`+"\n", x, y, float64(cell)/8, svgFill(MoveColor))
+		}
+	}
+
+	// Labels, in a fixed order so the output is reproducible
+	squares := make([]model.Position, 0, len(notes.Labels))
+	for p := range notes.Labels {
+		squares = append(squares, p)
+	}
+	sort.Slice(squares, func(i, j int) bool {
+		a, b := squares[i], squares[j]
+		return a.Row < b.Row || a.Row == b.Row && a.Col < b.Col
+	})
+	for _, p := range squares {
+		x, y := center(p)
+		// Readable on either disc color and on the board
+		labelColor := AnnotationColor
+		if board.GetPiece(p.Row, p.Col) == model.White {
+			labelColor = BlackColor
+		}
+		fmt.Fprintf(bw, `<text x="%g" y="%g" font-size="%d" font-weight="bold" text-anchor="middle" dominant-baseline="central" %s>%s</text>`+"\n",
+			x, y, cell*2/5, svgFill(labelColor), html.EscapeString(notes.Labels[p]))
+	}
+
+	if len(notes.Arrows) > 0 {
+		head := float64(cell) / 4
+		fmt.Fprintf(bw, `<g stroke="%s" stroke-opacity="%.2f" stroke-width="%d" stroke-linecap="round" %s>`+"\n",
+			svgColor(ArrowColor), float64(ArrowColor.A)/255, max(cell/10, 2), svgFill(ArrowColor))
+		for _, a := range notes.Arrows {
+			x1, y1 := center(a.From)
+			x2, y2 := center(a.To)
+			angle := math.Atan2(y2-y1, x2-x1)
+			// Stop the shaft where the head starts
+			sx, sy := x2-head*math.Cos(angle), y2-head*math.Sin(angle)
+			fmt.Fprintf(bw, `<line x1="%g" y1="%g" x2="%.1f" y2="%.1f"/>`+"\n", x1, y1, sx, sy)
+			lx, ly := sx+head/2*math.Sin(angle), sy-head/2*math.Cos(angle)
+			rx, ry := sx-head/2*math.Sin(angle), sy+head/2*math.Cos(angle)
+			fmt.Fprintf(bw, `<polygon points="%g,%g %.1f,%.1f %.1f,%.1f" stroke="none"/>`+"\n", x2, y2, lx, ly, rx, ry)
+		}
+		bw.WriteString("</g>\n")
+	}
+
+	if opts.Coordinates {
+		fmt.Fprintf(bw, `<g font-size="12" text-anchor="middle" dominant-baseline="central" fill="%s">`+"\n", svgColor(LabelColor))
+		for i := 0; i < board.Size; i++ {
+			c := margin + i*cell + cell/2
+			col, row := string(rune('A'+i)), string(rune('1'+i))
+			fmt.Fprintf(bw, `<text x="%d" y="%d">%s</text><text x="%d" y="%d">%s</text>`+"\n", c, margin/2, col, c, size-margin/2, col)
+			fmt.Fprintf(bw, `<text x="%d" y="%d">%s</text><text x="%d" y="%d">%s</text>`+"\n", margin/2, c, row, size-margin/2, c, row)
+		}
+		bw.WriteString("</g>\n")
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// svgColor writes an opaque color as #rrggbb
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// svgFill returns fill attributes for a color, with its opacity when it is
+// translucent
+func svgFill(c color.RGBA) string {
+	if c.A == 255 {
+		return fmt.Sprintf(`fill="%s"`, svgColor(c))
+	}
+	return fmt.Sprintf(`fill="%s" fill-opacity="%.2f"`, svgColor(c), float64(c.A)/255)
+}
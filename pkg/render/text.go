@@ -0,0 +1,70 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// TextStyle selects the characters used for a text diagram
+type TextStyle struct {
+	Black, White, Empty, Move string
+	Separator                 string // Between squares
+}
+
+// Predefined text styles
+var (
+	ASCII   = TextStyle{Black: "X", White: "O", Empty: ".", Move: "*", Separator: " "}
+	Unicode = TextStyle{Black: "●", White: "○", Empty: "·", Move: "+", Separator: " "}
+	Emoji   = TextStyle{Black: "⚫", White: "⚪", Empty: "🟩", Move: "🟢", Separator: ""}
+)
+
+// Text draws the board as a diagram with coordinates
+// Legal moves of the side to move are marked when showMoves is set.
+func Text(board *model.Board, style TextStyle, showMoves bool) string {
+	legal := make(map[model.Position]bool)
+	if showMoves {
+		for _, move := range board.GetValidMoves() {
+			legal[move] = true
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("  ")
+	for col := 0; col < board.Size; col++ {
+		if col > 0 {
+			sb.WriteString(style.Separator)
+		}
+		letter := string(rune('A' + col))
+		if style.Separator == "" {
+			// Emoji are two columns wide
+			letter = string(rune('Ａ' + col))
+		}
+		sb.WriteString(letter)
+	}
+	sb.WriteString("\n")
+
+	for row := 0; row < board.Size; row++ {
+		sb.WriteString(string(rune('1'+row)) + " ")
+		for col := 0; col < board.Size; col++ {
+			if col > 0 {
+				sb.WriteString(style.Separator)
+			}
+			switch board.GetPiece(row, col) {
+			case model.Black:
+				sb.WriteString(style.Black)
+			case model.White:
+				sb.WriteString(style.White)
+			default:
+				if legal[model.Position{Row: row, Col: col}] {
+					sb.WriteString(style.Move)
+				} else {
+					sb.WriteString(style.Empty)
+				}
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
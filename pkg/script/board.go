@@ -0,0 +1,144 @@
+package script
+
+import (
+	"fmt"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/notation"
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+)
+
+// boardValue is a board as scripts see it; it cannot be changed, play
+// returns a new one
+type boardValue struct {
+	board *model.Board
+}
+
+var _ starlark.HasAttrs = boardValue{}
+
+// boardAttrs are the attributes of a board in scripts
+var boardAttrs = []string{"at", "count", "empty", "moves", "play", "regions", "turn"}
+
+func (b boardValue) String() string        { return b.board.PositionString() }
+func (b boardValue) Type() string          { return "board" }
+func (b boardValue) Freeze()               {}
+func (b boardValue) Truth() starlark.Bool  { return starlark.True }
+func (b boardValue) Hash() (uint32, error) { return starlark.String(b.String()).Hash() }
+func (b boardValue) AttrNames() []string   { return boardAttrs }
+
+// Attr returns the attribute name of the board
+func (b boardValue) Attr(name string) (starlark.Value, error) {
+	switch name {
+	case "turn":
+		return starlark.String(colorName(b.board.CurrentPlayer)), nil
+	case "empty":
+		return starlark.MakeInt(64 - b.board.BlackCnt - b.board.WhiteCnt), nil
+	case "at", "count", "moves", "play", "regions":
+		return starlark.NewBuiltin(name, b.method), nil
+	}
+	return nil, nil
+}
+
+// method calls the board method fn.Name()
+func (b boardValue) method(_ *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	switch fn.Name() {
+	case "moves":
+		if err := starlark.UnpackArgs("moves", args, kwargs); err != nil {
+			return nil, err
+		}
+		var moves []starlark.Value
+		for _, m := range b.board.GetValidMoves() {
+			moves = append(moves, starlark.String(notation.Format(m.Row, m.Col)))
+		}
+		return starlark.NewList(moves), nil
+
+	case "regions":
+		if err := starlark.UnpackArgs("regions", args, kwargs); err != nil {
+			return nil, err
+		}
+		var regions []starlark.Value
+		for _, r := range b.board.Regions() {
+			squares := make([]starlark.Value, len(r.Squares))
+			for i, p := range r.Squares {
+				squares[i] = starlark.String(notation.Format(p.Row, p.Col))
+			}
+			regions = append(regions, starlarkstruct.FromStringDict(starlark.String("region"), starlark.StringDict{
+				"squares":     starlark.NewList(squares),
+				"odd":         starlark.Bool(r.Odd()),
+				"black_moves": starlark.MakeInt(r.BlackMoves),
+				"white_moves": starlark.MakeInt(r.WhiteMoves),
+			}))
+		}
+		return starlark.NewList(regions), nil
+
+	case "at":
+		var square string
+		if err := starlark.UnpackArgs("at", args, kwargs, "square", &square); err != nil {
+			return nil, err
+		}
+		row, col, err := notation.Parse(square)
+		if err != nil || row < 0 {
+			return nil, fmt.Errorf("at: bad square %q", square)
+		}
+		return starlark.String(colorName(b.board.GetPiece(row, col))), nil
+
+	case "count":
+		var color string
+		if err := starlark.UnpackArgs("count", args, kwargs, "color", &color); err != nil {
+			return nil, err
+		}
+		piece, err := parseColor(color)
+		if err != nil {
+			return nil, fmt.Errorf("count: %w", err)
+		}
+		if piece == model.Black {
+			return starlark.MakeInt(b.board.BlackCnt), nil
+		}
+		return starlark.MakeInt(b.board.WhiteCnt), nil
+
+	default: // play
+		var move string
+		if err := starlark.UnpackArgs("play", args, kwargs, "move", &move); err != nil {
+			return nil, err
+		}
+		row, col, err := notation.Parse(move)
+		if err != nil {
+			return nil, fmt.Errorf("play: %w", err)
+		}
+		next := b.board.Clone()
+		if row < 0 {
+			if next.HasValidMove() {
+				return nil, fmt.Errorf("play: %w", model.ErrCannotPass)
+			}
+			next.PassTurn()
+		} else if err := next.CheckMove(row, col); err != nil {
+			return nil, fmt.Errorf("play: %w", err)
+		} else {
+			next.MakeMove(row, col)
+		}
+		return boardValue{next}, nil
+	}
+}
+
+// colorName names a piece for scripts: "black", "white" or "" for none
+func colorName(p model.Piece) string {
+	switch p {
+	case model.Black:
+		return "black"
+	case model.White:
+		return "white"
+	}
+	return ""
+}
+
+// parseColor reads a color name given by a script
+func parseColor(name string) (model.Piece, error) {
+	switch name {
+	case "black":
+		return model.Black, nil
+	case "white":
+		return model.White, nil
+	}
+	return model.Empty, fmt.Errorf("unknown color %q", name)
+}
@@ -0,0 +1,183 @@
+// Package script runs bots written in Starlark, a small Python dialect,
+// loaded at runtime so they can be changed without rebuilding the program.
+//
+// A script defines any of these functions:
+//
+//	choose_move(board)        returns the move to play, e.g. "d3", or "pass"
+//	evaluate(board, color)    returns a score for color, higher is better
+//	on_move(board, color, move)
+//	                          is called after every move of the game, with
+//	                          the board after it
+//
+// A script with choose_move plays its moves itself; one with only evaluate
+// has the built-in search use it in place of the built-in evaluation.
+// Boards have the attributes turn ("black" or "white") and empty (squares
+// left), and the methods moves(), at(square), count(color), play(move),
+// which returns the board after the move, and regions(), the empty regions
+// with their squares, odd and each side's moves in them (black_moves,
+// white_moves).
+package script
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/notation"
+	"go.starlark.net/starlark"
+)
+
+// Names of the functions a script may define
+const (
+	FuncChooseMove = "choose_move"
+	FuncEvaluate   = "evaluate"
+	FuncOnMove     = "on_move"
+)
+
+// MaxSteps bounds the work of a single call into a script, so a script
+// stuck in a loop fails instead of hanging the game
+const MaxSteps = 10_000_000
+
+// ErrNoBot is returned for a script that defines neither choose_move nor
+// evaluate
+var ErrNoBot = errors.New("script defines neither " + FuncChooseMove + " nor " + FuncEvaluate)
+
+// Script is a loaded bot script
+type Script struct {
+	Path string
+	src  []byte
+}
+
+// Load reads the script at path and checks that it runs and defines a bot
+func Load(path string) (*Script, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Script{Path: path, src: src}
+	if _, err := s.instance(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Player returns a player for one game, with state of its own: every
+// player runs a fresh copy of the script. Scripts that only evaluate are
+// searched at the given difficulty.
+func (s *Script) Player(difficulty string, piece model.Piece) (engine.Player, error) {
+	in, err := s.instance()
+	if err != nil {
+		return nil, err
+	}
+	if in.globals.Has(FuncChooseMove) {
+		return in, nil
+	}
+	p := ai.NewPlayer(difficulty, piece)
+	p.Book = nil
+	p.Evaluator = in
+	return &searchPlayer{Player: p, in: in}, nil
+}
+
+// instance is one run of a script, holding its global state
+type instance struct {
+	path    string
+	mu      sync.Mutex // Scripts run one call at a time
+	globals starlark.StringDict
+	warn    sync.Once // Evaluation failures are logged once
+}
+
+// instance runs the script afresh
+func (s *Script) instance() (*instance, error) {
+	in := &instance{path: s.Path}
+	globals, err := starlark.ExecFile(in.thread(), s.Path, s.src, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !globals.Has(FuncChooseMove) && !globals.Has(FuncEvaluate) {
+		return nil, fmt.Errorf("%s: %w", s.Path, ErrNoBot)
+	}
+	in.globals = globals
+	return in, nil
+}
+
+// thread returns a thread for one call into the script, printing to the log
+func (in *instance) thread() *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: in.path,
+		Print: func(_ *starlark.Thread, msg string) {
+			logging.For("script").Info(msg, "script", in.path)
+		},
+	}
+	thread.SetMaxExecutionSteps(MaxSteps)
+	return thread
+}
+
+// call calls the script's function name; it returns None if there is none
+func (in *instance) call(name string, args ...starlark.Value) (starlark.Value, error) {
+	fn, ok := in.globals[name]
+	if !ok {
+		return starlark.None, nil
+	}
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return starlark.Call(in.thread(), fn, args, nil)
+}
+
+// GetMove asks the script's choose_move for a move
+func (in *instance) GetMove(board *model.Board) (int, int, error) {
+	v, err := in.call(FuncChooseMove, boardValue{board})
+	if err != nil {
+		return -1, -1, err
+	}
+	if v == starlark.None {
+		return -1, -1, nil
+	}
+	move, ok := starlark.AsString(v)
+	if !ok {
+		return -1, -1, fmt.Errorf("%s: %s returned %s, not a move", in.path, FuncChooseMove, v.Type())
+	}
+	return notation.Parse(move)
+}
+
+// Evaluate scores board for side with the script's evaluate; positions it
+// fails on score 0, and the first failure is logged
+func (in *instance) Evaluate(board *model.Board, side model.Piece) int {
+	v, err := in.call(FuncEvaluate, boardValue{board}, starlark.String(colorName(side)))
+	if err == nil {
+		var score int
+		if score, err = starlark.AsInt32(v); err == nil {
+			return score
+		}
+	}
+	in.warn.Do(func() {
+		logging.For("script").Warn("evaluate failed", "script", in.path, "err", err)
+	})
+	return 0
+}
+
+// ObserveMove calls the script's on_move hook, if it has one
+func (in *instance) ObserveMove(board *model.Board, move model.Move) {
+	pos := move.Position
+	_, err := in.call(FuncOnMove, boardValue{board}, starlark.String(colorName(move.Piece)),
+		starlark.String(notation.Format(pos.Row, pos.Col)))
+	if err != nil {
+		logging.For("script").Warn("on_move failed", "script", in.path, "err", err)
+	}
+}
+
+// searchPlayer is the built-in search playing with a script's evaluation;
+// the script still hears of every move
+type searchPlayer struct {
+	*ai.Player
+	in *instance
+}
+
+// ObserveMove passes the move on to the script
+func (p *searchPlayer) ObserveMove(board *model.Board, move model.Move) {
+	p.in.ObserveMove(board, move)
+}
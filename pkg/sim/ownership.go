@@ -0,0 +1,169 @@
+package sim
+
+import (
+	"context"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Policy chooses the moves of playouts
+type Policy int
+
+// Playout policies
+const (
+	// PolicyRandom plays uniformly random legal moves
+	PolicyRandom Policy = iota
+	// PolicyHeuristic plays the Medium AI's move half of the time and a
+	// random one otherwise, for playouts closer to real games that still
+	// vary
+	PolicyHeuristic
+)
+
+// DefaultPlayouts is the number of playouts run when none is given
+const DefaultPlayouts = 500
+
+// OwnershipOptions control PlayoutOwnership
+type OwnershipOptions struct {
+	Playouts int // 0 for DefaultPlayouts
+	Policy   Policy
+	Seed     int64
+	Workers  int // Playouts run at once; 0 for one per CPU
+}
+
+// Ownership counts how often each square ended up black or white over many
+// playouts from a position
+type Ownership struct {
+	Playouts                    int // Playouts finished
+	Black, White                [8][8]int
+	BlackWins, WhiteWins, Draws int
+}
+
+// Share returns the fraction of playouts in which the square ended up black
+// and white; they add up to less than 1 when it was sometimes left empty
+func (o Ownership) Share(row, col int) (black, white float64) {
+	if o.Playouts == 0 {
+		return 0, 0
+	}
+	n := float64(o.Playouts)
+	return float64(o.Black[row][col]) / n, float64(o.White[row][col]) / n
+}
+
+// add counts the final position of one playout
+func (o *Ownership) add(board *model.Board) {
+	o.Playouts++
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			switch board.GetPiece(row, col) {
+			case model.Black:
+				o.Black[row][col]++
+			case model.White:
+				o.White[row][col]++
+			}
+		}
+	}
+	switch board.GetWinner() {
+	case model.Black:
+		o.BlackWins++
+	case model.White:
+		o.WhiteWins++
+	default:
+		o.Draws++
+	}
+}
+
+// PlayoutOwnership plays games out from board with the policy and counts
+// who owns each square at the end, a picture of which regions are settled
+// and which are still contested. It stops early when the context is done,
+// counting the playouts finished by then.
+func PlayoutOwnership(ctx context.Context, board *model.Board, opts OwnershipOptions) Ownership {
+	playouts := opts.Playouts
+	if playouts <= 0 {
+		playouts = DefaultPlayouts
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, playouts)
+
+	// Checked between moves rather than through Options.Context, which
+	// would run every move of these quick players in a goroutine of its own
+	cancelled := func(*model.Game) error { return ctx.Err() }
+
+	var (
+		mu    sync.Mutex
+		total Ownership
+		wg    sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(opts.Seed + int64(w)))
+			black := newPlayoutPlayer(opts.Policy, model.Black, rng)
+			white := newPlayoutPlayer(opts.Policy, model.White, rng)
+
+			var own Ownership
+			for i := w; i < playouts; i += workers {
+				r := RunGame(black, white, Options{Start: board, Stop: []StopCondition{cancelled}})
+				if !r.Finished() {
+					break
+				}
+				own.add(r.Game.Board)
+			}
+
+			mu.Lock()
+			total.merge(own)
+			mu.Unlock()
+		}(w)
+	}
+	wg.Wait()
+	return total
+}
+
+// merge adds the counts of other
+func (o *Ownership) merge(other Ownership) {
+	o.Playouts += other.Playouts
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			o.Black[row][col] += other.Black[row][col]
+			o.White[row][col] += other.White[row][col]
+		}
+	}
+	o.BlackWins += other.BlackWins
+	o.WhiteWins += other.WhiteWins
+	o.Draws += other.Draws
+}
+
+// playoutPlayer plays the moves of a playout policy
+type playoutPlayer struct {
+	rng    *rand.Rand
+	greedy *ai.Player // Nil for random playouts
+}
+
+// newPlayoutPlayer creates a player for one side of playouts
+func newPlayoutPlayer(policy Policy, piece model.Piece, rng *rand.Rand) *playoutPlayer {
+	p := &playoutPlayer{rng: rng}
+	if policy == PolicyHeuristic {
+		p.greedy = ai.NewPlayer(ai.Medium, piece)
+		p.greedy.Telemetry = nil
+	}
+	return p
+}
+
+// GetMove returns the policy's move
+func (p *playoutPlayer) GetMove(board *model.Board) (int, int, error) {
+	if p.greedy != nil && p.rng.Intn(2) == 0 {
+		return p.greedy.GetMove(board)
+	}
+	moves := board.GetValidMoves()
+	if len(moves) == 0 {
+		return -1, -1, nil
+	}
+	move := moves[p.rng.Intn(len(moves))]
+	return move.Row, move.Col, nil
+}
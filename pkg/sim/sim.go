@@ -0,0 +1,229 @@
+// Package sim plays one game between two players to the end or until a stop
+// condition is met, recording everything that happened. It is the common
+// core of tournaments, adaptive difficulty and dataset generation.
+package sim
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Reasons a game is stopped before it is finished
+var (
+	ErrMaxMoves = errors.New("move limit reached")
+	ErrTimeout  = errors.New("game timed out")
+)
+
+// ErrLostOnTime is the forfeit reason of a player that ran out of time
+var ErrLostOnTime = errors.New("lost on time")
+
+// Player chooses moves for one side of a game
+type Player = engine.Player
+
+// StopCondition is checked before every move; a non-nil error stops the game
+// unfinished, with the error as the reason
+type StopCondition func(game *model.Game) error
+
+// TimeControl is the time each player has for a game; a zero Base means the
+// game is untimed
+type TimeControl struct {
+	Base      time.Duration
+	Increment time.Duration // Added after every move
+}
+
+// Options control how a game is run; the zero value plays an untimed game
+// from the starting position to the end
+type Options struct {
+	// Context stops the game when it is done, even in the middle of a move
+	Context context.Context
+	// Timeout stops the game after this long; 0 means no limit
+	Timeout time.Duration
+	// MaxMoves stops the game after this many plies, passes included, not
+	// counting the opening; 0 means no limit
+	MaxMoves int
+	// Start is the position the game starts from; nil for the starting
+	// position
+	Start *model.Board
+	// Opening is played from Start before the players take over
+	Opening     []model.Position
+	TimeControl TimeControl
+	// Stop holds further conditions checked before every move
+	Stop []StopCondition
+}
+
+// Ply is one move of a game and how long the player took over it
+// Passes forced by the rules are recorded with no time.
+type Ply struct {
+	Move model.Move
+	Time time.Duration
+}
+
+// Result is everything that happened in a game
+type Result struct {
+	Game                   *model.Game
+	Winner                 model.Piece // Empty for a draw or an unfinished game
+	BlackDiscs, WhiteDiscs int
+	Transcript             string // Played from Game.StartBoard()
+	Moves                  []Ply  // The plies played after the opening
+
+	// Forfeit is why the loser forfeited: an error, an illegal move or
+	// ErrLostOnTime; the loser is the opponent of Winner
+	Forfeit error
+	// Stopped is the stop condition that ended the game unfinished
+	Stopped error
+}
+
+// Finished reports whether the game was played out or forfeited rather than
+// stopped
+func (r Result) Finished() bool {
+	return r.Stopped == nil
+}
+
+// MaxMoves returns a stop condition ending the game once it has n plies,
+// the opening and passes included
+func MaxMoves(n int) StopCondition {
+	return func(game *model.Game) error {
+		if len(game.History) >= n {
+			return ErrMaxMoves
+		}
+		return nil
+	}
+}
+
+// RunGame plays a game between black and white and returns its result
+// Players that are move observers are told of every move. A player that
+// returns an error or an illegal move forfeits, as does one that runs out of
+// time in a timed game. A player interrupted by the context keeps running in
+// the background until its move returns.
+func RunGame(black, white Player, opts Options) Result {
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, opts.Timeout, ErrTimeout)
+		defer cancel()
+	}
+
+	game := model.NewGame()
+	if opts.Start != nil {
+		game = model.NewGameFrom(opts.Start)
+	}
+	game.ReplayMoves(opts.Opening)
+	result := Result{Game: game}
+
+	players := map[model.Piece]Player{model.Black: black, model.White: white}
+	clocks := map[model.Piece]*engine.Clock{
+		model.Black: {Remaining: opts.TimeControl.Base, Increment: opts.TimeControl.Increment},
+		model.White: {Remaining: opts.TimeControl.Base, Increment: opts.TimeControl.Increment},
+	}
+	timed := opts.TimeControl.Base > 0
+
+	for !game.GameOver {
+		if result.Stopped = stopped(ctx, game, len(result.Moves), opts); result.Stopped != nil {
+			break
+		}
+
+		side := game.GetCurrentPlayer()
+		if !game.HasValidMove() {
+			game.Pass()
+			result.Moves = append(result.Moves, Ply{Move: game.History[len(game.History)-1]})
+			engine.Observe(game, black, white)
+			continue
+		}
+
+		var clock *engine.Clock
+		if timed {
+			clock = clocks[side]
+		}
+		started := time.Now()
+		row, col, err := ask(ctx, game.Board.Clone(), players[side], clock)
+		elapsed := time.Since(started)
+		if err != nil && ctx.Err() != nil {
+			result.Stopped = context.Cause(ctx)
+			break
+		}
+		if timed {
+			clock.Remaining -= elapsed
+			if err == nil && clock.Remaining < 0 {
+				err = ErrLostOnTime
+			}
+		}
+		if err == nil {
+			if row < 0 || col < 0 {
+				err = game.Pass()
+			} else {
+				err = game.MakeMove(row, col)
+			}
+		}
+		if err != nil {
+			result.Forfeit = fmt.Errorf("%s: %w", model.GetPieceName(side), err)
+			result.Winner = side.Opponent()
+			break
+		}
+		if timed {
+			clock.Remaining += clock.Increment
+		}
+		result.Moves = append(result.Moves, Ply{Move: game.History[len(game.History)-1], Time: elapsed})
+		engine.Observe(game, black, white)
+	}
+
+	result.BlackDiscs, result.WhiteDiscs = game.GetScore()
+	if result.Forfeit == nil && game.GameOver {
+		result.Winner = game.Winner
+	}
+	result.Transcript = game.Transcript()
+	return result
+}
+
+// stopped returns the reason to stop the game before the next move, if any
+func stopped(ctx context.Context, game *model.Game, played int, opts Options) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	if opts.MaxMoves > 0 && played >= opts.MaxMoves {
+		return ErrMaxMoves
+	}
+	for _, stop := range opts.Stop {
+		if err := stop(game); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ask gets the player's move, giving up when the context is done
+// Players that can pace themselves are told their clock, if they have one.
+func ask(ctx context.Context, board *model.Board, player Player, clock *engine.Clock) (int, int, error) {
+	get := func() (int, int, error) {
+		if t, ok := player.(engine.TimedPlayer); ok && clock != nil {
+			return t.GetMoveTimed(board, *clock)
+		}
+		return player.GetMove(board)
+	}
+	if ctx.Done() == nil {
+		return get()
+	}
+
+	type reply struct {
+		row, col int
+		err      error
+	}
+	replies := make(chan reply, 1)
+	go func() {
+		row, col, err := get()
+		replies <- reply{row, col, err}
+	}()
+	select {
+	case r := <-replies:
+		return r.row, r.col, r.err
+	case <-ctx.Done():
+		return -1, -1, context.Cause(ctx)
+	}
+}
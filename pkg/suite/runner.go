@@ -0,0 +1,95 @@
+package suite
+
+import (
+	"context"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Outcome is the engine's answer to one test
+type Outcome struct {
+	Test     Test
+	Move     model.Position // Move chosen after the full search
+	Depth    int
+	Score    int
+	Nodes    int64
+	Elapsed  time.Duration // Including the unfinished last iteration
+	Solved   bool
+	SolvedAt time.Duration // Time from which the search kept choosing a solving move
+
+	// SolvedAtNodes is SolvedAt counted in nodes, which unlike time repeats
+	// exactly from run to run
+	SolvedAtNodes int64
+}
+
+// Summary totals the outcomes of a run
+type Summary struct {
+	Total, Solved int
+	Nodes         int64
+	Elapsed       time.Duration
+}
+
+// Runner searches each test position until the first of its limits
+type Runner struct {
+	Player    *ai.Player
+	TimeLimit time.Duration // Search time per position, 0 for no limit
+	MaxDepth  int           // Search depth limit, 0 to search to the end of the game
+	NodeLimit int64         // Nodes searched per position, 0 for no limit
+}
+
+// Run searches every test and returns the outcomes; report, if set, is
+// called after each test. Cancelling ctx ends the run early.
+func (r *Runner) Run(ctx context.Context, tests []Test, report func(Outcome)) ([]Outcome, Summary) {
+	var outcomes []Outcome
+	var summary Summary
+
+	for _, test := range tests {
+		if ctx.Err() != nil {
+			break
+		}
+
+		outcome := r.runTest(ctx, test)
+		outcomes = append(outcomes, outcome)
+
+		summary.Total++
+		if outcome.Solved {
+			summary.Solved++
+		}
+		summary.Nodes += outcome.Nodes
+		summary.Elapsed += outcome.Elapsed
+
+		if report != nil {
+			report(outcome)
+		}
+	}
+	return outcomes, summary
+}
+
+// runTest searches one position
+func (r *Runner) runTest(ctx context.Context, test Test) Outcome {
+	start := time.Now()
+	outcome := Outcome{Test: test, SolvedAt: -1, SolvedAtNodes: -1}
+	limits := ai.SearchLimits{Depth: r.MaxDepth, Nodes: r.NodeLimit, Time: r.TimeLimit}
+	info := r.Player.Search(ctx, test.Board, limits, func(info ai.Info) {
+		// Remember when the engine settled on a solving move
+		if test.Accepts(info.Best) {
+			if outcome.SolvedAt < 0 {
+				outcome.SolvedAt = info.Elapsed
+				outcome.SolvedAtNodes = info.Nodes
+			}
+		} else {
+			outcome.SolvedAt = -1
+			outcome.SolvedAtNodes = -1
+		}
+	})
+
+	outcome.Move = info.Best
+	outcome.Depth = info.Depth
+	outcome.Score = info.Score
+	outcome.Nodes = info.Nodes
+	outcome.Elapsed = time.Since(start)
+	outcome.Solved = info.Depth > 0 && test.Accepts(info.Best)
+	return outcome
+}
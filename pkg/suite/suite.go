@@ -0,0 +1,163 @@
+// Package suite reads position test suites and scores the engine on them,
+// so changes to the evaluation or search can be checked on known positions
+// rather than by self-play alone.
+//
+// A suite file has one test per line, in a format modeled on chess EPD: the
+// position as written by Board.PositionString followed by operations
+// separated by semicolons. Blank lines and lines starting with '#' are
+// ignored.
+//
+//	---------------------------OX------XXX-------------------------- O bm C3 E3; id "opening 1";
+//
+// Supported operations are bm (best moves, any of which solves the test),
+// am (moves to avoid) and id (a name for the test).
+package suite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Test is one position of a suite
+type Test struct {
+	ID    string
+	Line  int // Line number in the suite file
+	Board *model.Board
+	Best  []model.Position // Moves that solve the test
+	Avoid []model.Position // Moves that fail the test
+}
+
+// Accepts reports whether playing move solves the test
+func (t Test) Accepts(move model.Position) bool {
+	for _, m := range t.Avoid {
+		if m == move {
+			return false
+		}
+	}
+	if len(t.Best) == 0 {
+		return true
+	}
+	for _, m := range t.Best {
+		if m == move {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseError reports a malformed suite line
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Load reads a suite file
+func Load(path string) ([]Test, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+// Parse reads a suite
+func Parse(r io.Reader) ([]Test, error) {
+	var tests []Test
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		test, err := parseLine(text)
+		if err != nil {
+			return nil, &ParseError{Line: line, Err: err}
+		}
+		test.Line = line
+		if test.ID == "" {
+			test.ID = fmt.Sprintf("line %d", line)
+		}
+		tests = append(tests, test)
+	}
+	return tests, scanner.Err()
+}
+
+// parseLine parses the position and operations of one test
+func parseLine(text string) (Test, error) {
+	fields := strings.SplitN(text, " ", 3)
+	if len(fields) < 2 {
+		return Test{}, fmt.Errorf("expected a position and side to move")
+	}
+	board, err := model.ParsePosition(fields[0] + " " + fields[1])
+	if err != nil {
+		return Test{}, err
+	}
+
+	test := Test{Board: board}
+	if len(fields) < 3 {
+		return test, nil
+	}
+
+	for _, op := range strings.Split(fields[2], ";") {
+		op = strings.TrimSpace(op)
+		if op == "" {
+			continue
+		}
+		name, args, _ := strings.Cut(op, " ")
+		switch name {
+		case "bm", "am":
+			moves, err := parseMoves(board, args)
+			if err != nil {
+				return Test{}, fmt.Errorf("%s: %w", name, err)
+			}
+			if name == "bm" {
+				test.Best = append(test.Best, moves...)
+			} else {
+				test.Avoid = append(test.Avoid, moves...)
+			}
+		case "id":
+			test.ID = strings.Trim(strings.TrimSpace(args), `"`)
+		default:
+			// Unknown operations are kept by other tools; ignore them
+		}
+	}
+	return test, nil
+}
+
+// parseMoves parses a list of moves, which must be legal on the board
+func parseMoves(board *model.Board, args string) ([]model.Position, error) {
+	var moves []model.Position
+	for _, field := range strings.Fields(args) {
+		row, col, err := model.ParseMove(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		if row >= 0 {
+			if err := board.CheckMove(row, col); err != nil {
+				return nil, err
+			}
+		}
+		moves = append(moves, model.Position{Row: row, Col: col})
+	}
+	if len(moves) == 0 {
+		return nil, fmt.Errorf("no moves given")
+	}
+	return moves, nil
+}
@@ -0,0 +1,46 @@
+package transcript
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// FuzzParse checks that any text either fails to parse or gives games whose
+// moves are on the board and whose named headers fill the game's fields,
+// and that a game's moves given as a "Moves:" header parse the same
+func FuzzParse(f *testing.F) {
+	f.Add("[Black \"alice\"]\n[White \"bob\"]\n[Result \"36-28\"]\nf5d6c3d3c4\n")
+	f.Add("Black: alice\nMoves: 1. f5 2.d6 pass 36-28\nBlack: bob\nf5")
+	f.Add("[]\n:\n")
+	f.Fuzz(func(t *testing.T, s string) {
+		games, err := Parse(strings.NewReader(s))
+		if err != nil {
+			return
+		}
+		for _, g := range games {
+			if err := model.CheckMoves(g.Moves); err != nil {
+				t.Fatal(err)
+			}
+			if g.Black != g.Headers["black"] || g.White != g.Headers["white"] || g.Date != g.Headers["date"] {
+				t.Fatalf("players %q and %q on %q, headers %v", g.Black, g.White, g.Date, g.Headers)
+			}
+			g.Replay() // Illegal moves are reported, not a panic
+
+			var moves []string
+			for _, m := range g.Moves {
+				moves = append(moves, model.FormatMove(m.Row, m.Col))
+			}
+			written := "Moves: " + strings.Join(moves, " ")
+			again, err := ParseGame(written)
+			if err != nil {
+				t.Fatalf("%q does not parse back: %v", written, err)
+			}
+			if !slices.Equal(again.Moves, g.Moves) {
+				t.Fatalf("%q parses back to %v, want %v", written, again.Moves, g.Moves)
+			}
+		}
+	})
+}
@@ -0,0 +1,208 @@
+// Package transcript reads games copied or exported from online Othello
+// apps such as eOthello and Othello Quest, which give a game as its move
+// list, optionally with the players and result:
+//
+//	[Black "alice"]
+//	[White "bob"]
+//	[Result "36-28"]
+//	f5d6c3d3c4f4f6f3e6e7...
+//
+// Headers are either tags like the above or lines such as "Black: alice",
+// and the moves may also be given as "Moves: f5d6c3...". Moves may be run
+// together, separated by spaces or commas, and numbered ("1. f5 2.d6"); a
+// final score such as "36-28" after the moves is taken as the result. A header after moves starts the next game, so an export of
+// several games can be read at once.
+package transcript
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// ErrNoMoves is returned for text holding no game
+var ErrNoMoves = errors.New("no moves found in the transcript")
+
+// Game is a single game read from a transcript
+type Game struct {
+	Black  string
+	White  string
+	Date   string
+	Result string // As written, e.g. "36-28"
+	Moves  []model.Position
+
+	// Headers holds every header by its lower-case name
+	Headers map[string]string
+}
+
+// Parse reads every game in r
+func Parse(r io.Reader) ([]*Game, error) {
+	var games []*Game
+	var headers map[string]string
+	var moves strings.Builder
+
+	finish := func() error {
+		if moves.Len() == 0 {
+			return nil
+		}
+		g, err := newGame(headers, moves.String())
+		if err != nil {
+			return fmt.Errorf("game %d: %w", len(games)+1, err)
+		}
+		games = append(games, g)
+		headers = nil
+		moves.Reset()
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		key, value, ok := parseHeader(line)
+		if ok && moveHeaders[key] {
+			ok, line = false, value
+		}
+		if !ok {
+			moves.WriteString(line)
+			moves.WriteByte(' ')
+			continue
+		}
+		if err := finish(); err != nil {
+			return games, err
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return games, err
+	}
+	if err := finish(); err != nil {
+		return games, err
+	}
+	if len(games) == 0 {
+		return nil, ErrNoMoves
+	}
+	return games, nil
+}
+
+// moveHeaders are headers whose value is the move list, as in
+// "Moves: f5d6c3"
+var moveHeaders = map[string]bool{"moves": true, "transcript": true, "record": true}
+
+// ParseGame reads a single game from s
+func ParseGame(s string) (*Game, error) {
+	games, err := Parse(strings.NewReader(s))
+	if err != nil {
+		return nil, err
+	}
+	return games[0], nil
+}
+
+// parseHeader reads a header line, `[Key "value"]` or "Key: value", and
+// returns its lower-case key
+func parseHeader(line string) (string, string, bool) {
+	if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+		key, value, ok := strings.Cut(line[1:len(line)-1], " ")
+		if !ok || !isWord(key) {
+			return "", "", false
+		}
+		return strings.ToLower(key), strings.Trim(strings.TrimSpace(value), `"`), true
+	}
+	key, value, ok := strings.Cut(line, ":")
+	if !ok || !isWord(strings.TrimSpace(key)) {
+		return "", "", false
+	}
+	return strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value), true
+}
+
+// isWord reports whether s is a header name: letters and spaces only, so a
+// move list is never taken for one
+func isWord(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != ' ' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// newGame builds a game from its headers and the text of its moves
+func newGame(headers map[string]string, text string) (*Game, error) {
+	g := &Game{
+		Black:   headers["black"],
+		White:   headers["white"],
+		Date:    headers["date"],
+		Result:  headers["result"],
+		Headers: headers,
+	}
+
+	var kept []string
+	for _, field := range strings.Fields(text) {
+		field = trimMoveNumber(field)
+		switch {
+		case field == "":
+			continue
+		case isScore(field):
+			if g.Result == "" {
+				g.Result = field
+			}
+			continue
+		}
+		kept = append(kept, field)
+	}
+
+	moves, err := model.ParseTranscript(strings.Join(kept, ""))
+	if err != nil {
+		return nil, err
+	}
+	if len(moves) == 0 {
+		return nil, ErrNoMoves
+	}
+	g.Moves = moves
+	return g, nil
+}
+
+// trimMoveNumber removes the number in front of a move, as in "12." or
+// "12.f5"
+func trimMoveNumber(field string) string {
+	digits, rest, ok := strings.Cut(field, ".")
+	if !ok || digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return field
+	}
+	return strings.TrimLeft(rest, ".")
+}
+
+// isScore reports whether a field is a final score such as "36-28"
+func isScore(field string) bool {
+	black, white, ok := strings.Cut(field, "-")
+	return ok && black != "" && white != "" &&
+		strings.Trim(black, "0123456789") == "" && strings.Trim(white, "0123456789") == ""
+}
+
+// Replay plays the game's moves on a fresh game with full validation,
+// keeping the players' names and the date as metadata
+func (g *Game) Replay() (*model.Game, error) {
+	game := model.NewGame()
+	if ply, err := game.ReplayMoves(g.Moves); err != nil {
+		return game, fmt.Errorf("move %d: %w", ply+1, err)
+	}
+	for key, value := range map[string]string{"black.name": g.Black, "white.name": g.White, "date": g.Date} {
+		if value != "" {
+			game.SetMetadata(key, value)
+		}
+	}
+	return game, nil
+}
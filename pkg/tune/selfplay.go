@@ -0,0 +1,44 @@
+package tune
+
+import (
+	"math/rand"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/arena"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// SelfPlay plays n games of the AI against itself from random openings and
+// returns them finished
+func SelfPlay(n int, difficulty string, openingPlies int, seed int64) []*model.Game {
+	rng := rand.New(rand.NewSource(seed))
+	games := make([]*model.Game, 0, n)
+	for i := 0; i < n; i++ {
+		game := model.NewGame()
+		game.ReplayMoves(arena.RandomOpening(rng, openingPlies))
+
+		players := map[model.Piece]*ai.Player{
+			model.Black: ai.NewPlayer(difficulty, model.Black),
+			model.White: ai.NewPlayer(difficulty, model.White),
+		}
+		for _, p := range players {
+			p.Seed = rng.Int63()
+			p.Telemetry = nil
+		}
+
+		for !game.GameOver {
+			if !game.HasValidMove() {
+				game.Pass()
+				continue
+			}
+			row, col, err := players[game.Board.CurrentPlayer].GetMove(game.Board)
+			if err != nil || game.MakeMove(row, col) != nil {
+				break
+			}
+		}
+		if game.GameOver {
+			games = append(games, game)
+		}
+	}
+	return games
+}
@@ -0,0 +1,231 @@
+// Package tune fits the static evaluation's weights to game outcomes by
+// logistic regression ("Texel tuning"): the evaluation of every position,
+// squashed by a sigmoid, should predict the result of the game it was
+// played in.
+package tune
+
+import (
+	"errors"
+	"math"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// ErrNoSamples is returned when there is nothing to tune on
+var ErrNoSamples = errors.New("no positions to tune on")
+
+// squareClasses is the number of squares that differ up to symmetry; the
+// table is tuned per class so it stays symmetric
+const squareClasses = 10
+
+// Number of tuned parameters: the square classes and mobility
+const params = squareClasses + 1
+
+// Sample is one position's features with the result of its game
+type Sample struct {
+	Squares  [squareClasses]float64 // Black minus White discs per square class
+	Mobility float64                // Black minus White legal moves
+	Result   float64                // 1 when Black won, 0.5 for a draw, 0 when White won
+}
+
+// features returns the sample as a parameter vector
+func (s *Sample) features() [params]float64 {
+	var x [params]float64
+	copy(x[:], s.Squares[:])
+	x[squareClasses] = s.Mobility
+	return x
+}
+
+// squareClass maps a square to its symmetry class: squares the board's
+// rotations and reflections map onto each other share a class
+func squareClass(row, col int) int {
+	r, c := min(row, 7-row), min(col, 7-col)
+	if r > c {
+		r, c = c, r
+	}
+	// Classes of the triangle 0 <= r <= c <= 3, numbered row by row
+	return r*4 - r*(r-1)/2 + c - r
+}
+
+// ResultFor labels a game by its winner
+func ResultFor(winner model.Piece) float64 {
+	switch winner {
+	case model.Black:
+		return 1
+	case model.White:
+		return 0
+	}
+	return 0.5
+}
+
+// GameSamples turns every position of a game after the first skip plies into
+// a sample labelled with the game's result; passes may be left out of moves
+func GameSamples(moves []model.Position, result float64, skip int) []Sample {
+	var samples []Sample
+	replay := model.NewGame()
+	for i, move := range moves {
+		if _, err := replay.ReplayMoves([]model.Position{move}); err != nil {
+			break
+		}
+		if i+1 <= skip || replay.GameOver {
+			continue
+		}
+
+		s := Sample{Result: result}
+		board := replay.Board
+		for r := 0; r < 8; r++ {
+			for c := 0; c < 8; c++ {
+				switch board.GetPiece(r, c) {
+				case model.Black:
+					s.Squares[squareClass(r, c)]++
+				case model.White:
+					s.Squares[squareClass(r, c)]--
+				}
+			}
+		}
+		s.Mobility = float64(board.Mobility(model.Black) - board.Mobility(model.White))
+		samples = append(samples, s)
+	}
+	return samples
+}
+
+// Options control the gradient descent
+type Options struct {
+	Iterations int
+	Rate       float64 // Step size in evaluation points per iteration
+	Progress   func(iteration int, loss float64)
+}
+
+// Result is the outcome of a tuning run
+type Result struct {
+	Weights     ai.Weights
+	Scale       float64 // Evaluation points per unit of the sigmoid's input
+	InitialLoss float64
+	FinalLoss   float64
+}
+
+// Tune fits the weights to the samples, starting from start
+// The sigmoid's scale is fitted to the starting weights first and then kept,
+// so the tuned weights stay in the same units as the rest of the evaluation.
+func Tune(samples []Sample, start ai.Weights, opts Options) (Result, error) {
+	if len(samples) == 0 {
+		return Result{}, ErrNoSamples
+	}
+
+	theta := fromWeights(start)
+	scale := fitScale(samples, theta)
+	res := Result{Scale: scale, InitialLoss: loss(samples, theta, scale)}
+
+	// Adam keeps the step size per parameter near Rate however small the
+	// gradients get
+	const beta1, beta2, eps = 0.9, 0.999, 1e-8
+	var m, v [params]float64
+	for it := 1; it <= opts.Iterations; it++ {
+		grad := gradient(samples, theta, scale)
+		for j := range theta {
+			m[j] = beta1*m[j] + (1-beta1)*grad[j]
+			v[j] = beta2*v[j] + (1-beta2)*grad[j]*grad[j]
+			mHat := m[j] / (1 - math.Pow(beta1, float64(it)))
+			vHat := v[j] / (1 - math.Pow(beta2, float64(it)))
+			theta[j] -= opts.Rate * mHat / (math.Sqrt(vHat) + eps)
+		}
+		if opts.Progress != nil {
+			opts.Progress(it, loss(samples, theta, scale))
+		}
+	}
+
+	res.Weights = toWeights(theta)
+	res.FinalLoss = loss(samples, fromWeights(res.Weights), scale)
+	return res, nil
+}
+
+// fromWeights averages the table over each square class
+func fromWeights(w ai.Weights) [params]float64 {
+	var theta [params]float64
+	var counts [squareClasses]int
+	for r := 0; r < 8; r++ {
+		for c := 0; c < 8; c++ {
+			k := squareClass(r, c)
+			theta[k] += float64(w.Table[r][c])
+			counts[k]++
+		}
+	}
+	for k := range counts {
+		theta[k] /= float64(counts[k])
+	}
+	theta[squareClasses] = float64(w.Mobility)
+	return theta
+}
+
+// toWeights rounds the parameters to a weights table
+func toWeights(theta [params]float64) ai.Weights {
+	var w ai.Weights
+	for r := 0; r < 8; r++ {
+		for c := 0; c < 8; c++ {
+			w.Table[r][c] = int(math.Round(theta[squareClass(r, c)]))
+		}
+	}
+	w.Mobility = int(math.Round(theta[squareClasses]))
+	return w
+}
+
+// predict returns the expected result of the sample
+func predict(s *Sample, theta [params]float64, scale float64) float64 {
+	x := s.features()
+	var eval float64
+	for j := range theta {
+		eval += theta[j] * x[j]
+	}
+	return 1 / (1 + math.Exp(-eval/scale))
+}
+
+// loss is the mean squared error of the predictions
+func loss(samples []Sample, theta [params]float64, scale float64) float64 {
+	var sum float64
+	for i := range samples {
+		d := samples[i].Result - predict(&samples[i], theta, scale)
+		sum += d * d
+	}
+	return sum / float64(len(samples))
+}
+
+// gradient returns the derivative of the loss for every parameter
+func gradient(samples []Sample, theta [params]float64, scale float64) [params]float64 {
+	var grad [params]float64
+	for i := range samples {
+		s := &samples[i]
+		p := predict(s, theta, scale)
+		g := -2 * (s.Result - p) * p * (1 - p) / scale
+		x := s.features()
+		for j := range grad {
+			grad[j] += g * x[j]
+		}
+	}
+	for j := range grad {
+		grad[j] /= float64(len(samples))
+	}
+	return grad
+}
+
+// fitScale finds the sigmoid scale that best fits the given weights,
+// searching factors of two and then refining between the neighbours
+func fitScale(samples []Sample, theta [params]float64) float64 {
+	best, bestLoss := 1.0, math.Inf(1)
+	for scale := 1.0; scale <= 1<<14; scale *= 2 {
+		if l := loss(samples, theta, scale); l < bestLoss {
+			best, bestLoss = scale, l
+		}
+	}
+
+	lo, hi := best/2, best*2
+	for i := 0; i < 30; i++ {
+		a, b := lo+(hi-lo)/3, hi-(hi-lo)/3
+		if loss(samples, theta, a) < loss(samples, theta, b) {
+			hi = b
+		} else {
+			lo = a
+		}
+	}
+	return (lo + hi) / 2
+}
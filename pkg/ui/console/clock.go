@@ -0,0 +1,83 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// clockWarning is the time left at which the player to move is warned
+const clockWarning = 10 * time.Second
+
+// errFlagFell is returned while waiting for a move when the mover's clock
+// runs out
+var errFlagFell = errors.New("out of time")
+
+// SetClock plays the game on a clock: each side starts with base and gains
+// increment after each of their moves; a zero base means no clock
+func (c *ConsoleGame) SetClock(base, increment time.Duration) {
+	c.clockBase, c.clockIncrement = base, increment
+}
+
+// timed reports whether the game is played on a clock
+func (c *ConsoleGame) timed() bool {
+	return c.clockBase > 0
+}
+
+// timeLeft returns the time a side has left: the base time and an increment
+// for every move it made, less the time it used
+func (c *ConsoleGame) timeLeft(piece model.Piece) time.Duration {
+	left := c.clockBase - c.clocks[piece]
+	for _, m := range c.game.History {
+		if m.Piece == piece {
+			left += c.clockIncrement
+		}
+	}
+	return left
+}
+
+// charge adds the time since started to the clock of the side to move
+func (c *ConsoleGame) charge(piece model.Piece, started time.Time) {
+	if c.timed() {
+		c.clocks[piece] += time.Since(started)
+	}
+}
+
+// flagFall ends the game when the side to move has run out of time,
+// reporting whether it did
+func (c *ConsoleGame) flagFall() bool {
+	side := c.game.Board.CurrentPlayer
+	if !c.timed() || c.timeLeft(side) > 0 {
+		return false
+	}
+	logging.For("console").Info("flag fell", "side", model.GetPieceName(side))
+	fmt.Fprintf(c.out, "\n%s is out of time.\n", model.GetPieceName(side))
+	c.game.EndEarly(side.Opponent(), model.EndTimeout)
+	return true
+}
+
+// aiClock returns the clock the computer paces its search by
+func (c *ConsoleGame) aiClock() ai.Clock {
+	return ai.Clock{Remaining: c.timeLeft(c.aiPlayer.Piece), Increment: c.clockIncrement}
+}
+
+// displayClocks shows the time both sides have left
+func (c *ConsoleGame) displayClocks() {
+	if c.timed() {
+		fmt.Fprintf(c.out, "Clock: Black %s, White %s\n", clockText(c.timeLeft(model.Black)), clockText(c.timeLeft(model.White)))
+	}
+}
+
+// clockText formats the time left on a clock, with tenths of a second once
+// it is under the warning time
+func clockText(d time.Duration) string {
+	d = max(d, 0)
+	if d < clockWarning {
+		return fmt.Sprintf("0:%04.1f", d.Seconds())
+	}
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
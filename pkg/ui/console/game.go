@@ -1,98 +1,218 @@
 package console
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/help"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
 	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/network"
+	"github.com/amirhossein-jamali/othello/pkg/notify"
 )
 
 // ConsoleGame represents the console-based game interface
 type ConsoleGame struct {
 	game        *model.Game
-	reader      *bufio.Reader
+	input       *lineReader
+	out         io.Writer
 	aiPlayer    *ai.Player
 	playerColor model.Piece
 	gameMode    string
+	view        View
+	thinking    <-chan opponentMove // The computer's move while it is thinking
+	awaiting    bool                // The opponent's turn has been shown
+
+	// A network game's opponent
+	peerName     string
+	peerSend     func(row, col int) error
+	peerMessages chan network.Message // Closed when the connection ends
+	done         chan struct{}        // Closed when the game loop ends
+	notifier     *notify.Notifier
+
+	// Clocks, when the game is timed
+	clockBase      time.Duration
+	clockIncrement time.Duration
+	clocks         map[model.Piece]time.Duration // Time used by each side
 }
 
-// NewConsoleGame creates a new console-based game
+// View is how the board is turned on the screen; moves are always entered
+// by the names of their squares
+type View string
+
+// Board views
+const (
+	ViewNormal  View = "normal"  // A1 at the top left, Black's side
+	ViewFlipped View = "flipped" // Turned around, as White sits
+	ViewRotated View = "rotated" // A quarter turn clockwise, A1 at the top right
+	ViewAuto    View = "auto"    // From the human's side: the side to move when both are human
+)
+
+// ParseView reads the name of a board view
+func ParseView(name string) (View, error) {
+	switch v := View(strings.ToLower(name)); v {
+	case ViewNormal, ViewFlipped, ViewRotated, ViewAuto:
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown board view %q: expected normal, flipped, rotated or auto", name)
+}
+
+// NewConsoleGame creates a new console-based game on the terminal
 func NewConsoleGame() *ConsoleGame {
+	return NewConsoleGameIO(os.Stdin, os.Stdout)
+}
+
+// NewConsoleGameIO creates a console game reading input from in and writing
+// to out, so the same frontend can serve remote sessions
+func NewConsoleGameIO(in io.Reader, out io.Writer) *ConsoleGame {
 	return &ConsoleGame{
 		game:        model.NewGame(),
-		reader:      bufio.NewReader(os.Stdin),
+		input:       newLineReader(in),
+		out:         out,
 		playerColor: model.Empty, // Will be set during initialization
+		view:        ViewNormal,
+		clocks:      make(map[model.Piece]time.Duration),
 	}
 }
 
+// SetView turns the board as the view says
+func (c *ConsoleGame) SetView(v View) {
+	c.view = v
+}
+
 // Run starts the console game loop
 func (c *ConsoleGame) Run() {
-	fmt.Println("Welcome to Othello!")
+	defer c.input.close()
+	fmt.Fprintln(c.out, "Welcome to Othello!")
 
 	// First select game mode
-	c.selectGameMode()
+	if err := c.selectGameMode(); err != nil {
+		return
+	}
 
 	// Then select player color if playing against AI
 	if c.gameMode != "human" {
-		c.selectPlayerColor()
+		if err := c.selectPlayerColor(); err != nil {
+			return
+		}
 	}
+	c.play()
+}
 
-	fmt.Println("\nGame started! Enter moves in the format 'A1', 'B2', etc.")
-	fmt.Println("Type 'quit' to exit the game.")
+// play runs the game loop once the sides are chosen
+func (c *ConsoleGame) play() {
+	if c.done != nil {
+		defer close(c.done)
+	}
+	fmt.Fprintln(c.out, "\nGame started! Enter moves in the format 'A1', 'B2', etc.")
+	fmt.Fprintln(c.out, "Type 'help' for the rules or 'quit' to exit the game.")
+	c.game.Listen(c.announce)
+	if c.peerSend != nil {
+		c.game.Listen(c.forward)
+	}
 
 	for !c.game.GameOver {
-		c.displayBoard()
-		c.displayStatus()
-
-		// If it's AI's turn and we're playing against AI
-		if c.gameMode != "human" && c.game.Board.CurrentPlayer != c.playerColor {
-			fmt.Println("AI is thinking...")
-			row, col, _ := c.aiPlayer.GetMove(c.game.Board)
-
-			if row < 0 || col < 0 {
-				fmt.Println("AI passes their turn.")
-				c.game.Pass()
-				continue
-			}
-
-			move := model.FormatMove(row, col)
-			fmt.Printf("AI places at %s\n", move)
-			c.game.MakeMove(row, col)
-			continue
+		if c.flagFall() {
+			break
 		}
 
-		if !c.game.HasValidMove() {
-			fmt.Println("No valid moves available. Press Enter to pass...")
-			c.reader.ReadString('\n')
-			err := c.game.Pass()
-			if err != nil {
-				fmt.Printf("Error: %v\n", err)
+		// A side without a move passes, whoever plays it; a network
+		// opponent sends their pass
+		own := c.ownTurn()
+		if !c.game.HasValidMove() && (own || c.peerSend == nil) {
+			if err := c.game.Pass(); err != nil {
+				logging.For("console").Error("pass rejected", "err", err)
+				return
 			}
 			continue
 		}
 
-		move, err := c.getPlayerMove()
+		// The opponent moves in the background, so commands can still be
+		// typed and a clock can run out while they do
+		if own || !c.awaiting {
+			c.displayBoard()
+			c.displayStatus()
+		}
+		switch {
+		case own:
+			c.prompt()
+		case c.awaiting:
+		case c.peerSend != nil:
+			c.awaiting = true
+			fmt.Fprintf(c.out, "Waiting for %s...\n", c.peerName)
+		default:
+			c.awaiting = true
+			fmt.Fprintln(c.out, "AI is thinking...")
+			c.thinkAI()
+		}
+
+		side := c.game.Board.CurrentPlayer
+		started := time.Now()
+		ev, err := c.wait(own)
+		c.charge(side, started)
+		if c.flagFall() {
+			break
+		}
+		if errors.Is(err, errFlagFell) {
+			continue // Checked again at the top of the loop
+		}
+		if errors.Is(err, errDisconnected) {
+			fmt.Fprintf(c.out, "\n%s left the game.\n", c.peerName)
+			return
+		}
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			// The input was closed, e.g. a remote player disconnected
+			return
+		}
+
+		if ev.move != nil {
+			c.awaiting = false
+			if err := c.playOpponentMove(*ev.move); err != nil {
+				logging.For("console").Error("opponent move rejected", "mode", c.gameMode, "err", err)
+				fmt.Fprintf(c.out, "The %s failed to move: %v\n", c.opponent(), err)
+				return
+			}
+			if c.peerSend != nil {
+				c.notifyTurn(*ev.move)
+			}
 			continue
 		}
 
+		move := parseCommand(ev.line)
 		if move == "quit" {
 			break
 		}
+		if move == "help" {
+			fmt.Fprint(c.out, "\n"+help.Text(help.ConsoleControls))
+			continue
+		}
+		if move == "flip" {
+			c.flipView()
+			if !own {
+				c.displayBoard()
+			}
+			continue
+		}
+		if !own {
+			fmt.Fprintf(c.out, "Please wait for the %s to move.\n", c.opponent())
+			continue
+		}
 
 		row, col, err := model.ParseMove(move)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			fmt.Fprintf(c.out, "Error: %v\n", err)
 			continue
 		}
 
 		err = c.game.MakeMove(row, col)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			fmt.Fprintf(c.out, "Error: %v\n", err)
 		}
 	}
 
@@ -100,139 +220,233 @@ func (c *ConsoleGame) Run() {
 	c.displayGameOver()
 }
 
+// announce tells the players of every move and pass as the game records it,
+// with the discs a move flipped and the moves both sides have after it
+func (c *ConsoleGame) announce(game *model.Game, move model.Move) {
+	player := model.GetPieceName(move.Piece)
+	switch {
+	case c.gameMode == "network" && move.Piece != c.playerColor:
+		player = c.peerName + " (" + player + ")"
+	case c.gameMode != "human" && move.Piece != c.playerColor:
+		player = "AI (" + player + ")"
+	}
+	if move.IsPass() {
+		fmt.Fprintf(c.out, "%s has no valid moves and passes.\n", player)
+		return
+	}
+	pos := move.Position
+	var flipped []string
+	for _, line := range move.Lines {
+		for _, sq := range line.Squares() {
+			flipped = append(flipped, model.FormatMove(sq.Row, sq.Col))
+		}
+	}
+	slices.Sort(flipped)
+	fmt.Fprintf(c.out, "%s plays %s, flipping %d: %s.\n", player, model.FormatMove(pos.Row, pos.Col), move.Flipped, strings.Join(flipped, " "))
+	fmt.Fprintf(c.out, "Mobility: Black %d, White %d\n", game.Board.Mobility(model.Black), game.Board.Mobility(model.White))
+}
+
 // selectGameMode lets the player choose the game mode
-func (c *ConsoleGame) selectGameMode() {
+func (c *ConsoleGame) selectGameMode() error {
 	for {
-		fmt.Println("\nSelect game mode:")
-		fmt.Println("1. Human vs Human")
-		fmt.Println("2. Human vs Easy AI")
-		fmt.Println("3. Human vs Medium AI")
-		fmt.Println("4. Human vs Hard AI")
-		fmt.Print("Enter choice (1-4): ")
-
-		input, _ := c.reader.ReadString('\n')
+		fmt.Fprintln(c.out, "\nSelect game mode:")
+		fmt.Fprintln(c.out, "1. Human vs Human")
+		fmt.Fprintln(c.out, "2. Human vs Easy AI")
+		fmt.Fprintln(c.out, "3. Human vs Medium AI")
+		fmt.Fprintln(c.out, "4. Human vs Hard AI")
+		fmt.Fprint(c.out, "Enter choice (1-4): ")
+
+		input, err := c.input.readLine()
+		if err != nil {
+			return err
+		}
 		input = strings.TrimSpace(input)
 
 		switch input {
 		case "1":
 			c.gameMode = "human"
-			return
+			return nil
 		case "2":
 			c.gameMode = ai.Easy
-			return
+			return nil
 		case "3":
 			c.gameMode = ai.Medium
-			return
+			return nil
 		case "4":
 			c.gameMode = ai.Hard
-			return
+			return nil
 		default:
-			fmt.Println("Invalid choice. Please try again.")
+			fmt.Fprintln(c.out, "Invalid choice. Please try again.")
 		}
 	}
 }
 
 // selectPlayerColor lets the player choose their color
-func (c *ConsoleGame) selectPlayerColor() {
+func (c *ConsoleGame) selectPlayerColor() error {
 	for {
-		fmt.Println("\nSelect your color:")
-		fmt.Println("B. Black (moves first)")
-		fmt.Println("W. White (moves second)")
-		fmt.Print("Enter choice (B/W): ")
+		fmt.Fprintln(c.out, "\nSelect your color:")
+		fmt.Fprintln(c.out, "B. Black (moves first)")
+		fmt.Fprintln(c.out, "W. White (moves second)")
+		fmt.Fprint(c.out, "Enter choice (B/W): ")
 
-		input, _ := c.reader.ReadString('\n')
+		input, err := c.input.readLine()
+		if err != nil {
+			return err
+		}
 		input = strings.TrimSpace(strings.ToUpper(input))
 
 		switch input {
 		case "B":
 			c.playerColor = model.Black
 			c.aiPlayer = ai.NewPlayer(c.gameMode, model.White)
-			return
+			c.aiPlayer.WriteMetadata(c.game)
+			return nil
 		case "W":
 			c.playerColor = model.White
 			c.aiPlayer = ai.NewPlayer(c.gameMode, model.Black)
-			return
+			c.aiPlayer.WriteMetadata(c.game)
+			return nil
 		default:
-			fmt.Println("Invalid choice. Please try again.")
+			fmt.Fprintln(c.out, "Invalid choice. Please try again.")
+		}
+	}
+}
+
+// ownTurn reports whether the side to move is played at this console
+func (c *ConsoleGame) ownTurn() bool {
+	return c.gameMode == "human" || c.game.Board.CurrentPlayer == c.playerColor
+}
+
+// opponent names the side played away from the console
+func (c *ConsoleGame) opponent() string {
+	if c.peerSend != nil {
+		return "opponent"
+	}
+	return "AI"
+}
+
+// symmetry returns the turn of the board on screen
+func (c *ConsoleGame) symmetry() model.Symmetry {
+	switch c.view {
+	case ViewFlipped:
+		return model.Rotate180
+	case ViewRotated:
+		return model.Rotate90
+	case ViewAuto:
+		side := c.playerColor
+		if c.gameMode == "human" {
+			side = c.game.Board.CurrentPlayer
+		}
+		if side == model.White {
+			return model.Rotate180
+		}
+	}
+	return model.Identity
+}
+
+// flipView turns the board around, between the normal and flipped views
+func (c *ConsoleGame) flipView() {
+	if c.symmetry() == model.Identity {
+		c.view = ViewFlipped
+	} else {
+		c.view = ViewNormal
+	}
+}
+
+// boardLabels returns the labels along the top of the board and down its
+// side in the current view: column letters or row numbers, as the turn of
+// the board puts them
+func (c *ConsoleGame) boardLabels() (top, side []string) {
+	back := c.symmetry().Inverse()
+	label := func(a, b model.Position) string {
+		p, q := back.Apply(a, 8), back.Apply(b, 8)
+		if p.Col == q.Col {
+			return string(rune('A' + p.Col))
 		}
+		return fmt.Sprint(p.Row + 1)
+	}
+	for i := 0; i < 8; i++ {
+		top = append(top, label(model.Position{Row: 0, Col: i}, model.Position{Row: 1, Col: i}))
+		side = append(side, label(model.Position{Row: i, Col: 0}, model.Position{Row: i, Col: 1}))
 	}
+	return top, side
 }
 
-// displayBoard shows the current state of the board
+// displayBoard shows the current state of the board in the current view
 func (c *ConsoleGame) displayBoard() {
-	fmt.Println("\n  A B C D E F G H")
-	fmt.Println("  ---------------")
+	back := c.symmetry().Inverse()
+	top, side := c.boardLabels()
+	header := "  " + strings.Join(top, " ")
+	fmt.Fprintln(c.out, "\n"+header)
+	fmt.Fprintln(c.out, "  ---------------")
 	for i := 0; i < 8; i++ {
-		fmt.Printf("%d|", i+1)
+		fmt.Fprintf(c.out, "%s|", side[i])
 		for j := 0; j < 8; j++ {
-			piece := c.game.Board.GetPiece(i, j)
+			p := back.Apply(model.Position{Row: i, Col: j}, 8)
+			piece := c.game.Board.GetPiece(p.Row, p.Col)
 			switch piece {
 			case model.Black:
-				fmt.Print("B ")
+				fmt.Fprint(c.out, "B ")
 			case model.White:
-				fmt.Print("W ")
+				fmt.Fprint(c.out, "W ")
 			default:
-				if c.isValidMove(i, j) {
-					fmt.Print("* ")
+				if c.isValidMove(p.Row, p.Col) {
+					fmt.Fprint(c.out, "* ")
 				} else {
-					fmt.Print(". ")
+					fmt.Fprint(c.out, ". ")
 				}
 			}
 		}
-		fmt.Printf("|%d\n", i+1)
+		fmt.Fprintf(c.out, "|%s\n", side[i])
 	}
-	fmt.Println("  ---------------")
-	fmt.Println("  A B C D E F G H")
+	fmt.Fprintln(c.out, "  ---------------")
+	fmt.Fprintln(c.out, header)
 }
 
 // displayStatus shows the current game status
 func (c *ConsoleGame) displayStatus() {
 	blackCount, whiteCount := c.game.GetScore()
-	fmt.Printf("\nScore: Black: %d, White: %d\n", blackCount, whiteCount)
+	fmt.Fprintf(c.out, "\nScore: Black: %d, White: %d\n", blackCount, whiteCount)
 
 	currentPlayer := "Black"
 	if c.game.Board.CurrentPlayer == model.White {
 		currentPlayer = "White"
 	}
-	fmt.Printf("%s's turn\n", currentPlayer)
-
-	if !c.game.HasValidMove() {
-		fmt.Println("No valid moves available!")
-	}
+	fmt.Fprintf(c.out, "%s's turn\n", currentPlayer)
+	c.displayClocks()
 }
 
 // displayGameOver shows the final game result
 func (c *ConsoleGame) displayGameOver() {
-	fmt.Println("\nGame Over!")
+	fmt.Fprintln(c.out, "\nGame Over!")
 	blackCount, whiteCount := c.game.GetScore()
-	fmt.Printf("Final Score - Black: %d, White: %d\n", blackCount, whiteCount)
+	fmt.Fprintf(c.out, "Final Score - Black: %d, White: %d\n", blackCount, whiteCount)
 
 	switch c.game.Winner {
 	case model.Black:
-		fmt.Println("Black wins!")
+		fmt.Fprintln(c.out, "Black wins!")
 	case model.White:
-		fmt.Println("White wins!")
+		fmt.Fprintln(c.out, "White wins!")
 	default:
-		fmt.Println("It's a tie!")
+		fmt.Fprintln(c.out, "It's a tie!")
 	}
 }
 
-// getPlayerMove reads and validates player input
-func (c *ConsoleGame) getPlayerMove() (string, error) {
-	fmt.Print("Enter your move: ")
-	move, err := c.reader.ReadString('\n')
-	if err != nil {
-		return "", err
-	}
-
-	move = strings.TrimSpace(move)
+// parseCommand reads a typed move or command
+func parseCommand(line string) string {
+	move := strings.TrimSpace(line)
 	move = strings.ToUpper(move)
 
-	if move == "QUIT" {
-		return "quit", nil
+	switch move {
+	case "QUIT":
+		return "quit"
+	case "HELP":
+		return "help"
+	case "FLIP":
+		return "flip"
 	}
 
-	return move, nil
+	return move
 }
 
 // isValidMove checks if a move is valid
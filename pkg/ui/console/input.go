@@ -0,0 +1,51 @@
+package console
+
+import (
+	"bufio"
+	"io"
+)
+
+// lineReader reads input lines in a goroutine of its own, so waiting for one
+// can be given up, e.g. when a clock runs out
+type lineReader struct {
+	lines chan string // Closed when the input ends
+	err   error       // Why the input ended, set before lines is closed
+	done  chan struct{}
+}
+
+// newLineReader starts reading lines from r
+func newLineReader(r io.Reader) *lineReader {
+	l := &lineReader{lines: make(chan string), done: make(chan struct{})}
+	go func() {
+		defer close(l.lines)
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadString('\n')
+			if err != nil {
+				l.err = err
+				return
+			}
+			select {
+			case l.lines <- line:
+			case <-l.done:
+				return
+			}
+		}
+	}()
+	return l
+}
+
+// readLine waits for the next line
+func (l *lineReader) readLine() (string, error) {
+	line, ok := <-l.lines
+	if !ok {
+		return "", l.err
+	}
+	return line, nil
+}
+
+// close stops handing out lines; a read already waiting on the input ends
+// when the input does
+func (l *lineReader) close() {
+	close(l.done)
+}
@@ -0,0 +1,121 @@
+package console
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/network"
+	"github.com/amirhossein-jamali/othello/pkg/notation"
+	"github.com/amirhossein-jamali/othello/pkg/notify"
+)
+
+// errDisconnected is returned while waiting for a move when the connection
+// to the opponent ends
+var errDisconnected = errors.New("opponent disconnected")
+
+// RunHost plays a network game as its host against the client on conn,
+// who plays remoteColor
+func (c *ConsoleGame) RunHost(conn *network.Conn, remoteColor model.Piece) {
+	defer c.input.close()
+	host := network.NewHost(conn, model.NewGame(), remoteColor)
+	host.OnUpdate = c.connect(remoteColor.Opponent(), conn.PeerName, host.PlayMove)
+	go c.serve(host.Serve)
+	c.play()
+}
+
+// RunClient plays a network game joined with client
+func (c *ConsoleGame) RunClient(client *network.Client) {
+	defer c.input.close()
+	client.OnUpdate = c.connect(client.Color, client.PeerName(), client.SendMove)
+	go c.serve(client.Serve)
+	c.play()
+}
+
+// connect sets the game up to play color against a network opponent, who
+// is sent the player's moves with send, and returns the callback the
+// connection hands the opponent's messages to
+func (c *ConsoleGame) connect(color model.Piece, name string, send func(row, col int) error) func(network.Message) {
+	c.gameMode = "network"
+	c.playerColor = color
+	c.peerName = name
+	if c.peerName == "" {
+		c.peerName = "Opponent"
+	}
+	c.peerSend = send
+	c.peerMessages = make(chan network.Message)
+	c.done = make(chan struct{})
+	fmt.Fprintf(c.out, "Connected to %s. You play %s.\n", c.peerName, model.GetPieceName(color))
+
+	return func(msg network.Message) {
+		select {
+		case c.peerMessages <- msg:
+		case <-c.done:
+		}
+	}
+}
+
+// serve runs the connection until it ends, then tells the game loop
+func (c *ConsoleGame) serve(run func() error) {
+	err := run()
+	logging.For("console").Debug("network game connection ended", "err", err)
+	close(c.peerMessages)
+}
+
+// SetNotifier tells the player of their turns in network games, which may
+// be played in a terminal they are not watching
+func (c *ConsoleGame) SetNotifier(n *notify.Notifier) {
+	c.notifier = n
+}
+
+// notifyTurn tells the player the opponent has moved and it is their turn
+func (c *ConsoleGame) notifyTurn(m opponentMove) {
+	if !c.notifier.Enabled() || c.game.GameOver || !c.ownTurn() {
+		return
+	}
+	turn := notify.Turn{
+		Kind:     notify.KindNetwork,
+		Opponent: c.peerName,
+		Color:    model.GetPieceName(c.playerColor),
+		Move:     model.FormatMove(m.row, m.col),
+	}
+	go func() {
+		if err := c.notifier.YourTurn(turn); err != nil {
+			logging.For("console").Warn("turn notification failed", "err", err)
+		}
+	}()
+}
+
+// forward sends the player's own moves and passes to the opponent
+func (c *ConsoleGame) forward(_ *model.Game, move model.Move) {
+	if move.Piece != c.playerColor {
+		return
+	}
+	if err := c.peerSend(move.Position.Row, move.Position.Col); err != nil {
+		logging.For("console").Error("move not sent", "move", model.FormatMove(move.Position.Row, move.Position.Col), "err", err)
+		fmt.Fprintf(c.out, "Your move could not be sent: %v\n", err)
+	}
+}
+
+// peerEvent turns a message from the opponent into an event for the game
+// loop; ok is false for messages that only need showing
+func (c *ConsoleGame) peerEvent(msg network.Message) (ev event, ok bool) {
+	switch msg.Type {
+	case network.TypeState:
+		// The host echoes the player's own moves, which are already played
+		if msg.Move == "" || msg.Position == c.game.Board.PositionString() {
+			return event{}, false
+		}
+		var m opponentMove
+		m.row, m.col, m.err = notation.Default.Parse(msg.Move, notation.Strict)
+		return event{move: &m}, true
+	case network.TypeError:
+		// Moves are checked before they are sent, so the games no longer agree
+		m := opponentMove{err: &network.ProtocolError{Code: msg.Code, Reason: msg.Reason, Move: msg.Move}}
+		return event{move: &m}, true
+	case network.TypeChat:
+		fmt.Fprintf(c.out, "\n%s: %s\n", c.peerName, msg.Text)
+	}
+	return event{}, false
+}
@@ -0,0 +1,263 @@
+package console
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+)
+
+// Telnet command bytes
+const (
+	telnetIAC  = 255 // Interpret as command
+	telnetSB   = 250 // Subnegotiation begin
+	telnetSE   = 240 // Subnegotiation end
+	telnetWILL = 251
+	telnetWONT = 252
+	telnetDO   = 253
+	telnetDONT = 254
+)
+
+// ErrServerClosed is returned by Serve after Close
+var ErrServerClosed = errors.New("console server closed")
+
+// Server serves the console frontend to telnet clients, each connection
+// playing its own game
+type Server struct {
+	MaxSessions int           // Connections beyond this are turned away; 0 means no limit
+	IdleTimeout time.Duration // Sessions without input for this long are closed; 0 means never
+
+	mu       sync.Mutex
+	sessions map[net.Conn]struct{}
+	listener net.Listener
+	closed   bool
+	wg       sync.WaitGroup
+	log      *slog.Logger
+}
+
+// NewServer creates a console server with sensible limits
+func NewServer() *Server {
+	return &Server{
+		MaxSessions: 64,
+		IdleTimeout: 30 * time.Minute,
+		sessions:    make(map[net.Conn]struct{}),
+		log:         logging.For("console"),
+	}
+}
+
+// ListenAndServe listens on addr and serves sessions until Close
+func (s *Server) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts connections on ln and serves each in its own goroutine
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+	s.listener = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		if !s.add(conn) {
+			fmt.Fprint(conn, "Sorry, the server is full. Please try again later.\r\n")
+			conn.Close()
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.remove(conn)
+			s.serve(conn)
+		}()
+	}
+}
+
+// Sessions returns the number of connected players
+func (s *Server) Sessions() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// Close stops accepting connections, disconnects every session and waits for
+// them to finish
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+	for conn := range s.sessions {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// add registers a session, refusing it when the server is full or closed
+func (s *Server) add(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed || (s.MaxSessions > 0 && len(s.sessions) >= s.MaxSessions) {
+		return false
+	}
+	s.sessions[conn] = struct{}{}
+	return true
+}
+
+// remove unregisters and closes a session
+func (s *Server) remove(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.sessions, conn)
+	s.mu.Unlock()
+	conn.Close()
+}
+
+// serve runs one console game over a connection
+func (s *Server) serve(conn net.Conn) {
+	addr := conn.RemoteAddr().String()
+	s.log.Info("console session started", "addr", addr, "sessions", s.Sessions())
+	started := time.Now()
+
+	defer func() {
+		// A bug in one session must not take down the others
+		if r := recover(); r != nil {
+			s.log.Error("console session panicked", "addr", addr, "panic", r)
+		}
+		s.log.Info("console session ended", "addr", addr, "duration", time.Since(started).Round(time.Second))
+	}()
+
+	in := &telnetReader{conn: conn, r: bufio.NewReader(conn), timeout: s.IdleTimeout}
+	out := &crlfWriter{w: conn}
+	NewConsoleGameIO(in, out).Run()
+}
+
+// telnetReader strips telnet negotiation from the input, refusing every
+// option the client offers, and applies the idle timeout to each read
+type telnetReader struct {
+	conn    net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+}
+
+// Read returns plain text typed by the client
+func (t *telnetReader) Read(p []byte) (int, error) {
+	if t.timeout > 0 {
+		t.conn.SetReadDeadline(time.Now().Add(t.timeout))
+	}
+
+	n := 0
+	for n < len(p) {
+		// Return what we have rather than block for more
+		if n > 0 && t.r.Buffered() == 0 {
+			break
+		}
+		b, err := t.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		switch b {
+		case telnetIAC:
+			if err := t.command(); err != nil {
+				return n, err
+			}
+		case '\r', 0:
+			// Lines end in CR LF or CR NUL; the console only needs LF
+		default:
+			p[n] = b
+			n++
+		}
+	}
+	return n, nil
+}
+
+// command consumes a telnet command following IAC
+func (t *telnetReader) command() error {
+	cmd, err := t.r.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case cmd == telnetIAC:
+		// Escaped 255 data byte, never valid in a move
+	case cmd == telnetSB:
+		// Skip the subnegotiation up to IAC SE
+		for {
+			b, err := t.r.ReadByte()
+			if err != nil {
+				return err
+			}
+			if b != telnetIAC {
+				continue
+			}
+			if b, err = t.r.ReadByte(); err != nil {
+				return err
+			}
+			if b == telnetSE {
+				return nil
+			}
+		}
+	case cmd >= telnetWILL && cmd <= telnetDONT:
+		option, err := t.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		// Decline every option so the client stays in plain line mode
+		switch cmd {
+		case telnetWILL:
+			t.conn.Write([]byte{telnetIAC, telnetDONT, option})
+		case telnetDO:
+			t.conn.Write([]byte{telnetIAC, telnetWONT, option})
+		}
+	}
+	return nil
+}
+
+// crlfWriter converts the console's LF line endings to the CR LF telnet
+// clients expect
+type crlfWriter struct {
+	w io.Writer
+}
+
+// Write writes p with every LF expanded to CR LF
+func (c *crlfWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(c.w, strings.ReplaceAll(string(p), "\n", "\r\n")); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
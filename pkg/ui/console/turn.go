@@ -0,0 +1,102 @@
+package console
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// opponentMove is a move chosen away from the keyboard, e.g. by the computer
+type opponentMove struct {
+	row, col int
+	err      error
+}
+
+// event is what a wait ended with: a line typed by the player or the
+// opponent's move
+type event struct {
+	line string
+	move *opponentMove
+}
+
+// thinkAI starts the computer looking for its move in the background; the
+// move arrives on c.thinking
+func (c *ConsoleGame) thinkAI() {
+	board := c.game.Board.Clone()
+	timed, clock := c.timed(), c.aiClock()
+	moves := make(chan opponentMove, 1) // The move is dropped if the game ends first
+	go func() {
+		var m opponentMove
+		if timed {
+			m.row, m.col, m.err = c.aiPlayer.GetMoveTimed(board, clock)
+		} else {
+			m.row, m.col, m.err = c.aiPlayer.GetMove(board)
+		}
+		moves <- m
+	}()
+	c.thinking = moves
+}
+
+// prompt asks the player for their move, with the time they have left
+func (c *ConsoleGame) prompt() {
+	if c.timed() {
+		fmt.Fprintf(c.out, "Enter your move (%s left): ", clockText(c.timeLeft(c.game.Board.CurrentPlayer)))
+		return
+	}
+	fmt.Fprint(c.out, "Enter your move: ")
+}
+
+// wait blocks until the player types a line, the opponent's move arrives
+// or the side to move runs out of time, when it returns errFlagFell, or the
+// network opponent disconnects, when it returns errDisconnected. The
+// player is warned when their own clock runs low.
+func (c *ConsoleGame) wait(own bool) (event, error) {
+	var warn, flag <-chan time.Time
+	if c.timed() {
+		left := c.timeLeft(c.game.Board.CurrentPlayer)
+		if own && left > clockWarning {
+			warn = time.After(left - clockWarning)
+		}
+		flag = time.After(left)
+	}
+
+	for {
+		select {
+		case line, ok := <-c.input.lines:
+			if !ok {
+				return event{}, c.input.err
+			}
+			return event{line: line}, nil
+		case m := <-c.thinking:
+			c.thinking = nil
+			return event{move: &m}, nil
+		case msg, ok := <-c.peerMessages:
+			if !ok {
+				return event{}, errDisconnected
+			}
+			if ev, ok := c.peerEvent(msg); ok {
+				return ev, nil
+			}
+		case <-warn:
+			warn = nil
+			fmt.Fprintf(c.out, "\n%d seconds left!\nEnter your move: ", int(clockWarning.Seconds()))
+		case <-flag:
+			return event{}, errFlagFell
+		}
+	}
+}
+
+// playOpponentMove plays the move the opponent chose
+func (c *ConsoleGame) playOpponentMove(m opponentMove) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.row < 0 {
+		return c.game.Pass()
+	}
+	if err := c.game.MakeMove(m.row, m.col); err != nil {
+		return fmt.Errorf("%s: %w", model.FormatMove(m.row, m.col), err)
+	}
+	return nil
+}
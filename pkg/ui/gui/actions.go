@@ -0,0 +1,259 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// flashDuration is how long a flash message stays on screen
+const flashDuration = 3 * time.Second
+
+// updateGlobalActions handles the shortcuts that work on every screen
+func (g *Game) updateGlobalActions() {
+	if g.keys.pressed(config.ActionFullscreen) {
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+	}
+	if g.keys.pressed(config.ActionScreenshot) {
+		g.screenshotPending = true
+	}
+	if g.keys.pressed(config.ActionDebugOverlay) {
+		g.debug.shown = !g.debug.shown
+	}
+	if g.keys.pressed(config.ActionBroadcast) {
+		g.toggleBroadcast()
+	}
+}
+
+// updateGameActions handles the in-game shortcuts
+func (g *Game) updateGameActions() {
+	if g.keys.pressed(config.ActionUndo) {
+		g.undo()
+	}
+	if g.keys.pressed(config.ActionHint) {
+		g.showHint()
+	}
+	if g.keys.pressed(config.ActionSaveGame) {
+		g.saveGameFile()
+	}
+	if g.keys.pressed(config.ActionLoadGame) {
+		g.loadGameFile()
+	}
+	if g.keys.pressed(config.ActionFindPosition) {
+		g.findPosition()
+	}
+}
+
+// undo takes back the last move; against the computer it also takes back
+// the computer's reply so it is the player's turn again
+func (g *Game) undo() {
+	if g.gameMode == ModeCorrespondence {
+		g.flash("Correspondence moves cannot be taken back")
+		return
+	}
+	side := g.undoingSide()
+	if g.assistLeft(side, library.MetaUndos) == 0 {
+		g.flash("No undos left in this game")
+		return
+	}
+	if err := g.othelloGame.Undo(); err != nil {
+		g.flash("Nothing to undo")
+		return
+	}
+	library.AddAssistance(g.othelloGame, side, library.MetaUndos)
+	// Keep going back past the computer's moves and forced passes
+	for g.isComputerTurn() || !g.othelloGame.HasValidMove() {
+		if g.othelloGame.Undo() != nil {
+			break
+		}
+	}
+
+	g.afterTakeBack()
+}
+
+// undoingSide returns the side whose move an undo takes back: the human
+// against the computer, otherwise the side that moved last
+func (g *Game) undoingSide() model.Piece {
+	if g.aiPlayer != nil {
+		return g.aiPlayer.Piece.Opponent()
+	}
+	if n := len(g.othelloGame.History); n > 0 {
+		return g.othelloGame.History[n-1].Piece
+	}
+	return g.othelloGame.Board.CurrentPlayer
+}
+
+// assistLeft returns how many hints or undos, as library.MetaHints or
+// library.MetaUndos, a side has left in this game in assisted mode, or -1
+// when they are not limited
+func (g *Game) assistLeft(side model.Piece, key string) int {
+	training := g.applied.Training
+	if !training.Assisted {
+		return -1
+	}
+	hints, undos := library.Assistance(g.othelloGame.Metadata, side)
+	if key == library.MetaHints {
+		return max(training.HintBudget-hints, 0)
+	}
+	return max(training.UndoBudget-undos, 0)
+}
+
+// drawAssistance shows the hints and undos left to the player in assisted
+// mode, below the board
+func (g *Game) drawAssistance(screen *ebiten.Image) {
+	if !g.applied.Training.Assisted || g.othelloGame.GameOver || g.gameMode == ModeCorrespondence {
+		return
+	}
+	side := g.othelloGame.Board.CurrentPlayer
+	if g.aiPlayer != nil {
+		side = g.aiPlayer.Piece.Opponent()
+	}
+	status := fmt.Sprintf("Assisted mode: %d of %d hints and %d of %d undos left",
+		g.assistLeft(side, library.MetaHints), g.applied.Training.HintBudget,
+		g.assistLeft(side, library.MetaUndos), g.applied.Training.UndoBudget)
+	x := g.layout.board.Min.X + g.layout.board.Dx()/2 - textWidth(g.resources.GetSmallFont(), status)/2
+	drawLabel(screen, status, g.resources.GetSmallFont(), x, g.layout.board.Max.Y+24, TextColor)
+}
+
+// afterTakeBack brings the move markers and turn state in line with a game
+// whose last moves were taken back
+func (g *Game) afterTakeBack() {
+	g.cancelBlunderCheck()
+	g.stopBrowsing()
+	g.review = false // Played on, the game ends as usual
+	g.validMoves = g.othelloGame.GetValidMoves()
+	g.markLastMove()
+	g.computerAction = false
+	g.animating = false
+}
+
+// markLastMove marks the last move played that was not a pass
+func (g *Game) markLastMove() {
+	g.lastMoveX, g.lastMoveY = -1, -1
+	for i := len(g.othelloGame.History) - 1; i >= 0; i-- {
+		if pos := g.othelloGame.History[i].Position; pos.Row >= 0 {
+			g.lastMoveX, g.lastMoveY = pos.Col, pos.Row
+			return
+		}
+	}
+}
+
+// showHint marks a suggested move for the side to move, preferring the
+// background analysis when it has a result
+func (g *Game) showHint() {
+	board := g.othelloGame.Board
+	if g.isComputerTurn() || !g.othelloGame.HasValidMove() {
+		g.flash("No hint available now")
+		return
+	}
+	if g.hintPosition == board.PositionString() {
+		return // Already shown
+	}
+	if g.assistLeft(board.CurrentPlayer, library.MetaHints) == 0 {
+		g.flash("No hints left in this game")
+		return
+	}
+
+	best := model.PassPosition
+	if info, ok := g.analysis.latest(); ok {
+		best = info.Best
+	} else {
+		row, col, err := ai.NewPlayer(ai.Medium, board.CurrentPlayer).GetMove(board)
+		if err == nil {
+			best = model.Position{Row: row, Col: col}
+		}
+	}
+	if best.Row < 0 {
+		g.flash("No hint available now")
+		return
+	}
+	g.hint = best
+	g.hintPosition = board.PositionString()
+	library.AddAssistance(g.othelloGame, board.CurrentPlayer, library.MetaHints)
+}
+
+// drawHint marks the suggested move and the lines it flips while the
+// position it was given for is on the board
+func (g *Game) drawHint(screen *ebiten.Image) {
+	if g.hintPosition == "" || g.hintPosition != g.othelloGame.Board.PositionString() {
+		return
+	}
+	center := g.cellCenter(g.hint)
+	drawCircle(screen, center.X, center.Y, CellSize/3, HintColor)
+	g.drawFlipArrows(screen, g.hint, g.othelloGame.Board.FlipLines(g.hint.Row, g.hint.Col), HintColor)
+}
+
+// flash shows a short message at the bottom of the screen
+func (g *Game) flash(message string) {
+	g.flashText = message
+	g.flashUntil = time.Now().Add(flashDuration)
+}
+
+// drawFlash draws the flash message while it is current
+func (g *Game) drawFlash(screen *ebiten.Image) {
+	if g.flashText == "" || time.Now().After(g.flashUntil) {
+		return
+	}
+	rect := image.Rect(ScreenWidth/2-250, ScreenHeight-60, ScreenWidth/2+250, ScreenHeight-36)
+	drawRect(screen, rect, PanelBackColor)
+	drawCenteredText(screen, g.flashText, g.resources.GetSmallFont(), rect, TextColor)
+}
+
+// saveScreenshot writes the rendered screen to a PNG file when one was
+// requested
+func (g *Game) saveScreenshot(screen *ebiten.Image) {
+	if !g.screenshotPending {
+		return
+	}
+	g.screenshotPending = false
+
+	img := image.NewRGBA(screen.Bounds())
+	screen.ReadPixels(img.Pix)
+
+	dir := screenshotDir()
+	path := filepath.Join(dir, fmt.Sprintf("othello-%s.png", time.Now().Format("20060102-150405")))
+	err := os.MkdirAll(dir, 0o755)
+	if err == nil {
+		err = writePNG(path, img)
+	}
+	if err != nil {
+		logging.For("gui").Error("failed to save screenshot", "file", path, "err", err)
+		g.flash("Screenshot failed: " + err.Error())
+		return
+	}
+	logging.For("gui").Info("screenshot saved", "file", path)
+	g.flash("Screenshot saved to " + path)
+}
+
+// screenshotDir returns the directory screenshots are saved to
+func screenshotDir() string {
+	if home, err := os.UserHomeDir(); err == nil {
+		return filepath.Join(home, "Pictures", "Othello")
+	}
+	return os.TempDir()
+}
+
+// writePNG encodes img to a new file
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,67 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Arrow styles
+const arrowWidth = 3
+
+var (
+	lastMoveArrowColor = color.RGBA{255, 255, 0, 190}
+	analysisArrowColor = color.RGBA{80, 160, 255, 160}
+)
+
+// cellCenter returns the center of a square on screen
+func (g *Game) cellCenter(p model.Position) image.Point {
+	return g.layout.cellRect(p.Row, p.Col).Min.Add(image.Pt(CellSize/2, CellSize/2))
+}
+
+// drawFlipArrows draws an arrow from a move along every line of discs it
+// flips, to the farthest disc flipped
+func (g *Game) drawFlipArrows(screen *ebiten.Image, move model.Position, lines []model.FlipLine, clr color.Color) {
+	from := g.cellCenter(move)
+	for _, line := range lines {
+		drawArrow(screen, from, g.cellCenter(line.End), arrowWidth, clr)
+	}
+}
+
+// lastMoveFlips returns the lines of discs the last move flipped, worked out
+// from the position before it once per position
+func (g *Game) lastMoveFlips() []model.FlipLine {
+	hash := g.othelloGame.Board.Hash()
+	if hash == g.lastFlipsHash {
+		return g.lastFlips
+	}
+	g.lastFlipsHash, g.lastFlips = hash, nil
+	for i := len(g.othelloGame.History) - 1; i >= 0; i-- {
+		if pos := g.othelloGame.History[i].Position; pos.Row >= 0 {
+			g.lastFlips = g.othelloGame.BoardAt(i).FlipLines(pos.Row, pos.Col)
+			break
+		}
+	}
+	return g.lastFlips
+}
+
+// drawAnalysisArrows shows the analysis' best move and the lines it would
+// flip while the analysis panel is open
+func (g *Game) drawAnalysisArrows(screen *ebiten.Image) {
+	if !g.applied.Layout.Analysis {
+		return
+	}
+	info, ok := g.analysis.latest()
+	if !ok || info.Best.Row < 0 {
+		return
+	}
+	board := g.shownBoard()
+	if g.hintPosition == board.PositionString() && g.hint == info.Best {
+		return // The hint shows the same move
+	}
+	g.drawFlipArrows(screen, info.Best, board.FlipLines(info.Best.Row, info.Best.Col), analysisArrowColor)
+}
@@ -0,0 +1,95 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/amirhossein-jamali/othello/pkg/broadcast"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// broadcastView shares the game on screen as a read-only live web page
+type broadcastView struct {
+	server *broadcast.Server
+	http   *http.Server
+	url    string
+	shown  string // What was last published
+}
+
+// toggleBroadcast starts serving the live page on the address in the
+// settings, or stops it
+func (g *Game) toggleBroadcast() {
+	log := logging.For("gui")
+	if b := g.broadcast; b != nil {
+		b.http.Close()
+		g.broadcast = nil
+		log.Info("broadcast stopped")
+		g.flash("Broadcast stopped")
+		return
+	}
+
+	ln, err := net.Listen("tcp", g.applied.Broadcast.Addr)
+	if err != nil {
+		log.Error("failed to start broadcast", "addr", g.applied.Broadcast.Addr, "err", err)
+		g.showNotice("Could not start the broadcast", err.Error())
+		return
+	}
+	b := &broadcastView{server: broadcast.NewServer(), url: broadcastURL(ln.Addr())}
+	b.http = &http.Server{Handler: b.server}
+	go func() {
+		if err := b.http.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+			log.Error("broadcast server failed", "err", err)
+		}
+	}()
+	g.broadcast = b
+	log.Info("broadcast started", "url", b.url)
+	g.flash("Broadcasting at " + b.url)
+}
+
+// broadcastURL is the address friends open, naming this machine when the
+// server listens on all interfaces
+func broadcastURL(addr net.Addr) string {
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "http://" + addr.String()
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		if name, err := os.Hostname(); err == nil {
+			host = name
+		}
+	}
+	return "http://" + net.JoinHostPort(host, port) + "/"
+}
+
+// updateBroadcast publishes the game on screen when it changed, and the
+// clocks as they tick
+func (g *Game) updateBroadcast() {
+	b := g.broadcast
+	if b == nil || g.othelloGame == nil || (g.gameState != StateInGame && g.gameState != StateGameOver) {
+		return
+	}
+	black, white := g.playerName(model.Black), g.playerName(model.White)
+	shown := fmt.Sprintf("%p %d %v %s %s", g.othelloGame, len(g.othelloGame.History), g.othelloGame.GameOver, black, white)
+	if shown != b.shown {
+		b.shown = shown
+		b.server.Publish(g.othelloGame, black, white)
+	}
+	b.server.SetClocks(g.clockText(model.Black), g.clockText(model.White))
+}
+
+// drawBroadcast marks a game being broadcast
+func (g *Game) drawBroadcast(screen *ebiten.Image) {
+	if g.broadcast == nil {
+		return
+	}
+	label := "LIVE " + g.broadcast.url
+	face := g.resources.GetSmallFont()
+	drawLabel(screen, label, face, ScreenWidth-textWidth(face, label)-12, 28, HighlightColor)
+}
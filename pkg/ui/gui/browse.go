@@ -0,0 +1,197 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Mini-board showing the live game while an earlier position is browsed
+const (
+	miniCell   = 16
+	miniSize   = 8 * miniCell
+	miniMargin = 8
+)
+
+// browser shows an earlier position of the game on the board while the
+// game itself goes on
+type browser struct {
+	ply   int          // Plies of the history shown, -1 for the live position
+	board *model.Board // Position after ply moves
+}
+
+// boardJump is a change of the board shown while browsing, animated for a
+// moment after it happens
+type boardJump struct {
+	diff  model.BoardDiff
+	start time.Time
+}
+
+// browsing reports whether an earlier position is on the board
+func (g *Game) browsing() bool {
+	return g.browse.ply >= 0
+}
+
+// browseTo shows the position after ply moves, or the live position when
+// ply reaches the end of the history
+func (g *Game) browseTo(ply int) {
+	ply = max(ply, 0)
+	if ply >= len(g.othelloGame.History) {
+		g.startJump(g.othelloGame.Board)
+		g.stopBrowsing()
+		return
+	}
+	board := g.othelloGame.BoardAt(ply)
+	g.startJump(board)
+	g.browse = browser{ply: ply, board: board}
+}
+
+// startJump animates the squares that change when the board shown becomes
+// to, so a jump of several moves can be followed
+func (g *Game) startJump(to *model.Board) {
+	g.jump = boardJump{diff: model.Diff(g.shownBoard(), to), start: time.Now()}
+}
+
+// drawJump lights up the squares the last jump changed, fading out over
+// the animation
+func (g *Game) drawJump(screen *ebiten.Image) {
+	elapsed := time.Since(g.jump.start).Seconds()
+	if elapsed >= AnimationDuration {
+		return
+	}
+	clr := HighlightColor
+	clr.A = uint8(float64(clr.A) * (1 - elapsed/AnimationDuration))
+	for _, squares := range [][]model.Position{g.jump.diff.Added, g.jump.diff.Removed, g.jump.diff.Flipped} {
+		for _, sq := range squares {
+			drawRect(screen, g.layout.cellRect(sq.Row, sq.Col).Inset(2), clr)
+		}
+	}
+}
+
+// stopBrowsing puts the live position back on the board
+func (g *Game) stopBrowsing() {
+	g.browse = browser{ply: -1}
+}
+
+// updateBrowsing steps through the history with the keyboard and picks a
+// position from the history panel; clicking the board returns to the game.
+// It reports whether the input was used.
+func (g *Game) updateBrowsing() bool {
+	current := len(g.othelloGame.History)
+	if g.browsing() {
+		current = g.browse.ply
+	}
+	switch {
+	case g.keys.pressed(config.ActionHistoryBack):
+		g.browseTo(current - 1)
+		return true
+	case g.keys.pressed(config.ActionHistoryForward) && g.browsing():
+		g.browseTo(current + 1)
+		return true
+	case !pointerJustPressed():
+		return false
+	}
+
+	x, y := cursorPosition()
+	if ply, ok := g.historyPlyAt(x, y); ok {
+		g.browseTo(ply)
+		return true
+	}
+	if g.browsing() && image.Pt(x, y).In(g.layout.board) {
+		g.stopBrowsing()
+		return true
+	}
+	return false
+}
+
+// historyPlyAt returns the ply after the move listed at x, y in the history
+// panel
+func (g *Game) historyPlyAt(x, y int) (int, bool) {
+	panel := g.layout.history
+	list := historyListRect(panel)
+	if panel.Empty() || !image.Pt(x, y).In(list) {
+		return 0, false
+	}
+	row := (y - list.Min.Y + g.historyScroll.offset) / HistoryItemH
+	i := row * 2
+	if x >= panel.Min.X+180 {
+		i++
+	}
+	if i >= len(g.othelloGame.History) {
+		return 0, false
+	}
+	return i + 1, true
+}
+
+// shownBoard returns the position on the board: the browsed one or the
+// live one
+func (g *Game) shownBoard() *model.Board {
+	if g.browsing() {
+		return g.browse.board
+	}
+	return g.othelloGame.Board
+}
+
+// drawBrowsedMove marks the move leading to the browsed position
+func (g *Game) drawBrowsedMove(screen *ebiten.Image) {
+	if g.browse.ply == 0 {
+		return
+	}
+	if pos := g.othelloGame.History[g.browse.ply-1].Position; pos.Row >= 0 {
+		drawOutline(screen, g.layout.cellRect(pos.Row, pos.Col), 3, HighlightColor)
+	}
+}
+
+// drawMiniBoard draws the live position in the board's top right corner so
+// the game is not lost from sight while browsing
+func (g *Game) drawMiniBoard(screen *ebiten.Image) {
+	b := g.layout.board
+	rect := image.Rect(b.Max.X-miniSize-miniMargin, b.Min.Y+miniMargin, b.Max.X-miniMargin, b.Min.Y+miniMargin+miniSize)
+	frame := image.Rect(rect.Min.X-3, rect.Min.Y-3, rect.Max.X+3, rect.Max.Y+20)
+	drawRect(screen, frame, PanelBackColor)
+	drawOutline(screen, frame, 1, PanelBorderColor)
+	drawRect(screen, rect, BoardColor)
+
+	board := g.othelloGame.Board
+	for row := 0; row < board.Size; row++ {
+		for col := 0; col < board.Size; col++ {
+			cell := image.Rect(rect.Min.X+col*miniCell, rect.Min.Y+row*miniCell, rect.Min.X+(col+1)*miniCell, rect.Min.Y+(row+1)*miniCell)
+			drawOutline(screen, cell, 1, GridColor)
+			if row == g.lastMoveY && col == g.lastMoveX {
+				drawRect(screen, cell.Inset(1), HighlightColor)
+			}
+			var clr color.Color
+			switch board.GetPiece(row, col) {
+			case model.Black:
+				clr = BlackPieceColor
+			case model.White:
+				clr = WhitePieceColor
+			default:
+				continue
+			}
+			drawCircle(screen, cell.Min.X+miniCell/2, cell.Min.Y+miniCell/2, miniCell/2-2, clr)
+		}
+	}
+	drawCenteredText(screen, "Live game", g.resources.GetSmallFont(), image.Rect(rect.Min.X, rect.Max.Y+3, rect.Max.X, rect.Max.Y+20), TextColor)
+}
+
+// reviewStatus describes a finished game loaded for replay
+func (g *Game) reviewStatus() string {
+	black, white := g.othelloGame.GetScore()
+	return fmt.Sprintf("Final position, %d-%d - %s steps back through the game, %s replays it",
+		black, white, g.keys.keyNames(config.ActionHistoryBack), g.keys.keyNames(config.ActionPlayback))
+}
+
+// browseStatus describes the browsed position for the status bar
+func (g *Game) browseStatus() string {
+	return fmt.Sprintf("Viewing move %d of %d - %s / %s to step, click the board to return to the game",
+		g.browse.ply, len(g.othelloGame.History),
+		g.keys.keyNames(config.ActionHistoryBack), g.keys.keyNames(config.ActionHistoryForward))
+}
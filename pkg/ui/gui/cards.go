@@ -0,0 +1,154 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Player card layout
+const (
+	cardTop    = 8
+	cardHeight = 62
+	cardGap    = 10
+	pulsePace  = 2.5 // Pulses per second of the side to move's highlight
+)
+
+// cardRects returns the Black and White player cards above the board
+func (l layout) cardRects() (black, white image.Rectangle) {
+	w := (l.board.Dx() - cardGap) / 2
+	black = image.Rect(l.board.Min.X, cardTop, l.board.Min.X+w, cardTop+cardHeight)
+	white = image.Rect(l.board.Max.X-w, cardTop, l.board.Max.X, cardTop+cardHeight)
+	return black, white
+}
+
+// resetClocks starts both players' clocks from zero, without a time control
+func (g *Game) resetClocks() {
+	g.clocks = map[model.Piece]time.Duration{}
+	g.clockTick = time.Now()
+	g.clockBase, g.clockIncrement = 0, 0
+}
+
+// maxClockTick caps the time charged for one tick; a longer gap means the
+// game loop was stopped, as mobile apps are in the background
+const maxClockTick = time.Second
+
+// tickClock charges the time since the last tick to the side to move while
+// running, which loses on time if it has a time control and no time left;
+// paused time (menus, help, dialogs) is not charged
+func (g *Game) tickClock(running bool) {
+	now := time.Now()
+	if running && g.othelloGame != nil && !g.othelloGame.GameOver && g.clocks != nil {
+		g.clocks[g.othelloGame.Board.CurrentPlayer] += min(now.Sub(g.clockTick), maxClockTick)
+		g.flagFall()
+	}
+	g.clockTick = now
+}
+
+// playerName returns the name shown on a player's card
+func (g *Game) playerName(piece model.Piece) string {
+	if name := g.teamName(piece); name != "" {
+		return name
+	}
+	switch {
+	case g.gameMode == ModeCorrespondence:
+		if game := g.corr.game(g.corrID); game != nil {
+			if piece == model.Black {
+				return game.Black
+			}
+			return game.White
+		}
+	case g.gameMode == ModeHumanVsHuman:
+		if piece == model.Black {
+			return "Player 1"
+		}
+		return "Player 2"
+	case g.aiPlayer != nil && g.aiPlayer.Piece == piece:
+		return g.computerName()
+	}
+	if g.options.PlayerName != "" {
+		return g.options.PlayerName
+	}
+	return "You"
+}
+
+// clockText returns a player's clock: the time they have used, the time
+// they have left under a time control, or in correspondence games the time
+// left for the side to move
+func (g *Game) clockText(piece model.Piece) string {
+	if g.gameMode == ModeCorrespondence {
+		game := g.corr.game(g.corrID)
+		if game == nil || game.ToMove() != piece {
+			return ""
+		}
+		return formatTimeLeft(game.TimeLeft(time.Now()))
+	}
+	d := g.clocks[piece].Round(time.Second)
+	if g.clockBase > 0 {
+		d = max(g.timeLeft(piece), 0).Round(time.Second)
+	}
+	return fmt.Sprintf("%d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// drawPlayerCards shows both players with their discs and clocks, the side
+// to move highlighted with a pulsing border
+func (g *Game) drawPlayerCards(screen *ebiten.Image) {
+	black, white := g.layout.cardRects()
+	blackCount, whiteCount := g.othelloGame.GetScore()
+	g.drawPlayerCard(screen, black, model.Black, blackCount)
+	g.drawPlayerCard(screen, white, model.White, whiteCount)
+}
+
+// drawPlayerCard draws one player's card
+func (g *Game) drawPlayerCard(screen *ebiten.Image, rect image.Rectangle, piece model.Piece, discs int) {
+	game := g.othelloGame
+	drawRect(screen, rect, PanelBackColor)
+
+	switch {
+	case game.GameOver && game.Winner == piece:
+		drawOutline(screen, rect, 3, HighlightColor)
+	case !game.GameOver && game.Board.CurrentPlayer == piece:
+		// Fade the highlight in and out so the turn catches the eye
+		phase := float64(time.Now().UnixMilli()) / 1000 * pulsePace * 2 * math.Pi
+		alpha := 0.55 + 0.45*math.Sin(phase)
+		hl := HighlightColor
+		pulse := color.RGBA{hl.R, hl.G, hl.B, uint8(255 * alpha)}
+		drawOutline(screen, rect, 3, pulse)
+	default:
+		drawOutline(screen, rect, 1, PanelBorderColor)
+	}
+
+	// Disc in the player's color, or the profile's avatar with a small one
+	radius := rect.Dy()/2 - 12
+	cx, cy := rect.Min.X+12+radius, rect.Min.Y+rect.Dy()/2
+	if g.avatar != nil && g.profileSide(piece) {
+		g.drawAvatar(screen, g.avatar, g.profileName(), image.Rect(cx-radius, cy-radius, cx+radius, cy+radius))
+		g.resources.drawPiece(screen, piece, cx+radius-radius/3, cy+radius-radius/3, radius/3)
+	} else {
+		g.resources.drawPiece(screen, piece, cx, cy, radius)
+	}
+
+	x := rect.Min.X + 2*radius + 24
+	clockRect := image.Rect(rect.Max.X-70, rect.Min.Y, rect.Max.X-8, rect.Max.Y)
+	name := truncateText(g.resources.GetNormalFont(), g.playerName(piece), clockRect.Min.X-x-4)
+	text.Draw(screen, name, g.resources.GetNormalFont(), x, rect.Min.Y+24, TextColor)
+
+	status := fmt.Sprintf("%s - %d discs", model.GetPieceName(piece), discs)
+	switch {
+	case game.GameOver && game.Winner == piece:
+		status += " - winner"
+	case !game.GameOver && game.Board.CurrentPlayer == piece:
+		status += " - to move"
+	}
+	text.Draw(screen, status, g.resources.GetSmallFont(), x, rect.Min.Y+46, TextColor)
+
+	drawCenteredText(screen, g.clockText(piece), g.resources.GetNormalFont(), clockRect, TextColor)
+}
@@ -0,0 +1,66 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// errNoClipboard is returned when no clipboard tool is available
+var errNoClipboard = errors.New("no clipboard tool found")
+
+// Ebiten has no clipboard API, so the system's clipboard tools are used
+
+// clipboardCommands returns the commands reading and writing the clipboard,
+// in order of preference
+func clipboardCommands() (read, write [][]string) {
+	switch runtime.GOOS {
+	case "windows":
+		return [][]string{{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}},
+			[][]string{{"clip"}}
+	case "darwin":
+		return [][]string{{"pbpaste"}}, [][]string{{"pbcopy"}}
+	default:
+		read = [][]string{{"xclip", "-o", "-selection", "clipboard"}, {"xsel", "--clipboard", "--output"}}
+		write = [][]string{{"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			read = append([][]string{{"wl-paste", "--no-newline"}}, read...)
+			write = append([][]string{{"wl-copy"}}, write...)
+		}
+		return read, write
+	}
+}
+
+// readClipboard returns the clipboard's text
+func readClipboard() (string, error) {
+	commands, _ := clipboardCommands()
+	for _, args := range commands {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		out, err := exec.Command(args[0], args[1:]...).Output()
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	}
+	return "", errNoClipboard
+}
+
+// writeClipboard replaces the clipboard's text
+func writeClipboard(s string) error {
+	_, commands := clipboardCommands()
+	for _, args := range commands {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			continue
+		}
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin = strings.NewReader(s)
+		return cmd.Run()
+	}
+	return errNoClipboard
+}
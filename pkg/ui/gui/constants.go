@@ -1,3 +1,5 @@
+//go:build !nogui
+
 package gui
 
 import "image/color"
@@ -11,12 +13,12 @@ const (
 	BoardMarginX  = 60                            // حاشیه سمت چپ تخته بازی
 	BoardMarginY  = 80                            // حاشیه بالای تخته بازی
 	GridLineWidth = 2                             // Increased line width for better visibility
-	HistoryPanelX = BoardMarginX + BoardSize + 30 // مکان پنل تاریخچه
-	HistoryPanelY = BoardMarginY
-	HistoryPanelW = 320       // عرض پنل تاریخچه
-	HistoryPanelH = BoardSize // ارتفاع پنل تاریخچه
-	HistoryTitleH = 30        // ارتفاع عنوان پنل تاریخچه
-	HistoryItemH  = 25        // ارتفاع هر آیتم در تاریخچه
+	SidePanelX    = BoardMarginX + BoardSize + 30 // Left edge of the panel column
+	SidePanelW    = 320                           // Width of the panel column
+	SidePanelGap  = 10                            // Space between stacked panels
+	PanelTitleH   = 30                            // ارتفاع عنوان پنل
+	HistoryItemH  = 25                            // ارتفاع هر آیتم در تاریخچه
+	EvalBarW      = 18                            // Width of the evaluation bar
 )
 
 // Animation constants
@@ -42,4 +44,5 @@ var (
 	BlackMoveColor   = color.RGBA{180, 180, 180, 255} // تاریخچه حرکت های سیاه
 	WhiteMoveColor   = color.RGBA{255, 255, 255, 255} // تاریخچه حرکت های سفید
 	MarkerDotColor   = color.RGBA{20, 20, 20, 200}    // رنگ نقاط راهنما روی تخته
+	HintColor        = color.RGBA{80, 160, 255, 220}  // Suggested move
 )
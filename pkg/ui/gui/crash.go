@@ -0,0 +1,97 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// crashInfo captures a panic raised inside the game loop
+type crashInfo struct {
+	reason interface{}
+	stack  []byte
+}
+
+// crashError is returned from Update to stop the game loop after a panic
+type crashError struct {
+	info crashInfo
+}
+
+// Error implements the error interface
+func (e *crashError) Error() string {
+	return fmt.Sprintf("panic in game loop: %v", e.info.reason)
+}
+
+// recoverPanic turns a panic in Update or Draw into a stored crash so the
+// loop can shut down cleanly and a report can be written
+func (g *Game) recoverPanic() {
+	if r := recover(); r != nil {
+		g.crash = &crashInfo{reason: r, stack: debug.Stack()}
+	}
+}
+
+// crashDir returns the directory crash reports are written to
+func crashDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "othello", "crashes")
+	}
+	return filepath.Join(os.TempDir(), "othello-crashes")
+}
+
+// writeCrashReport saves the game state and stack trace to a new file and
+// returns its path
+func (g *Game) writeCrashReport(reason interface{}, stack []byte) (string, error) {
+	dir := crashDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Othello crash report\n")
+	fmt.Fprintf(&buf, "Time: %s\n", now.Format(time.RFC3339))
+	fmt.Fprintf(&buf, "Reason: %v\n", reason)
+	fmt.Fprintf(&buf, "Screen: %d  Mode: %d\n\n", g.gameState, g.gameMode)
+
+	if g.othelloGame != nil {
+		fmt.Fprintf(&buf, "Position: %s\n", g.othelloGame.Board.PositionString())
+		fmt.Fprintf(&buf, "Transcript: %s\n\n", g.othelloGame.Transcript())
+		fmt.Fprintf(&buf, "Game record:\n")
+		if err := model.WriteGameRecord(&buf, model.NewGameRecord(g.othelloGame)); err != nil {
+			fmt.Fprintf(&buf, "(could not serialize game: %v)\n", err)
+		}
+		buf.WriteString("\n")
+	} else {
+		buf.WriteString("No game in progress\n\n")
+	}
+
+	fmt.Fprintf(&buf, "Stack trace:\n%s", stack)
+
+	path := filepath.Join(dir, fmt.Sprintf("crash-%s.txt", now.Format("20060102-150405")))
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// reportCrash writes a crash report and tells the user where to find it
+func (g *Game) reportCrash(reason interface{}, stack []byte) {
+	fmt.Fprintln(os.Stderr, "\nSorry, Othello ran into a problem and had to close.")
+
+	path, err := g.writeCrashReport(reason, stack)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "The crash report could not be saved (%v).\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n%s", reason, stack)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "A crash report with the current position was saved to:\n  %s\n", path)
+	fmt.Fprintln(os.Stderr, "Please attach it when reporting the problem.")
+}
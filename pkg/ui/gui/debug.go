@@ -0,0 +1,83 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"runtime"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Debug overlay tuning
+const (
+	debugTelemetryLimit = 200         // Searches kept when no telemetry log is configured
+	memSampleInterval   = time.Second // Reading memory statistics stops the world
+	debugLineH          = 18
+)
+
+// debugOverlay shows engine and runtime statistics over the screen
+type debugOverlay struct {
+	shown bool
+
+	memSampled time.Time
+	mem        runtime.MemStats
+}
+
+// memStats returns the memory statistics, sampled at most once per interval
+func (d *debugOverlay) memStats() *runtime.MemStats {
+	if time.Since(d.memSampled) >= memSampleInterval {
+		runtime.ReadMemStats(&d.mem)
+		d.memSampled = time.Now()
+	}
+	return &d.mem
+}
+
+// searchStats describes a search's depth, speed and transposition table use
+func searchStats(info ai.Info) string {
+	return fmt.Sprintf("depth %d, %.0fk nodes/s, TT hits %.0f%%",
+		info.Depth, info.NodesPerSecond()/1000, info.TTHitRate()*100)
+}
+
+// debugLines collects the overlay's statistics
+func (g *Game) debugLines() []string {
+	lines := []string{fmt.Sprintf("FPS %.0f, TPS %.0f", ebiten.ActualFPS(), ebiten.ActualTPS())}
+
+	if info, ok := g.analysis.latest(); ok {
+		lines = append(lines, "Analysis: "+searchStats(info))
+	} else {
+		lines = append(lines, "Analysis: idle")
+	}
+
+	if records := g.telemetry.Records(ai.Query{Source: ai.SourceSearch, Limit: 1}); len(records) > 0 {
+		r := records[0]
+		lines = append(lines, fmt.Sprintf("Last AI search (%s): %s", r.Move, searchStats(r.Info())))
+	} else {
+		lines = append(lines, "Last AI search: none")
+	}
+
+	mem := g.debug.memStats()
+	lines = append(lines,
+		fmt.Sprintf("Heap %.1f MB, system %.1f MB", float64(mem.HeapAlloc)/(1<<20), float64(mem.Sys)/(1<<20)),
+		fmt.Sprintf("GC cycles %d, goroutines %d", mem.NumGC, runtime.NumGoroutine()))
+	return lines
+}
+
+// drawDebugOverlay draws the statistics in the top left corner when shown
+func (g *Game) drawDebugOverlay(screen *ebiten.Image) {
+	if !g.debug.shown {
+		return
+	}
+
+	lines := g.debugLines()
+	rect := image.Rect(4, 4, 380, 4+debugLineH*len(lines)+10)
+	drawRect(screen, rect, PanelBackColor)
+	drawOutline(screen, rect, 1, PanelBorderColor)
+	for i, line := range lines {
+		text.Draw(screen, line, g.resources.GetSmallFont(), rect.Min.X+8, rect.Min.Y+debugLineH*(i+1), TextColor)
+	}
+}
@@ -0,0 +1,125 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/widget"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	demoMoveDelay = 600 * time.Millisecond // Least pause between the demo's moves
+	demoRestart   = 5 * time.Second        // A finished demo game stays this long
+)
+
+// demoGame is a game between two computer players shown behind the main
+// menu once it has sat idle, as in an arcade's attract mode
+type demoGame struct {
+	driver   *widget.Driver
+	playback playback
+	lastMove time.Time
+
+	image *ebiten.Image // The board as last drawn
+	drawn string        // What the image shows
+}
+
+// newDemoGame starts a demo game
+func newDemoGame() *demoGame {
+	d := &demoGame{driver: widget.NewDriver(nil), lastMove: time.Now()}
+	d.driver.SetPlayer(engine.Black, engine.NewAI(engine.Medium, engine.Black))
+	d.driver.SetPlayer(engine.White, engine.NewAI(engine.Medium, engine.White))
+	d.driver.Observe(func(widget.Event) { d.lastMove = time.Now() })
+	return d
+}
+
+// update plays the next move when the playback says, delay apart at 1x,
+// and starts over a while after the game ends
+func (d *demoGame) update(delay time.Duration) {
+	switch {
+	case d.driver.Game().GameOver:
+		if time.Since(d.lastMove) >= demoRestart {
+			d.driver.Reset(nil)
+			d.lastMove = time.Now()
+		}
+	case d.driver.Thinking() || d.playback.due(max(delay, demoMoveDelay)):
+		d.driver.Update()
+	}
+}
+
+// draw draws the demo's board in the middle of the screen, dimmed so the
+// menu stays readable
+func (d *demoGame) draw(screen *ebiten.Image) {
+	game := d.driver.Game()
+	drawn := game.Board.PositionString()
+	if d.image == nil || d.drawn != drawn {
+		if d.image != nil {
+			d.image.Dispose()
+		}
+		d.image = ebiten.NewImageFromImage(d.driver.Image(BoardSize))
+		d.drawn = drawn
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	bounds := d.image.Bounds()
+	op.GeoM.Translate(float64(ScreenWidth-bounds.Dx())/2, float64(ScreenHeight-bounds.Dy())/2)
+	op.ColorScale.ScaleAlpha(0.35)
+	screen.DrawImage(d.image, op)
+}
+
+// anyInput reports whether the player did anything this frame
+func anyInput() bool {
+	return pointerJustPressed() || pointerMoved() || len(inpututil.AppendJustPressedKeys(nil)) > 0
+}
+
+// updateIdle restarts the idle time on any input, and while the main menu
+// is not on screen on its own. Input other than the playback keys stops the
+// demo; either way it reports whether the demo took the input, so it is not
+// passed on.
+func (g *Game) updateIdle() bool {
+	if g.demo != nil && g.updatePlayback(&g.demo.playback) {
+		g.idleSince = time.Now()
+		return true
+	}
+	if !anyInput() && g.gameState == StateMainMenu && g.dialog == nil {
+		return false
+	}
+	g.idleSince = time.Now()
+	if g.demo == nil {
+		return false
+	}
+	g.demo = nil
+	logging.For("gui").Debug("demo stopped")
+	return true
+}
+
+// updateDemo starts the demo once the main menu has sat idle for the time
+// in the settings, and plays it on
+func (g *Game) updateDemo() {
+	if g.demo != nil {
+		g.demo.update(g.moveDelay)
+		return
+	}
+	after := time.Duration(g.applied.Menu.DemoAfterSeconds) * time.Second
+	if after > 0 && time.Since(g.idleSince) >= after {
+		g.demo = newDemoGame()
+		logging.For("gui").Debug("demo started")
+	}
+}
+
+// drawDemo draws the demo behind the main menu
+func (g *Game) drawDemo(screen *ebiten.Image) {
+	if g.demo != nil {
+		g.demo.draw(screen)
+		status := fmt.Sprintf("Demo %s - %s / %s change the speed, any other key stops it",
+			g.demo.playback.label(), g.keys.keyNames(config.ActionPlaybackSlower), g.keys.keyNames(config.ActionPlaybackFaster))
+		drawCenteredText(screen, status, g.resources.GetSmallFont(), image.Rect(0, ScreenHeight-35, ScreenWidth, ScreenHeight-5), TextColor)
+	}
+}
@@ -0,0 +1,180 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/font"
+)
+
+// Modal is a box drawn over the screen that takes all input until it closes
+type Modal interface {
+	Update()
+	Draw(screen *ebiten.Image, res *Resources)
+	Closed() bool
+}
+
+// Dialog is a titled box with a message and a row of buttons, drawn over a
+// dimmed screen
+// Choosing a button or pressing Escape closes it; Escape runs OnCancel.
+type Dialog struct {
+	Title    string
+	Message  string
+	Rect     image.Rectangle
+	OnCancel func()
+
+	form   *Form
+	closed bool
+}
+
+// DialogButton is a choice offered by a dialog
+type DialogButton struct {
+	Text    string
+	OnClick func()
+}
+
+// NewDialog creates a dialog centered on the screen; the first button gets
+// the focus so Enter picks it
+func NewDialog(title, message string, buttons ...DialogButton) *Dialog {
+	const w, h, buttonW, buttonH, gap = 460, 200, 140, 40, 20
+	rect := image.Rect((ScreenWidth-w)/2, (ScreenHeight-h)/2, (ScreenWidth+w)/2, (ScreenHeight+h)/2)
+	d := &Dialog{Title: title, Message: message, Rect: rect, form: NewForm()}
+
+	rowW := len(buttons)*buttonW + (len(buttons)-1)*gap
+	x := rect.Min.X + (w-rowW)/2
+	y := rect.Max.Y - buttonH - 20
+	for _, b := range buttons {
+		onClick := b.OnClick
+		d.form.Add(NewButton(image.Rect(x, y, x+buttonW, y+buttonH), b.Text, func() {
+			d.closed = true
+			if onClick != nil {
+				onClick()
+			}
+		}))
+		x += buttonW + gap
+	}
+	if len(buttons) > 0 {
+		d.form.Focus(d.form.Widgets[0])
+	}
+	return d
+}
+
+// Closed reports whether a choice was made
+func (d *Dialog) Closed() bool {
+	return d.closed
+}
+
+// Update lets the dialog's buttons handle input
+func (d *Dialog) Update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		d.closed = true
+		if d.OnCancel != nil {
+			d.OnCancel()
+		}
+		return
+	}
+	d.form.Update()
+}
+
+// Draw renders the dialog over the screen
+func (d *Dialog) Draw(screen *ebiten.Image, res *Resources) {
+	drawRect(screen, image.Rect(0, 0, ScreenWidth, ScreenHeight), color.RGBA{0, 0, 0, 160})
+	drawRect(screen, d.Rect, PanelBackColor)
+	drawOutline(screen, d.Rect, 2, PanelBorderColor)
+
+	titleRect := image.Rect(d.Rect.Min.X, d.Rect.Min.Y+10, d.Rect.Max.X, d.Rect.Min.Y+50)
+	drawCenteredText(screen, d.Title, res.GetLargeFont(), titleRect, TextColor)
+
+	lineRect := image.Rect(d.Rect.Min.X, titleRect.Max.Y, d.Rect.Max.X, titleRect.Max.Y+22)
+	for _, line := range wrapText(res.GetNormalFont(), d.Message, d.Rect.Dx()-40) {
+		drawCenteredText(screen, line, res.GetNormalFont(), lineRect, TextColor)
+		lineRect = lineRect.Add(image.Pt(0, 22))
+	}
+
+	d.form.Draw(screen, res)
+}
+
+// wrapText breaks s into lines no wider than width
+func wrapText(face font.Face, s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && textWidth(face, candidate) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// showDialog opens a modal dialog; input reaches nothing else until it
+// closes
+func (g *Game) showDialog(d Modal) {
+	g.dialog = d
+}
+
+// updateDialog gives the open dialog all input, reporting whether one was
+// open
+func (g *Game) updateDialog() bool {
+	d := g.dialog
+	if d == nil {
+		return false
+	}
+	d.Update()
+	// The choice may have opened another dialog
+	if d.Closed() && g.dialog == d {
+		g.dialog = nil
+	}
+	return true
+}
+
+// showNotice opens a dialog with a message and an OK button
+func (g *Game) showNotice(title, message string) {
+	g.showDialog(NewDialog(title, message, DialogButton{Text: "OK"}))
+}
+
+// gameInProgress reports whether leaving the board would throw away a local
+// game; correspondence games are kept on the server
+func (g *Game) gameInProgress() bool {
+	return g.gameState == StateInGame && g.session.inProgress()
+}
+
+// leaveToMainMenu returns to the main menu, first asking before abandoning
+// a game in progress. With several games open it closes the tab instead, and
+// while a game for a new tab is chosen it goes back to the open game.
+func (g *Game) leaveToMainMenu() {
+	if g.newTabPending && g.gameState != StateInGame && g.gameState != StateGameOver {
+		g.newTabPending = false
+		g.gameState = g.session.screen
+		return
+	}
+	if (g.gameState == StateInGame || g.gameState == StateGameOver) && len(g.sessions) > 1 {
+		g.closeTab(g.activeTab())
+		return
+	}
+	leave := func() {
+		g.analysis.stop()
+		g.gameState = StateMainMenu
+	}
+	if !g.gameInProgress() {
+		leave()
+		return
+	}
+	g.showDialog(NewDialog("Abandon game?", "The current game will be lost.",
+		DialogButton{Text: "Keep playing"},
+		DialogButton{Text: "Abandon", OnClick: leave},
+	))
+}
@@ -0,0 +1,58 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"io/fs"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// openDroppedFiles opens a game file dropped on the window: on the Library
+// screen its games are added to the library, elsewhere its game is put on
+// the board. Of several files dropped at once only the first game file is
+// used.
+func (g *Game) openDroppedFiles() {
+	dropped := ebiten.DroppedFiles()
+	if dropped == nil {
+		return
+	}
+	entries, err := fs.ReadDir(dropped, ".")
+	if err != nil {
+		logging.For("gui").Warn("failed to read dropped files", "err", err)
+		return
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !matchesExt(name, gameFileExts) {
+			continue
+		}
+		games, err := readDroppedFile(dropped, name)
+		if err == nil && g.gameState == StateLibrary && g.library != nil {
+			err = g.addToLibrary(name, games)
+		} else if err == nil && g.canOpenGame() {
+			g.openGames(name, games)
+		}
+		if err != nil {
+			logging.For("gui").Error("failed to open dropped file", "file", name, "err", err)
+			g.showNotice("Could not open "+name, err.Error())
+		}
+		return
+	}
+	if len(entries) > 0 {
+		g.flash("Drop a .json, .ggf, .wtb or .txt game file to open it")
+	}
+}
+
+// readDroppedFile reads the games in a dropped file
+func readDroppedFile(dropped fs.FS, name string) ([]*model.Game, error) {
+	f, err := dropped.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readGames(name, f)
+}
@@ -0,0 +1,187 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Effect tuning
+const (
+	bigFlip        = 8   // Discs a move must flip to be celebrated
+	particleLife   = 0.9 // Seconds a particle lives
+	particleSpeed  = 180 // Initial speed in pixels per second
+	particleGrav   = 260 // Downward pull in pixels per second squared
+	shakeDuration  = 0.35
+	maxParticles   = 400
+	cornerParts    = 24
+	bigFlipParts   = 16
+	finishingParts = 90
+)
+
+// particle is a fading dot thrown out by a celebrated move
+type particle struct {
+	x, y, vx, vy float64
+	age          float64 // Seconds since it was spawned
+	radius       int
+	clr          color.RGBA
+}
+
+// effects celebrates notable moves with bursts of particles and an
+// optional screen shake
+type effects struct {
+	particles []particle
+	shake     float64       // Seconds of shaking left
+	strength  float64       // Largest offset of the shake in pixels
+	canvas    *ebiten.Image // The frame is drawn here while the screen shakes
+	rng       *rand.Rand
+}
+
+// newEffects creates an effects system with nothing running
+func newEffects() *effects {
+	return &effects{rng: rand.New(rand.NewSource(rand.Int63()))}
+}
+
+// burst throws n particles out from x, y
+func (e *effects) burst(x, y float64, n int, clr color.RGBA) {
+	for i := 0; i < n && len(e.particles) < maxParticles; i++ {
+		angle := e.rng.Float64() * 2 * math.Pi
+		speed := particleSpeed * (0.4 + 0.6*e.rng.Float64())
+		e.particles = append(e.particles, particle{
+			x: x, y: y,
+			vx:     math.Cos(angle) * speed,
+			vy:     math.Sin(angle)*speed - particleSpeed/2,
+			radius: 2 + e.rng.Intn(3),
+			clr:    clr,
+		})
+	}
+}
+
+// startShake shakes the screen by up to strength pixels
+func (e *effects) startShake(strength float64) {
+	e.shake = shakeDuration
+	e.strength = math.Max(e.strength, strength)
+}
+
+// update moves the particles on by one tick
+func (e *effects) update() {
+	dt := 1 / float64(ebiten.TPS())
+	alive := e.particles[:0]
+	for _, p := range e.particles {
+		p.age += dt
+		if p.age >= particleLife {
+			continue
+		}
+		p.vy += particleGrav * dt
+		p.x += p.vx * dt
+		p.y += p.vy * dt
+		alive = append(alive, p)
+	}
+	e.particles = alive
+
+	if e.shake > 0 {
+		e.shake -= dt
+		if e.shake <= 0 {
+			e.shake, e.strength = 0, 0
+		}
+	}
+}
+
+// draw renders the live particles, fading them out with age
+func (e *effects) draw(screen *ebiten.Image) {
+	for _, p := range e.particles {
+		fade := 1 - p.age/particleLife
+		c := color.RGBA{
+			uint8(float64(p.clr.R) * fade), uint8(float64(p.clr.G) * fade),
+			uint8(float64(p.clr.B) * fade), uint8(float64(p.clr.A) * fade),
+		}
+		drawCircle(screen, int(p.x), int(p.y), p.radius, c)
+	}
+}
+
+// shakeOffset returns how far the frame is moved this tick, false when the
+// screen is still
+func (e *effects) shakeOffset() (float64, float64, bool) {
+	if e.shake <= 0 {
+		return 0, 0, false
+	}
+	s := e.strength * e.shake / shakeDuration
+	return (e.rng.Float64()*2 - 1) * s, (e.rng.Float64()*2 - 1) * s, true
+}
+
+// drawShaken draws a frame with draw, moved by the screen shake if one runs
+func (e *effects) drawShaken(screen *ebiten.Image, draw func(*ebiten.Image)) {
+	dx, dy, ok := e.shakeOffset()
+	if !ok {
+		draw(screen)
+		return
+	}
+	if e.canvas == nil {
+		e.canvas = ebiten.NewImage(ScreenWidth, ScreenHeight)
+	}
+	e.canvas.Fill(BackgroundColor)
+	draw(e.canvas)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(dx, dy)
+	screen.DrawImage(e.canvas, op)
+}
+
+// celebrateMove starts the effects a move deserves: corners, moves flipping
+// many discs and moves winning the game or wiping out the opponent. black
+// and white are the disc counts before the move.
+func (g *Game) celebrateMove(row, col, black, white int) {
+	cfg := g.applied.Effects
+	if !cfg.Particles && !cfg.ScreenShake {
+		return
+	}
+
+	board := g.othelloGame.Board
+	mover := board.GetPiece(row, col)
+	before, after, opponent := black, board.BlackCnt, board.WhiteCnt
+	if mover == model.White {
+		before, after, opponent = white, board.WhiteCnt, board.BlackCnt
+	}
+	flipped := after - before - 1
+
+	cell := g.layout.cellRect(row, col)
+	x, y := float64(cell.Min.X+CellSize/2), float64(cell.Min.Y+CellSize/2)
+	discColor := BlackPieceColor
+	if mover == model.White {
+		discColor = WhitePieceColor
+	}
+	gold := color.RGBA{255, 200, 40, 255}
+
+	strength := 0.0
+	switch {
+	case opponent == 0 || (g.othelloGame.GameOver && g.othelloGame.Winner == mover):
+		if cfg.Particles {
+			// Confetti over the whole board
+			b := g.layout.board
+			for i := 0; i < 6; i++ {
+				px := float64(b.Min.X) + g.effects.rng.Float64()*float64(b.Dx())
+				py := float64(b.Min.Y) + g.effects.rng.Float64()*float64(b.Dy())
+				g.effects.burst(px, py, finishingParts/6, []color.RGBA{gold, discColor, HintColor}[i%3])
+			}
+		}
+		strength = 8
+	case flipped >= bigFlip:
+		if cfg.Particles {
+			g.effects.burst(x, y, bigFlipParts+flipped, discColor)
+		}
+		strength = 3 + float64(flipped-bigFlip)/2
+	}
+	if (row == 0 || row == board.Size-1) && (col == 0 || col == board.Size-1) {
+		if cfg.Particles {
+			g.effects.burst(x, y, cornerParts, gold)
+		}
+		strength = math.Max(strength, 4)
+	}
+	if cfg.ScreenShake && strength > 0 {
+		g.effects.startShake(strength)
+	}
+}
@@ -0,0 +1,286 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/font"
+)
+
+// File dialog layout
+const (
+	fileDialogW = 620
+	fileDialogH = 500
+	fileRowH    = 26
+)
+
+// fileEntry is a folder or file listed by a file dialog
+type fileEntry struct {
+	name string
+	dir  bool
+}
+
+// FileDialog picks a file to open, or a name to save to, by browsing folders
+// instead of typing paths
+// Clicking a folder enters it and Backspace goes up; clicking a file puts its
+// name in the name field, and clicking it again or pressing Enter picks it.
+// A whole path can also be typed or pasted into the name field. Only folders
+// and files with one of the dialog's extensions are listed.
+type FileDialog struct {
+	Title    string
+	Rect     image.Rectangle
+	OnChoose func(path string)
+
+	save    bool
+	exts    []string
+	dir     string
+	entries []fileEntry
+	replace string // Existing file the user was warned about replacing
+	status  string // Problem shown under the name field
+
+	form   *Form
+	list   *List
+	name   *TextInput
+	closed bool
+}
+
+// NewOpenDialog creates a dialog choosing an existing file, starting in dir
+func NewOpenDialog(title, dir string, exts []string, onChoose func(path string)) *FileDialog {
+	return newFileDialog(title, dir, "", exts, false, onChoose)
+}
+
+// NewSaveDialog creates a dialog choosing where to save, suggesting name in
+// dir; the first extension is added to names typed without one
+func NewSaveDialog(title, dir, name string, exts []string, onChoose func(path string)) *FileDialog {
+	return newFileDialog(title, dir, name, exts, true, onChoose)
+}
+
+// newFileDialog lays out an open or save dialog centered on the screen
+func newFileDialog(title, dir, name string, exts []string, save bool, onChoose func(path string)) *FileDialog {
+	const buttonW, buttonH, gap = 120, 40, 16
+	rect := image.Rect((ScreenWidth-fileDialogW)/2, (ScreenHeight-fileDialogH)/2, (ScreenWidth+fileDialogW)/2, (ScreenHeight+fileDialogH)/2)
+	d := &FileDialog{Title: title, Rect: rect, OnChoose: onChoose, save: save, exts: exts}
+
+	left, right := rect.Min.X+20, rect.Max.X-20
+	d.list = NewList(image.Rect(left, rect.Min.Y+80, right, rect.Max.Y-130), nil, d.pick)
+	d.list.RowH = fileRowH
+	d.name = NewTextInput(image.Rect(left, rect.Max.Y-120, right, rect.Max.Y-90), "File name or path")
+	d.name.SetText(name)
+	d.name.OnSubmit = func(string) { d.confirm() }
+
+	action := "Open"
+	if save {
+		action = "Save"
+	}
+	y := rect.Max.Y - buttonH - 20
+	x := right - 2*buttonW - gap
+	d.form = NewForm(d.list, d.name,
+		NewButton(image.Rect(left, y, left+buttonW, y+buttonH), "Up", d.up),
+		NewButton(image.Rect(x, y, x+buttonW, y+buttonH), "Cancel", d.cancel),
+		NewButton(image.Rect(x+buttonW+gap, y, right, y+buttonH), action, d.confirm),
+	)
+	if save {
+		d.form.Focus(d.name)
+	} else {
+		d.form.Focus(d.list)
+	}
+	d.chdir(dir)
+	return d
+}
+
+// Closed reports whether a file was chosen or the dialog was cancelled
+func (d *FileDialog) Closed() bool {
+	return d.closed
+}
+
+// chdir lists dir, staying in the current folder if it cannot be read
+func (d *FileDialog) chdir(dir string) {
+	dir = filepath.Clean(dir)
+	all, err := os.ReadDir(dir)
+	if err != nil {
+		d.status = err.Error()
+		return
+	}
+
+	var folders, files []fileEntry
+	for _, e := range all {
+		name := e.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		// Follow links to folders
+		folder := e.IsDir()
+		if e.Type()&fs.ModeSymlink != 0 {
+			folder = isDir(filepath.Join(dir, name))
+		}
+		switch {
+		case folder:
+			folders = append(folders, fileEntry{name: name, dir: true})
+		case len(d.exts) == 0 || matchesExt(name, d.exts):
+			files = append(files, fileEntry{name: name})
+		}
+	}
+	byName := func(list []fileEntry) {
+		sort.Slice(list, func(i, j int) bool { return strings.ToLower(list[i].name) < strings.ToLower(list[j].name) })
+	}
+	byName(folders)
+	byName(files)
+
+	d.dir = dir
+	d.entries = append(folders, files...)
+	d.status = ""
+	d.replace = ""
+	items := make([]string, len(d.entries))
+	for i, e := range d.entries {
+		items[i] = e.name
+		if e.dir {
+			items[i] += string(filepath.Separator)
+		}
+	}
+	d.list.Items = items
+	d.list.Selected = -1
+	d.list.scroll.offset = 0
+}
+
+// matchesExt reports whether a file name has one of the extensions
+func matchesExt(name string, exts []string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// pick handles a click or Enter on a listed entry
+func (d *FileDialog) pick(i int) {
+	e := d.entries[i]
+	switch {
+	case e.dir:
+		d.chdir(filepath.Join(d.dir, e.name))
+	case d.name.Text == e.name:
+		d.confirm()
+	default:
+		d.name.SetText(e.name)
+		d.status = ""
+	}
+}
+
+// up shows the folder containing the current one
+func (d *FileDialog) up() {
+	d.chdir(filepath.Dir(d.dir))
+}
+
+// cancel closes the dialog without a choice
+func (d *FileDialog) cancel() {
+	d.closed = true
+}
+
+// confirm picks the file named in the name field, entering it instead if it
+// is a folder
+func (d *FileDialog) confirm() {
+	name := strings.TrimSpace(d.name.Text)
+	if name == "" {
+		d.status = "Choose a file first"
+		return
+	}
+	path := expandHome(name)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(d.dir, path)
+	}
+	if d.save && filepath.Ext(path) == "" && len(d.exts) > 0 && !isDir(path) {
+		path += d.exts[0]
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && info.IsDir():
+		d.name.SetText("")
+		d.chdir(path)
+		return
+	case d.save && err == nil && d.replace != path:
+		d.replace = path
+		d.status = filepath.Base(path) + " already exists, save again to replace it"
+		return
+	case !d.save && errors.Is(err, fs.ErrNotExist):
+		d.status = "No such file: " + name
+		return
+	case !d.save && err != nil:
+		d.status = err.Error()
+		return
+	}
+
+	d.closed = true
+	d.OnChoose(path)
+}
+
+// Update gives the list, the name field and the buttons the input
+func (d *FileDialog) Update() {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		d.cancel()
+		return
+	case d.form.Focused() != d.name && inpututil.IsKeyJustPressed(ebiten.KeyBackspace):
+		d.up()
+		return
+	}
+	d.form.Update()
+}
+
+// Draw renders the dialog over the screen
+func (d *FileDialog) Draw(screen *ebiten.Image, res *Resources) {
+	drawRect(screen, image.Rect(0, 0, ScreenWidth, ScreenHeight), color.RGBA{0, 0, 0, 160})
+	drawRect(screen, d.Rect, PanelBackColor)
+	drawOutline(screen, d.Rect, 2, PanelBorderColor)
+
+	titleRect := image.Rect(d.Rect.Min.X, d.Rect.Min.Y+10, d.Rect.Max.X, d.Rect.Min.Y+50)
+	drawCenteredText(screen, d.Title, res.GetLargeFont(), titleRect, TextColor)
+
+	face := res.GetSmallFont()
+	left := d.list.Rect.Min.X
+	drawLabel(screen, truncatePath(face, d.dir, d.list.Rect.Dx()), face, left, d.Rect.Min.Y+68, TextColor)
+
+	d.form.Draw(screen, res)
+	if len(d.entries) == 0 {
+		drawCenteredText(screen, "No folders or matching files here", face, d.list.Rect, TextColor)
+	}
+	if d.status != "" {
+		drawLabel(screen, truncateText(face, d.status, d.list.Rect.Dx()), face, left, d.name.Rect.Max.Y+20, HighlightColor)
+	}
+}
+
+// truncatePath shortens a path from the left so its end, the part that
+// tells folders apart, stays readable
+func truncatePath(face font.Face, path string, width int) string {
+	if textWidth(face, path) <= width {
+		return path
+	}
+	runes := []rune(path)
+	for len(runes) > 0 && textWidth(face, "..."+string(runes)) > width {
+		runes = runes[1:]
+	}
+	return "..." + string(runes)
+}
+
+// expandHome replaces a leading ~ with the home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
@@ -0,0 +1,67 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// resumeCountdown is how long play waits after the window regains focus
+const resumeCountdown = 3 * time.Second
+
+// focusPause holds a game still while the window is in the background
+type focusPause struct {
+	paused   bool
+	resumeAt time.Time // Zero until the window has focus again
+}
+
+// updateFocus pauses a game on screen when the window loses focus and
+// counts down once it is back, reporting whether play is held. The clocks,
+// the computer's moves and the background analysis all wait.
+func (g *Game) updateFocus() bool {
+	focused := ebiten.IsFocused()
+	p := &g.pause
+	switch {
+	case !focused && !p.paused:
+		if g.gameState != StateInGame || g.dialog != nil {
+			return false
+		}
+		p.paused, p.resumeAt = true, time.Time{}
+		g.analysis.stop()
+		logging.For("gui").Info("game paused", "reason", "focus lost")
+	case !focused:
+		p.resumeAt = time.Time{} // Lost again during the countdown
+	case p.paused && p.resumeAt.IsZero():
+		p.resumeAt = time.Now().Add(resumeCountdown)
+	case p.paused && !time.Now().Before(p.resumeAt):
+		p.paused = false
+		// The computer takes its usual pause before moving again
+		for _, s := range g.sessions {
+			s.lastActionTime = time.Now()
+		}
+		logging.For("gui").Info("game resumed", "reason", "focus regained")
+	}
+	return p.paused
+}
+
+// drawPause dims the board of a paused game and shows the countdown
+func (g *Game) drawPause(screen *ebiten.Image) {
+	if !g.pause.paused {
+		return
+	}
+	drawRect(screen, image.Rect(0, 0, ScreenWidth, ScreenHeight), color.RGBA{0, 0, 0, 160})
+
+	msg := "Paused"
+	if !g.pause.resumeAt.IsZero() {
+		left := math.Ceil(time.Until(g.pause.resumeAt).Seconds())
+		msg = fmt.Sprintf("Resuming in %.0f", max(left, 1))
+	}
+	drawCenteredText(screen, msg, g.resources.GetLargeFont(), g.layout.board, TextColor)
+}
@@ -1,18 +1,29 @@
+//go:build !nogui
+
 package gui
 
 import (
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
 	"math"
+	"os"
+	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
 	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/plugin"
+	"github.com/amirhossein-jamali/othello/pkg/profile"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text"
-	"golang.org/x/image/font"
 	"golang.org/x/image/math/fixed"
 )
 
@@ -34,6 +45,11 @@ const (
 	StateColorSelect
 	StateInGame
 	StateGameOver
+	StateMyGames
+	StateHelp
+	StateLibrary
+	StateStatistics
+	StateProfiles
 )
 
 // GameMode represents the game mode
@@ -44,58 +60,183 @@ const (
 	ModeHumanVsEasyAI
 	ModeHumanVsMediumAI
 	ModeHumanVsHardAI
+	ModeCorrespondence
+	ModeExternal // Against an installed engine
 )
 
 // Game represents the main Ebiten game structure
 type Game struct {
 	gameState GameState
-	gameMode  GameMode
 
-	// Core game logic
-	othelloGame *model.Game
-	aiPlayer    *ai.Player
+	// The game on the screen, and every game open in a tab
+	*session
+	sessions      []*session
+	newTabPending bool           // The next game started opens in a new tab
+	chosenMode    GameMode       // Mode picked for the game about to start
+	chosenEngine  int            // Installed engine picked, for ModeExternal
+	chosenPreset  *config.Preset // Preset picked for the game about to start, nil for none
+	setup         gameSetup
 
 	// Resources
 	resources *Resources
 
-	// Display state
-	selectedCellX  int
-	selectedCellY  int
-	validMoves     []model.Position
-	lastMoveX      int
-	lastMoveY      int
-	computerAction bool
-	lastActionTime time.Time
-
-	// Animation
-	animating      bool
-	animationStart time.Time
-
 	board       *model.Board
 	gameOver    bool
 	message     string
 	colorChosen bool
+
+	// Set when Update or Draw panicked
+	crash *crashInfo
+
+	options Options
+	corr    *correspondenceView // nil unless a correspondence server is configured
+	library *libraryView        // nil without a game library
+
+	// Local user profiles, nil without a profile store
+	profiles *profileView
+	profile  *profile.Profile // In use, nil for the guest
+	avatar   *ebiten.Image    // Of the profile in use, nil when it has none
+
+	// Live settings
+	settings  <-chan config.Config // nil without a settings watcher
+	applied   config.Config
+	moveDelay time.Duration // Pause before the AI moves
+
+	// In-game panels
+	layout layout
+
+	// On-screen buttons standing in for keys in the touch layout, and what
+	// the resume file last saved
+	touchBar *Form
+	resumed  string
+
+	// Menu screens
+	mainMenu, modeMenu, gameOverMenu *Form
+	presetWidgets                    []Widget // The main menu's preset buttons
+	dialog                           Modal    // Modal dialog, nil when none is open
+
+	// Folder the last file dialog chose a file from
+	fileDir string
+
+	// Help screen
+	helpReturn GameState // Screen to go back to
+	helpScroll scrollView
+
+	// Keyboard shortcuts and what they trigger
+	keys              keymap
+	flashText         string
+	flashUntil        time.Time
+	screenshotPending bool
+
+	// Engine statistics overlay, fed by the telemetry of the AI's searches
+	debug     debugOverlay
+	telemetry *ai.Telemetry
+
+	// Advanced analysis overlays: the board tinted by who owns each square
+	// in playouts, and the empty regions outlined
+	showOwnership bool
+	showRegions   bool
+
+	// Particles and screen shake celebrating notable moves
+	effects *effects
+
+	// Held while the window is in the background
+	pause focusPause
+
+	// Computer game played behind the main menu once it sits idle
+	idleSince time.Time
+	demo      *demoGame
+
+	// Live web page of the game on screen, nil when not broadcasting
+	broadcast *broadcastView
 }
 
 // NewGame creates a new GUI game
 func NewGame() *Game {
-	return &Game{
+	g := &Game{
 		board:       model.NewBoard(),
 		gameOver:    false,
 		message:     "Choose your color: Press B for Black, W for White",
 		colorChosen: false,
 		resources:   NewResources(),
 		gameState:   StateMainMenu,
+		applied:     config.Default(),
+		moveDelay:   time.Duration(config.Default().AI.MoveDelayMS) * time.Millisecond,
+		layout:      computeLayout(config.Default().Layout, false),
+		session:     newSession(),
+		keys:        newKeymap(config.DefaultKeys()),
+		telemetry:   ai.DefaultTelemetry,
+		effects:     newEffects(),
+		idleSince:   time.Now(),
+	}
+	if g.telemetry == nil {
+		g.telemetry = ai.NewTelemetry(debugTelemetryLimit)
 	}
+	g.buildMenus()
+	return g
 }
 
 // Update handles game logic updates each frame
 func (g *Game) Update() error {
-	// Always check for main menu return key (escape) in any state except main menu
+	defer g.recoverPanic()
+
+	// Stop the loop if the previous frame crashed
+	if g.crash != nil {
+		return &crashError{info: *g.crash}
+	}
+	updatePointer()
+	if g.updateIdle() {
+		return nil
+	}
+
+	// Clocks only run while the game is on screen, not behind a dialog and
+	// not paused in the background
+	paused := g.updateFocus()
+	g.tickClock(g.gameState == StateInGame && g.dialog == nil && !paused)
+	g.updateResume()
+	g.updateBroadcast()
+
+	// Apply settings changed while the game runs
+	select {
+	case cfg := <-g.settings:
+		g.applySettings(cfg)
+	default:
+	}
+	if paused {
+		return nil
+	}
+
+	g.effects.update()
+
+	// A dialog takes all input until it closes
+	if g.updateDialog() {
+		return nil
+	}
+	g.updateTouchBar()
+	g.showCorrespondenceNotices()
+	g.openDroppedFiles()
+
+	// Escape leaves the help screen, or returns to the main menu from any
+	// other screen
+	if g.gameState == StateHelp && inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.gameState = g.helpReturn
+		return nil
+	}
 	if g.gameState != StateMainMenu && inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-		g.gameState = StateMainMenu
+		g.leaveToMainMenu()
+		return nil
+	}
+	if g.gameState != StateHelp && g.keys.pressed(config.ActionHelp) {
+		g.openHelp()
 		return nil
 	}
+	g.updateGlobalActions()
+	if g.gameState == StateInGame || g.gameState == StateGameOver {
+		g.updateSimulBoards()
+		if g.updateTabs() {
+			return nil
+		}
+	}
 
 	switch g.gameState {
 	case StateMainMenu:
@@ -108,12 +249,24 @@ func (g *Game) Update() error {
 		g.updateGame()
 	case StateGameOver:
 		g.updateGameOver()
+	case StateMyGames:
+		g.updateMyGames()
+	case StateHelp:
+		g.updateHelp()
+	case StateLibrary:
+		g.updateLibrary()
+	case StateStatistics:
+		g.updateStatistics()
+	case StateProfiles:
+		g.updateProfiles()
 	}
 	return nil
 }
 
 // Draw renders the game
 func (g *Game) Draw(screen *ebiten.Image) {
+	defer g.recoverPanic()
+
 	// Clear the screen
 	screen.Fill(BackgroundColor)
 
@@ -125,10 +278,33 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	case StateColorSelect:
 		g.drawColorSelect(screen)
 	case StateInGame:
-		g.drawGame(screen)
+		g.effects.drawShaken(screen, g.drawGame)
+		g.effects.draw(screen)
+		g.drawTabs(screen)
+		g.drawBroadcast(screen)
+		g.drawPause(screen)
 	case StateGameOver:
-		g.drawGameOver(screen)
+		g.effects.drawShaken(screen, g.drawGameOver)
+		g.effects.draw(screen)
+		g.drawTabs(screen)
+	case StateMyGames:
+		g.drawMyGames(screen)
+	case StateHelp:
+		g.drawHelp(screen)
+	case StateLibrary:
+		g.drawLibrary(screen)
+	case StateStatistics:
+		g.drawStatistics(screen)
+	case StateProfiles:
+		g.drawProfiles(screen)
 	}
+
+	if g.dialog != nil {
+		g.dialog.Draw(screen, g.resources)
+	}
+	g.drawFlash(screen)
+	g.saveScreenshot(screen)
+	g.drawDebugOverlay(screen)
 }
 
 // Layout returns the game's logical screen dimensions
@@ -136,129 +312,110 @@ func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
 	return ScreenWidth, ScreenHeight
 }
 
-// updateMainMenu handles main menu interactions
-func (g *Game) updateMainMenu() {
-	// Process mouse clicks only when released to prevent accidental selections
-	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-		// Handle start button click
-		x, y := ebiten.CursorPosition()
-		startButtonRect := image.Rect(ScreenWidth/2-100, ScreenHeight/2-25, ScreenWidth/2+100, ScreenHeight/2+25)
+// buildMenus creates the widgets of the menu screens
+func (g *Game) buildMenus() {
+	title := func(y int, s string) *Label {
+		return &Label{Rect: image.Rect(0, y-30, ScreenWidth, y+10), Text: s, Face: g.resources.GetLargeFont(), Align: AlignCenter}
+	}
 
-		if startButtonRect.Min.X <= x && x <= startButtonRect.Max.X &&
-			startButtonRect.Min.Y <= y && y <= startButtonRect.Max.Y {
+	g.mainMenu = NewForm(
+		title(ScreenHeight/3, "Othello / Reversi"),
+		NewButton(image.Rect(ScreenWidth/2-100, ScreenHeight/2-25, ScreenWidth/2+100, ScreenHeight/2+25), "Start Game", func() {
+			g.chosenPreset = nil
 			g.gameState = StateGameMode
-		}
+		}),
+	)
+	// Load Game, then My Games only when a correspondence server is
+	// configured and Library only with a game library
+	buttonRect := menuButtonRect
+	g.mainMenu.Add(NewButton(buttonRect, "Load Game", g.loadGameFile))
+	buttonRect = buttonRect.Add(image.Pt(0, 70))
+	if g.corr != nil {
+		g.mainMenu.Add(NewButton(buttonRect, "My Games", g.openMyGames))
+		buttonRect = buttonRect.Add(image.Pt(0, 70))
+	}
+	if g.library != nil {
+		g.mainMenu.Add(NewButton(buttonRect, "Library", g.openLibrary))
+		buttonRect = buttonRect.Add(image.Pt(0, 70))
+		g.library.search = NewTextInput(librarySearchRect, "Search, e.g. alice result:black opening:f5d6 after:2024-01-01")
+		g.library.menu = NewForm(
+			g.library.search,
+			NewButton(importButtonRect, "Import", g.importToLibrary),
+			NewButton(statsButtonRect, "Statistics", g.openStatistics),
+		)
+		g.library.statsMenu = NewForm(NewButton(statsButtonRect, "Library", g.openLibrary))
+	}
+	g.mainMenu.Add(NewButton(buttonRect, "Rules & Help", g.openHelp))
+	if g.profiles != nil {
+		g.mainMenu.Add(NewButton(profileButtonRect, g.profileName(), g.openProfiles))
+		g.buildProfileMenu()
 	}
-}
-
-// drawMainMenu renders the main menu
-func (g *Game) drawMainMenu(screen *ebiten.Image) {
-	// Clear the screen
-	screen.Fill(BackgroundColor)
 
-	// Title
-	titleText := "Othello / Reversi"
-	bounds, _ := font.BoundString(g.resources.GetLargeFont(), titleText)
-	x := (ScreenWidth - fixedToIntWidth(bounds)) / 2
-	y := ScreenHeight / 3
-	text.Draw(screen, titleText, g.resources.GetLargeFont(), x, y, TextColor)
+	g.modeMenu = NewForm(title(ScreenHeight/5, "Select Game Mode"))
+	modes := []struct {
+		mode  GameMode
+		setup gameSetup
+		label string
+	}{
+		{ModeHumanVsHuman, setupSingle, "Human vs Human"},
+		{ModeHumanVsEasyAI, setupSingle, "Human vs Computer (Easy)"},
+		{ModeHumanVsMediumAI, setupSingle, "Human vs Computer (Medium)"},
+		{ModeHumanVsHardAI, setupSingle, "Human vs Computer (Hard)"},
+		{simulMode, setupSimul, "Simul: Two Boards vs Computer"},
+		{ModeHumanVsMediumAI, setupConsultation, "Consultation: Pair vs Computer"},
+		{ModeHumanVsHuman, setupConsultation, "Consultation: Pair vs Pair"},
+	}
+	buttonHeight := 50
+	buttonSpacing := 12
+	buttonY := ScreenHeight/5 + 40
+	centerX := ScreenWidth / 2
+	if len(g.options.Engines) > 0 {
+		centerX -= 160 // Installed engines are listed on the right
+	}
+	for _, m := range modes {
+		mode, setup := m.mode, m.setup
+		rect := image.Rect(centerX-150, buttonY, centerX+150, buttonY+buttonHeight)
+		g.modeMenu.Add(NewButton(rect, m.label, func() {
+			g.setup = setup
+			g.startGame(mode)
+		}))
+		buttonY += buttonHeight + buttonSpacing
+	}
+	g.addEngineButtons(ScreenHeight/5+40, buttonHeight, buttonSpacing)
+	g.buildPresetButtons(g.applied.Presets)
 
-	// Get mouse position for hover effect
-	mouseX, mouseY := ebiten.CursorPosition()
-	startButtonRect := image.Rect(ScreenWidth/2-100, ScreenHeight/2-25, ScreenWidth/2+100, ScreenHeight/2+25)
-	buttonHovered := startButtonRect.Min.X <= mouseX && mouseX <= startButtonRect.Max.X &&
-		startButtonRect.Min.Y <= mouseY && mouseY <= startButtonRect.Max.Y
+	g.gameOverMenu = NewForm(
+		NewButton(image.Rect(ScreenWidth/2-100, ScreenHeight-100, ScreenWidth/2+100, ScreenHeight-50), "Main Menu", func() {
+			g.gameState = StateMainMenu
+		}),
+	)
+}
 
-	// Start button with hover effect
-	buttonColor := ButtonColor
-	if buttonHovered {
-		buttonColor = HoverColor
-	}
-	drawRect(screen, startButtonRect, buttonColor)
+// updateMainMenu handles main menu interactions
+func (g *Game) updateMainMenu() {
+	g.updateDemo()
+	g.mainMenu.Update()
+}
 
-	buttonText := "Start Game"
-	bounds, _ = font.BoundString(g.resources.GetNormalFont(), buttonText)
-	x = (ScreenWidth - fixedToIntWidth(bounds)) / 2
-	y = ScreenHeight/2 + fixedToIntHeight(bounds)/2
-	text.Draw(screen, buttonText, g.resources.GetNormalFont(), x, y, TextColor)
+// drawMainMenu renders the main menu, over the demo when one plays
+func (g *Game) drawMainMenu(screen *ebiten.Image) {
+	g.drawDemo(screen)
+	g.mainMenu.Draw(screen, g.resources)
 }
 
 // updateGameMode handles game mode selection
 func (g *Game) updateGameMode() {
-	// Process mouse clicks only when released to prevent accidental selections
-	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-		x, y := ebiten.CursorPosition()
-
-		// Calculate button positions and check clicks
-		buttonHeight := 50
-		buttonSpacing := 20
-		buttonY := ScreenHeight / 3
-
-		for mode := ModeHumanVsHuman; mode <= ModeHumanVsHardAI; mode++ {
-			buttonRect := image.Rect(ScreenWidth/2-150, buttonY, ScreenWidth/2+150, buttonY+buttonHeight)
-
-			if buttonRect.Min.X <= x && x <= buttonRect.Max.X &&
-				buttonRect.Min.Y <= y && y <= buttonRect.Max.Y {
-				g.startGame(mode)
-				return
-			}
-
-			buttonY += buttonHeight + buttonSpacing
-		}
-	}
+	g.modeMenu.Update()
 }
 
 // drawGameMode renders the game mode selection screen
 func (g *Game) drawGameMode(screen *ebiten.Image) {
-	// Title
-	titleText := "Select Game Mode"
-	bounds, _ := font.BoundString(g.resources.GetLargeFont(), titleText)
-	x := (ScreenWidth - fixedToIntWidth(bounds)) / 2
-	y := ScreenHeight / 5
-	text.Draw(screen, titleText, g.resources.GetLargeFont(), x, y, TextColor)
-
-	// Mode buttons
-	buttonHeight := 50
-	buttonSpacing := 20
-	buttonY := ScreenHeight / 3
-
-	modes := []string{
-		"Human vs Human",
-		"Human vs Computer (Easy)",
-		"Human vs Computer (Medium)",
-		"Human vs Computer (Hard)",
-	}
-
-	// Get cursor position for hover effect
-	cx, cy := ebiten.CursorPosition()
-
-	for _, modeText := range modes {
-		buttonRect := image.Rect(ScreenWidth/2-150, buttonY, ScreenWidth/2+150, buttonY+buttonHeight)
-
-		// Check if mouse is over this button for hover effect
-		hover := buttonRect.Min.X <= cx && cx <= buttonRect.Max.X &&
-			buttonRect.Min.Y <= cy && cy <= buttonRect.Max.Y
-
-		// Draw button with appropriate color
-		buttonColor := ButtonColor
-		if hover {
-			buttonColor = HoverColor
-		}
-		drawRect(screen, buttonRect, buttonColor)
-
-		// Draw text
-		bounds, _ = font.BoundString(g.resources.GetNormalFont(), modeText)
-		x = (ScreenWidth - fixedToIntWidth(bounds)) / 2
-		y = buttonY + buttonHeight/2 + fixedToIntHeight(bounds)/3
-		text.Draw(screen, modeText, g.resources.GetNormalFont(), x, y, TextColor)
-
-		buttonY += buttonHeight + buttonSpacing
-	}
+	g.modeMenu.Draw(screen, g.resources)
 }
 
 // startGame initializes a new game with the selected mode
 func (g *Game) startGame(mode GameMode) {
-	g.gameMode = mode
+	g.chosenMode = mode
 
 	// If playing against computer, show color selection screen
 	if mode != ModeHumanVsHuman {
@@ -267,11 +424,25 @@ func (g *Game) startGame(mode GameMode) {
 	}
 
 	// For human vs human, just start the game directly
-	g.initializeGame(model.Black) // Player 1 is always black in human vs human
+	g.beginGame(model.Black) // Player 1 is always black in human vs human
 }
 
 // initializeGame sets up the game with the selected color for the human player
 func (g *Game) initializeGame(humanColor model.Piece) {
+	logging.For("gui").Info("new game", "mode", g.chosenMode, "human", model.GetPieceName(humanColor))
+
+	var external plugin.Player
+	if g.chosenMode == ModeExternal {
+		var err error
+		if external, err = g.startExternal(humanColor.Opponent()); err != nil {
+			g.gameState = StateGameMode
+			g.showNotice("Engine unavailable", err.Error())
+			return
+		}
+	}
+
+	g.beginSession()
+	g.gameMode = g.chosenMode
 	g.othelloGame = model.NewGame()
 	g.gameState = StateInGame
 	g.validMoves = g.othelloGame.GetValidMoves()
@@ -281,6 +452,10 @@ func (g *Game) initializeGame(humanColor model.Piece) {
 	g.lastMoveY = -1
 	g.computerAction = false
 	g.animating = false
+	g.cancelBlunderCheck()
+	g.stopBrowsing()
+	g.resetClocks()
+	g.startClocks()
 
 	// Create AI if playing against computer
 	if g.gameMode != ModeHumanVsHuman {
@@ -300,6 +475,14 @@ func (g *Game) initializeGame(humanColor model.Piece) {
 		}
 
 		g.aiPlayer = ai.NewPlayer(difficulty, aiColor)
+		g.aiPlayer.Telemetry = g.telemetry
+		if external != nil {
+			e := g.options.Engines[g.chosenEngine]
+			g.external, g.externalName = external, e.Name
+			g.othelloGame.SetMetadata(strings.ToLower(model.GetPieceName(aiColor))+".name", e.Name)
+		} else {
+			g.aiPlayer.WriteMetadata(g.othelloGame)
+		}
 
 		// If AI is black, let it make the first move
 		if aiColor == model.Black {
@@ -311,21 +494,21 @@ func (g *Game) initializeGame(humanColor model.Piece) {
 // updateColorSelect handles color selection screen interactions
 func (g *Game) updateColorSelect() {
 	// Process mouse clicks only when released to prevent accidental selections
-	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-		x, y := ebiten.CursorPosition()
+	if pointerJustReleased() {
+		x, y := cursorPosition()
 		pieceY := ScreenHeight * 3 / 4
 
 		// Check if black piece was clicked
 		blackX := ScreenWidth / 3
 		if math.Sqrt(math.Pow(float64(x-blackX), 2)+math.Pow(float64(y-pieceY), 2)) <= 40 {
-			g.initializeGame(model.Black)
+			g.beginGame(model.Black)
 			return
 		}
 
 		// Check if white piece was clicked
 		whiteX := ScreenWidth * 2 / 3
 		if math.Sqrt(math.Pow(float64(x-whiteX), 2)+math.Pow(float64(y-pieceY), 2)) <= 40 {
-			g.initializeGame(model.White)
+			g.beginGame(model.White)
 			return
 		}
 	}
@@ -338,17 +521,17 @@ func (g *Game) drawColorSelect(screen *ebiten.Image) {
 
 	// Title
 	titleText := "Choose Your Color"
-	bounds, _ := font.BoundString(g.resources.GetLargeFont(), titleText)
+	bounds := textBounds(g.resources.GetLargeFont(), titleText)
 	x := (ScreenWidth - fixedToIntWidth(bounds)) / 2
 	y := ScreenHeight / 4
-	text.Draw(screen, titleText, g.resources.GetLargeFont(), x, y, TextColor)
+	drawLabel(screen, titleText, g.resources.GetLargeFont(), x, y, TextColor)
 
 	// Instructions
 	instructionText := "Click on a piece to select your color"
-	bounds, _ = font.BoundString(g.resources.GetNormalFont(), instructionText)
+	bounds = textBounds(g.resources.GetNormalFont(), instructionText)
 	x = (ScreenWidth - fixedToIntWidth(bounds)) / 2
 	y = ScreenHeight / 2
-	text.Draw(screen, instructionText, g.resources.GetNormalFont(), x, y, TextColor)
+	drawLabel(screen, instructionText, g.resources.GetNormalFont(), x, y, TextColor)
 
 	// Draw black and white pieces
 	pieceY := ScreenHeight * 3 / 4
@@ -356,12 +539,12 @@ func (g *Game) drawColorSelect(screen *ebiten.Image) {
 
 	// Black piece with label
 	blackLabel := "Black"
-	bounds, _ = font.BoundString(g.resources.GetNormalFont(), blackLabel)
+	bounds = textBounds(g.resources.GetNormalFont(), blackLabel)
 	blackLabelX := ScreenWidth/3 - fixedToIntWidth(bounds)/2
-	text.Draw(screen, blackLabel, g.resources.GetNormalFont(), blackLabelX, pieceY-pieceRadius-10, TextColor)
+	drawLabel(screen, blackLabel, g.resources.GetNormalFont(), blackLabelX, pieceY-pieceRadius-10, TextColor)
 
 	// Draw black piece with highlight effect when hovered
-	mouseX, mouseY := ebiten.CursorPosition()
+	mouseX, mouseY := cursorPosition()
 	blackX := ScreenWidth / 3
 	blackHovered := math.Sqrt(math.Pow(float64(mouseX-blackX), 2)+math.Pow(float64(mouseY-pieceY), 2)) <= float64(pieceRadius)
 
@@ -372,13 +555,13 @@ func (g *Game) drawColorSelect(screen *ebiten.Image) {
 	}
 
 	// Always draw the piece
-	drawCircle(screen, blackX, pieceY, pieceRadius, BlackPieceColor)
+	g.resources.drawPiece(screen, model.Black, blackX, pieceY, pieceRadius)
 
 	// White piece with label
 	whiteLabel := "White"
-	bounds, _ = font.BoundString(g.resources.GetNormalFont(), whiteLabel)
+	bounds = textBounds(g.resources.GetNormalFont(), whiteLabel)
 	whiteLabelX := ScreenWidth*2/3 - fixedToIntWidth(bounds)/2
-	text.Draw(screen, whiteLabel, g.resources.GetNormalFont(), whiteLabelX, pieceY-pieceRadius-10, TextColor)
+	drawLabel(screen, whiteLabel, g.resources.GetNormalFont(), whiteLabelX, pieceY-pieceRadius-10, TextColor)
 
 	// Draw white piece with highlight effect when hovered
 	whiteX := ScreenWidth * 2 / 3
@@ -391,19 +574,44 @@ func (g *Game) drawColorSelect(screen *ebiten.Image) {
 	}
 
 	// Always draw the piece
-	drawCircle(screen, whiteX, pieceY, pieceRadius, WhitePieceColor)
+	g.resources.drawPiece(screen, model.White, whiteX, pieceY, pieceRadius)
 
 	// Draw a hint to press ESC to return to main menu
 	escText := "Press ESC to return to main menu"
-	bounds, _ = font.BoundString(g.resources.GetSmallFont(), escText)
+	bounds = textBounds(g.resources.GetSmallFont(), escText)
 	x = (ScreenWidth - fixedToIntWidth(bounds)) / 2
 	y = ScreenHeight - 20
-	text.Draw(screen, escText, g.resources.GetSmallFont(), x, y, TextColor)
+	drawLabel(screen, escText, g.resources.GetSmallFont(), x, y, TextColor)
 }
 
 // updateGame handles in-game interactions
 func (g *Game) updateGame() {
-	if g.othelloGame.GameOver {
+	if g.gameMode == ModeCorrespondence {
+		g.syncCorrespondenceGame()
+	}
+
+	g.updatePanelToggles()
+	g.updateBlunderToggle()
+	g.updateGameActions()
+	g.updateAnalysis()
+	g.updateOwnership()
+	g.updateRegions()
+	g.updateHistoryScroll()
+	browsed := g.updateBrowsing()
+	g.updateReplay()
+
+	// The game waits while the player's last move is judged
+	if g.updateBlunderCheck() {
+		return
+	}
+
+	// A finished game loaded for replay stays on the board
+	if g.othelloGame.GameOver && !g.review {
+		black, white := g.othelloGame.GetScore()
+		logging.For("gui").Info("game over", "winner", model.GetPieceName(g.othelloGame.Winner), "black", black, "white", white)
+		g.stopBrowsing()
+		g.breakdown = gameBreakdown(g.othelloGame)
+		g.saveToLibrary()
 		g.gameState = StateGameOver
 		return
 	}
@@ -419,6 +627,12 @@ func (g *Game) updateGame() {
 		}
 	}
 
+	// In a simul the player moves on to a board waiting for them while the
+	// computer replies here
+	if g.simul && g.isComputerTurn() && g.nextSimulBoard() {
+		return
+	}
+
 	// Handle computer's turn
 	if g.isComputerTurn() {
 		// Add a delay before computer's move for better UX
@@ -429,7 +643,7 @@ func (g *Game) updateGame() {
 		}
 
 		// Wait a bit before making the move
-		if time.Since(g.lastActionTime).Milliseconds() < 800 {
+		if time.Since(g.lastActionTime) < g.moveDelay {
 			return
 		}
 
@@ -438,61 +652,88 @@ func (g *Game) updateGame() {
 		return
 	}
 
-	// Handle human player's input
-	g.handlePlayerInput()
+	// Handle human player's input; the board takes no moves while an
+	// earlier position is shown
+	if !browsed && !g.browsing() {
+		g.handlePlayerInput()
+	}
 }
 
 // isComputerTurn checks if it's the computer's turn
 func (g *Game) isComputerTurn() bool {
-	return g.gameMode != ModeHumanVsHuman &&
-		g.othelloGame.Board.CurrentPlayer == g.aiPlayer.Piece
+	return g.toMove(g.othelloGame.Board.CurrentPlayer).ai != nil
 }
 
 // makeComputerMove processes the AI's move
 func (g *Game) makeComputerMove() {
+	black, white := g.othelloGame.GetScore()
+	if row, col, ok := g.playComputerMove(); ok {
+		g.celebrateMove(row, col, black, white)
+		g.animating = true
+		g.animationStart = time.Now()
+	}
+}
+
+// playComputerMove has the AI move or pass, reporting the square it played
+func (g *Game) playComputerMove() (row, col int, ok bool) {
 	// If AI has no valid moves, pass
 	if !g.othelloGame.HasValidMove() {
 		g.othelloGame.Pass()
 		g.validMoves = g.othelloGame.GetValidMoves()
-		return
+		return 0, 0, false
 	}
 
-	// Get AI's move
-	row, col, err := g.aiPlayer.GetMove(g.othelloGame.Board)
+	// Get AI's move, charging the search to the AI's clock
+	var player engine.Player = g.aiPlayer
+	if g.external != nil {
+		player = g.external
+	}
+	var err error
+	if clock, ok := g.computerClock(); ok && g.external == nil {
+		row, col, err = g.aiPlayer.GetMoveTimed(g.othelloGame.Board, clock)
+	} else {
+		row, col, err = player.GetMove(g.othelloGame.Board)
+	}
+	g.tickClock(true)
+	if g.othelloGame.GameOver {
+		return 0, 0, false // Lost on time while thinking
+	}
 	if err != nil {
+		logging.For("gui").Error("AI failed to choose a move", "err", err)
 		g.othelloGame.Pass()
 		g.validMoves = g.othelloGame.GetValidMoves()
-		return
+		return 0, 0, false
 	}
 
 	// Make the move
 	err = g.othelloGame.MakeMove(row, col)
-	if err == nil {
+	if err != nil {
+		logging.For("gui").Error("AI move rejected", "err", err)
+	} else {
 		g.lastMoveX = col
 		g.lastMoveY = row
-		g.animating = true
-		g.animationStart = time.Now()
 	}
 
 	// Update valid moves for next player
 	g.validMoves = g.othelloGame.GetValidMoves()
+	return row, col, err == nil
 }
 
 // handlePlayerInput processes user input during the game
 func (g *Game) handlePlayerInput() {
 	// Get mouse position
-	mouseX, mouseY := ebiten.CursorPosition()
+	mouseX, mouseY := cursorPosition()
 
 	// Update selected cell based on mouse position
 	g.updateSelectedCell(mouseX, mouseY)
 
 	// Handle board click
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+	if pointerJustPressed() {
 		g.handleBoardClick()
 	}
 
-	// Handle space key for passing when no valid moves
-	if inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyP) {
+	// Handle the pass shortcut when no valid moves
+	if g.keys.pressed(config.ActionPass) {
 		// Only allow passing when player has no valid moves
 		if !g.othelloGame.HasValidMove() && !g.othelloGame.GameOver {
 			g.othelloGame.Pass()
@@ -503,19 +744,10 @@ func (g *Game) handlePlayerInput() {
 
 // updateSelectedCell updates the currently selected cell based on mouse position
 func (g *Game) updateSelectedCell(mouseX, mouseY int) {
-	// Check if mouse is over the board
-	if mouseX >= BoardMarginX && mouseX < BoardMarginX+BoardSize &&
-		mouseY >= BoardMarginY && mouseY < BoardMarginY+BoardSize {
-		// Calculate cell coordinates
-		col := (mouseX - BoardMarginX) / CellSize
-		row := (mouseY - BoardMarginY) / CellSize
-
-		g.selectedCellX = col
-		g.selectedCellY = row
-	} else {
-		g.selectedCellX = -1
-		g.selectedCellY = -1
-	}
+	// Cells are -1 when the mouse is off the board
+	row, col, _ := g.layout.cellAt(mouseX, mouseY)
+	g.selectedCellX = col
+	g.selectedCellY = row
 }
 
 // handleBoardClick processes a click on the board
@@ -525,6 +757,11 @@ func (g *Game) handleBoardClick() {
 		return
 	}
 
+	// Correspondence games only accept moves for our own color
+	if g.gameMode == ModeCorrespondence && g.othelloGame.Board.CurrentPlayer != g.corrColor {
+		return
+	}
+
 	// Check if the move is valid
 	isValid := false
 	for _, move := range g.validMoves {
@@ -538,15 +775,29 @@ func (g *Game) handleBoardClick() {
 		return
 	}
 
+	// Judge the move against the position it is played in
+	if g.blunderAlertOn() {
+		move := model.Position{Row: g.selectedCellY, Col: g.selectedCellX}
+		g.checkBlunder(g.othelloGame.Board, move, len(g.othelloGame.History))
+	}
+
 	// Make the move
+	black, white := g.othelloGame.GetScore()
 	err := g.othelloGame.MakeMove(g.selectedCellY, g.selectedCellX)
-	if err == nil {
+	if err != nil {
+		g.cancelBlunderCheck()
+	} else {
+		g.celebrateMove(g.selectedCellY, g.selectedCellX, black, white)
 		g.lastMoveX = g.selectedCellX
 		g.lastMoveY = g.selectedCellY
 		g.animating = true
 		g.animationStart = time.Now()
 		g.validMoves = g.othelloGame.GetValidMoves()
 
+		if g.gameMode == ModeCorrespondence {
+			g.corr.submit(g.corrID, g.selectedCellY, g.selectedCellX)
+		}
+
 		// If the next player has no valid moves, pass automatically
 		if !g.othelloGame.HasValidMove() && !g.othelloGame.GameOver {
 			g.othelloGame.Pass()
@@ -560,51 +811,72 @@ func (g *Game) drawGame(screen *ebiten.Image) {
 	// Draw the board
 	boardImage := g.resources.GetBoardImage()
 	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(BoardMarginX), float64(BoardMarginY))
+	op.GeoM.Translate(float64(g.layout.board.Min.X), float64(g.layout.board.Min.Y))
 	screen.DrawImage(boardImage, op)
+	g.drawOwnership(screen)
+	g.drawJump(screen)
 
 	// Draw pieces
 	g.drawPieces(screen)
+	g.drawRegions(screen)
 
-	// Draw valid moves
-	g.drawValidMoves(screen)
+	if g.browsing() {
+		g.drawBrowsedMove(screen)
+		g.drawMiniBoard(screen)
+	} else {
+		// Draw valid moves
+		g.drawValidMoves(screen)
 
-	// Draw selected cell highlight
-	g.drawSelectedCell(screen)
+		// Draw selected cell highlight
+		g.drawSelectedCell(screen)
 
-	// Draw last move indicator
-	g.drawLastMove(screen)
+		// Draw last move indicator
+		g.drawLastMove(screen)
 
-	// Draw history panel
-	g.drawHistoryPanel(screen)
+		// Draw the suggested move
+		g.drawHint(screen)
+	}
+	g.drawAnalysisArrows(screen)
+
+	// Draw the panels enabled in the layout
+	if !g.layout.history.Empty() {
+		g.drawHistoryPanel(screen, g.layout.history)
+	}
+	if !g.layout.analysis.Empty() {
+		g.drawAnalysisPanel(screen, g.layout.analysis)
+	}
+	if !g.layout.chat.Empty() {
+		g.drawChatPanel(screen, g.layout.chat)
+	}
+	if !g.layout.touchBar.Empty() {
+		g.drawTouchBar(screen)
+	}
+	if !g.layout.evalBar.Empty() {
+		g.drawEvalBar(screen, g.layout.evalBar)
+	}
 
 	// Draw status bar
 	g.drawStatusBar(screen)
+	g.drawAssistance(screen)
 }
 
 // drawPieces renders all pieces on the board
 func (g *Game) drawPieces(screen *ebiten.Image) {
+	board := g.shownBoard()
 	for row := 0; row < 8; row++ {
 		for col := 0; col < 8; col++ {
-			piece := g.othelloGame.Board.GetPiece(row, col)
+			piece := board.GetPiece(row, col)
 			if piece == model.Empty {
 				continue
 			}
 
 			// Calculate piece position
-			centerX := BoardMarginX + col*CellSize + CellSize/2
-			centerY := BoardMarginY + row*CellSize + CellSize/2
+			cell := g.layout.cellRect(row, col)
+			centerX := cell.Min.X + CellSize/2
+			centerY := cell.Min.Y + CellSize/2
 			radius := (CellSize / 2) - 4
 
-			// Choose color based on piece type
-			var pieceColor color.Color
-			if piece == model.Black {
-				pieceColor = BlackPieceColor
-			} else {
-				pieceColor = WhitePieceColor
-			}
-
-			drawCircle(screen, centerX, centerY, radius, pieceColor)
+			g.resources.drawPiece(screen, piece, centerX, centerY, radius)
 		}
 	}
 }
@@ -612,8 +884,9 @@ func (g *Game) drawPieces(screen *ebiten.Image) {
 // drawValidMoves highlights valid moves
 func (g *Game) drawValidMoves(screen *ebiten.Image) {
 	for _, move := range g.validMoves {
-		centerX := BoardMarginX + move.Col*CellSize + CellSize/2
-		centerY := BoardMarginY + move.Row*CellSize + CellSize/2
+		cell := g.layout.cellRect(move.Row, move.Col)
+		centerX := cell.Min.X + CellSize/2
+		centerY := cell.Min.Y + CellSize/2
 		radius := CellSize / 4
 
 		drawCircle(screen, centerX, centerY, radius, ValidMoveColor)
@@ -634,20 +907,16 @@ func (g *Game) drawSelectedCell(screen *ebiten.Image) {
 
 		// Only highlight if it's a valid move
 		if isValidMove {
-			x := BoardMarginX + g.selectedCellX*CellSize
-			y := BoardMarginY + g.selectedCellY*CellSize
-
-			rect := image.Rect(x, y, x+CellSize, y+CellSize)
-			drawRect(screen, rect, HighlightColor)
+			drawRect(screen, g.layout.cellRect(g.selectedCellY, g.selectedCellX), HighlightColor)
 		}
 	}
 }
 
-// drawLastMove highlights the last move played
+// drawLastMove highlights the last move played and the discs it flipped
 func (g *Game) drawLastMove(screen *ebiten.Image) {
 	if g.lastMoveX >= 0 && g.lastMoveY >= 0 {
-		x := BoardMarginX + g.lastMoveX*CellSize
-		y := BoardMarginY + g.lastMoveY*CellSize
+		cell := g.layout.cellRect(g.lastMoveY, g.lastMoveX)
+		x, y := cell.Min.X, cell.Min.Y
 
 		// Draw small markers at the corners of the cell
 		markerSize := 5
@@ -661,73 +930,73 @@ func (g *Game) drawLastMove(screen *ebiten.Image) {
 		drawRect(screen, image.Rect(x, y+CellSize-markerSize, x+markerSize, y+CellSize), markerColor)
 		// Bottom right
 		drawRect(screen, image.Rect(x+CellSize-markerSize, y+CellSize-markerSize, x+CellSize, y+CellSize), markerColor)
+
+		// Arrows along the lines it flipped
+		g.drawFlipArrows(screen, model.Position{Row: g.lastMoveY, Col: g.lastMoveX}, g.lastMoveFlips(), lastMoveArrowColor)
 	}
 }
 
-// drawHistoryPanel renders the game history in a dedicated panel
-func (g *Game) drawHistoryPanel(screen *ebiten.Image) {
-	// Draw panel background
-	panelRect := image.Rect(HistoryPanelX, HistoryPanelY, HistoryPanelX+HistoryPanelW, HistoryPanelY+HistoryPanelH)
-	drawRect(screen, panelRect, PanelBackColor)
-
-	// Draw panel border
-	borderWidth := 2
-	// Top border
-	drawRect(screen, image.Rect(panelRect.Min.X, panelRect.Min.Y, panelRect.Max.X, panelRect.Min.Y+borderWidth), PanelBorderColor)
-	// Left border
-	drawRect(screen, image.Rect(panelRect.Min.X, panelRect.Min.Y, panelRect.Min.X+borderWidth, panelRect.Max.Y), PanelBorderColor)
-	// Right border
-	drawRect(screen, image.Rect(panelRect.Max.X-borderWidth, panelRect.Min.Y, panelRect.Max.X, panelRect.Max.Y), PanelBorderColor)
-	// Bottom border
-	drawRect(screen, image.Rect(panelRect.Min.X, panelRect.Max.Y-borderWidth, panelRect.Max.X, panelRect.Max.Y), PanelBorderColor)
+// historyListRect returns the scrolling part of the history panel, below
+// the column headers
+func historyListRect(panelRect image.Rectangle) image.Rectangle {
+	top := panelRect.Min.Y + PanelTitleH + HistoryItemH + 7
+	return image.Rect(panelRect.Min.X+2, top, panelRect.Max.X-4, panelRect.Max.Y-4)
+}
 
-	// Draw panel title
-	titleText := "Game History"
-	bounds, _ := font.BoundString(g.resources.GetNormalFont(), titleText)
-	titleX := HistoryPanelX + (HistoryPanelW-fixedToIntWidth(bounds))/2
-	titleY := HistoryPanelY + HistoryTitleH/2 + fixedToIntHeight(bounds)/3
-	text.Draw(screen, titleText, g.resources.GetNormalFont(), titleX, titleY, TextColor)
+// updateHistoryScroll scrolls the history panel; it follows the latest move
+// unless the player scrolled back
+func (g *Game) updateHistoryScroll() {
+	if g.layout.history.Empty() {
+		return
+	}
+	rows := (len(g.othelloGame.History) + 1) / 2
+	g.historyScroll.update(historyListRect(g.layout.history), rows*HistoryItemH)
+}
 
-	// Draw horizontal separator under title
-	drawRect(screen, image.Rect(HistoryPanelX, HistoryPanelY+HistoryTitleH, HistoryPanelX+HistoryPanelW, HistoryPanelY+HistoryTitleH+1), PanelBorderColor)
+// drawHistoryPanel renders the game history, one row per black and white move
+func (g *Game) drawHistoryPanel(screen *ebiten.Image, panelRect image.Rectangle) {
+	top := g.drawPanel(screen, panelRect, "Game History")
+	x := panelRect.Min.X
 
 	// List moves history
 	moveCount := len(g.othelloGame.History)
 	if moveCount > 0 {
 		// Show column headers
-		headerY := HistoryPanelY + HistoryTitleH + HistoryItemH
-		text.Draw(screen, "Move", g.resources.GetSmallFont(), HistoryPanelX+15, headerY, TextColor)
-		text.Draw(screen, "Black", g.resources.GetSmallFont(), HistoryPanelX+80, headerY, BlackMoveColor)
-		text.Draw(screen, "White", g.resources.GetSmallFont(), HistoryPanelX+180, headerY, WhiteMoveColor)
+		headerY := top + HistoryItemH
+		text.Draw(screen, "Move", g.resources.GetSmallFont(), x+15, headerY, TextColor)
+		text.Draw(screen, "Black", g.resources.GetSmallFont(), x+80, headerY, BlackMoveColor)
+		text.Draw(screen, "White", g.resources.GetSmallFont(), x+180, headerY, WhiteMoveColor)
 
 		// Draw separator under headers
-		drawRect(screen, image.Rect(HistoryPanelX, headerY+5, HistoryPanelX+HistoryPanelW, headerY+6), PanelBorderColor)
-
-		// Calculate number of moves to display (2 moves per row - one black, one white)
-		maxMoves := 16 // Maximum number of moves to display
-		startMove := 0
-		if moveCount > maxMoves {
-			startMove = moveCount - maxMoves
-		}
-
-		// Display moves in pairs (black and white)
-		moveNum := startMove/2 + 1
-		rowY := headerY + HistoryItemH
+		drawRect(screen, image.Rect(x, headerY+5, panelRect.Max.X, headerY+6), PanelBorderColor)
+
+		// Rows outside the visible part of the list are clipped
+		list := g.historyScroll.clip(screen)
+		area := list.Bounds()
+		firstRow := g.historyScroll.offset / HistoryItemH
+		lastRow := (g.historyScroll.offset + area.Dy()) / HistoryItemH
+
+		for row := firstRow; row <= lastRow && row*2 < moveCount; row++ {
+			i := row * 2
+			rowY := area.Min.Y + (row+1)*HistoryItemH - 7 - g.historyScroll.offset
+
+			// The browsed move
+			if ply := g.browse.ply; ply > 0 && (ply-1)/2 == row {
+				column := x + 75 + (ply-1)%2*100
+				drawRect(list, image.Rect(column, rowY-HistoryItemH+8, column+80, rowY+6), HighlightColor)
+			}
 
-		for i := startMove; i < moveCount; i += 2 {
 			// Move number
-			moveNumStr := fmt.Sprintf("%d.", moveNum)
-			text.Draw(screen, moveNumStr, g.resources.GetSmallFont(), HistoryPanelX+15, rowY, TextColor)
+			moveNumStr := fmt.Sprintf("%d.", row+1)
+			text.Draw(list, moveNumStr, g.resources.GetSmallFont(), x+15, rowY, TextColor)
 
 			// Black's move
-			if i < moveCount {
-				move := g.othelloGame.History[i]
-				moveStr := "Pass"
-				if move.Position.Row >= 0 {
-					moveStr = model.FormatMove(move.Position.Row, move.Position.Col)
-				}
-				text.Draw(screen, moveStr, g.resources.GetSmallFont(), HistoryPanelX+80, rowY, BlackMoveColor)
+			move := g.othelloGame.History[i]
+			moveStr := "Pass"
+			if move.Position.Row >= 0 {
+				moveStr = model.FormatMove(move.Position.Row, move.Position.Col)
 			}
+			text.Draw(list, moveStr, g.resources.GetSmallFont(), x+80, rowY, BlackMoveColor)
 
 			// White's move
 			if i+1 < moveCount {
@@ -736,63 +1005,80 @@ func (g *Game) drawHistoryPanel(screen *ebiten.Image) {
 				if move.Position.Row >= 0 {
 					moveStr = model.FormatMove(move.Position.Row, move.Position.Col)
 				}
-				text.Draw(screen, moveStr, g.resources.GetSmallFont(), HistoryPanelX+180, rowY, WhiteMoveColor)
+				text.Draw(list, moveStr, g.resources.GetSmallFont(), x+180, rowY, WhiteMoveColor)
 			}
-
-			moveNum++
-			rowY += HistoryItemH
 		}
+		g.historyScroll.drawScrollbar(screen)
 	} else {
-		// No moves yet
-		noMovesText := "No moves yet"
-		bounds, _ := font.BoundString(g.resources.GetSmallFont(), noMovesText)
-		x := HistoryPanelX + (HistoryPanelW-fixedToIntWidth(bounds))/2
-		y := HistoryPanelY + HistoryTitleH + 50
-		text.Draw(screen, noMovesText, g.resources.GetSmallFont(), x, y, TextColor)
+		g.drawPanelNote(screen, panelRect, top, "No moves yet")
 	}
 }
 
-// drawStatusBar renders the score and current player
+// drawStatusBar renders the player cards and the pass prompt
 func (g *Game) drawStatusBar(screen *ebiten.Image) {
-	blackCount, whiteCount := g.othelloGame.GetScore()
-
-	// Draw scores
-	scoreText := fmt.Sprintf("Black: %d   White: %d", blackCount, whiteCount)
-	bounds, _ := font.BoundString(g.resources.GetNormalFont(), scoreText)
-	x := (BoardMarginX + BoardSize/2) - fixedToIntWidth(bounds)/2
-	y := 40
-	text.Draw(screen, scoreText, g.resources.GetNormalFont(), x, y, TextColor)
-
-	// Draw current player indicator
-	statusText := g.othelloGame.GetGameStatus()
-	bounds, _ = font.BoundString(g.resources.GetNormalFont(), statusText)
-	x = (BoardMarginX + BoardSize/2) - fixedToIntWidth(bounds)/2
-	y = ScreenHeight - 30
-	text.Draw(screen, statusText, g.resources.GetNormalFont(), x, y, TextColor)
+	g.drawPlayerCards(screen)
+
+	if g.browsing() || g.review {
+		status := g.reviewStatus()
+		switch {
+		case g.review && g.browsing():
+			status = g.replayStatus()
+		case g.browsing():
+			status = g.browseStatus()
+		}
+		x := g.layout.board.Min.X + g.layout.board.Dx()/2 - textWidth(g.resources.GetSmallFont(), status)/2
+		text.Draw(screen, status, g.resources.GetSmallFont(), x, ScreenHeight-30, HighlightColor)
+		return
+	}
 
 	// Show prompt for passing when no valid moves available
 	if !g.othelloGame.HasValidMove() && !g.othelloGame.GameOver {
-		passText := "No valid moves! Press SPACE or P to pass"
-		bounds, _ = font.BoundString(g.resources.GetSmallFont(), passText)
-		x = (BoardMarginX + BoardSize/2) - fixedToIntWidth(bounds)/2
-		y = ScreenHeight - 10
+		passText := "No valid moves! Press " + g.keys.keyNames(config.ActionPass) + " to pass"
+		bounds := textBounds(g.resources.GetSmallFont(), passText)
+		x := g.layout.board.Min.X + g.layout.board.Dx()/2 - fixedToIntWidth(bounds)/2
+		y := ScreenHeight - 30
 		text.Draw(screen, passText, g.resources.GetSmallFont(), x, y, color.RGBA{255, 255, 0, 255}) // Yellow text
 	}
+
+	if g.blunder != nil {
+		checkText := "Checking your move..."
+		bounds := textBounds(g.resources.GetSmallFont(), checkText)
+		x := g.layout.board.Min.X + g.layout.board.Dx()/2 - fixedToIntWidth(bounds)/2
+		text.Draw(screen, checkText, g.resources.GetSmallFont(), x, ScreenHeight-30, TextColor)
+		return
+	}
+
+	// Otherwise a tip for the phase of the game
+	if g.othelloGame.HasValidMove() && !g.othelloGame.GameOver {
+		tip := phaseTip(g.othelloGame.Board)
+		x := g.layout.board.Min.X + g.layout.board.Dx()/2 - textWidth(g.resources.GetSmallFont(), tip)/2
+		text.Draw(screen, tip, g.resources.GetSmallFont(), x, ScreenHeight-30, TextColor)
+	}
+}
+
+// phaseTip describes the phase of the game on board with advice for it
+func phaseTip(board *model.Board) string {
+	switch model.Phase(board) {
+	case model.Opening:
+		return "Opening: keep your discs few and your moves many"
+	case model.Midgame:
+		return "Midgame: fight for mobility and keep away from the corners' neighbors"
+	}
+	empties := board.Size*board.Size - board.BlackCnt - board.WhiteCnt
+	return fmt.Sprintf("Endgame: %d empty squares left, every disc counts", empties)
 }
 
 // updateGameOver handles game over screen interactions
 func (g *Game) updateGameOver() {
-	// Process mouse clicks only when released to prevent accidental selections
-	if inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft) {
-		// Check for menu button click
-		x, y := ebiten.CursorPosition()
-		menuButtonRect := image.Rect(ScreenWidth/2-100, ScreenHeight-100, ScreenWidth/2+100, ScreenHeight-50)
-
-		if menuButtonRect.Min.X <= x && x <= menuButtonRect.Max.X &&
-			menuButtonRect.Min.Y <= y && y <= menuButtonRect.Max.Y {
-			g.gameState = StateMainMenu
-		}
+	if g.keys.pressed(config.ActionSaveGame) {
+		g.saveGameFile()
+		return
 	}
+	if g.keys.pressed(config.ActionFindPosition) {
+		g.findPosition()
+		return
+	}
+	g.gameOverMenu.Update()
 }
 
 // drawGameOver renders the game over screen
@@ -806,74 +1092,190 @@ func (g *Game) drawGameOver(screen *ebiten.Image) {
 
 	// Draw game over text
 	gameOverText := "Game Over"
-	bounds, _ := font.BoundString(g.resources.GetLargeFont(), gameOverText)
+	bounds := textBounds(g.resources.GetLargeFont(), gameOverText)
 	x := (ScreenWidth - fixedToIntWidth(bounds)) / 2
 	y := ScreenHeight/3 - fixedToIntHeight(bounds)/2
-	text.Draw(screen, gameOverText, g.resources.GetLargeFont(), x, y, TextColor)
+	drawLabel(screen, gameOverText, g.resources.GetLargeFont(), x, y, TextColor)
 
 	// Draw the result
 	blackCount, whiteCount := g.othelloGame.GetScore()
 	var resultText string
-	if blackCount > whiteCount {
+	switch g.othelloGame.Winner {
+	case model.Black:
 		resultText = "Black Wins!"
-	} else if whiteCount > blackCount {
+	case model.White:
 		resultText = "White Wins!"
-	} else {
+	default:
 		resultText = "It's a Tie!"
 	}
 
-	bounds, _ = font.BoundString(g.resources.GetLargeFont(), resultText)
+	bounds = textBounds(g.resources.GetLargeFont(), resultText)
 	x = (ScreenWidth - fixedToIntWidth(bounds)) / 2
 	y = ScreenHeight/3 + 50
 	text.Draw(screen, resultText, g.resources.GetLargeFont(), x, y, TextColor)
 
 	// Draw the score
 	scoreText := fmt.Sprintf("Final Score: Black %d - White %d", blackCount, whiteCount)
-	bounds, _ = font.BoundString(g.resources.GetNormalFont(), scoreText)
+	bounds = textBounds(g.resources.GetNormalFont(), scoreText)
 	x = (ScreenWidth - fixedToIntWidth(bounds)) / 2
 	y = ScreenHeight/3 + 100
 	text.Draw(screen, scoreText, g.resources.GetNormalFont(), x, y, TextColor)
 
-	// Get mouse position for hover effect
-	mouseX, mouseY := ebiten.CursorPosition()
-	menuButtonRect := image.Rect(ScreenWidth/2-100, ScreenHeight-100, ScreenWidth/2+100, ScreenHeight-50)
-	buttonHovered := menuButtonRect.Min.X <= mouseX && mouseX <= menuButtonRect.Max.X &&
-		menuButtonRect.Min.Y <= mouseY && mouseY <= menuButtonRect.Max.Y
-
-	// Draw menu button with hover effect
-	buttonColor := ButtonColor
-	if buttonHovered {
-		buttonColor = HoverColor
+	// Draw the breakdown, with the accuracy once the game is analyzed
+	lines := g.breakdown
+	if accuracy := g.gameOverAccuracy(); accuracy != "" {
+		lines = append(lines[:len(lines):len(lines)], accuracy)
+	}
+	for _, line := range lines {
+		y += 26
+		drawCenteredText(screen, line, g.resources.GetSmallFont(), image.Rect(0, y-18, ScreenWidth, y+6), TextColor)
 	}
-	drawRect(screen, menuButtonRect, buttonColor)
 
-	menuText := "Main Menu"
-	bounds, _ = font.BoundString(g.resources.GetNormalFont(), menuText)
-	x = (ScreenWidth - fixedToIntWidth(bounds)) / 2
-	y = ScreenHeight - 75 + fixedToIntHeight(bounds)/3
-	text.Draw(screen, menuText, g.resources.GetNormalFont(), x, y, TextColor)
+	g.gameOverMenu.Draw(screen, g.resources)
 }
 
-// drawRect draws a filled rectangle
-func drawRect(dst *ebiten.Image, rect image.Rectangle, clr color.Color) {
-	rectImg := ebiten.NewImage(rect.Dx(), rect.Dy())
-	rectImg.Fill(clr)
+// gameBreakdown describes how a finished game was won for the game over
+// screen
+func gameBreakdown(game *model.Game) []string {
+	summary := game.Summarize()
+	loser := model.GetPieceName(game.Winner.Opponent())
+
+	var lines []string
+	switch {
+	case game.EndReason == model.EndResigned:
+		lines = append(lines, loser+" resigned")
+	case game.EndReason == model.EndTimeout:
+		lines = append(lines, loser+" ran out of time")
+	case summary.Wipeout:
+		lines = append(lines, "Won by wipeout: "+loser+" has no discs left")
+	}
+
+	lines = append(lines, fmt.Sprintf("Empty squares remaining: %d", summary.Empties))
+	if summary.LongestCapturePly > 0 {
+		m := summary.LongestCapture
+		lines = append(lines, fmt.Sprintf("Longest capture: %d discs by %s with %s (move %d)",
+			m.Flipped, model.GetPieceName(m.Piece), model.FormatMove(m.Position.Row, m.Position.Col), summary.LongestCapturePly))
+	}
 
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
-	dst.DrawImage(rectImg, op)
+	if phases := ai.PhaseEvals(game); len(phases) > 0 {
+		parts := make([]string, len(phases))
+		for i, p := range phases {
+			parts[i] = fmt.Sprintf("%s %+d", p.Name, p.Average)
+		}
+		lines = append(lines, "Average evaluation (Black's view): "+strings.Join(parts, ", "))
+	}
+	for _, side := range []model.Piece{model.Black, model.White} {
+		if help := library.DescribeAssistance(game.Metadata, side); help != "" {
+			lines = append(lines, fmt.Sprintf("%s was assisted with %s", model.GetPieceName(side), help))
+		}
+	}
+	return lines
+}
+
+// Options configures optional GUI features
+type Options struct {
+	CorrespondenceURL    string // Server for the My Games screen; empty hides it
+	CorrespondenceSecret string // Proves the player's name to the server
+	PlayerName           string
+	Settings             *config.Watcher  // Applied live when the settings file changes; may be nil
+	Library              *library.Library // Finished games are saved here; nil hides the Library screen
+
+	// Local user profiles picked from at startup; nil leaves them out. The
+	// settings and library above are the guest's.
+	Profiles *profile.Store
+	Profile  *profile.Profile // Played as from the start instead of asking; may be nil
+
+	// Touch lays the game out for fingers: no panels needing a keyboard, and
+	// buttons for the actions otherwise on keys
+	Touch bool
+	// Engines are the installed engines offered on the mode menu
+	Engines []plugin.Engine
+	// ResumeFile keeps the game in progress, saved after every move, and is
+	// picked up again on the next start; empty disables it. Mobile apps use
+	// it as the system may stop them at any time in the background.
+	ResumeFile string
+	// Preset names a preset of the settings to start a game with at once,
+	// unless a profile is to be chosen first
+	Preset string
 }
 
 // RunGame starts the GUI game
 func RunGame() {
-	game := NewGame()
+	Run(Options{})
+}
+
+// Run starts the GUI game with the given options
+func Run(opts Options) {
+	game := NewGameWithOptions(opts)
 
 	// Configure the window
 	ebiten.SetWindowSize(ScreenWidth, ScreenHeight)
 	ebiten.SetWindowTitle("Othello / Reversi")
 
+	// Panics outside the game loop still get a crash report
+	defer func() {
+		if r := recover(); r != nil {
+			logging.For("gui").Error("panic", "reason", r)
+			game.reportCrash(r, debug.Stack())
+			os.Exit(2)
+		}
+	}()
+
 	// Run the game
 	if err := ebiten.RunGame(game); err != nil {
-		panic(err)
+		logging.For("gui").Error("game loop failed", "err", err)
+
+		var crash *crashError
+		if errors.As(err, &crash) {
+			game.reportCrash(crash.info.reason, crash.info.stack)
+		} else {
+			game.reportCrash(err, debug.Stack())
+		}
+		os.Exit(2)
+	}
+}
+
+// NewGameWithOptions creates the GUI game without running it, for hosts
+// with their own game loop such as mobile apps
+func NewGameWithOptions(opts Options) *Game {
+	game := NewGame()
+	game.options = opts
+	if opts.CorrespondenceURL != "" {
+		game.corr = newCorrespondenceView(opts.CorrespondenceURL, opts.PlayerName, opts.CorrespondenceSecret)
+	}
+	if opts.Library != nil {
+		depth := config.Default().Library.AnalysisDepth
+		if opts.Settings != nil {
+			depth = opts.Settings.Current().Library.AnalysisDepth
+		}
+		game.library = newLibraryView(opts.Library, depth)
+	}
+	if opts.Profiles != nil {
+		game.profiles = newProfileView(opts.Profiles, opts)
+		if len(game.profiles.profiles) > 0 {
+			game.gameState = StateProfiles
+		}
+	}
+	game.buildMenus()
+	if opts.Settings != nil {
+		current := opts.Settings.Current()
+		// The caller loads the opening book at startup
+		game.applied.AI.Book = current.AI.Book
+		game.applySettings(current)
+		game.settings = opts.Settings.Subscribe()
+	}
+	if opts.Profiles != nil && opts.Profile != nil {
+		game.useProfile(opts.Profile)
+	}
+	if opts.Touch && opts.Settings == nil {
+		game.layout = computeLayout(game.applied.Layout, true)
+		game.buildTouchBar()
+	}
+	if opts.ResumeFile != "" && game.gameState != StateProfiles {
+		game.resumeGame()
+	}
+	if opts.Preset != "" && game.gameState == StateMainMenu {
+		game.startPresetNamed(opts.Preset)
 	}
+	return game
 }
@@ -0,0 +1,99 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+
+	"github.com/amirhossein-jamali/othello/pkg/help"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Help screen layout
+const (
+	helpLineH    = 20
+	helpSectionH = 30 // Height of a section title, including the space above it
+	helpKeysX    = 540
+	helpActionX  = 680
+)
+
+// helpRulesRect is the scrolling area holding the rules
+var helpRulesRect = image.Rect(60, 90, 500, ScreenHeight-50)
+
+// helpLine is a line of the rules column
+type helpLine struct {
+	text  string
+	title bool
+	y     int // Baseline relative to the top of the content
+}
+
+// openHelp shows the rules and controls, returning to the current screen
+// when closed
+func (g *Game) openHelp() {
+	g.helpReturn = g.gameState
+	g.gameState = StateHelp
+	g.helpScroll = scrollView{}
+}
+
+// rulesLines lays out the rules column, returning the lines and the content
+// height
+func (g *Game) rulesLines() ([]helpLine, int) {
+	face := g.resources.GetNormalFont()
+	width := helpRulesRect.Dx() - scrollbarW - 10
+
+	var lines []helpLine
+	y := 0
+	for _, section := range help.Rules {
+		y += helpSectionH
+		lines = append(lines, helpLine{text: section.Title, title: true, y: y})
+		for _, paragraph := range section.Lines {
+			for _, line := range wrapText(face, paragraph, width) {
+				y += helpLineH
+				lines = append(lines, helpLine{text: line, y: y})
+			}
+			y += helpLineH / 2
+		}
+	}
+	return lines, y + helpLineH/2
+}
+
+// updateHelp scrolls the rules
+func (g *Game) updateHelp() {
+	_, height := g.rulesLines()
+	g.helpScroll.update(helpRulesRect, height)
+}
+
+// drawHelp renders the rules next to the controls reference
+func (g *Game) drawHelp(screen *ebiten.Image) {
+	drawCenteredText(screen, "Rules & Controls", g.resources.GetLargeFont(), image.Rect(0, 20, ScreenWidth, 70), TextColor)
+
+	// Rules, scrolling when they do not fit
+	clip := g.helpScroll.clip(screen)
+	lines, _ := g.rulesLines()
+	for _, line := range lines {
+		y := helpRulesRect.Min.Y + line.y - g.helpScroll.offset
+		if line.title {
+			text.Draw(clip, line.text, g.resources.GetNormalFont(), helpRulesRect.Min.X, y, PanelBorderColor)
+		} else {
+			text.Draw(clip, line.text, g.resources.GetNormalFont(), helpRulesRect.Min.X+10, y, TextColor)
+		}
+	}
+	g.helpScroll.drawScrollbar(screen)
+
+	// Controls
+	y := helpRulesRect.Min.Y + helpSectionH
+	text.Draw(screen, "Controls", g.resources.GetNormalFont(), helpKeysX, y, PanelBorderColor)
+	for _, b := range g.keys.controls() {
+		y += helpLineH
+		text.Draw(screen, b.Keys, g.resources.GetSmallFont(), helpKeysX, y, BlackMoveColor)
+		for i, line := range wrapText(g.resources.GetSmallFont(), b.Action, ScreenWidth-helpActionX-40) {
+			if i > 0 {
+				y += helpLineH - 4
+			}
+			text.Draw(screen, line, g.resources.GetSmallFont(), helpActionX, y, TextColor)
+		}
+	}
+
+	drawCenteredText(screen, "Press ESC to go back", g.resources.GetSmallFont(), image.Rect(0, ScreenHeight-35, ScreenWidth, ScreenHeight-5), TextColor)
+}
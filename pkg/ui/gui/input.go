@@ -0,0 +1,99 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/help"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// actionHelp describes the bindable actions for the help screen
+var actionHelp = map[string]string{
+	config.ActionUndo:           "Take back your last move",
+	config.ActionHint:           "Show a suggested move",
+	config.ActionPass:           "Pass when you have no legal move",
+	config.ActionFullscreen:     "Toggle fullscreen",
+	config.ActionScreenshot:     "Save a screenshot",
+	config.ActionHelp:           "Show this help",
+	config.ActionToggleHistory:  "Toggle the history panel",
+	config.ActionToggleAnalysis: "Toggle the analysis panel",
+	config.ActionToggleChat:     "Toggle the chat panel",
+	config.ActionToggleEvalBar:  "Toggle the evaluation bar",
+	config.ActionDebugOverlay:   "Toggle the engine statistics overlay",
+	config.ActionBlunderAlert:   "Toggle the blunder alert practice mode",
+	config.ActionHistoryBack:    "Show the position before the one on the board",
+	config.ActionHistoryForward: "Show the next position, back to the game at the end",
+	config.ActionNextTab:        "Switch to the next game tab",
+	config.ActionPrevTab:        "Switch to the previous game tab",
+	config.ActionSaveGame:       "Save the game to a file",
+	config.ActionLoadGame:       "Load a saved game in a new tab",
+	config.ActionFindPosition:   "Find the position on the board in the library's games",
+	config.ActionPlayback:       "Play or pause a replay, or the demo game",
+	config.ActionPlaybackFaster: "Speed playback up",
+	config.ActionPlaybackSlower: "Slow playback down",
+	config.ActionPlaybackStep:   "Play one move of a paused replay or demo",
+	config.ActionBroadcast:      "Start or stop broadcasting the game as a live web page",
+	config.ActionOwnership:      "Toggle the overlay of who each square tends to end up with",
+	config.ActionRegions:        "Toggle the outlines of the empty regions and their parity",
+}
+
+// keymap maps actions to the keys triggering them
+// All keyboard shortcuts go through it so they can be remapped in the
+// settings file; only the standard Escape, Tab and Enter keys are fixed.
+type keymap map[string][]ebiten.Key
+
+// newKeymap resolves the key names in the settings, skipping unknown names
+func newKeymap(bindings map[string][]string) keymap {
+	k := make(keymap, len(bindings))
+	for action, names := range bindings {
+		for _, name := range names {
+			var key ebiten.Key
+			if err := key.UnmarshalText([]byte(name)); err != nil {
+				logging.For("gui").Warn("ignoring unknown key in settings", "action", action, "key", name)
+				continue
+			}
+			k[action] = append(k[action], key)
+		}
+	}
+	return k
+}
+
+// pressed reports whether a key bound to the action was just pressed, or
+// its button on the touch bar tapped
+func (k keymap) pressed(action string) bool {
+	if action == tappedAction {
+		return true
+	}
+	for _, key := range k[action] {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyNames describes the keys bound to an action, e.g. "Space / P"
+func (k keymap) keyNames(action string) string {
+	names := make([]string, len(k[action]))
+	for i, key := range k[action] {
+		names[i] = key.String()
+	}
+	if len(names) == 0 {
+		return "(unbound)"
+	}
+	return strings.Join(names, " / ")
+}
+
+// controls lists the GUI's controls with the current key bindings
+func (k keymap) controls() []help.Binding {
+	var bindings []help.Binding
+	for _, action := range config.Actions {
+		bindings = append(bindings, help.Binding{Keys: k.keyNames(action), Action: actionHelp[action]})
+	}
+	return append(bindings, help.GUIControls...)
+}
@@ -0,0 +1,135 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+)
+
+// layout holds the screen areas of the in-game view; hidden panels have
+// empty rectangles
+type layout struct {
+	board    image.Rectangle
+	evalBar  image.Rectangle
+	history  image.Rectangle
+	analysis image.Rectangle
+	chat     image.Rectangle
+	touchBar image.Rectangle // Buttons for the touch layout
+}
+
+// computeLayout places the board and the panels enabled in panels
+// Side panels share the column right of the board, with the history
+// getting twice the height of the others. Without side panels the board is
+// centered. The touch layout leaves out the analysis and chat panels for
+// the touch bar.
+func computeLayout(panels config.Layout, touch bool) layout {
+	var l layout
+
+	type slot struct {
+		rect   *image.Rectangle
+		weight int
+	}
+	var column []slot
+	if panels.History {
+		column = append(column, slot{&l.history, 2})
+	}
+	if panels.Analysis && !touch {
+		column = append(column, slot{&l.analysis, 1})
+	}
+	if panels.Chat && !touch {
+		column = append(column, slot{&l.chat, 1})
+	}
+	if touch {
+		column = append(column, slot{&l.touchBar, 1})
+	}
+
+	boardX := BoardMarginX
+	if len(column) == 0 {
+		boardX = (ScreenWidth - BoardSize) / 2
+	}
+	l.board = image.Rect(boardX, BoardMarginY, boardX+BoardSize, BoardMarginY+BoardSize)
+
+	if panels.EvalBar {
+		l.evalBar = image.Rect(boardX-EvalBarW-12, BoardMarginY, boardX-12, BoardMarginY+BoardSize)
+	}
+
+	totalWeight := 0
+	for _, s := range column {
+		totalWeight += s.weight
+	}
+	free := BoardSize - SidePanelGap*(len(column)-1)
+	y := BoardMarginY
+	for i, s := range column {
+		h := free * s.weight / max(totalWeight, 1)
+		if i == len(column)-1 {
+			h = BoardMarginY + BoardSize - y // Absorb rounding
+		}
+		*s.rect = image.Rect(SidePanelX, y, SidePanelX+SidePanelW, y+h)
+		y += h + SidePanelGap
+	}
+	return l
+}
+
+// cellRect returns the screen area of a board square
+func (l layout) cellRect(row, col int) image.Rectangle {
+	x := l.board.Min.X + col*CellSize
+	y := l.board.Min.Y + row*CellSize
+	return image.Rect(x, y, x+CellSize, y+CellSize)
+}
+
+// cellAt returns the board square under a screen point
+func (l layout) cellAt(x, y int) (row, col int, ok bool) {
+	if !image.Pt(x, y).In(l.board) {
+		return -1, -1, false
+	}
+	return (y - l.board.Min.Y) / CellSize, (x - l.board.Min.X) / CellSize, true
+}
+
+// panelActions are the actions toggling the in-game panels
+var panelActions = []struct {
+	action string
+	toggle func(*config.Layout)
+}{
+	{config.ActionToggleHistory, func(l *config.Layout) { l.History = !l.History }},
+	{config.ActionToggleAnalysis, func(l *config.Layout) { l.Analysis = !l.Analysis }},
+	{config.ActionToggleChat, func(l *config.Layout) { l.Chat = !l.Chat }},
+	{config.ActionToggleEvalBar, func(l *config.Layout) { l.EvalBar = !l.EvalBar }},
+}
+
+// updatePanelToggles switches panels on and off from the keyboard and saves
+// the new layout to the settings file
+func (g *Game) updatePanelToggles() {
+	cfg := g.applied
+	changed := false
+	for _, pa := range panelActions {
+		if g.keys.pressed(pa.action) {
+			pa.toggle(&cfg.Layout)
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	g.applySettings(cfg)
+	g.saveSettings()
+}
+
+// saveSettings writes the applied settings to the settings file so they
+// survive a restart
+func (g *Game) saveSettings() {
+	w := g.options.Settings
+	if w == nil {
+		return
+	}
+	log := logging.For("gui")
+	if err := config.Save(w.Path(), g.applied); err != nil {
+		log.Error("failed to save settings", "file", w.Path(), "err", err)
+		return
+	}
+	if err := w.Reload(); err != nil {
+		log.Warn("failed to reload saved settings", "file", w.Path(), "err", err)
+	}
+}
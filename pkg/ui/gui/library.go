@@ -0,0 +1,288 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// librarySearchRect is the Library screen's search field
+var librarySearchRect = image.Rect(myGamesListX, 84, myGamesListX+myGamesRowW, 120)
+
+// libraryView lists the saved games while the analyzer annotates them in the
+// background
+type libraryView struct {
+	lib      *library.Library
+	analyzer *library.Analyzer
+	list     scrollView
+	search   *TextInput
+
+	// The games listed for the current search, until it or the library
+	// changes
+	shown     []*library.Game
+	query     string
+	board     *model.Board // Position searched for, nil when none
+	searchErr string
+	changed   <-chan struct{}
+
+	menu, statsMenu *Form
+
+	stop context.CancelFunc // Stops the analyzer
+}
+
+// newLibraryView shows the library and starts analyzing its games
+func newLibraryView(lib *library.Library, depth int) *libraryView {
+	ctx, stop := context.WithCancel(context.Background())
+	v := &libraryView{lib: lib, analyzer: library.NewAnalyzer(lib, depth), stop: stop}
+	go v.analyzer.Run(ctx)
+	return v
+}
+
+// games returns the games matching the search, or every game while it is
+// empty
+func (v *libraryView) games() []*library.Game {
+	query := strings.TrimSpace(v.search.Text)
+	select {
+	case <-v.changed:
+		v.shown = nil
+	default:
+	}
+	if v.shown != nil && query == v.query {
+		return v.shown
+	}
+
+	v.changed = v.lib.Changed()
+	v.query, v.board, v.searchErr = query, nil, ""
+	v.shown = []*library.Game{}
+	if query == "" {
+		v.shown = v.lib.List()
+		return v.shown
+	}
+	q, err := library.ParseQuery(query)
+	if err != nil {
+		v.searchErr = err.Error()
+		return v.shown
+	}
+	v.board = q.Board
+	if found := v.lib.Search(q); found != nil {
+		v.shown = found
+	}
+	return v.shown
+}
+
+// findPosition searches the library for the position on the board, live or
+// browsed. The game stays open: Escape returns to it and a game opened from
+// the results gets a new tab.
+func (g *Game) findPosition() {
+	if g.library == nil {
+		g.flash("Position search needs the game library")
+		return
+	}
+	board := g.othelloGame.Board
+	if g.browsing() {
+		board = g.browse.board
+	}
+	g.library.search.SetText(fmt.Sprintf("pos:%q", board.PositionString()))
+	g.leaveSession()
+	g.newTabPending = true
+	g.gameState = StateLibrary
+}
+
+// openLibraryGame shows a library game for replay, at the searched position
+// when the search has one
+func (g *Game) openLibraryGame(saved *library.Game) {
+	if !g.canOpenGame() {
+		return
+	}
+	game, err := saved.Record.Replay()
+	if err != nil {
+		logging.For("gui").Error("failed to open library game", "id", saved.ID, "err", err)
+		g.showNotice("Could not open the game", err.Error())
+		return
+	}
+	name := fmt.Sprintf("%s vs %s", saved.PlayerName(model.Black), saved.PlayerName(model.White))
+	g.openGames(name, []*model.Game{game})
+	if g.library.board != nil {
+		if ply := g.library.lib.PositionPly(saved, g.library.board); ply >= 0 {
+			g.browseTo(ply)
+		}
+	}
+}
+
+// saveToLibrary stores a finished local game in the library
+func (g *Game) saveToLibrary() {
+	if g.library == nil {
+		return
+	}
+	g.savedID = ""
+	if g.gameMode == ModeCorrespondence {
+		return
+	}
+	record := model.NewGameRecord(g.othelloGame)
+	for _, side := range []model.Piece{model.Black, model.White} {
+		if g.profileSide(side) {
+			if record.Metadata == nil {
+				record.Metadata = make(map[string]string)
+			}
+			// Named so the statistics tell the profile's users apart
+			record.Metadata[strings.ToLower(model.GetPieceName(side))+".name"] = g.profile.Name
+		}
+	}
+	if g.teams != nil {
+		if record.Metadata == nil {
+			record.Metadata = make(map[string]string)
+		}
+		g.recordTeams(record.Metadata)
+	}
+	saved, err := g.library.lib.Add(record)
+	if err != nil {
+		logging.For("gui").Error("failed to save game to the library", "err", err)
+		g.flash("Could not save the game: " + err.Error())
+		return
+	}
+	g.savedID = saved.ID
+}
+
+// gameOverAccuracy describes how well both sides played the game just
+// finished, once the library has analyzed it
+func (g *Game) gameOverAccuracy() string {
+	if g.library == nil || g.savedID == "" {
+		return ""
+	}
+	game, err := g.library.lib.Get(g.savedID)
+	if err != nil {
+		return ""
+	}
+	if game.Analysis == nil {
+		if g.library.analyzer.Pending(game) {
+			return "Analyzing the game..."
+		}
+		return ""
+	}
+
+	var parts []string
+	for _, side := range []model.Piece{model.Black, model.White} {
+		accuracy, ok := game.Analysis.Accuracy(side)
+		if !ok {
+			continue
+		}
+		loss, _ := game.Analysis.AverageLoss(side)
+		parts = append(parts, fmt.Sprintf("%s %.1f discs (%.0f%%)", model.GetPieceName(side), loss, accuracy))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Average loss per move: " + strings.Join(parts, ", ")
+}
+
+// openLibrary shows the list of saved games
+func (g *Game) openLibrary() {
+	g.gameState = StateLibrary
+}
+
+// updateLibrary scrolls the game list
+func (g *Game) updateLibrary() {
+	g.library.menu.Update()
+	games := g.library.games()
+	g.library.list.update(myGamesListRect, len(games)*(myGamesRowH+myGamesRowGap)-myGamesRowGap)
+
+	// Releasing a drag is not a click
+	if !pointerJustReleased() || g.library.list.scrolledByDrag() {
+		return
+	}
+	mouse := image.Pt(cursorPosition())
+	if !mouse.In(myGamesListRect) {
+		return
+	}
+	for i, game := range games {
+		if mouse.In(g.libraryRowRect(i)) {
+			g.openLibraryGame(game)
+			return
+		}
+	}
+}
+
+// libraryRowRect returns the rectangle of the i-th list row on screen
+func (g *Game) libraryRowRect(i int) image.Rectangle {
+	y := myGamesListY + i*(myGamesRowH+myGamesRowGap) - g.library.list.offset
+	return image.Rect(myGamesListX, y, myGamesListX+myGamesRowW, y+myGamesRowH)
+}
+
+// analysisState describes how far the analysis of a saved game is
+func (v *libraryView) analysisState(game *library.Game) string {
+	switch {
+	case v.analyzer.Current() == game.ID:
+		return "Analyzing..."
+	case game.Analysis == nil && v.analyzer.Pending(game):
+		return "Waiting for analysis"
+	case game.Analysis == nil:
+		return "Not analyzed"
+	}
+
+	black, okBlack := game.Analysis.Accuracy(model.Black)
+	white, okWhite := game.Analysis.Accuracy(model.White)
+	if !okBlack || !okWhite {
+		return "No moves to rate"
+	}
+	blackLoss, _ := game.Analysis.AverageLoss(model.Black)
+	whiteLoss, _ := game.Analysis.AverageLoss(model.White)
+	return fmt.Sprintf("%.0f%% / %.0f%%, loss %.1f / %.1f", black, white, blackLoss, whiteLoss)
+}
+
+// drawLibrary renders the list of saved games with their accuracy
+func (g *Game) drawLibrary(screen *ebiten.Image) {
+	titleText := "Library"
+	bounds := textBounds(g.resources.GetLargeFont(), titleText)
+	drawLabel(screen, titleText, g.resources.GetLargeFont(), (ScreenWidth-fixedToIntWidth(bounds))/2, 60, TextColor)
+	g.library.menu.Draw(screen, g.resources)
+
+	games := g.library.games()
+	if len(games) == 0 {
+		status := "Finished games are saved here"
+		switch {
+		case g.library.searchErr != "":
+			status = "Cannot search: " + g.library.searchErr
+		case g.library.query != "":
+			status = "No games match the search"
+		}
+		bounds = textBounds(g.resources.GetNormalFont(), status)
+		text.Draw(screen, status, g.resources.GetNormalFont(), (ScreenWidth-fixedToIntWidth(bounds))/2, myGamesListY+30, TextColor)
+	}
+
+	list := g.library.list.clip(screen)
+	for i, game := range games {
+		rect := g.libraryRowRect(i)
+		if !rect.Overlaps(myGamesListRect) {
+			continue
+		}
+		drawRect(list, rect, PanelBackColor)
+
+		line := fmt.Sprintf("%s  %s vs %s  %s", game.Saved.Format("2006-01-02 15:04"),
+			game.PlayerName(model.Black), game.PlayerName(model.White), game.Record.Result)
+		if board := g.library.board; board != nil {
+			line += fmt.Sprintf("  (at move %d)", g.library.lib.PositionPly(game, board))
+		}
+		if game.Assisted(model.Black) || game.Assisted(model.White) {
+			line += "  (assisted)"
+		}
+		text.Draw(list, line, g.resources.GetNormalFont(), rect.Min.X+15, rect.Min.Y+25, TextColor)
+
+		state := g.library.analysisState(game)
+		bounds = textBounds(g.resources.GetNormalFont(), state)
+		text.Draw(list, state, g.resources.GetNormalFont(), rect.Max.X-fixedToIntWidth(bounds)-15, rect.Min.Y+25, TextColor)
+	}
+	g.library.list.drawScrollbar(screen)
+
+	escText := "Click a game to replay it - Accuracy and discs lost per move are Black / White - ESC returns"
+	bounds = textBounds(g.resources.GetSmallFont(), escText)
+	drawLabel(screen, escText, g.resources.GetSmallFont(), (ScreenWidth-fixedToIntWidth(bounds))/2, ScreenHeight-20, TextColor)
+}
@@ -0,0 +1,410 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/correspondence"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/notify"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// My Games screen layout
+const (
+	myGamesListX    = 100
+	myGamesListY    = 130
+	myGamesRowW     = ScreenWidth - 2*myGamesListX
+	myGamesRowH     = 40
+	myGamesRowGap   = 8
+	myGamesPollWait = 30 * time.Second
+)
+
+// myGamesListRect is the scrolling area holding the game rows, with room for
+// the scrollbar on the right
+var myGamesListRect = image.Rect(myGamesListX, myGamesListY, myGamesListX+myGamesRowW+12, ScreenHeight-40)
+
+// menuButtonRect is the first main menu button below Start Game; the others
+// follow it down the screen
+var menuButtonRect = image.Rect(ScreenWidth/2-100, ScreenHeight/2+45, ScreenWidth/2+100, ScreenHeight/2+95)
+
+// correspondenceView keeps the player's correspondence games up to date by
+// long-polling the server in the background
+type correspondenceView struct {
+	client *correspondence.Client
+
+	mu      sync.Mutex
+	games   map[string]*correspondence.Game
+	status  string
+	polling bool
+	offline bool     // The last poll failed
+	notices []string // Messages for the player not shown yet
+
+	// Tells the player of their turns while the window is minimized
+	notifier *notify.Notifier
+
+	// Number of stored moves the board reflects, per game open in a tab
+	synced map[string]int
+
+	list scrollView
+}
+
+// newCorrespondenceView creates a view for the given server and player
+func newCorrespondenceView(url, player, secret string) *correspondenceView {
+	return &correspondenceView{
+		client: correspondence.NewClient(url, player, secret),
+		games:  make(map[string]*correspondence.Game),
+		synced: make(map[string]int),
+		status: "Loading games...",
+	}
+}
+
+// startPolling launches the background poller once
+func (v *correspondenceView) startPolling() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.polling {
+		return
+	}
+	v.polling = true
+	go v.poll()
+}
+
+// poll waits for game changes on the server for as long as the GUI runs
+func (v *correspondenceView) poll() {
+	var since time.Time
+	for {
+		resp, err := v.client.List(context.Background(), since, myGamesPollWait)
+		if err != nil {
+			logging.For("gui").Warn("correspondence poll failed", "err", err)
+			v.mu.Lock()
+			v.status = "Server unavailable, retrying..."
+			if !v.offline {
+				v.offline = true
+				v.notices = append(v.notices, "Lost connection to the correspondence server. Retrying in the background.")
+			}
+			v.mu.Unlock()
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
+		v.mu.Lock()
+		var turns []notify.Turn
+		for _, game := range resp.Games {
+			// The first poll loads the games rather than bringing news
+			if turn, ok := v.turnIn(v.games[game.ID], game); ok && !since.IsZero() {
+				turns = append(turns, turn)
+			}
+			v.games[game.ID] = game
+		}
+		v.status = ""
+		v.offline = false
+		notifier := v.notifier
+		v.mu.Unlock()
+		since = resp.Now
+
+		// A slow webhook must not hold up the next poll
+		if notifier.Enabled() && len(turns) > 0 && ebiten.IsWindowMinimized() {
+			go func() {
+				for _, turn := range turns {
+					if err := notifier.YourTurn(turn); err != nil {
+						logging.For("gui").Warn("turn notification failed", "game", turn.Game, "err", err)
+					}
+				}
+			}()
+		}
+	}
+}
+
+// turnIn returns the notification for a game that has become the player's
+// move since the last copy of it, old, which is nil for a new game
+func (v *correspondenceView) turnIn(old, game *correspondence.Game) (notify.Turn, bool) {
+	player := v.client.Player
+	color := game.ColorOf(player)
+	if color == model.Empty || game.ToMove() != color || (old != nil && len(old.Moves) == len(game.Moves)) {
+		return notify.Turn{}, false
+	}
+	turn := notify.Turn{
+		Kind:     notify.KindCorrespondence,
+		Game:     game.ID,
+		Player:   player,
+		Opponent: game.Opponent(player),
+		Color:    model.GetPieceName(color),
+	}
+	if n := len(game.Moves); n > 0 {
+		turn.Move = game.Moves[n-1]
+	}
+	return turn, true
+}
+
+// setNotifier changes how the player is told of their turns
+func (v *correspondenceView) setNotifier(n *notify.Notifier) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.notifier = n
+}
+
+// takeNotice removes and returns the oldest unseen notice
+func (v *correspondenceView) takeNotice() (string, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.notices) == 0 {
+		return "", false
+	}
+	notice := v.notices[0]
+	v.notices = v.notices[1:]
+	return notice, true
+}
+
+// showCorrespondenceNotices shows the next correspondence notice, if any
+func (g *Game) showCorrespondenceNotices() {
+	if g.corr == nil {
+		return
+	}
+	if notice, ok := g.corr.takeNotice(); ok {
+		g.showNotice("Correspondence", notice)
+	}
+}
+
+// sortedGames returns the games with those awaiting our move first
+func (v *correspondenceView) sortedGames() []*correspondence.Game {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	games := make([]*correspondence.Game, 0, len(v.games))
+	for _, game := range v.games {
+		games = append(games, game)
+	}
+
+	player := v.client.Player
+	sort.Slice(games, func(i, j int) bool {
+		iTurn := games[i].ToMove() == games[i].ColorOf(player)
+		jTurn := games[j].ToMove() == games[j].ColorOf(player)
+		if iTurn != jTurn {
+			return iTurn
+		}
+		return games[i].Updated.After(games[j].Updated)
+	})
+	return games
+}
+
+// game returns the latest copy of a game
+func (v *correspondenceView) game(id string) *correspondence.Game {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.games[id]
+}
+
+// submit sends a move on the given game in the background
+func (v *correspondenceView) submit(id string, row, col int) {
+	move := model.FormatMove(row, col)
+
+	go func() {
+		game, err := v.client.Play(context.Background(), id, move)
+		if err != nil {
+			logging.For("gui").Warn("correspondence move refused", "game", id, "move", move, "err", err)
+			message := fmt.Sprintf("Move %s was not accepted: %v", move, err)
+			// Force the board to reload from the last known server state
+			v.mu.Lock()
+			v.status = message
+			v.notices = append(v.notices, message)
+			v.synced[id] = -1
+			v.mu.Unlock()
+			return
+		}
+
+		v.mu.Lock()
+		v.games[game.ID] = game
+		v.mu.Unlock()
+	}()
+}
+
+// openMyGames shows the list of correspondence games
+func (g *Game) openMyGames() {
+	g.corr.startPolling()
+	g.gameState = StateMyGames
+}
+
+// updateMyGames scrolls the game list and handles clicks on it
+func (g *Game) updateMyGames() {
+	games := g.corr.sortedGames()
+	g.corr.list.update(myGamesListRect, len(games)*(myGamesRowH+myGamesRowGap)-myGamesRowGap)
+
+	// Releasing a drag is not a click
+	if !pointerJustReleased() || g.corr.list.scrolledByDrag() {
+		return
+	}
+
+	mouse := image.Pt(cursorPosition())
+	if !mouse.In(myGamesListRect) {
+		return
+	}
+	for i, game := range games {
+		if mouse.In(g.myGamesRowRect(i)) {
+			g.openCorrespondenceGame(game)
+			return
+		}
+	}
+}
+
+// myGamesRowRect returns the rectangle of the i-th list row on screen
+func (g *Game) myGamesRowRect(i int) image.Rectangle {
+	y := myGamesListY + i*(myGamesRowH+myGamesRowGap) - g.corr.list.offset
+	return image.Rect(myGamesListX, y, myGamesListX+myGamesRowW, y+myGamesRowH)
+}
+
+// openCorrespondenceGame shows a correspondence game on the board
+func (g *Game) openCorrespondenceGame(game *correspondence.Game) {
+	// A game already open in a tab is brought back rather than opened twice
+	for i, s := range g.sessions {
+		if s.gameMode == ModeCorrespondence && s.corrID == game.ID {
+			g.newTabPending = false
+			g.switchTab(i)
+			g.gameState = g.session.screen
+			return
+		}
+	}
+
+	g.beginSession()
+	g.gameMode = ModeCorrespondence
+	g.corrID = game.ID
+	g.corrColor = game.ColorOf(g.corr.client.Player)
+	g.corr.mu.Lock()
+	g.corr.synced[game.ID] = -1
+	g.corr.mu.Unlock()
+
+	g.othelloGame = model.NewGame()
+	g.resetClocks()
+	g.gameState = StateInGame
+
+	g.syncCorrespondenceGame()
+}
+
+// syncCorrespondenceGame reloads the open game when the server has moves the
+// board does not show yet
+func (g *Game) syncCorrespondenceGame() {
+	game := g.corr.game(g.corrID)
+	if game == nil {
+		return
+	}
+
+	g.corr.mu.Lock()
+	synced, ok := g.corr.synced[game.ID]
+	upToDate := ok && synced == len(game.Moves)
+	g.corr.mu.Unlock()
+	if upToDate && !game.IsOver() {
+		return
+	}
+
+	replayed, err := game.Replay()
+	if err != nil {
+		logging.For("gui").Error("correspondence game does not replay", "game", game.ID, "err", err)
+		return
+	}
+
+	// Resignations and timeouts end the game without a final position
+	if game.IsOver() && !replayed.GameOver {
+		winner := model.Empty
+		switch game.Winner {
+		case "Black":
+			winner = model.Black
+		case "White":
+			winner = model.White
+		}
+		replayed.EndEarly(winner, game.EndReason)
+	}
+
+	g.othelloGame = replayed
+	g.validMoves = replayed.GetValidMoves()
+	if n := len(replayed.History); n > 0 {
+		last := replayed.History[n-1].Position
+		g.lastMoveX, g.lastMoveY = last.Col, last.Row
+	}
+
+	g.corr.mu.Lock()
+	g.corr.synced[game.ID] = len(game.Moves)
+	g.corr.mu.Unlock()
+}
+
+// drawMyGames renders the list of correspondence games
+func (g *Game) drawMyGames(screen *ebiten.Image) {
+	titleText := "My Games"
+	bounds := textBounds(g.resources.GetLargeFont(), titleText)
+	drawLabel(screen, titleText, g.resources.GetLargeFont(), (ScreenWidth-fixedToIntWidth(bounds))/2, 60, TextColor)
+
+	g.corr.mu.Lock()
+	status := g.corr.status
+	g.corr.mu.Unlock()
+
+	games := g.corr.sortedGames()
+	if status == "" && len(games) == 0 {
+		status = "No correspondence games yet"
+	}
+	if status != "" {
+		bounds = textBounds(g.resources.GetNormalFont(), status)
+		text.Draw(screen, status, g.resources.GetNormalFont(), (ScreenWidth-fixedToIntWidth(bounds))/2, 100, TextColor)
+	}
+
+	mouse := image.Pt(cursorPosition())
+	now := time.Now()
+	player := g.corr.client.Player
+
+	list := g.corr.list.clip(screen)
+	for i, game := range games {
+		rect := g.myGamesRowRect(i)
+		if !rect.Overlaps(myGamesListRect) {
+			continue
+		}
+
+		rowColor := PanelBackColor
+		if mouse.In(rect) && mouse.In(myGamesListRect) {
+			rowColor = HoverColor
+		}
+		drawRect(list, rect, rowColor)
+
+		color := game.ColorOf(player)
+		line := fmt.Sprintf("vs %-16s as %-5s  %d moves", game.Opponent(player), model.GetPieceName(color), len(game.Moves))
+		text.Draw(list, line, g.resources.GetNormalFont(), rect.Min.X+15, rect.Min.Y+25, TextColor)
+
+		var state string
+		switch {
+		case game.IsOver():
+			state = fmt.Sprintf("Finished (%s): %s", game.EndReason, game.Winner)
+			if game.Winner == "Empty" {
+				state = "Finished: draw"
+			}
+		case game.ToMove() == color:
+			state = "Your turn - " + formatTimeLeft(game.TimeLeft(now)) + " left"
+		default:
+			state = "Waiting for opponent"
+		}
+		bounds = textBounds(g.resources.GetNormalFont(), state)
+		text.Draw(list, state, g.resources.GetNormalFont(), rect.Max.X-fixedToIntWidth(bounds)-15, rect.Min.Y+25, TextColor)
+	}
+	g.corr.list.drawScrollbar(screen)
+
+	escText := "Click a game to open it - Press ESC to return to main menu"
+	bounds = textBounds(g.resources.GetSmallFont(), escText)
+	drawLabel(screen, escText, g.resources.GetSmallFont(), (ScreenWidth-fixedToIntWidth(bounds))/2, ScreenHeight-20, TextColor)
+}
+
+// formatTimeLeft renders a correspondence deadline in days and hours
+func formatTimeLeft(d time.Duration) string {
+	if d <= 0 {
+		return "no time"
+	}
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, int(d.Minutes())%60)
+}
@@ -0,0 +1,111 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"context"
+	"image/color"
+	"sync"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/sim"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Ownership overlay tuning
+const (
+	ownershipPlayouts = 400
+	ownershipAlpha    = 170 // Tint of a square always owned by one color
+	ownershipInset    = 3
+)
+
+// ownershipView plays the position on the board out in the background and
+// keeps the result for the overlay
+type ownershipView struct {
+	mu       sync.Mutex
+	position string
+	cancel   context.CancelFunc
+	stats    sim.Ownership
+	ok       bool
+}
+
+// follow starts the playouts of board unless they are already running
+func (o *ownershipView) follow(board *model.Board) {
+	position := board.PositionString()
+	if o.cancel != nil && position == o.position {
+		return
+	}
+	o.stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	o.position, o.cancel = position, cancel
+	board = board.Clone()
+	go func() {
+		stats := sim.PlayoutOwnership(ctx, board, sim.OwnershipOptions{Playouts: ownershipPlayouts})
+		o.mu.Lock()
+		if ctx.Err() == nil {
+			o.stats, o.ok = stats, true
+		}
+		o.mu.Unlock()
+	}()
+}
+
+// stop cancels the running playouts and forgets their result
+func (o *ownershipView) stop() {
+	if o.cancel == nil {
+		return
+	}
+	o.mu.Lock()
+	o.cancel()
+	o.ok = false
+	o.mu.Unlock()
+	o.cancel = nil
+}
+
+// latest returns the result for the position on the board, once the
+// playouts have finished
+func (o *ownershipView) latest() (sim.Ownership, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.stats, o.ok
+}
+
+// updateOwnership toggles the overlay from the keyboard and keeps its
+// playouts on the position on the board while it is shown
+func (g *Game) updateOwnership() {
+	if g.keys.pressed(config.ActionOwnership) {
+		g.showOwnership = !g.showOwnership
+		if g.showOwnership {
+			g.flash("Ownership on: darker squares end up black more often, lighter ones white")
+		} else {
+			g.flash("Ownership off")
+		}
+	}
+	if !g.showOwnership || g.othelloGame.GameOver {
+		g.ownership.stop()
+		return
+	}
+	g.ownership.follow(g.shownBoard())
+}
+
+// drawOwnership tints every square by how often it ended up black or white
+// in the playouts, under the discs
+func (g *Game) drawOwnership(screen *ebiten.Image) {
+	if !g.showOwnership {
+		return
+	}
+	stats, ok := g.ownership.latest()
+	if !ok {
+		return
+	}
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			black, white := stats.Share(row, col)
+			cell := g.layout.cellRect(row, col).Inset(ownershipInset)
+			drawRect(screen, cell, color.RGBA{0, 0, 0, uint8(black * ownershipAlpha)})
+			light := uint8(white * ownershipAlpha)
+			drawRect(screen, cell, color.RGBA{light, light, light, light})
+		}
+	}
+}
@@ -0,0 +1,262 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+	"sync"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Analysis panel tuning
+const (
+	analysisDepth = 12 // The background search stops here
+	analysisPVLen = 8  // Principal variation moves shown
+)
+
+// drawPanel draws a framed panel with a title, returning the y coordinate
+// where its content starts
+func (g *Game) drawPanel(screen *ebiten.Image, panelRect image.Rectangle, title string) int {
+	drawRect(screen, panelRect, PanelBackColor)
+	drawOutline(screen, panelRect, 2, PanelBorderColor)
+
+	// Draw panel title
+	bounds := textBounds(g.resources.GetNormalFont(), title)
+	titleX := panelRect.Min.X + (panelRect.Dx()-fixedToIntWidth(bounds))/2
+	titleY := panelRect.Min.Y + PanelTitleH/2 + fixedToIntHeight(bounds)/3
+	drawLabel(screen, title, g.resources.GetNormalFont(), titleX, titleY, TextColor)
+
+	// Draw horizontal separator under title
+	top := panelRect.Min.Y + PanelTitleH
+	drawRect(screen, image.Rect(panelRect.Min.X, top, panelRect.Max.X, top+1), PanelBorderColor)
+	return top
+}
+
+// drawPanelNote centers a short message in an otherwise empty panel
+func (g *Game) drawPanelNote(screen *ebiten.Image, panelRect image.Rectangle, top int, note string) {
+	bounds := textBounds(g.resources.GetSmallFont(), note)
+	x := panelRect.Min.X + (panelRect.Dx()-fixedToIntWidth(bounds))/2
+	y := min(top+50, (top+panelRect.Max.Y)/2)
+	text.Draw(screen, note, g.resources.GetSmallFont(), x, y, TextColor)
+}
+
+// analysisView searches the position on the board in the background for the
+// analysis panel and the evaluation bar
+type analysisView struct {
+	position string             // Position being searched
+	cancel   context.CancelFunc // nil when no search runs
+
+	mu   sync.Mutex
+	info ai.Info
+	ok   bool // Whether info describes the current position
+
+	// Win/draw/loss proof, run alongside the search late in the game
+	side    model.Piece
+	outcome ai.Outcome
+	solved  bool
+}
+
+// follow starts searching board unless its position is already searched
+func (a *analysisView) follow(board *model.Board) {
+	position := board.PositionString()
+	if a.cancel != nil && position == a.position {
+		return
+	}
+	a.stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.position, a.cancel = position, cancel
+	a.side = board.CurrentPlayer
+
+	player := &ai.Player{Difficulty: ai.Hard, Piece: board.CurrentPlayer, Weights: ai.DefaultWeights}
+	if board.Size*board.Size-board.BlackCnt-board.WhiteCnt <= ai.WDLEmpties {
+		board := board.Clone()
+		go func() {
+			outcome, ok := player.SolveWDL(ctx, board)
+			a.mu.Lock()
+			if ok && ctx.Err() == nil {
+				a.outcome, a.solved = outcome, true
+			}
+			a.mu.Unlock()
+		}()
+	}
+
+	// A position analyzed before is shown at once, and needs no search if
+	// it was analyzed deep enough
+	cache := ai.DefaultCache
+	var cached ai.Info
+	if cache != nil {
+		if info, ok := cache.Get(board); ok {
+			cached = info
+			a.mu.Lock()
+			a.info, a.ok = info, true
+			a.mu.Unlock()
+		}
+	}
+	if cached.Depth >= analysisDepth || cached.IsMate() {
+		return
+	}
+
+	updates := player.AnalyzeStream(ctx, board, analysisDepth)
+	board = board.Clone()
+	go func() {
+		for info := range updates {
+			if info.Depth <= cached.Depth && !info.IsMate() {
+				continue
+			}
+			if cache != nil {
+				cache.Put(board, info)
+			}
+			a.mu.Lock()
+			// Results of a cancelled search describe an old position
+			if ctx.Err() == nil {
+				a.info, a.ok = info, true
+			}
+			a.mu.Unlock()
+		}
+	}()
+}
+
+// stop cancels the running search and forgets its result
+func (a *analysisView) stop() {
+	if a.cancel == nil {
+		return
+	}
+	a.mu.Lock()
+	a.cancel()
+	a.ok, a.solved = false, false
+	a.mu.Unlock()
+	a.cancel = nil
+}
+
+// latest returns the deepest result so far for the current position
+func (a *analysisView) latest() (ai.Info, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.info, a.ok
+}
+
+// proof returns the proven outcome of the current position for the side to
+// move, once the solver has finished
+func (a *analysisView) proof() (ai.Outcome, model.Piece, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.outcome, a.side, a.solved
+}
+
+// wantsAnalysis reports whether the settings use the background search: for
+// a panel or to judge the player's moves
+func wantsAnalysis(cfg config.Config) bool {
+	return cfg.Layout.Analysis || cfg.Layout.EvalBar || cfg.Training.BlunderAlert
+}
+
+// updateAnalysis keeps the background search on the position on the board
+// while something needs it
+func (g *Game) updateAnalysis() {
+	if !wantsAnalysis(g.applied) || g.othelloGame.GameOver {
+		g.analysis.stop()
+		return
+	}
+	g.analysis.follow(g.shownBoard())
+}
+
+// drawAnalysisPanel shows the engine's view of the current position
+func (g *Game) drawAnalysisPanel(screen *ebiten.Image, panelRect image.Rectangle) {
+	top := g.drawPanel(screen, panelRect, "Analysis")
+
+	info, ok := g.analysis.latest()
+	if !ok {
+		note := "Thinking..."
+		if g.othelloGame.GameOver {
+			note = "Game over"
+		}
+		g.drawPanelNote(screen, panelRect, top, note)
+		return
+	}
+
+	score := fmt.Sprintf("%+d", info.Score)
+	if info.IsMate() {
+		score += " (solved)"
+	}
+	best := "Pass"
+	if info.Best.Row >= 0 {
+		best = model.FormatMove(info.Best.Row, info.Best.Col)
+	}
+	pv := make([]string, 0, analysisPVLen)
+	for _, m := range info.PV[:min(len(info.PV), analysisPVLen)] {
+		if m.Row < 0 {
+			pv = append(pv, "--")
+		} else {
+			pv = append(pv, model.FormatMove(m.Row, m.Col))
+		}
+	}
+
+	lines := []string{
+		fmt.Sprintf("Depth %d   %d nodes", info.Depth, info.Nodes),
+		fmt.Sprintf("Score %s for %s", score, model.GetPieceName(info.Side)),
+		"Best move " + best,
+		strings.Join(pv, " "),
+	}
+	if outcome, side, ok := g.analysis.proof(); ok {
+		lines = append(lines, outcome.Describe(side))
+	}
+	x := panelRect.Min.X + 15
+	y := top + HistoryItemH
+	for _, line := range lines {
+		if y > panelRect.Max.Y-8 {
+			break
+		}
+		text.Draw(screen, line, g.resources.GetSmallFont(), x, y, TextColor)
+		y += HistoryItemH
+	}
+}
+
+// drawChatPanel shows the game's chat; local games have none
+func (g *Game) drawChatPanel(screen *ebiten.Image, panelRect image.Rectangle) {
+	top := g.drawPanel(screen, panelRect, "Chat")
+	g.drawPanelNote(screen, panelRect, top, "No chat in this game")
+}
+
+// drawEvalBar shows Black's share of the evaluation as the dark part of a
+// vertical bar, Black at the bottom
+func (g *Game) drawEvalBar(screen *ebiten.Image, barRect image.Rectangle) {
+	drawRect(screen, barRect, WhitePieceColor)
+
+	share := 0.5
+	if info, ok := g.analysis.latest(); ok {
+		score := info.Score
+		if info.Side == model.White {
+			score = -score
+		}
+		share = ai.WinProbability(score)
+	}
+	// A proven result fills the bar for the winner and is marked above it
+	if outcome, side, ok := g.analysis.proof(); ok {
+		label := "="
+		switch outcome.Winner(side) {
+		case model.Black:
+			share, label = 1, "B"
+		case model.White:
+			share, label = 0, "W"
+		default:
+			share = 0.5
+		}
+		labelRect := image.Rect(barRect.Min.X-6, barRect.Min.Y-24, barRect.Max.X+6, barRect.Min.Y-4)
+		drawCenteredText(screen, label, g.resources.GetSmallFont(), labelRect, HighlightColor)
+	}
+	h := int(math.Round(share * float64(barRect.Dy())))
+	drawRect(screen, image.Rect(barRect.Min.X, barRect.Max.Y-h, barRect.Max.X, barRect.Max.Y), BlackPieceColor)
+
+	// Mark the middle so small advantages are visible
+	mid := barRect.Min.Y + barRect.Dy()/2
+	drawRect(screen, image.Rect(barRect.Min.X-3, mid, barRect.Max.X+3, mid+1), PanelBorderColor)
+}
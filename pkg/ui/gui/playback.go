@@ -0,0 +1,110 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+)
+
+// playbackSpeeds are the speeds playback can run at, as multiples of its
+// normal pace
+var playbackSpeeds = []float64{0.5, 1, 2, 4, 8}
+
+// normalSpeed is the index of 1x in playbackSpeeds
+const normalSpeed = 1
+
+// replayInterval is the time between moves of a replay at 1x
+const replayInterval = time.Second
+
+// playback paces moves played by themselves, in a replay or the demo game:
+// it can be paused, stepped a move at a time and run from half to eight
+// times its normal pace. The zero value runs at 1x.
+type playback struct {
+	speed  int // Offset from normalSpeed in playbackSpeeds
+	paused bool
+	step   bool      // One move asked for while paused
+	last   time.Time // When the last move was played
+}
+
+// due reports whether the next move should be played now, interval being
+// the pause between moves at 1x. It starts the wait for the move after.
+func (p *playback) due(interval time.Duration) bool {
+	now := time.Now()
+	if p.last.IsZero() {
+		p.last = now
+	}
+	switch {
+	case p.step:
+		p.step = false
+	case p.paused:
+		return false
+	case now.Sub(p.last).Seconds() < interval.Seconds()/playbackSpeeds[normalSpeed+p.speed]:
+		return false
+	}
+	p.last = now
+	return true
+}
+
+// restart starts the wait for the next move afresh, e.g. after the player
+// moved through the game by hand
+func (p *playback) restart() {
+	p.last = time.Now()
+}
+
+// label describes the playback, e.g. "2x" or "Paused"
+func (p *playback) label() string {
+	if p.paused {
+		return "Paused"
+	}
+	return strconv.FormatFloat(playbackSpeeds[normalSpeed+p.speed], 'f', -1, 64) + "x"
+}
+
+// updatePlayback applies the playback keys to p, reporting whether one was
+// pressed
+func (g *Game) updatePlayback(p *playback) bool {
+	switch {
+	case g.keys.pressed(config.ActionPlayback):
+		p.paused = !p.paused
+		p.restart()
+	case g.keys.pressed(config.ActionPlaybackFaster):
+		p.speed = min(p.speed+1, len(playbackSpeeds)-1-normalSpeed)
+	case g.keys.pressed(config.ActionPlaybackSlower):
+		p.speed = max(p.speed-1, -normalSpeed)
+	case g.keys.pressed(config.ActionPlaybackStep):
+		p.paused, p.step = true, true
+	default:
+		return false
+	}
+	return true
+}
+
+// updateReplay plays a finished game loaded for replay through, a move at a
+// time; it starts paused at the final position
+func (g *Game) updateReplay() {
+	if !g.review {
+		return
+	}
+	if g.updatePlayback(&g.replay) && !g.replay.paused && !g.browsing() {
+		g.browseTo(0) // Played from the start when at the end
+		g.replay.restart()
+	}
+	if !g.browsing() {
+		g.replay.paused = true
+		return
+	}
+	if g.replay.due(replayInterval) {
+		g.browseTo(g.browse.ply + 1)
+	}
+}
+
+// replayStatus describes the replay for the status bar
+func (g *Game) replayStatus() string {
+	return fmt.Sprintf("Move %d of %d, %s - %s plays or pauses, %s / %s change the speed, %s steps",
+		g.browse.ply, len(g.othelloGame.History), g.replay.label(), g.keys.keyNames(config.ActionPlayback),
+		g.keys.keyNames(config.ActionPlaybackSlower), g.keys.keyNames(config.ActionPlaybackFaster),
+		g.keys.keyNames(config.ActionPlaybackStep))
+}
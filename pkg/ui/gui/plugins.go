@@ -0,0 +1,68 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/plugin"
+)
+
+// maxEngineButtons is how many installed engines fit on the mode menu
+const maxEngineButtons = 7
+
+// addEngineButtons lists the installed engines on the mode menu, in a
+// column to the right of the built-in modes
+func (g *Game) addEngineButtons(top, height, spacing int) {
+	engines := g.options.Engines
+	if len(engines) > maxEngineButtons {
+		logging.For("gui").Warn("too many engines for the menu", "shown", maxEngineButtons, "found", len(engines))
+		engines = engines[:maxEngineButtons]
+	}
+
+	y := top
+	for i, e := range engines {
+		i := i
+		rect := image.Rect(ScreenWidth/2+10, y, ScreenWidth/2+310, y+height)
+		g.modeMenu.Add(NewButton(rect, "Human vs "+e.Name, func() {
+			g.chosenEngine = i
+			g.startGame(ModeExternal)
+		}))
+		y += height + spacing
+	}
+}
+
+// startExternal starts the chosen engine to play piece in the game about to
+// begin, reporting why if it cannot
+func (g *Game) startExternal(piece model.Piece) (plugin.Player, error) {
+	e := g.options.Engines[g.chosenEngine]
+	player, err := e.New(piece)
+	if err != nil {
+		logging.For("gui").Error("failed to start engine", "engine", e.Name, "err", err)
+		return nil, fmt.Errorf("%s could not be started: %w", e.Name, err)
+	}
+	logging.For("gui").Info("engine started", "engine", e.Name, "color", model.GetPieceName(piece))
+	return player, nil
+}
+
+// stopExternal stops the session's engine, if it plays one
+func (s *session) stopExternal() {
+	if s.external == nil {
+		return
+	}
+	if err := s.external.Close(); err != nil {
+		logging.For("gui").Warn("engine did not stop cleanly", "engine", s.externalName, "err", err)
+	}
+	s.external = nil
+}
+
+// computerName names the computer player for its card, e.g. "AI (hard)"
+func (s *session) computerName() string {
+	if s.external != nil {
+		return s.externalName
+	}
+	return fmt.Sprintf("AI (%s)", s.aiPlayer.Difficulty)
+}
@@ -0,0 +1,78 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// pointer is what the player points with: the mouse, or the first finger
+// on a touch screen, so every screen works the same with either
+var pointer struct {
+	pos               image.Point
+	mouse             image.Point // Where the mouse was last frame
+	touch             ebiten.TouchID
+	touching          bool
+	pressed, released bool // This frame
+	moved             bool
+	touches           []ebiten.TouchID
+}
+
+// updatePointer reads the mouse and touch screen; call it at the start of
+// every update
+func updatePointer() {
+	p := &pointer
+	p.pressed, p.released = false, false
+	tappedAction = ""
+	last := p.pos
+
+	if p.touching {
+		if inpututil.IsTouchJustReleased(p.touch) {
+			p.touching, p.released = false, true // Released where it was last seen
+		} else {
+			p.pos = image.Pt(ebiten.TouchPosition(p.touch))
+		}
+	} else if p.touches = inpututil.AppendJustPressedTouchIDs(p.touches[:0]); len(p.touches) > 0 {
+		p.touch, p.touching, p.pressed = p.touches[0], true, true
+		p.pos = image.Pt(ebiten.TouchPosition(p.touch))
+	}
+
+	mouse := image.Pt(ebiten.CursorPosition())
+	mousePressed := inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft)
+	mouseReleased := inpututil.IsMouseButtonJustReleased(ebiten.MouseButtonLeft)
+	if !p.touching && (mouse != p.mouse || mousePressed || mouseReleased) {
+		p.pos = mouse
+	}
+	p.mouse = mouse
+	p.pressed = p.pressed || mousePressed
+	p.released = p.released || mouseReleased
+	p.moved = p.pos != last
+}
+
+// cursorPosition returns where the pointer is
+func cursorPosition() (int, int) {
+	return pointer.pos.X, pointer.pos.Y
+}
+
+// pointerJustPressed reports a click or a finger put down this frame
+func pointerJustPressed() bool {
+	return pointer.pressed
+}
+
+// pointerJustReleased reports a mouse button or finger lifted this frame
+func pointerJustReleased() bool {
+	return pointer.released
+}
+
+// pointerMoved reports whether the pointer moved this frame
+func pointerMoved() bool {
+	return pointer.moved
+}
+
+// pointerPressed reports whether the mouse button or a finger is down
+func pointerPressed() bool {
+	return pointer.touching || ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+}
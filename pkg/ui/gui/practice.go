@@ -0,0 +1,162 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// blunderCheckDepth is the deepest a move is searched to decide whether it
+// was a blunder, keeping the pause after a move short
+const blunderCheckDepth = 8
+
+// blunderCheck is a search judging the move the player just made
+type blunderCheck struct {
+	ply    int // Length of the history before the move
+	cancel context.CancelFunc
+	result chan blunderVerdict
+}
+
+// blunderVerdict compares a move with the best one
+type blunderVerdict struct {
+	move, best model.Position
+	loss       float64 // Discs the move is expected to cost
+	played     int     // Score after the move, for the mover
+	bestScore  int
+	line       []model.Position // Expected continuation after the best move
+}
+
+// blunderAlertOn reports whether moves are checked for blunders in this game
+func (g *Game) blunderAlertOn() bool {
+	return g.applied.Training.BlunderAlert && g.gameMode != ModeCorrespondence
+}
+
+// updateBlunderToggle switches the blunder alert on and off from the
+// keyboard and saves the setting
+func (g *Game) updateBlunderToggle() {
+	if !g.keys.pressed(config.ActionBlunderAlert) {
+		return
+	}
+	cfg := g.applied
+	cfg.Training.BlunderAlert = !cfg.Training.BlunderAlert
+	g.applySettings(cfg)
+	g.saveSettings()
+	if cfg.Training.BlunderAlert {
+		g.flash(fmt.Sprintf("Blunder alert on: moves losing over %.0f discs pause the game", cfg.Training.BlunderThreshold))
+	} else {
+		g.cancelBlunderCheck()
+		g.flash("Blunder alert off")
+	}
+}
+
+// checkBlunder starts judging a move made on board, the position before it.
+// The live analysis of that position is used when it has one, so usually
+// only the position after the move needs searching.
+func (g *Game) checkBlunder(board *model.Board, move model.Position, ply int) {
+	before, ok := g.analysis.latest()
+	if ok && before.Best == move {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	check := &blunderCheck{ply: ply, cancel: cancel, result: make(chan blunderVerdict, 1)}
+	g.blunder = check
+
+	board = board.Clone()
+	go func() {
+		depth := blunderCheckDepth
+		if ok && !before.IsMate() {
+			depth = min(before.Depth, blunderCheckDepth)
+		}
+		depth = max(depth, 2)
+		if !ok || (before.Depth != depth && !before.IsMate()) {
+			before = ai.EvaluatePositions(ctx, []*model.Board{board}, ai.BatchOptions{Depth: depth, Workers: 1})[0]
+		}
+
+		// The position after the move is searched one ply less, as in the
+		// library's game analysis
+		after := board.Clone()
+		after.MakeMove(move.Row, move.Col)
+		reply := ai.EvaluatePositions(ctx, []*model.Board{after}, ai.BatchOptions{Depth: depth - 1, Workers: 1})[0]
+		if ctx.Err() != nil {
+			return
+		}
+		played := reply.Score
+		if reply.Side != board.CurrentPlayer {
+			played = -played
+		}
+		check.result <- blunderVerdict{
+			move:      move,
+			best:      before.Best,
+			loss:      ai.Discs(before.Score) - ai.Discs(played),
+			played:    played,
+			bestScore: before.Score,
+			line:      before.PV,
+		}
+	}()
+}
+
+// cancelBlunderCheck abandons the check of the last move, if any
+func (g *Game) cancelBlunderCheck() {
+	if g.blunder != nil {
+		g.blunder.cancel()
+		g.blunder = nil
+	}
+}
+
+// updateBlunderCheck holds the game while the last move is being judged and
+// offers to take back a blunder, reporting whether the game is held
+func (g *Game) updateBlunderCheck() bool {
+	check := g.blunder
+	if check == nil {
+		return false
+	}
+	var verdict blunderVerdict
+	select {
+	case verdict = <-check.result:
+	default:
+		return true
+	}
+	g.blunder = nil
+	if verdict.loss <= g.applied.Training.BlunderThreshold || verdict.best.Row < 0 {
+		return false
+	}
+
+	g.showDialog(NewDialog("Blunder?", explainBlunder(verdict),
+		DialogButton{Text: "Retry", OnClick: func() { g.retryMove(check.ply) }},
+		DialogButton{Text: "Keep move"},
+	))
+	return true
+}
+
+// explainBlunder describes what the move cost and what was better
+func explainBlunder(v blunderVerdict) string {
+	line := make([]string, 0, 4)
+	for _, m := range v.line[:min(len(v.line), 4)] {
+		if m.Row < 0 {
+			line = append(line, "--")
+		} else {
+			line = append(line, model.FormatMove(m.Row, m.Col))
+		}
+	}
+	return fmt.Sprintf("%s loses about %.0f discs. %s was better (%+.0f discs for you instead of %+.0f); expected line: %s.",
+		model.FormatMove(v.move.Row, v.move.Col), v.loss, model.FormatMove(v.best.Row, v.best.Col),
+		ai.Discs(v.bestScore), ai.Discs(v.played), strings.Join(line, " "))
+}
+
+// retryMove takes back everything played since the history had ply entries
+// so the player can choose again
+func (g *Game) retryMove(ply int) {
+	for len(g.othelloGame.History) > ply {
+		if g.othelloGame.Undo() != nil {
+			break
+		}
+	}
+	g.afterTakeBack()
+}
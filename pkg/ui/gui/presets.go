@@ -0,0 +1,128 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"slices"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Preset buttons on the main menu, in a column on the left
+const (
+	maxMenuPresets = 6
+	presetButtonH  = 40
+	presetSpacing  = 10
+)
+
+// presetTitleRect is the heading of the preset buttons
+var presetTitleRect = image.Rect(30, ScreenHeight/2-70, 250, ScreenHeight/2-35)
+
+// buildPresetButtons lists the settings' presets on the main menu, replacing
+// the buttons of the previous settings
+func (g *Game) buildPresetButtons(presets []config.Preset) {
+	g.mainMenu.Widgets = slices.DeleteFunc(g.mainMenu.Widgets, func(w Widget) bool {
+		return slices.Contains(g.presetWidgets, w)
+	})
+	g.presetWidgets = nil
+	if len(presets) == 0 {
+		return
+	}
+
+	g.presetWidgets = append(g.presetWidgets, NewLabel(presetTitleRect, "Presets"))
+	rect := image.Rect(presetTitleRect.Min.X, presetTitleRect.Max.Y+presetSpacing, presetTitleRect.Max.X, presetTitleRect.Max.Y+presetSpacing+presetButtonH)
+	for _, p := range presets[:min(len(presets), maxMenuPresets)] {
+		p := p
+		g.presetWidgets = append(g.presetWidgets, NewButton(rect, p.Name, func() { g.startPreset(p) }))
+		rect = rect.Add(image.Pt(0, presetButtonH+presetSpacing))
+	}
+	g.mainMenu.Add(g.presetWidgets...)
+}
+
+// startPreset starts a game set up as the preset says, asking for a color
+// only if it names none
+func (g *Game) startPreset(p config.Preset) {
+	logging.For("gui").Info("preset chosen", "preset", p.Name)
+	if p.Theme != "" && p.Theme != g.applied.Theme {
+		applyTheme(p.Theme)
+		g.resources.initBoardImage()
+		clearTextCache()
+		g.applied.Theme = p.Theme // The settings' theme returns when they change
+	}
+
+	g.chosenPreset = &p
+	g.setup = setupSingle
+	mode := map[string]GameMode{
+		"human":   ModeHumanVsHuman,
+		ai.Easy:   ModeHumanVsEasyAI,
+		ai.Medium: ModeHumanVsMediumAI,
+		ai.Hard:   ModeHumanVsHardAI,
+	}[p.Opponent]
+	switch {
+	case mode == ModeHumanVsHuman || p.Color == "":
+		g.startGame(mode)
+	case p.Color == "white":
+		g.chosenMode = mode
+		g.beginGame(model.White)
+	default:
+		g.chosenMode = mode
+		g.beginGame(model.Black)
+	}
+}
+
+// startPresetNamed starts the preset given on the command line
+func (g *Game) startPresetNamed(name string) {
+	p, ok := g.applied.Preset(name)
+	if !ok {
+		g.showNotice("Unknown preset", fmt.Sprintf("The settings define no preset named %q.", name))
+		return
+	}
+	g.startPreset(p)
+}
+
+// startClocks sets the clocks of a game just started to the chosen preset's
+// time control, if it has one; the preset applies to this game only
+func (g *Game) startClocks() {
+	if g.chosenPreset == nil {
+		return
+	}
+	// Presets are validated with the settings
+	g.clockBase, g.clockIncrement, _ = g.chosenPreset.TimeControl()
+	g.chosenPreset = nil
+}
+
+// timeLeft returns the time a side has left on a running clock: the base
+// time and an increment for every move it made, less the time it used
+func (g *Game) timeLeft(piece model.Piece) time.Duration {
+	left := g.clockBase - g.clocks[piece]
+	for _, m := range g.othelloGame.History {
+		if m.Piece == piece {
+			left += g.clockIncrement
+		}
+	}
+	return left
+}
+
+// flagFall ends the game when the side to move has run out of time
+func (g *Game) flagFall() {
+	side := g.othelloGame.Board.CurrentPlayer
+	if g.clockBase > 0 && !g.othelloGame.GameOver && g.timeLeft(side) <= 0 {
+		logging.For("gui").Info("flag fell", "side", model.GetPieceName(side))
+		g.othelloGame.EndEarly(side.Opponent(), model.EndTimeout)
+	}
+}
+
+// computerClock returns the clock the computer paces its search by, or
+// false when the game has none
+func (g *Game) computerClock() (ai.Clock, bool) {
+	if g.clockBase == 0 || g.aiPlayer == nil {
+		return ai.Clock{}, false
+	}
+	return ai.Clock{Remaining: g.timeLeft(g.aiPlayer.Piece), Increment: g.clockIncrement}, true
+}
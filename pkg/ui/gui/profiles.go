@@ -0,0 +1,232 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/profile"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Profile picker layout
+const (
+	profileRowW   = 300
+	profileRowH   = 50
+	profileRowGap = 12
+	profileListY  = 150
+	profileMax    = 6 // Profiles listed; more are chosen with -profile
+	avatarGap     = 10
+)
+
+// profileButtonRect is the main menu button showing the profile in use,
+// which opens the picker
+var profileButtonRect = image.Rect(ScreenWidth-220, 20, ScreenWidth-20, 60)
+
+// profileView lets the local users pick their profile
+type profileView struct {
+	store    *profile.Store
+	profiles []profile.Profile
+	avatars  map[string]*ebiten.Image // By profile name, nil entries for none
+	name     *TextInput
+	menu     *Form
+
+	guest    Options         // Settings and library used without a profile
+	settings *config.Watcher // Of the profile in use, nil for the guest's
+}
+
+// newProfileView lists the profiles of the store
+func newProfileView(store *profile.Store, guest Options) *profileView {
+	v := &profileView{store: store, guest: guest}
+	v.reload()
+	return v
+}
+
+// reload lists the profiles again and loads their avatars
+func (v *profileView) reload() {
+	profiles, err := v.store.List()
+	if err != nil {
+		logging.For("gui").Error("failed to list profiles", "err", err)
+	}
+	v.profiles = profiles
+	v.avatars = make(map[string]*ebiten.Image, len(profiles))
+	for _, p := range profiles {
+		v.avatars[p.Name] = loadAvatar(p)
+	}
+}
+
+// loadAvatar returns a profile's avatar as an image to draw, nil when it has
+// none or it cannot be read
+func loadAvatar(p profile.Profile) *ebiten.Image {
+	img, err := p.Avatar()
+	if err != nil {
+		logging.For("gui").Warn("failed to load avatar", "profile", p.Name, "err", err)
+	}
+	if img == nil {
+		return nil
+	}
+	return ebiten.NewImageFromImage(img)
+}
+
+// profileRowRect returns the picker button of the i-th profile; the guest
+// button follows the profiles
+func profileRowRect(i int) image.Rectangle {
+	x := ScreenWidth/2 - profileRowW/2 + (profileRowH+avatarGap)/2
+	y := profileListY + i*(profileRowH+profileRowGap)
+	return image.Rect(x, y, x+profileRowW, y+profileRowH)
+}
+
+// buildProfileMenu creates the picker's buttons
+func (g *Game) buildProfileMenu() {
+	v := g.profiles
+	v.menu = NewForm()
+	shown := v.profiles[:min(len(v.profiles), profileMax)]
+	for i, p := range shown {
+		p := p
+		v.menu.Add(NewButton(profileRowRect(i), p.Name, func() { g.useProfile(&p) }))
+	}
+	v.menu.Add(NewButton(profileRowRect(len(shown)), "Guest", func() { g.useProfile(nil) }))
+
+	inputRect := profileRowRect(len(shown) + 1).Add(image.Pt(0, profileRowGap))
+	inputRect.Max.X -= 110
+	v.name = NewTextInput(inputRect, "New profile name")
+	addRect := image.Rect(inputRect.Max.X+10, inputRect.Min.Y, inputRect.Max.X+110, inputRect.Max.Y)
+	v.menu.Add(v.name, NewButton(addRect, "Add", g.addProfile))
+}
+
+// openProfiles shows the profile picker, unless a game is still being played
+// in a tab, since it would be saved to the other profile's library
+func (g *Game) openProfiles() {
+	for _, s := range g.sessions {
+		if s.othelloGame != nil && !s.othelloGame.GameOver && s.gameMode != ModeCorrespondence {
+			g.flash("Finish or close your games before switching profiles")
+			return
+		}
+	}
+	g.profiles.reload()
+	g.buildProfileMenu()
+	g.gameState = StateProfiles
+}
+
+// addProfile creates a profile with the name typed and switches to it
+func (g *Game) addProfile() {
+	p, err := g.profiles.store.Create(strings.TrimSpace(g.profiles.name.Text))
+	if err != nil {
+		g.showNotice("Could not add the profile", err.Error())
+		return
+	}
+	g.profiles.reload()
+	g.useProfile(&p)
+}
+
+// useProfile switches to a profile's settings, library and avatar, or with
+// nil back to those the game was started with, and goes to the main menu
+func (g *Game) useProfile(p *profile.Profile) {
+	v := g.profiles
+	opts := v.guest
+	var settings *config.Watcher
+	if p != nil {
+		var err error
+		if settings, err = config.Watch(p.ConfigPath(), 2*time.Second); err != nil {
+			g.showNotice("Could not open the profile", err.Error())
+			return
+		}
+		opts.Settings = settings
+		if opts.Library != nil {
+			lib, err := library.Open(p.LibraryDir())
+			if err != nil {
+				settings.Close()
+				g.showNotice("Could not open the profile", err.Error())
+				return
+			}
+			opts.Library = lib
+		}
+		if opts.PlayerName == "" {
+			opts.PlayerName = p.Name
+		}
+	}
+	if v.settings != nil {
+		v.settings.Close()
+	}
+	v.settings = settings
+
+	if g.library != nil && g.library.lib != opts.Library {
+		g.library.stop()
+		g.library = nil
+	}
+	if opts.Library != nil && g.library == nil {
+		depth := config.Default().Library.AnalysisDepth
+		if opts.Settings != nil {
+			depth = opts.Settings.Current().Library.AnalysisDepth
+		}
+		g.library = newLibraryView(opts.Library, depth)
+	}
+	g.options = opts
+	g.profile, g.avatar = p, nil
+	if p != nil {
+		g.avatar = v.avatars[p.Name]
+	}
+	g.buildMenus()
+	if opts.Settings != nil {
+		g.applySettings(opts.Settings.Current())
+		g.settings = opts.Settings.Subscribe()
+	}
+	logging.For("gui").Info("profile selected", "profile", g.profileName())
+	g.gameState = StateMainMenu
+}
+
+// profileName returns the name of the profile in use, "Guest" without one
+func (g *Game) profileName() string {
+	if g.profile == nil {
+		return "Guest"
+	}
+	return g.profile.Name
+}
+
+// profileSide reports whether the profile's user plays the given side of the
+// game on the screen, which is so against the AI
+func (g *Game) profileSide(piece model.Piece) bool {
+	return g.profile != nil && g.gameMode != ModeCorrespondence && g.aiPlayer != nil && g.aiPlayer.Piece != piece
+}
+
+// updateProfiles handles the profile picker
+func (g *Game) updateProfiles() {
+	g.profiles.menu.Update()
+}
+
+// drawProfiles renders the profile picker, each profile with its avatar
+func (g *Game) drawProfiles(screen *ebiten.Image) {
+	drawCenteredText(screen, "Who is playing?", g.resources.GetLargeFont(), image.Rect(0, 50, ScreenWidth, 110), TextColor)
+	v := g.profiles
+	for i, p := range v.profiles[:min(len(v.profiles), profileMax)] {
+		rect := profileRowRect(i)
+		avatar := image.Rect(rect.Min.X-avatarGap-profileRowH, rect.Min.Y, rect.Min.X-avatarGap, rect.Max.Y)
+		g.drawAvatar(screen, v.avatars[p.Name], p.Name, avatar)
+	}
+	v.menu.Draw(screen, g.resources)
+}
+
+// drawAvatar draws a profile's avatar scaled into a square, or the initial
+// of its name on a panel when it has none
+func (g *Game) drawAvatar(screen *ebiten.Image, avatar *ebiten.Image, name string, rect image.Rectangle) {
+	if avatar == nil {
+		drawRect(screen, rect, PanelBackColor)
+		drawOutline(screen, rect, 1, PanelBorderColor)
+		initial := strings.ToUpper(string([]rune(name)[:1]))
+		drawCenteredText(screen, initial, g.resources.GetLargeFont(), rect, TextColor)
+		return
+	}
+	size := avatar.Bounds().Size()
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(rect.Dx())/float64(size.X), float64(rect.Dy())/float64(size.Y))
+	op.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(avatar, op)
+	drawOutline(screen, rect, 1, PanelBorderColor)
+}
@@ -0,0 +1,97 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Region overlay tuning
+const (
+	regionLine  = 3 // Outline width
+	regionInset = 2
+)
+
+// Region outline colors by parity
+var (
+	oddRegionColor  = color.RGBA{255, 200, 0, 230}
+	evenRegionColor = color.RGBA{80, 160, 255, 230}
+)
+
+// updateRegions toggles the region overlay from the keyboard
+func (g *Game) updateRegions() {
+	if !g.keys.pressed(config.ActionRegions) {
+		return
+	}
+	g.showRegions = !g.showRegions
+	if g.showRegions {
+		g.flash("Regions on: gold outlines are odd regions, blue ones even")
+	} else {
+		g.flash("Regions off")
+	}
+}
+
+// drawRegions outlines the empty regions of the board on screen, colored by
+// parity and labeled with their size and who can play into them
+func (g *Game) drawRegions(screen *ebiten.Image) {
+	if !g.showRegions {
+		return
+	}
+	board := g.shownBoard()
+	regions := board.Regions()
+	owner := make(map[model.Position]int)
+	for i, r := range regions {
+		for _, p := range r.Squares {
+			owner[p] = i
+		}
+	}
+	inRegion := func(p model.Position, i int) bool {
+		j, ok := owner[p]
+		return ok && j == i
+	}
+
+	face := g.resources.GetSmallFont()
+	for i, r := range regions {
+		clr := evenRegionColor
+		if r.Odd() {
+			clr = oddRegionColor
+		}
+		for _, p := range r.Squares {
+			cell := g.layout.cellRect(p.Row, p.Col).Inset(regionInset)
+			if !inRegion(model.Position{Row: p.Row - 1, Col: p.Col}, i) {
+				drawRect(screen, image.Rect(cell.Min.X, cell.Min.Y, cell.Max.X, cell.Min.Y+regionLine), clr)
+			}
+			if !inRegion(model.Position{Row: p.Row + 1, Col: p.Col}, i) {
+				drawRect(screen, image.Rect(cell.Min.X, cell.Max.Y-regionLine, cell.Max.X, cell.Max.Y), clr)
+			}
+			if !inRegion(model.Position{Row: p.Row, Col: p.Col - 1}, i) {
+				drawRect(screen, image.Rect(cell.Min.X, cell.Min.Y, cell.Min.X+regionLine, cell.Max.Y), clr)
+			}
+			if !inRegion(model.Position{Row: p.Row, Col: p.Col + 1}, i) {
+				drawRect(screen, image.Rect(cell.Max.X-regionLine, cell.Min.Y, cell.Max.X, cell.Max.Y), clr)
+			}
+		}
+
+		first := g.layout.cellRect(r.Squares[0].Row, r.Squares[0].Col)
+		drawLabel(screen, regionLabel(r), face, first.Min.X+regionInset+regionLine+2, first.Min.Y+18, clr)
+	}
+}
+
+// regionLabel gives a region's size and, when only one side can play into
+// it, that side, e.g. "3 B"
+func regionLabel(r model.Region) string {
+	label := fmt.Sprint(len(r.Squares))
+	switch {
+	case r.BlackMoves > 0 && r.WhiteMoves == 0:
+		label += " B"
+	case r.WhiteMoves > 0 && r.BlackMoves == 0:
+		label += " W"
+	}
+	return label
+}
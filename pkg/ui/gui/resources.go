@@ -1,3 +1,5 @@
+//go:build !nogui
+
 package gui
 
 import (
@@ -5,7 +7,9 @@ import (
 	"image/color"
 	"math"
 
+	"github.com/amirhossein-jamali/othello/pkg/model"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/basicfont"
 )
@@ -14,6 +18,9 @@ import (
 type Resources struct {
 	fonts      *FontResources
 	boardImage *ebiten.Image
+
+	skin        *Skin // nil for the plain look
+	plainPieces map[model.Piece]*ebiten.Image
 }
 
 // FontResources contains font faces for different sizes
@@ -32,6 +39,10 @@ func NewResources() *Resources {
 			largeFont:  basicfont.Face7x13,
 		},
 		boardImage: ebiten.NewImage(BoardSize, BoardSize),
+		plainPieces: map[model.Piece]*ebiten.Image{
+			model.Black: newPlainPiece(BlackPieceColor),
+			model.White: newPlainPiece(WhitePieceColor),
+		},
 	}
 
 	// Initialize the board image
@@ -40,27 +51,25 @@ func NewResources() *Resources {
 	return res
 }
 
-// initBoardImage creates the base board image
+// initBoardImage creates the base board image from the skin or the theme
 func (r *Resources) initBoardImage() {
+	if r.skin != nil && r.skin.Board != nil {
+		r.boardImage.Clear()
+		b := r.skin.Board.Bounds()
+		op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+		op.GeoM.Scale(float64(BoardSize)/float64(b.Dx()), float64(BoardSize)/float64(b.Dy()))
+		r.boardImage.DrawImage(r.skin.Board, op)
+		return
+	}
+
 	// Fill with board color
 	r.boardImage.Fill(BoardColor)
 
 	// Draw grid lines with increased width
 	for i := 0; i <= 8; i++ {
-		// Calculate position as float for more precise drawing
-		pos := float64(i * CellSize)
-
-		// Draw horizontal grid lines
-		for j := 0; j < GridLineWidth; j++ {
-			offset := float64(j)
-			ebitenutil_DrawLine(r.boardImage, 0, pos+offset, BoardSize, pos+offset, GridColor)
-		}
-
-		// Draw vertical grid lines
-		for j := 0; j < GridLineWidth; j++ {
-			offset := float64(j)
-			ebitenutil_DrawLine(r.boardImage, pos+offset, 0, pos+offset, BoardSize, GridColor)
-		}
+		pos := i * CellSize
+		drawRect(r.boardImage, image.Rect(0, pos, BoardSize, pos+GridLineWidth), GridColor)
+		drawRect(r.boardImage, image.Rect(pos, 0, pos+GridLineWidth, BoardSize), GridColor)
 	}
 
 	// Add cell borders for better visualization
@@ -80,23 +89,22 @@ func (r *Resources) initBoardImage() {
 // drawCellBorder draws a border inside a cell
 func drawCellBorder(dst *ebiten.Image, x, y, width, height, borderWidth int, clr color.Color) {
 	// Top border
-	drawRectangle(dst, image.Rect(x, y, x+width, y+borderWidth), clr)
+	drawRect(dst, image.Rect(x, y, x+width, y+borderWidth), clr)
 	// Left border
-	drawRectangle(dst, image.Rect(x, y, x+borderWidth, y+height), clr)
+	drawRect(dst, image.Rect(x, y, x+borderWidth, y+height), clr)
 	// Right border
-	drawRectangle(dst, image.Rect(x+width-borderWidth, y, x+width, y+height), clr)
+	drawRect(dst, image.Rect(x+width-borderWidth, y, x+width, y+height), clr)
 	// Bottom border
-	drawRectangle(dst, image.Rect(x, y+height-borderWidth, x+width, y+height), clr)
+	drawRect(dst, image.Rect(x, y+height-borderWidth, x+width, y+height), clr)
 }
 
-// drawRectangle draws a filled rectangle
-func drawRectangle(dst *ebiten.Image, rect image.Rectangle, clr color.Color) {
-	rectImg := ebiten.NewImage(rect.Dx(), rect.Dy())
-	rectImg.Fill(clr)
-
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
-	dst.DrawImage(rectImg, op)
+// drawRect draws a filled rectangle
+// Primitives are drawn as triangles, so drawing them allocates no images.
+func drawRect(dst *ebiten.Image, rect image.Rectangle, clr color.Color) {
+	if rect.Empty() {
+		return
+	}
+	vector.DrawFilledRect(dst, float32(rect.Min.X), float32(rect.Min.Y), float32(rect.Dx()), float32(rect.Dy()), clr, false)
 }
 
 // GetBoardImage returns the board image
@@ -119,47 +127,30 @@ func (r *Resources) GetLargeFont() font.Face {
 	return r.fonts.largeFont
 }
 
-// ebitenutil_DrawLine draws a line (replacement for ebitenutil.DrawLine)
-func ebitenutil_DrawLine(dst *ebiten.Image, x1, y1, x2, y2 float64, clr color.Color) {
-	length := math.Hypot(x2-x1, y2-y1)
+// drawArrow draws an antialiased arrow from one point to another, its head
+// at to
+func drawArrow(dst *ebiten.Image, from, to image.Point, width float32, clr color.Color) {
+	dx, dy := float64(to.X-from.X), float64(to.Y-from.Y)
+	length := math.Hypot(dx, dy)
 	if length == 0 {
 		return
 	}
-
-	angle := math.Atan2(y2-y1, x2-x1)
-
-	width := 1.0 // Line width
-	height := float64(math.Max(1, length))
-
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Scale(width, height)
-	op.GeoM.Rotate(angle)
-	op.GeoM.Translate(x1, y1)
-	op.ColorM.ScaleWithColor(clr)
-
-	lineImg := ebiten.NewImage(1, 1)
-	lineImg.Fill(color.White)
-	dst.DrawImage(lineImg, op)
+	ux, uy := dx/length, dy/length
+	head := math.Min(float64(width)*3, length/2)
+
+	x0, y0 := float32(from.X), float32(from.Y)
+	x1, y1 := float32(to.X), float32(to.Y)
+	vector.StrokeLine(dst, x0, y0, x1-float32(ux*head/2), y1-float32(uy*head/2), width, clr, true)
+
+	// The head's two barbs, swept back from the tip
+	for _, side := range []float64{-1, 1} {
+		bx := float64(to.X) - head*ux + side*head*0.6*uy
+		by := float64(to.Y) - head*uy - side*head*0.6*ux
+		vector.StrokeLine(dst, x1, y1, float32(bx), float32(by), width, clr, true)
+	}
 }
 
-// drawCircle fills a circle at the specified position
+// drawCircle fills an antialiased circle at the specified position
 func drawCircle(dst *ebiten.Image, centerX, centerY, radius int, clr color.Color) {
-	diameter := radius * 2
-	circleImg := ebiten.NewImage(diameter, diameter)
-
-	for y := 0; y < diameter; y++ {
-		for x := 0; x < diameter; x++ {
-			dx := float64(x - radius)
-			dy := float64(y - radius)
-			distance := math.Sqrt(dx*dx + dy*dy)
-
-			if distance <= float64(radius) {
-				circleImg.Set(x, y, clr)
-			}
-		}
-	}
-
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(centerX-radius), float64(centerY-radius))
-	dst.DrawImage(circleImg, op)
+	vector.DrawFilledCircle(dst, float32(centerX), float32(centerY), float32(radius), clr, true)
 }
@@ -0,0 +1,88 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// updateResume keeps the resume file in step with the game on screen: saved
+// after every move while it is in progress, removed once there is none
+func (g *Game) updateResume() {
+	path := g.options.ResumeFile
+	if path == "" {
+		return
+	}
+	state := ""
+	if g.gameState == StateInGame && g.inProgress() {
+		state = fmt.Sprintf("%p %d", g.othelloGame, len(g.othelloGame.History))
+	}
+	if state == g.resumed {
+		return
+	}
+	g.resumed = state
+
+	log := logging.For("gui")
+	if state == "" {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Warn("failed to remove resume file", "file", path, "err", err)
+		}
+		return
+	}
+	if err := writeGameFile(path, g.othelloGame); err != nil {
+		log.Warn("failed to save resume file", "file", path, "err", err)
+	}
+}
+
+// resumeGame puts the game in the resume file back on the board, against
+// the computer player recorded in it if there was one
+func (g *Game) resumeGame() {
+	path := g.options.ResumeFile
+	games, err := readGameFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logging.For("gui").Warn("failed to read resume file", "file", path, "err", err)
+		}
+		return
+	}
+	game := games[0]
+	if game.GameOver {
+		return
+	}
+
+	g.beginSession()
+	g.gameMode = ModeHumanVsHuman
+	g.othelloGame = game
+	for _, piece := range []model.Piece{model.Black, model.White} {
+		// The game goes on with today's settings, so only the difficulty
+		// and seed are taken from it
+		difficulty, seed, err := ai.RecordedAI(game.Metadata, piece)
+		if err != nil || difficulty == "" {
+			continue
+		}
+		g.aiPlayer = ai.NewPlayer(difficulty, piece)
+		g.aiPlayer.Seed = seed
+		g.aiPlayer.Telemetry = g.telemetry
+		switch difficulty {
+		case ai.Easy:
+			g.gameMode = ModeHumanVsEasyAI
+		case ai.Medium:
+			g.gameMode = ModeHumanVsMediumAI
+		default:
+			g.gameMode = ModeHumanVsHardAI
+		}
+	}
+	g.markLastMove()
+	g.validMoves = game.GetValidMoves()
+	g.resetClocks()
+	g.gameState = StateInGame
+	logging.For("gui").Info("game resumed", "file", path, "moves", len(game.History))
+	g.flash("Resumed your last game")
+}
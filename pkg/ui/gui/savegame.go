@@ -0,0 +1,240 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ggf"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/transcript"
+	"github.com/amirhossein-jamali/othello/pkg/wthor"
+)
+
+// gameFileExts are the extensions of the game files that can be opened: JSON
+// records, GGF games, WTHOR databases and transcripts from online apps
+var gameFileExts = []string{".json", ".ggf", ".wtb", ".txt"}
+
+// savedGameExts are the extensions games are saved with
+var savedGameExts = gameFileExts[:1]
+
+// startDir returns the folder the next file dialog opens in: the last one a
+// file was chosen from, or the documents folder
+func (g *Game) startDir() string {
+	if g.fileDir != "" && isDir(g.fileDir) {
+		return g.fileDir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	if docs := filepath.Join(home, "Documents"); isDir(docs) {
+		return docs
+	}
+	return home
+}
+
+// saveGameFile asks where to save the game on the board and writes it there
+func (g *Game) saveGameFile() {
+	game := g.othelloGame
+	name := fmt.Sprintf("othello-%s.json", time.Now().Format("20060102-150405"))
+	g.showDialog(NewSaveDialog("Save Game", g.startDir(), name, savedGameExts, func(path string) {
+		g.fileDir = filepath.Dir(path)
+		if err := writeGameFile(path, game); err != nil {
+			logging.For("gui").Error("failed to save game", "file", path, "err", err)
+			g.showNotice("Could not save the game", err.Error())
+			return
+		}
+		logging.For("gui").Info("game saved", "file", path)
+		g.flash("Game saved to " + path)
+	}))
+}
+
+// loadGameFile asks for a game file and puts its game on the board
+func (g *Game) loadGameFile() {
+	if !g.canOpenGame() {
+		return
+	}
+	g.showDialog(NewOpenDialog("Load Game", g.startDir(), gameFileExts, func(path string) {
+		g.fileDir = filepath.Dir(path)
+		games, err := readGameFile(path)
+		if err != nil {
+			logging.For("gui").Error("failed to load game", "file", path, "err", err)
+			g.showNotice("Could not load the game", err.Error())
+			return
+		}
+		g.openGames(filepath.Base(path), games)
+	}))
+}
+
+// canOpenGame reports whether a loaded game has somewhere to go: a game on
+// screen, or the one a library search was started from, is kept and the
+// loaded one gets a new tab
+func (g *Game) canOpenGame() bool {
+	inGame := g.gameState == StateInGame || g.gameState == StateGameOver
+	if (inGame || g.newTabPending) && len(g.sessions) >= maxTabs {
+		g.flash(fmt.Sprintf("At most %d games can be open, close one first", maxTabs))
+		return false
+	}
+	return true
+}
+
+// openGames puts the first game read from a file on the board, in a new tab
+// when a game is already on screen. An unfinished game can be played on; a
+// finished one is shown from its first move to be stepped through.
+func (g *Game) openGames(source string, games []*model.Game) {
+	game := games[0]
+	g.newTabPending = g.newTabPending || g.gameState == StateInGame || g.gameState == StateGameOver
+	g.beginSession()
+	g.gameMode = ModeHumanVsHuman
+	g.source = source
+	g.othelloGame = game
+	g.markLastMove()
+	g.validMoves = game.GetValidMoves()
+	g.resetClocks()
+	g.gameState = StateInGame
+	if game.GameOver {
+		g.review = true
+		g.replay = playback{paused: true}
+		g.browseTo(0)
+	}
+
+	logging.For("gui").Info("game loaded", "file", source, "games", len(games))
+	if len(games) > 1 {
+		g.flash(fmt.Sprintf("%s holds %d games, showing the first", source, len(games)))
+		return
+	}
+	g.flash("Loaded " + source)
+}
+
+// importToLibrary asks for a game file and adds its games to the library
+func (g *Game) importToLibrary() {
+	g.showDialog(NewOpenDialog("Import Game", g.startDir(), gameFileExts, func(path string) {
+		g.fileDir = filepath.Dir(path)
+		games, err := readGameFile(path)
+		if err == nil {
+			err = g.addToLibrary(filepath.Base(path), games)
+		}
+		if err != nil {
+			logging.For("gui").Error("failed to import game", "file", path, "err", err)
+			g.showNotice("Could not import the game", err.Error())
+		}
+	}))
+}
+
+// addToLibrary adds the games read from a file to the library, leaving out
+// those it already holds
+func (g *Game) addToLibrary(source string, games []*model.Game) error {
+	records := make([]*model.GameRecord, len(games))
+	for i, game := range games {
+		records[i] = model.NewGameRecord(game)
+	}
+	stats, err := g.library.lib.Import(records)
+	if err != nil {
+		return err
+	}
+	logging.For("gui").Info("games imported", "file", source, "added", stats.Added, "duplicates", stats.Duplicates)
+	switch {
+	case len(games) == 1 && stats.Added == 1:
+		g.flash("Added " + source + " to the library")
+	case len(games) == 1:
+		g.flash(source + " is already in the library")
+	default:
+		g.flash(fmt.Sprintf("%s: %s", source, stats))
+	}
+	return nil
+}
+
+// writeGameFile saves a game as a JSON record
+func writeGameFile(path string, game *model.Game) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := model.WriteGameRecord(f, model.NewGameRecord(game)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// readGameFile reads the games in a game file
+func readGameFile(path string) ([]*model.Game, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readGames(filepath.Base(path), f)
+}
+
+// readGames reads and replays the games in a file, telling its format from
+// the name's extension: a JSON record, GGF games, a WTHOR database or
+// transcripts
+func readGames(name string, r io.Reader) ([]*model.Game, error) {
+	var games []*model.Game
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".json":
+		record, err := model.ReadGameRecord(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		game, err := record.Replay()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		games = append(games, game)
+	case ".ggf":
+		parsed, err := ggf.Parse(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for i, p := range parsed {
+			game, err := p.Replay()
+			if err != nil {
+				return nil, fmt.Errorf("%s: game %d: %w", name, i+1, err)
+			}
+			games = append(games, game)
+		}
+	case ".wtb":
+		reader, err := wthor.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		parsed, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for i, p := range parsed {
+			game, err := p.Replay()
+			if err != nil {
+				return nil, fmt.Errorf("%s: game %d: %w", name, i+1, err)
+			}
+			games = append(games, game)
+		}
+	case ".txt":
+		parsed, err := transcript.Parse(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		for i, p := range parsed {
+			game, err := p.Replay()
+			if err != nil {
+				return nil, fmt.Errorf("%s: game %d: %w", name, i+1, err)
+			}
+			games = append(games, game)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unknown game file type %q", name, ext)
+	}
+	if len(games) == 0 {
+		return nil, fmt.Errorf("%s holds no games", name)
+	}
+	return games, nil
+}
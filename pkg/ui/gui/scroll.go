@@ -0,0 +1,110 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Scrolling tuning
+const (
+	wheelStep     = 40 // Pixels scrolled per wheel notch
+	dragThreshold = 4  // Pixels the mouse must move before a press becomes a drag
+	scrollbarW    = 6  // Width of the scrollbar drawn at the right of the area
+	minThumbH     = 20
+)
+
+// scrollView scrolls content taller than its area with the mouse wheel, by
+// dragging the content, or by dragging the scrollbar thumb
+// Owners call update every frame before handling clicks, draw the content
+// into clip shifted up by offset, then call drawScrollbar.
+type scrollView struct {
+	offset    int  // Pixels of content scrolled out above the area
+	followEnd bool // Keep the end of the content in view while it grows
+
+	area    image.Rectangle
+	content int
+
+	dragging   bool
+	dragThumb  bool // The drag started on the scrollbar thumb
+	dragged    bool // The current or last press moved far enough to scroll
+	dragStartY int
+	dragOffset int
+}
+
+// maxOffset returns the largest useful offset
+func (s *scrollView) maxOffset() int {
+	return max(s.content-s.area.Dy(), 0)
+}
+
+// update sets the visible area and content height, then applies the wheel
+// and drags
+func (s *scrollView) update(area image.Rectangle, content int) {
+	atEnd := s.offset >= s.maxOffset()
+	s.area, s.content = area, content
+	if s.followEnd && atEnd {
+		s.offset = s.maxOffset()
+	}
+
+	mouse := image.Pt(cursorPosition())
+	if _, dy := ebiten.Wheel(); dy != 0 && mouse.In(area) {
+		s.offset -= int(dy * wheelStep)
+	}
+
+	switch {
+	case pointerJustPressed() && mouse.In(area):
+		s.dragging, s.dragged = true, false
+		s.dragThumb = mouse.In(s.thumbRect())
+		s.dragStartY, s.dragOffset = mouse.Y, s.offset
+	case s.dragging && pointerPressed():
+		dy := mouse.Y - s.dragStartY
+		if dy > dragThreshold || dy < -dragThreshold {
+			s.dragged = true
+		}
+		if s.dragged && s.dragThumb {
+			// The thumb moves over the area's height, the content over its own
+			s.offset = s.dragOffset + dy*s.content/max(area.Dy(), 1)
+		} else if s.dragged {
+			s.offset = s.dragOffset - dy
+		}
+	case s.dragging:
+		s.dragging = false
+	}
+
+	s.offset = min(max(s.offset, 0), s.maxOffset())
+}
+
+// scrolledByDrag reports whether the current or just released press
+// scrolled, so owners can ignore it as a click
+func (s *scrollView) scrolledByDrag() bool {
+	return s.dragged
+}
+
+// clip returns the part of screen covering the area; drawing into it is
+// cut off at the area's edges
+func (s *scrollView) clip(screen *ebiten.Image) *ebiten.Image {
+	return screen.SubImage(s.area).(*ebiten.Image)
+}
+
+// thumbRect returns the scrollbar thumb, or an empty rectangle when all
+// content fits
+func (s *scrollView) thumbRect() image.Rectangle {
+	if s.maxOffset() == 0 {
+		return image.Rectangle{}
+	}
+	h := max(s.area.Dy()*s.area.Dy()/s.content, minThumbH)
+	y := s.area.Min.Y + (s.area.Dy()-h)*s.offset/s.maxOffset()
+	return image.Rect(s.area.Max.X-scrollbarW, y, s.area.Max.X, y+h)
+}
+
+// drawScrollbar draws the track and thumb when the content overflows
+func (s *scrollView) drawScrollbar(screen *ebiten.Image) {
+	thumb := s.thumbRect()
+	if thumb.Empty() {
+		return
+	}
+	drawRect(screen, image.Rect(thumb.Min.X, s.area.Min.Y, thumb.Max.X, s.area.Max.Y), PanelBackColor)
+	drawRect(screen, thumb, PanelBorderColor)
+}
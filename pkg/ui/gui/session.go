@@ -0,0 +1,338 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/amirhossein-jamali/othello/pkg/plugin"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Tab bar layout, along the bottom of the screen
+const (
+	tabH       = 22
+	tabTop     = ScreenHeight - tabH - 2
+	tabW       = 150
+	tabGap     = 4
+	tabCloseW  = 18
+	maxTabs    = 5
+	tabNewText = "+"
+)
+
+// session is one game open in a tab: the game and everything shown with it
+// Only the active session runs; the others wait with their clocks stopped.
+type session struct {
+	screen   GameState // StateInGame or StateGameOver, saved while in the background
+	gameMode GameMode
+
+	// Core game logic
+	othelloGame *model.Game
+	aiPlayer    *ai.Player
+
+	// Installed engine choosing the moves of the aiPlayer's color, if one
+	// was picked over the built-in AI
+	external     plugin.Player
+	externalName string
+
+	// Display state
+	selectedCellX  int
+	selectedCellY  int
+	validMoves     []model.Position
+	lastMoveX      int
+	lastMoveY      int
+	computerAction bool
+	lastActionTime time.Time
+
+	// Animation
+	animating      bool
+	animationStart time.Time
+	jump           boardJump // Squares changed by the last move through the history
+
+	// In-game panels
+	analysis      analysisView
+	ownership     ownershipView
+	historyScroll scrollView
+
+	// Earlier position shown on the board while the game goes on
+	browse browser
+
+	// Check of the player's last move by the blunder alert, nil when none runs
+	blunder *blunderCheck
+
+	// Suggested move and the position it was given for
+	hint         model.Position
+	hintPosition string
+
+	// Lines of discs the last move flipped, for the position with this hash
+	lastFlips     []model.FlipLine
+	lastFlipsHash uint64
+
+	// Lines describing the finished game on the game over screen
+	breakdown []string
+
+	// Time each side has spent on its moves
+	clocks    map[model.Piece]time.Duration
+	clockTick time.Time
+
+	// Time control of a game started from a preset: the time each side
+	// starts with, zero for none, and the time added after each move
+	clockBase      time.Duration
+	clockIncrement time.Duration
+
+	// Correspondence game on the board, when gameMode is ModeCorrespondence
+	corrID    string
+	corrColor model.Piece
+
+	// Library ID of the finished game, empty if it was not saved
+	savedID string
+
+	// File the game was loaded from, and whether it was loaded finished to
+	// be replayed rather than played
+	source string
+	review bool
+	replay playback // Paces the replay of a game loaded finished
+
+	// One of the boards of a simul, played on by the computer while in the
+	// background
+	simul bool
+
+	// Players taking turns for each color in a consultation game, nil for
+	// one player a color
+	teams map[model.Piece][]controller
+}
+
+// newSession creates an empty session for a game about to start
+func newSession() *session {
+	return &session{
+		screen:        StateInGame,
+		selectedCellX: -1, selectedCellY: -1,
+		lastMoveX: -1, lastMoveY: -1,
+		historyScroll: scrollView{followEnd: true},
+		browse:        browser{ply: -1},
+		hint:          model.PassPosition,
+	}
+}
+
+// title names the session on its tab
+func (s *session) title(g *Game) string {
+	var name string
+	switch s.gameMode {
+	case ModeHumanVsHuman:
+		name = "Human vs Human"
+	case ModeHumanVsEasyAI:
+		name = "vs Easy AI"
+	case ModeHumanVsMediumAI:
+		name = "vs Medium AI"
+	case ModeHumanVsHardAI:
+		name = "vs Hard AI"
+	case ModeExternal:
+		name = "vs " + s.externalName
+	case ModeCorrespondence:
+		name = "Correspondence"
+		if game := g.corr.game(s.corrID); game != nil {
+			opponent := game.White
+			if s.corrColor == model.White {
+				opponent = game.Black
+			}
+			name = "vs " + opponent
+		}
+	}
+	if s.source != "" {
+		name = s.source
+	}
+	if s.simul {
+		name = "Simul " + name
+	}
+	if s.teams != nil {
+		name = strings.Replace(name, "Human vs Human", "Pair vs Pair", 1)
+		name = "Consultation " + name
+	}
+	if s.othelloGame != nil && s.othelloGame.GameOver {
+		name += " (over)"
+	}
+	return name
+}
+
+// inProgress reports whether closing the session would throw away a local
+// game; correspondence games are kept on the server
+func (s *session) inProgress() bool {
+	return s.gameMode != ModeCorrespondence && !s.othelloGame.GameOver && len(s.othelloGame.History) > 0
+}
+
+// activeTab returns the index of the session on screen
+func (g *Game) activeTab() int {
+	for i, s := range g.sessions {
+		if s == g.session {
+			return i
+		}
+	}
+	return 0
+}
+
+// beginSession picks the session a new game is played in: a new tab when
+// one was asked for, otherwise the active tab's game is replaced
+func (g *Game) beginSession() {
+	g.analysis.stop()
+	g.ownership.stop()
+	g.cancelBlunderCheck()
+	fresh := newSession()
+	for i, s := range g.sessions {
+		if s == g.session && !g.newTabPending {
+			s.stopExternal()
+			g.sessions[i] = fresh
+			g.session = fresh
+			return
+		}
+	}
+	g.newTabPending = false
+	g.session = fresh
+	g.sessions = append(g.sessions, fresh)
+}
+
+// leaveSession stops the background work of the active session before
+// another one takes the screen
+func (g *Game) leaveSession() {
+	if g.gameState == StateInGame || g.gameState == StateGameOver {
+		g.session.screen = g.gameState
+	}
+	g.analysis.stop()
+	g.ownership.stop()
+}
+
+// switchTab brings the i-th session to the screen
+func (g *Game) switchTab(i int) {
+	if i < 0 || i >= len(g.sessions) || g.sessions[i] == g.session {
+		return
+	}
+	g.leaveSession()
+	g.session = g.sessions[i]
+	g.gameState = g.session.screen
+	g.tickClock(false) // Time in the background is not charged
+}
+
+// openNewTab starts choosing a game for a new tab
+func (g *Game) openNewTab() {
+	if len(g.sessions) >= maxTabs {
+		g.flash(fmt.Sprintf("At most %d games can be open", maxTabs))
+		return
+	}
+	g.leaveSession()
+	g.newTabPending = true
+	g.gameState = StateGameMode
+}
+
+// closeTab closes the i-th session, asking first if its game is in progress
+func (g *Game) closeTab(i int) {
+	s := g.sessions[i]
+	closeIt := func() {
+		if s == g.session {
+			g.analysis.stop()
+			g.ownership.stop()
+			g.cancelBlunderCheck()
+		}
+		s.stopExternal()
+		g.sessions = append(g.sessions[:i], g.sessions[i+1:]...)
+		if s != g.session {
+			return
+		}
+		if len(g.sessions) == 0 {
+			g.session = newSession()
+			g.gameState = StateMainMenu
+			return
+		}
+		g.session = g.sessions[max(i-1, 0)]
+		g.gameState = g.session.screen
+		g.tickClock(false)
+	}
+	if !s.inProgress() {
+		closeIt()
+		return
+	}
+	g.showDialog(NewDialog("Close game?", "The game in this tab will be lost.",
+		DialogButton{Text: "Keep playing"},
+		DialogButton{Text: "Close", OnClick: closeIt},
+	))
+}
+
+// tabRect returns the i-th tab; the tab after the last is the new tab button
+func (g *Game) tabRect(i int) image.Rectangle {
+	x := g.layout.board.Min.X + i*(tabW+tabGap)
+	if i == len(g.sessions) {
+		return image.Rect(x, tabTop, x+tabH, tabTop+tabH)
+	}
+	return image.Rect(x, tabTop, x+tabW, tabTop+tabH)
+}
+
+// tabCloseRect returns the close button on a tab
+func tabCloseRect(tab image.Rectangle) image.Rectangle {
+	return image.Rect(tab.Max.X-tabCloseW, tab.Min.Y, tab.Max.X, tab.Max.Y)
+}
+
+// updateTabs switches, opens and closes tabs from the tab bar and the
+// keyboard, reporting whether the input was used
+func (g *Game) updateTabs() bool {
+	active, n := g.activeTab(), len(g.sessions)
+	switch {
+	case g.keys.pressed(config.ActionNextTab) && n > 1:
+		g.switchTab((active + 1) % n)
+		return true
+	case g.keys.pressed(config.ActionPrevTab) && n > 1:
+		g.switchTab((active + n - 1) % n)
+		return true
+	case !pointerJustPressed():
+		return false
+	}
+
+	cursor := image.Pt(cursorPosition())
+	if cursor.In(g.tabRect(n)) {
+		g.openNewTab()
+		return true
+	}
+	for i := range g.sessions {
+		tab := g.tabRect(i)
+		switch {
+		case cursor.In(tabCloseRect(tab)):
+			g.closeTab(i)
+			return true
+		case cursor.In(tab):
+			g.switchTab(i)
+			return true
+		}
+	}
+	return false
+}
+
+// drawTabs draws a tab for every open game and the new tab button
+func (g *Game) drawTabs(screen *ebiten.Image) {
+	face := g.resources.GetSmallFont()
+	for i, s := range g.sessions {
+		tab := g.tabRect(i)
+		back := PanelBackColor
+		if s == g.session {
+			back = ButtonColor
+		} else if cursorIn(tab) {
+			back = HoverColor
+		}
+		drawRect(screen, tab, back)
+		drawOutline(screen, tab, 1, PanelBorderColor)
+
+		label := truncateText(face, s.title(g), tab.Dx()-tabCloseW-12)
+		drawLabel(screen, label, face, tab.Min.X+6, tab.Min.Y+tab.Dy()/2+4, TextColor)
+		drawCenteredText(screen, "x", face, tabCloseRect(tab), TextColor)
+	}
+	if len(g.sessions) < maxTabs {
+		add := g.tabRect(len(g.sessions))
+		if cursorIn(add) {
+			drawRect(screen, add, HoverColor)
+		}
+		drawOutline(screen, add, 1, PanelBorderColor)
+		drawCenteredText(screen, tabNewText, face, add, TextColor)
+	}
+}
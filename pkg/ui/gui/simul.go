@@ -0,0 +1,79 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// simulMode is the opponents' level in a simul
+const simulMode = ModeHumanVsMediumAI
+
+// startSimul opens two tabs against the computer, the player taking the
+// chosen color on the board shown and the other color in the tab before it.
+// The computer keeps playing on the board not on screen.
+func (g *Game) startSimul(humanColor model.Piece) {
+	need := 1 // The first board replaces the game on screen
+	if g.newTabPending || len(g.sessions) == 0 {
+		need = 2
+	}
+	if len(g.sessions)+need > maxTabs {
+		g.flash("Close a tab to make room for the simul's two boards")
+		g.leaveToMainMenu()
+		return
+	}
+
+	g.chosenMode = simulMode
+	g.initializeGame(humanColor.Opponent())
+	g.simul = true
+	g.leaveSession()
+	g.newTabPending = true
+	g.initializeGame(humanColor)
+	g.simul = true
+}
+
+// nextSimulBoard brings the next simul board where it is the player's turn
+// to the screen, reporting whether there was one
+func (g *Game) nextSimulBoard() bool {
+	active, n := g.activeTab(), len(g.sessions)
+	for step := 1; step < n; step++ {
+		s := g.sessions[(active+step)%n]
+		if !s.simul || s.othelloGame.GameOver || s.gameMode == ModeHumanVsHuman ||
+			s.othelloGame.Board.CurrentPlayer == s.aiPlayer.Piece {
+			continue
+		}
+		g.switchTab((active + step) % n)
+		return true
+	}
+	return false
+}
+
+// updateSimulBoards lets the computer move on the simul boards not on
+// screen, after the usual pause
+func (g *Game) updateSimulBoards() {
+	active := g.session
+	defer func() { g.session = active }()
+
+	for _, s := range g.sessions {
+		if s == active || !s.simul || s.othelloGame.GameOver {
+			continue
+		}
+		g.session = s
+		if !g.isComputerTurn() {
+			continue
+		}
+		if !s.computerAction {
+			s.computerAction = true
+			s.lastActionTime = time.Now()
+			continue
+		}
+		if time.Since(s.lastActionTime) < g.moveDelay {
+			continue
+		}
+		g.tickClock(false) // Only the search is charged to the computer
+		g.playComputerMove()
+		s.computerAction = false
+	}
+}
@@ -0,0 +1,142 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// builtinSkins holds the skins shipped with the game, one directory each
+//
+//go:embed skins
+var builtinSkins embed.FS
+
+// Skin files; a skin may leave any of them out to keep the plain drawing
+const (
+	skinBoardFile = "board.png" // The whole board including its grid
+	skinBlackFile = "black.png"
+	skinWhiteFile = "white.png"
+)
+
+// ErrUnknownSkin is returned for a skin that is neither built in nor in the
+// user's skins directory
+var ErrUnknownSkin = errors.New("unknown skin")
+
+// Skin replaces the plain board and discs with images, which are scaled to
+// fit; nil images are drawn in the theme's colors
+type Skin struct {
+	Name  string
+	Board *ebiten.Image
+	Black *ebiten.Image
+	White *ebiten.Image
+}
+
+// LoadSkin loads the named skin from the user's skins directory, or the
+// built-in skin of that name
+func LoadSkin(name string) (*Skin, error) {
+	var skinFS fs.FS
+	if dir := filepath.Join(config.SkinsDir(), name); isDir(dir) {
+		skinFS = os.DirFS(dir)
+	} else if info, err := fs.Stat(builtinSkins, path.Join("skins", name)); err == nil && info.IsDir() {
+		skinFS, _ = fs.Sub(builtinSkins, path.Join("skins", name))
+	} else {
+		return nil, fmt.Errorf("%w %q, choose from %s", ErrUnknownSkin, name, strings.Join(Skins(), ", "))
+	}
+
+	s := &Skin{Name: name}
+	for file, img := range map[string]**ebiten.Image{skinBoardFile: &s.Board, skinBlackFile: &s.Black, skinWhiteFile: &s.White} {
+		data, err := fs.ReadFile(skinFS, file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("skin %s: %w", name, err)
+		}
+		decoded, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("skin %s: %s: %w", name, file, err)
+		}
+		*img = ebiten.NewImageFromImage(decoded)
+	}
+	return s, nil
+}
+
+// Skins lists the built-in skins and those in the user's skins directory
+func Skins() []string {
+	seen := make(map[string]bool)
+	builtin, _ := fs.ReadDir(builtinSkins, "skins")
+	user, _ := os.ReadDir(config.SkinsDir())
+	for _, e := range append(builtin, user...) {
+		if e.IsDir() {
+			seen[e.Name()] = true
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isDir reports whether path is a directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// pieceImage returns the image a disc is drawn with: the skin's, or a plain
+// disc in the piece's color
+func (r *Resources) pieceImage(piece model.Piece) *ebiten.Image {
+	if r.skin != nil {
+		if piece == model.Black && r.skin.Black != nil {
+			return r.skin.Black
+		}
+		if piece == model.White && r.skin.White != nil {
+			return r.skin.White
+		}
+	}
+	return r.plainPieces[piece]
+}
+
+// drawPiece draws a disc of the given radius centered on x, y
+func (r *Resources) drawPiece(dst *ebiten.Image, piece model.Piece, x, y, radius int) {
+	img := r.pieceImage(piece)
+	scale := float64(2*radius) / float64(img.Bounds().Dx())
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(float64(x-radius), float64(y-radius))
+	dst.DrawImage(img, op)
+}
+
+// SetSkin switches to the given skin, nil for the plain look
+func (r *Resources) SetSkin(s *Skin) {
+	r.skin = s
+	r.initBoardImage()
+}
+
+// plainPieceRadius is the radius the plain discs are rendered at before
+// scaling, large enough to stay smooth at every size they are shown
+const plainPieceRadius = 64
+
+// newPlainPiece renders a plain disc once so drawing it needs no new image
+func newPlainPiece(clr color.Color) *ebiten.Image {
+	img := ebiten.NewImage(2*plainPieceRadius, 2*plainPieceRadius)
+	drawCircle(img, plainPieceRadius, plainPieceRadius, plainPieceRadius, clr)
+	return img
+}
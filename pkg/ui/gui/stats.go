@@ -0,0 +1,130 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+)
+
+// Statistics screen layout
+const (
+	statsTableY     = 130
+	statsRowH       = 30
+	statsMaxPlayers = 5
+	statsTrendGames = 5  // Games compared for the trend
+	statsChartGames = 30 // Most recent games in the chart
+	statsChartH     = 160
+)
+
+// statsButtonRect is the button switching between the Library and
+// Statistics screens
+var statsButtonRect = image.Rect(ScreenWidth-200, 35, ScreenWidth-40, 75)
+
+// importButtonRect is the Library screen button adding a game from a file
+var importButtonRect = statsButtonRect.Sub(image.Pt(statsButtonRect.Dx()+20, 0))
+
+// openStatistics shows the players' accuracy over their analyzed games
+func (g *Game) openStatistics() {
+	g.gameState = StateStatistics
+}
+
+// updateStatistics handles the statistics screen's button
+func (g *Game) updateStatistics() {
+	g.library.statsMenu.Update()
+}
+
+// statsChartPlayer picks the player whose games are charted: the human,
+// named after their profile when they have one, otherwise the player with
+// the most games
+func statsChartPlayer(stats []library.PlayerStats, human string) library.PlayerStats {
+	for _, s := range stats {
+		if s.Player == human {
+			return s
+		}
+	}
+	return stats[0]
+}
+
+// humanName returns the name the library's statistics know the player by
+func (g *Game) humanName() string {
+	if g.profile != nil {
+		return g.profile.Name
+	}
+	return "Human"
+}
+
+// drawStatistics renders a table of every player's accuracy and a chart of
+// how much the human lost per move in recent games
+func (g *Game) drawStatistics(screen *ebiten.Image) {
+	titleText := "Statistics"
+	bounds := textBounds(g.resources.GetLargeFont(), titleText)
+	drawLabel(screen, titleText, g.resources.GetLargeFont(), (ScreenWidth-fixedToIntWidth(bounds))/2, 60, TextColor)
+	g.library.statsMenu.Draw(screen, g.resources)
+
+	stats := library.Stats(g.library.lib.List())
+	if len(stats) == 0 {
+		drawCenteredText(screen, "No analyzed games yet", g.resources.GetNormalFont(), image.Rect(0, 80, ScreenWidth, 120), TextColor)
+		return
+	}
+
+	face := g.resources.GetNormalFont()
+	columns := []int{myGamesListX, myGamesListX + 170, myGamesListX + 250, myGamesListX + 400, myGamesListX + 500, myGamesListX + 620}
+	row := func(y int, cells ...string) {
+		for i, cell := range cells {
+			text.Draw(screen, cell, face, columns[i], y, TextColor)
+		}
+	}
+
+	y := statsTableY
+	row(y, "Player", "Games", "Wins", "Accuracy", "Loss/move", "Last 5 games")
+	drawRect(screen, image.Rect(myGamesListX, y+8, myGamesListX+myGamesRowW, y+9), PanelBorderColor)
+	for _, s := range stats[:min(len(stats), statsMaxPlayers)] {
+		y += statsRowH
+		trend := "-"
+		if change, ok := s.Trend(statsTrendGames); ok {
+			trend = fmt.Sprintf("%+.1f discs", change)
+		}
+		wins, assisted := s.Wins()
+		winsText := fmt.Sprint(wins)
+		if assisted > 0 {
+			winsText += fmt.Sprintf(" (%d assisted)", assisted)
+		}
+		name := truncateText(face, s.Player, columns[1]-columns[0]-10)
+		row(y, name, fmt.Sprint(len(s.Games)), winsText, fmt.Sprintf("%.0f%%", s.Accuracy()), fmt.Sprintf("%.1f discs", s.AverageLoss()), trend)
+	}
+
+	g.drawLossChart(screen, statsChartPlayer(stats, g.humanName()), y+60)
+
+	escText := "Lower loss is better; a negative trend means improvement - Press ESC to return to main menu"
+	bounds = textBounds(g.resources.GetSmallFont(), escText)
+	drawLabel(screen, escText, g.resources.GetSmallFont(), (ScreenWidth-fixedToIntWidth(bounds))/2, ScreenHeight-20, TextColor)
+}
+
+// drawLossChart draws the player's average loss per move in each of their
+// recent games as bars, oldest on the left
+func (g *Game) drawLossChart(screen *ebiten.Image, s library.PlayerStats, top int) {
+	games := s.Games[max(len(s.Games)-statsChartGames, 0):]
+	caption := fmt.Sprintf("%s: discs lost per move, last %d games", s.Player, len(games))
+	text.Draw(screen, caption, g.resources.GetSmallFont(), myGamesListX, top, TextColor)
+
+	area := image.Rect(myGamesListX, top+10, myGamesListX+myGamesRowW, top+10+statsChartH)
+	drawRect(screen, area, PanelBackColor)
+	drawOutline(screen, area, 1, PanelBorderColor)
+
+	worst := 1.0
+	for _, game := range games {
+		worst = max(worst, game.AverageLoss)
+	}
+	barW := area.Dx() / statsChartGames
+	for i, game := range games {
+		h := int(game.AverageLoss / worst * float64(area.Dy()-10))
+		x := area.Min.X + i*barW
+		drawRect(screen, image.Rect(x+3, area.Max.Y-h, x+barW-3, area.Max.Y), HighlightColor)
+	}
+	text.Draw(screen, fmt.Sprintf("%.1f", worst), g.resources.GetSmallFont(), area.Max.X+6, area.Min.Y+12, TextColor)
+}
@@ -0,0 +1,115 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"strings"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// gameSetup is how the game about to start is played
+type gameSetup int
+
+const (
+	setupSingle       gameSetup = iota // One player for each color
+	setupSimul                         // Two boards against the computer
+	setupConsultation                  // Two players taking turns for a color
+)
+
+// controller is one of the players taking turns for a color: a person, or
+// the computer when ai is set
+type controller struct {
+	name string
+	ai   *ai.Player
+}
+
+// beginGame starts the chosen kind of game with the human playing humanColor
+func (g *Game) beginGame(humanColor model.Piece) {
+	setup := g.setup
+	g.setup = setupSingle
+	switch setup {
+	case setupSimul:
+		g.startSimul(humanColor)
+	case setupConsultation:
+		g.initializeGame(humanColor)
+		g.formTeams(humanColor)
+	default:
+		g.initializeGame(humanColor)
+	}
+}
+
+// formTeams pairs up the players of a consultation game just started: two
+// people for the human color against the computer, or two for each color
+// with the turns going round all four
+func (g *Game) formTeams(humanColor model.Piece) {
+	if g.aiPlayer == nil || g.gameMode == ModeHumanVsHuman {
+		g.teams = map[model.Piece][]controller{
+			model.Black: {{name: "Player 1"}, {name: "Player 3"}},
+			model.White: {{name: "Player 2"}, {name: "Player 4"}},
+		}
+		return
+	}
+	g.teams = map[model.Piece][]controller{
+		humanColor:       {{name: "Player 1"}, {name: "Player 2"}},
+		g.aiPlayer.Piece: {{name: g.computerName(), ai: g.aiPlayer}},
+	}
+}
+
+// controllers returns who plays a color, in the order they take its turns:
+// the team of a consultation game, otherwise the one player
+func (s *session) controllers(piece model.Piece) []controller {
+	if team := s.teams[piece]; len(team) > 0 {
+		return team
+	}
+	if s.aiPlayer != nil && s.aiPlayer.Piece == piece && s.gameMode != ModeHumanVsHuman && s.gameMode != ModeCorrespondence {
+		return []controller{{ai: s.aiPlayer}}
+	}
+	return []controller{{}}
+}
+
+// toMove returns the player whose turn it is the next time the color moves.
+// A forced pass does not use up a player's turn.
+func (s *session) toMove(piece model.Piece) controller {
+	team := s.controllers(piece)
+	turns := 0
+	for _, m := range s.othelloGame.History {
+		if m.Piece == piece && m.Position.Row >= 0 {
+			turns++
+		}
+	}
+	return team[turns%len(team)]
+}
+
+// teamName describes a color's team for its player card, the player to
+// move next first, or returns "" outside consultation games
+func (s *session) teamName(piece model.Piece) string {
+	team := s.teams[piece]
+	if len(team) < 2 {
+		return ""
+	}
+	next := s.toMove(piece)
+	names := []string{next.name}
+	for _, c := range team {
+		if c != next {
+			names = append(names, c.name)
+		}
+	}
+	return names[0] + ", then " + strings.Join(names[1:], ", ")
+}
+
+// recordTeams names the teams in a record of the game, e.g. "Player 1 &
+// Player 3" for Black
+func (s *session) recordTeams(md map[string]string) {
+	for piece, team := range s.teams {
+		if len(team) < 2 {
+			continue
+		}
+		names := make([]string, len(team))
+		for i, c := range team {
+			names[i] = c.name
+		}
+		md[strings.ToLower(model.GetPieceName(piece))+".name"] = strings.Join(names, " & ")
+	}
+}
@@ -0,0 +1,128 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Most screens draw the same strings every frame; measuring them and laying
+// out their glyphs again each time costs more than the drawing itself. The
+// caches below remember both, and are emptied when they grow past their
+// limits so changing strings such as clocks and node counts cannot fill
+// them up.
+const (
+	maxCachedMetrics = 4096
+	maxCachedLabels  = 512
+)
+
+// textKey identifies a string as drawn in a face
+type textKey struct {
+	face font.Face
+	s    string
+}
+
+// labelKey identifies a rendered label
+type labelKey struct {
+	textKey
+	clr color.RGBA
+}
+
+// metrics are a string's measurements
+type metrics struct {
+	bounds  fixed.Rectangle26_6
+	advance fixed.Int26_6
+}
+
+// label is a string rendered once into an image
+type label struct {
+	img    *ebiten.Image
+	offset image.Point // From the text origin to the image's top left
+}
+
+var (
+	cachedMetrics = make(map[textKey]metrics)
+	cachedLabels  = make(map[labelKey]label)
+)
+
+// measureText returns the measurements of s, remembered from the first call
+func measureText(face font.Face, s string) metrics {
+	key := textKey{face, s}
+	if m, ok := cachedMetrics[key]; ok {
+		return m
+	}
+	if len(cachedMetrics) >= maxCachedMetrics {
+		clear(cachedMetrics)
+	}
+	var m metrics
+	m.bounds, m.advance = font.BoundString(face, s)
+	cachedMetrics[key] = m
+	return m
+}
+
+// textBounds returns the bounds of s drawn at the origin, like
+// font.BoundString
+func textBounds(face font.Face, s string) fixed.Rectangle26_6 {
+	return measureText(face, s).bounds
+}
+
+// textWidth returns how far s advances the pen in pixels, like
+// font.MeasureString
+func textWidth(face font.Face, s string) int {
+	return measureText(face, s).advance.Ceil()
+}
+
+// drawLabel draws s with its origin at x, y like text.Draw, from an image
+// rendered the first time the label is drawn
+func drawLabel(dst *ebiten.Image, s string, face font.Face, x, y int, clr color.Color) {
+	key := labelKey{textKey{face, s}, color.RGBAModel.Convert(clr).(color.RGBA)}
+	l, ok := cachedLabels[key]
+	if !ok {
+		l = renderLabel(s, face, clr)
+		if len(cachedLabels) >= maxCachedLabels {
+			clearLabels()
+		}
+		cachedLabels[key] = l
+	}
+	if l.img == nil {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(x+l.offset.X), float64(y+l.offset.Y))
+	dst.DrawImage(l.img, op)
+}
+
+// renderLabel draws s into an image just large enough to hold it
+func renderLabel(s string, face font.Face, clr color.Color) label {
+	b := textBounds(face, s)
+	rect := image.Rect(b.Min.X.Floor(), b.Min.Y.Floor(), b.Max.X.Ceil(), b.Max.Y.Ceil())
+	if rect.Empty() {
+		return label{}
+	}
+	img := ebiten.NewImage(rect.Dx(), rect.Dy())
+	text.Draw(img, s, face, -rect.Min.X, -rect.Min.Y, clr)
+	return label{img: img, offset: rect.Min}
+}
+
+// clearLabels frees every rendered label
+func clearLabels() {
+	for _, l := range cachedLabels {
+		if l.img != nil {
+			l.img.Dispose()
+		}
+	}
+	clear(cachedLabels)
+}
+
+// clearTextCache forgets all measurements and labels; it is called when the
+// look of text may have changed
+func clearTextCache() {
+	clear(cachedMetrics)
+	clearLabels()
+}
@@ -0,0 +1,292 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+	"strings"
+	"unicode"
+
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+)
+
+// textPadding is the space between a text field's border and its text
+const textPadding = 8
+
+// TextInput is a one-line text field
+// While focused it takes typing, moves the cursor with the arrow keys, Home
+// and End (Ctrl jumps by word), selects with Shift or the mouse, and supports
+// Ctrl+A, Ctrl+C, Ctrl+X and Ctrl+V. Enter calls OnSubmit.
+type TextInput struct {
+	Rect        image.Rectangle
+	Text        string
+	Placeholder string // Shown while the field is empty and unfocused
+	MaxLen      int    // Maximum length in characters, 0 for no limit
+	OnSubmit    func(s string)
+
+	cursor    int       // Cursor position in runes
+	anchor    int       // Other end of the selection; equals cursor when nothing is selected
+	scrollX   int       // Pixels of text scrolled out on the left
+	selecting bool      // A mouse drag is selecting text
+	face      font.Face // Face of the last Draw, used to map clicks to positions
+}
+
+// NewTextInput creates an empty text field
+func NewTextInput(rect image.Rectangle, placeholder string) *TextInput {
+	return &TextInput{Rect: rect, Placeholder: placeholder}
+}
+
+// Bounds returns the field's area
+func (t *TextInput) Bounds() image.Rectangle { return t.Rect }
+
+// Focusable reports that text fields take the keyboard focus
+func (t *TextInput) Focusable() bool { return true }
+
+// Selection returns the selected text
+func (t *TextInput) Selection() string {
+	start, end := t.selection()
+	return string([]rune(t.Text)[start:end])
+}
+
+// SetText replaces the text and moves the cursor to its end
+func (t *TextInput) SetText(s string) {
+	t.Text = s
+	t.cursor = len([]rune(s))
+	t.anchor = t.cursor
+}
+
+// selection returns the selected rune range, empty when nothing is selected
+func (t *TextInput) selection() (int, int) {
+	return min(t.cursor, t.anchor), max(t.cursor, t.anchor)
+}
+
+// Update applies typing, editing keys and mouse selection while focused
+func (t *TextInput) Update(focused bool) {
+	// Text may have been set directly
+	n := len([]rune(t.Text))
+	t.cursor, t.anchor = min(t.cursor, n), min(t.anchor, n)
+
+	if !focused {
+		t.selecting = false
+		return
+	}
+
+	t.updateMouse()
+
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl) || ebiten.IsKeyPressed(ebiten.KeyMeta)
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+
+	switch {
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyA):
+		t.anchor, t.cursor = 0, n
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyC):
+		t.copySelection()
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyX):
+		t.copySelection()
+		t.insert("")
+	case ctrl && inpututil.IsKeyJustPressed(ebiten.KeyV):
+		s, err := readClipboard()
+		if err != nil {
+			logging.For("gui").Warn("paste failed", "err", err)
+			break
+		}
+		t.insert(s)
+	case repeatingKey(ebiten.KeyArrowLeft):
+		t.moveCursor(t.step(-1, ctrl), shift, -1)
+	case repeatingKey(ebiten.KeyArrowRight):
+		t.moveCursor(t.step(1, ctrl), shift, 1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		t.moveCursor(0, shift, 0)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		t.moveCursor(n, shift, 0)
+	case repeatingKey(ebiten.KeyBackspace):
+		if t.cursor == t.anchor {
+			t.anchor = t.step(-1, ctrl)
+		}
+		t.insert("")
+	case repeatingKey(ebiten.KeyDelete):
+		if t.cursor == t.anchor {
+			t.anchor = t.step(1, ctrl)
+		}
+		t.insert("")
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		if t.OnSubmit != nil {
+			t.OnSubmit(t.Text)
+		}
+	}
+
+	// Shortcut letters must not be typed into the field
+	if !ctrl {
+		if chars := ebiten.AppendInputChars(nil); len(chars) > 0 {
+			t.insert(string(chars))
+		}
+	}
+}
+
+// updateMouse places the cursor on click and selects while dragging
+func (t *TextInput) updateMouse() {
+	x, _ := cursorPosition()
+	switch {
+	case pointerJustPressed() && cursorIn(t.Rect):
+		t.cursor = t.indexAt(x)
+		if !ebiten.IsKeyPressed(ebiten.KeyShift) {
+			t.anchor = t.cursor
+		}
+		t.selecting = true
+	case t.selecting && pointerPressed():
+		t.cursor = t.indexAt(x)
+	default:
+		t.selecting = false
+	}
+}
+
+// moveCursor moves the cursor to pos, extending the selection with shift
+// Without shift, a selection collapses to its end in direction dir instead.
+func (t *TextInput) moveCursor(pos int, shift bool, dir int) {
+	if !shift && t.cursor != t.anchor && dir != 0 {
+		start, end := t.selection()
+		pos = start
+		if dir > 0 {
+			pos = end
+		}
+	}
+	t.cursor = pos
+	if !shift {
+		t.anchor = pos
+	}
+}
+
+// step returns the cursor position one character, or with word one word,
+// away in direction dir
+func (t *TextInput) step(dir int, word bool) int {
+	runes := []rune(t.Text)
+	pos := t.cursor
+	if !word {
+		return min(max(pos+dir, 0), len(runes))
+	}
+	// Skip spaces, then the word
+	if dir < 0 {
+		for pos > 0 && unicode.IsSpace(runes[pos-1]) {
+			pos--
+		}
+		for pos > 0 && !unicode.IsSpace(runes[pos-1]) {
+			pos--
+		}
+		return pos
+	}
+	for pos < len(runes) && unicode.IsSpace(runes[pos]) {
+		pos++
+	}
+	for pos < len(runes) && !unicode.IsSpace(runes[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// insert replaces the selection with s, dropping characters that cannot be
+// shown on one line and anything past MaxLen
+func (t *TextInput) insert(s string) {
+	s = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		if !unicode.IsPrint(r) {
+			return -1
+		}
+		return r
+	}, s)
+
+	runes := []rune(t.Text)
+	start, end := t.selection()
+	added := []rune(s)
+	if t.MaxLen > 0 {
+		room := max(t.MaxLen-(len(runes)-(end-start)), 0)
+		added = added[:min(len(added), room)]
+	}
+
+	result := make([]rune, 0, len(runes)-(end-start)+len(added))
+	result = append(result, runes[:start]...)
+	result = append(result, added...)
+	result = append(result, runes[end:]...)
+	t.Text = string(result)
+	t.cursor = start + len(added)
+	t.anchor = t.cursor
+}
+
+// copySelection puts the selected text on the clipboard
+func (t *TextInput) copySelection() {
+	if t.cursor == t.anchor {
+		return
+	}
+	if err := writeClipboard(t.Selection()); err != nil {
+		logging.For("gui").Warn("copy failed", "err", err)
+	}
+}
+
+// textWidth measures the first n runes of the text
+func (t *TextInput) textWidth(n int) int {
+	if t.face == nil {
+		return 0
+	}
+	return textWidth(t.face, string([]rune(t.Text)[:n]))
+}
+
+// indexAt returns the cursor position nearest to screen coordinate x
+func (t *TextInput) indexAt(x int) int {
+	x = x - t.Rect.Min.X - textPadding + t.scrollX
+	n := len([]rune(t.Text))
+	for i := 0; i < n; i++ {
+		if x < (t.textWidth(i)+t.textWidth(i+1))/2 {
+			return i
+		}
+	}
+	return n
+}
+
+// Draw renders the field with its text or placeholder, the selection and
+// the cursor
+func (t *TextInput) Draw(screen *ebiten.Image, res *Resources, focused bool) {
+	t.face = res.GetNormalFont()
+
+	drawRect(screen, t.Rect, PanelBackColor)
+	border := CellBorderColor
+	if focused {
+		border = PanelBorderColor
+	}
+	drawOutline(screen, t.Rect, 2, border)
+
+	inner := t.Rect.Inset(textPadding)
+	bounds := textBounds(t.face, "Mg")
+	baseline := t.Rect.Min.Y + t.Rect.Dy()/2 + fixedToIntHeight(bounds)/3
+
+	if t.Text == "" && !focused {
+		text.Draw(screen, t.Placeholder, t.face, inner.Min.X, baseline, BlackMoveColor)
+		return
+	}
+
+	// Scroll horizontally to keep the cursor visible
+	cursorX := t.textWidth(t.cursor)
+	if cursorX-t.scrollX > inner.Dx() {
+		t.scrollX = cursorX - inner.Dx()
+	} else if cursorX < t.scrollX {
+		t.scrollX = cursorX
+	}
+	originX := inner.Min.X - t.scrollX
+
+	clip := screen.SubImage(inner.Inset(-2)).(*ebiten.Image)
+	if start, end := t.selection(); focused && start != end {
+		sel := image.Rect(originX+t.textWidth(start), t.Rect.Min.Y+6, originX+t.textWidth(end), t.Rect.Max.Y-6)
+		drawRect(clip, sel, HighlightColor)
+	}
+	text.Draw(clip, t.Text, t.face, originX, baseline, TextColor)
+
+	// A blinking bar marks the cursor while focused
+	if focused && blinkOn() {
+		x := originX + cursorX
+		drawRect(clip, image.Rect(x, t.Rect.Min.Y+6, x+2, t.Rect.Max.Y-6), TextColor)
+	}
+}
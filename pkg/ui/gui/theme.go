@@ -0,0 +1,148 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image/color"
+	"slices"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/ai"
+	"github.com/amirhossein-jamali/othello/pkg/book"
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/notify"
+)
+
+// palette is the set of colors a theme assigns
+type palette struct {
+	background, board, grid, cellBorder color.RGBA
+	validMove, highlight                color.RGBA
+	button, hover                       color.RGBA
+	panelBack, panelBorder              color.RGBA
+}
+
+// themes maps theme names to their palettes; classic is the original look
+var themes = map[string]palette{
+	config.ThemeClassic: {
+		background: BackgroundColor, board: BoardColor, grid: GridColor, cellBorder: CellBorderColor,
+		validMove: ValidMoveColor, highlight: HighlightColor,
+		button: ButtonColor, hover: HoverColor,
+		panelBack: PanelBackColor, panelBorder: PanelBorderColor,
+	},
+	config.ThemeDark: {
+		background: color.RGBA{18, 18, 22, 255}, board: color.RGBA{28, 70, 52, 255},
+		grid: color.RGBA{10, 30, 22, 255}, cellBorder: color.RGBA{14, 40, 30, 255},
+		validMove: color.RGBA{90, 200, 140, 200}, highlight: color.RGBA{200, 170, 60, 140},
+		button: color.RGBA{40, 60, 80, 255}, hover: color.RGBA{60, 85, 110, 255},
+		panelBack: color.RGBA{28, 32, 40, 235}, panelBorder: color.RGBA{80, 110, 140, 255},
+	},
+	config.ThemeContrast: {
+		background: color.RGBA{0, 0, 0, 255}, board: color.RGBA{0, 110, 0, 255},
+		grid: color.RGBA{255, 255, 255, 255}, cellBorder: color.RGBA{0, 0, 0, 255},
+		validMove: color.RGBA{255, 255, 0, 255}, highlight: color.RGBA{255, 0, 255, 180},
+		button: color.RGBA{0, 0, 160, 255}, hover: color.RGBA{0, 0, 255, 255},
+		panelBack: color.RGBA{0, 0, 0, 255}, panelBorder: color.RGBA{255, 255, 255, 255},
+	},
+}
+
+// applyTheme switches the GUI colors to the named theme, falling back to
+// classic for unknown names
+func applyTheme(name string) {
+	p, ok := themes[name]
+	if !ok {
+		p = themes[config.ThemeClassic]
+	}
+	BackgroundColor, BoardColor, GridColor, CellBorderColor = p.background, p.board, p.grid, p.cellBorder
+	ValidMoveColor, HighlightColor = p.validMove, p.highlight
+	ButtonColor, HoverColor = p.button, p.hover
+	PanelBackColor, PanelBorderColor = p.panelBack, p.panelBorder
+}
+
+// applySettings applies new settings to the running game
+func (g *Game) applySettings(cfg config.Config) {
+	log := logging.For("gui")
+
+	if cfg.Theme != g.applied.Theme {
+		applyTheme(cfg.Theme)
+		g.resources.initBoardImage()
+		clearTextCache()
+		log.Info("theme changed", "theme", cfg.Theme)
+	}
+
+	if cfg.Language != g.applied.Language {
+		// English is the only language so far, so there is no text to
+		// reload yet
+		log.Info("language changed", "language", cfg.Language)
+	}
+
+	if cfg.Skin != g.applied.Skin {
+		var skin *Skin
+		if cfg.Skin != "" {
+			var err error
+			if skin, err = LoadSkin(cfg.Skin); err != nil {
+				log.Error("failed to load skin, keeping the previous one", "skin", cfg.Skin, "err", err)
+				cfg.Skin = g.applied.Skin
+				skin = g.resources.skin
+			}
+		}
+		g.resources.SetSkin(skin)
+		log.Info("skin changed", "skin", cfg.Skin)
+	}
+
+	if !slices.Equal(cfg.Presets, g.applied.Presets) {
+		g.buildPresetButtons(cfg.Presets)
+	}
+
+	g.keys = newKeymap(cfg.Keys)
+	g.layout = computeLayout(cfg.Layout, g.options.Touch)
+	if g.options.Touch {
+		g.buildTouchBar()
+	}
+	if !wantsAnalysis(cfg) {
+		g.analysis.stop()
+	}
+	g.moveDelay = time.Duration(cfg.AI.MoveDelayMS) * time.Millisecond
+	if g.corr != nil {
+		g.corr.setNotifier(notify.New(cfg.Notify.Desktop, cfg.Notify.Webhook))
+	}
+	if g.library != nil {
+		g.library.analyzer.SetDepth(cfg.Library.AnalysisDepth)
+	}
+
+	if cfg.AI.Book != g.applied.AI.Book {
+		var b *book.Book
+		if cfg.AI.Book != "" {
+			var err error
+			if b, err = book.Load(cfg.AI.Book); err != nil {
+				log.Error("failed to load opening book, keeping the previous one", "file", cfg.AI.Book, "err", err)
+				cfg.AI.Book = g.applied.AI.Book
+				b = ai.DefaultBook
+			}
+		}
+		ai.DefaultBook = b
+		if g.aiPlayer != nil {
+			g.aiPlayer.Book = b
+		}
+		log.Info("opening book changed", "file", cfg.AI.Book)
+	}
+
+	if cfg.AI.Weights != g.applied.AI.Weights {
+		var w *ai.Weights
+		if cfg.AI.Weights != "" {
+			var err error
+			if w, err = ai.LoadWeights(cfg.AI.Weights); err != nil {
+				log.Error("failed to load evaluation weights, keeping the previous ones", "file", cfg.AI.Weights, "err", err)
+				cfg.AI.Weights = g.applied.AI.Weights
+				w = ai.DefaultWeights
+			}
+		}
+		ai.DefaultWeights = w
+		if g.aiPlayer != nil {
+			g.aiPlayer.Weights = w
+		}
+		log.Info("evaluation weights changed", "file", cfg.AI.Weights)
+	}
+
+	g.applied = cfg
+}
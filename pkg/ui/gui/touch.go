@@ -0,0 +1,64 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// touchActions are the actions on the touch bar, in button order
+var touchActions = []struct {
+	action string
+	label  string
+}{
+	{config.ActionUndo, "Undo"},
+	{config.ActionHint, "Hint"},
+	{config.ActionPass, "Pass"},
+	{config.ActionSaveGame, "Save"},
+	{config.ActionHistoryBack, "Back"},
+	{config.ActionHistoryForward, "Forward"},
+}
+
+// tappedAction is the action whose touch bar button was tapped this frame;
+// keymap.pressed reports it as if its key was pressed. updatePointer clears
+// it every frame.
+var tappedAction string
+
+// buildTouchBar lays out the touch bar's buttons, two to a row, with a
+// button back to the menu last
+func (g *Game) buildTouchBar() {
+	const gap = 8
+	area := g.layout.touchBar
+	rows := (len(touchActions)+1)/2 + 1
+	w, h := (area.Dx()-gap)/2, (area.Dy()-gap*(rows-1))/rows
+	cell := func(i int) image.Rectangle {
+		x, y := area.Min.X+(i%2)*(w+gap), area.Min.Y+(i/2)*(h+gap)
+		return image.Rect(x, y, x+w, y+h)
+	}
+
+	g.touchBar = NewForm()
+	for i, ta := range touchActions {
+		action := ta.action
+		g.touchBar.Add(NewButton(cell(i), ta.label, func() { tappedAction = action }))
+	}
+	menu := cell(len(touchActions) + len(touchActions)%2)
+	menu.Max.X = area.Max.X
+	g.touchBar.Add(NewButton(menu, "Menu", g.leaveToMainMenu))
+}
+
+// updateTouchBar handles taps on the touch bar while a game is played
+func (g *Game) updateTouchBar() {
+	if g.touchBar != nil && g.gameState == StateInGame {
+		g.touchBar.Update()
+	}
+}
+
+// drawTouchBar draws the touch bar
+func (g *Game) drawTouchBar(screen *ebiten.Image) {
+	if g.touchBar != nil {
+		g.touchBar.Draw(screen, g.resources)
+	}
+}
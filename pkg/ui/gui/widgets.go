@@ -0,0 +1,343 @@
+//go:build !nogui
+
+package gui
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"golang.org/x/image/font"
+)
+
+// Widget is an element of a screen that handles its own hit testing
+type Widget interface {
+	Bounds() image.Rectangle
+	Focusable() bool
+	// Update handles input; only the focused widget receives keys
+	Update(focused bool)
+	Draw(screen *ebiten.Image, res *Resources, focused bool)
+}
+
+// Form holds a screen's widgets and tracks keyboard focus
+// Tab and Shift+Tab move the focus, clicking a focusable widget focuses it.
+type Form struct {
+	Widgets []Widget
+	focus   int // Index of the focused widget, -1 for none
+}
+
+// NewForm creates a form with nothing focused
+func NewForm(widgets ...Widget) *Form {
+	return &Form{Widgets: widgets, focus: -1}
+}
+
+// Add appends widgets to the form
+func (f *Form) Add(widgets ...Widget) {
+	f.Widgets = append(f.Widgets, widgets...)
+}
+
+// Focused returns the focused widget, or nil
+func (f *Form) Focused() Widget {
+	if f.focus < 0 || f.focus >= len(f.Widgets) {
+		return nil
+	}
+	return f.Widgets[f.focus]
+}
+
+// Focus moves the keyboard focus to w
+func (f *Form) Focus(w Widget) {
+	f.focus = -1
+	for i, candidate := range f.Widgets {
+		if candidate == w && w.Focusable() {
+			f.focus = i
+		}
+	}
+}
+
+// Update moves the focus and lets every widget handle input
+func (f *Form) Update() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		step := 1
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			step = -1
+		}
+		f.moveFocus(step)
+	}
+
+	if pointerJustPressed() {
+		mouse := image.Pt(cursorPosition())
+		for i, w := range f.Widgets {
+			if w.Focusable() && mouse.In(w.Bounds()) {
+				f.focus = i
+			}
+		}
+	}
+
+	// Widgets may change the form from their callbacks, so iterate a copy
+	widgets := append([]Widget(nil), f.Widgets...)
+	focused := f.Focused()
+	for _, w := range widgets {
+		w.Update(w == focused)
+	}
+}
+
+// moveFocus focuses the next focusable widget in the given direction
+func (f *Form) moveFocus(step int) {
+	n := len(f.Widgets)
+	i := f.focus
+	if i < 0 && step < 0 {
+		i = 0
+	}
+	for range f.Widgets {
+		i = ((i+step)%n + n) % n
+		if f.Widgets[i].Focusable() {
+			f.focus = i
+			return
+		}
+	}
+}
+
+// Draw renders every widget
+func (f *Form) Draw(screen *ebiten.Image, res *Resources) {
+	focused := f.Focused()
+	for _, w := range f.Widgets {
+		w.Draw(screen, res, w == focused)
+	}
+}
+
+// Button runs OnClick when clicked, or on Enter or Space while focused
+// Clicks count when the mouse is released so a press can still be aborted
+// by moving off the button.
+type Button struct {
+	Rect    image.Rectangle
+	Text    string
+	OnClick func()
+}
+
+// NewButton creates a button
+func NewButton(rect image.Rectangle, label string, onClick func()) *Button {
+	return &Button{Rect: rect, Text: label, OnClick: onClick}
+}
+
+// Bounds returns the button's area
+func (b *Button) Bounds() image.Rectangle { return b.Rect }
+
+// Focusable reports that buttons take the keyboard focus
+func (b *Button) Focusable() bool { return true }
+
+// Update detects clicks and key presses
+func (b *Button) Update(focused bool) {
+	clicked := pointerJustReleased() && cursorIn(b.Rect)
+	pressed := focused && (inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace))
+	if (clicked || pressed) && b.OnClick != nil {
+		b.OnClick()
+	}
+}
+
+// Draw renders the button, lighter while hovered and outlined while focused
+func (b *Button) Draw(screen *ebiten.Image, res *Resources, focused bool) {
+	buttonColor := ButtonColor
+	if cursorIn(b.Rect) {
+		buttonColor = HoverColor
+	}
+	drawRect(screen, b.Rect, buttonColor)
+	if focused {
+		drawOutline(screen, b.Rect, 2, PanelBorderColor)
+	}
+	drawCenteredText(screen, b.Text, res.GetNormalFont(), b.Rect, TextColor)
+}
+
+// Alignment of a label's text in its rectangle
+type Alignment int
+
+const (
+	AlignLeft Alignment = iota
+	AlignCenter
+)
+
+// Label draws a line of text
+type Label struct {
+	Rect  image.Rectangle
+	Text  string
+	Face  font.Face // nil uses the normal font
+	Align Alignment
+	Color color.Color // nil uses TextColor
+}
+
+// NewLabel creates a centered label in the normal font
+func NewLabel(rect image.Rectangle, s string) *Label {
+	return &Label{Rect: rect, Text: s, Align: AlignCenter}
+}
+
+// Bounds returns the label's area
+func (l *Label) Bounds() image.Rectangle { return l.Rect }
+
+// Focusable reports that labels never take the focus
+func (l *Label) Focusable() bool { return false }
+
+// Update does nothing; labels ignore input
+func (l *Label) Update(focused bool) {}
+
+// Draw renders the text
+func (l *Label) Draw(screen *ebiten.Image, res *Resources, focused bool) {
+	face := l.Face
+	if face == nil {
+		face = res.GetNormalFont()
+	}
+	clr := l.Color
+	if clr == nil {
+		clr = TextColor
+	}
+	if l.Align == AlignCenter {
+		drawCenteredText(screen, l.Text, face, l.Rect, clr)
+		return
+	}
+	bounds := textBounds(face, l.Text)
+	drawLabel(screen, l.Text, face, l.Rect.Min.X, l.Rect.Min.Y+l.Rect.Dy()/2+fixedToIntHeight(bounds)/3, clr)
+}
+
+// List shows selectable rows, scrolling when they do not fit
+// Clicking a row or pressing Enter on it calls OnSelect; the arrow keys move
+// the selection while the list is focused.
+type List struct {
+	Rect     image.Rectangle
+	Items    []string
+	RowH     int
+	Selected int // -1 for none
+	OnSelect func(i int)
+
+	scroll scrollView
+}
+
+// NewList creates a list with nothing selected
+func NewList(rect image.Rectangle, items []string, onSelect func(i int)) *List {
+	return &List{Rect: rect, Items: items, RowH: 30, Selected: -1, OnSelect: onSelect}
+}
+
+// Bounds returns the list's area
+func (l *List) Bounds() image.Rectangle { return l.Rect }
+
+// Focusable reports that lists take the keyboard focus
+func (l *List) Focusable() bool { return true }
+
+// rowRect returns the on-screen rectangle of row i
+func (l *List) rowRect(i int) image.Rectangle {
+	y := l.Rect.Min.Y + i*l.RowH - l.scroll.offset
+	return image.Rect(l.Rect.Min.X, y, l.Rect.Max.X-scrollbarW-2, y+l.RowH)
+}
+
+// Update scrolls the list and handles selection
+func (l *List) Update(focused bool) {
+	l.scroll.update(l.Rect, len(l.Items)*l.RowH)
+
+	if pointerJustReleased() && !l.scroll.scrolledByDrag() && cursorIn(l.Rect) {
+		for i := range l.Items {
+			if cursorIn(l.rowRect(i)) {
+				l.choose(i)
+				return
+			}
+		}
+	}
+
+	if !focused || len(l.Items) == 0 {
+		return
+	}
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		l.Selected = min(l.Selected+1, len(l.Items)-1)
+		l.scrollTo(l.Selected)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		l.Selected = max(l.Selected-1, 0)
+		l.scrollTo(l.Selected)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) && l.Selected >= 0:
+		l.choose(l.Selected)
+	}
+}
+
+// choose selects row i and reports it
+func (l *List) choose(i int) {
+	l.Selected = i
+	if l.OnSelect != nil {
+		l.OnSelect(i)
+	}
+}
+
+// scrollTo scrolls just enough to show row i
+func (l *List) scrollTo(i int) {
+	top, bottom := i*l.RowH, (i+1)*l.RowH
+	if top < l.scroll.offset {
+		l.scroll.offset = top
+	} else if bottom > l.scroll.offset+l.Rect.Dy() {
+		l.scroll.offset = bottom - l.Rect.Dy()
+	}
+}
+
+// Draw renders the visible rows
+func (l *List) Draw(screen *ebiten.Image, res *Resources, focused bool) {
+	drawRect(screen, l.Rect, PanelBackColor)
+	clip := l.scroll.clip(screen)
+	for i, item := range l.Items {
+		row := l.rowRect(i)
+		if !row.Overlaps(l.Rect) {
+			continue
+		}
+		switch {
+		case i == l.Selected:
+			drawRect(clip, row, ButtonColor)
+		case cursorIn(row) && cursorIn(l.Rect):
+			drawRect(clip, row, HoverColor)
+		}
+		bounds := textBounds(res.GetNormalFont(), item)
+		drawLabel(clip, item, res.GetNormalFont(), row.Min.X+10, row.Min.Y+row.Dy()/2+fixedToIntHeight(bounds)/3, TextColor)
+	}
+	l.scroll.drawScrollbar(screen)
+	if focused {
+		drawOutline(screen, l.Rect, 1, PanelBorderColor)
+	}
+}
+
+// cursorIn reports whether the mouse is over rect
+func cursorIn(rect image.Rectangle) bool {
+	return image.Pt(cursorPosition()).In(rect)
+}
+
+// repeatingKey reports a key press, repeating while the key is held
+func repeatingKey(key ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || (d >= 30 && d%3 == 0)
+}
+
+// blinkOn alternates twice a second for blinking cursors
+func blinkOn() bool {
+	return time.Now().UnixMilli()/500%2 == 0
+}
+
+// drawCenteredText draws s centered in rect
+func drawCenteredText(dst *ebiten.Image, s string, face font.Face, rect image.Rectangle, clr color.Color) {
+	bounds := textBounds(face, s)
+	x := rect.Min.X + (rect.Dx()-fixedToIntWidth(bounds))/2
+	y := rect.Min.Y + rect.Dy()/2 + fixedToIntHeight(bounds)/3
+	drawLabel(dst, s, face, x, y, clr)
+}
+
+// truncateText shortens s with an ellipsis to fit width
+func truncateText(face font.Face, s string, width int) string {
+	if textWidth(face, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(face, string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// drawOutline draws a border of the given width inside rect
+func drawOutline(dst *ebiten.Image, rect image.Rectangle, width int, clr color.Color) {
+	drawRect(dst, image.Rect(rect.Min.X, rect.Min.Y, rect.Max.X, rect.Min.Y+width), clr)
+	drawRect(dst, image.Rect(rect.Min.X, rect.Max.Y-width, rect.Max.X, rect.Max.Y), clr)
+	drawRect(dst, image.Rect(rect.Min.X, rect.Min.Y, rect.Min.X+width, rect.Max.Y), clr)
+	drawRect(dst, image.Rect(rect.Max.X-width, rect.Min.Y, rect.Max.X, rect.Max.Y), clr)
+}
@@ -0,0 +1,60 @@
+//go:build !nogui
+
+// Package mobile builds the GUI as a library for Android and iOS apps with
+// ebitenmobile:
+//
+//	ebitenmobile bind -target android -javapkg io.github.othello -o othello.aar ./pkg/ui/mobile
+//	ebitenmobile bind -target ios -o Othello.xcframework ./pkg/ui/mobile
+//
+// The app shows the game in an EbitenView and calls Start once, before the
+// view appears, with its private files directory. The GUI uses the touch
+// layout and saves the game in progress after every move, so it continues
+// where it was left even if the system stopped the app in the background.
+package mobile
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/amirhossein-jamali/othello/pkg/config"
+	"github.com/amirhossein-jamali/othello/pkg/library"
+	"github.com/amirhossein-jamali/othello/pkg/logging"
+	"github.com/amirhossein-jamali/othello/pkg/ui/gui"
+	ebitenmobile "github.com/hajimehoshi/ebiten/v2/mobile"
+)
+
+// started makes Start run once; the view may be created again, e.g. when
+// the screen rotates, but the game lives as long as the process
+var started sync.Once
+
+// Start sets up the game with its settings, library, log and resume file in
+// dataDir and hands it to the EbitenView
+func Start(dataDir string) error {
+	var err error
+	started.Do(func() { err = start(dataDir) })
+	return err
+}
+
+// start does Start's work
+func start(dataDir string) error {
+	if _, err := logging.Setup(logging.Options{File: filepath.Join(dataDir, "othello.log")}); err != nil {
+		return err
+	}
+	settings, err := config.Watch(filepath.Join(dataDir, "settings.json"), 2*time.Second)
+	if err != nil {
+		return err
+	}
+	lib, err := library.Open(filepath.Join(dataDir, "library"))
+	if err != nil {
+		return err
+	}
+
+	ebitenmobile.SetGame(gui.NewGameWithOptions(gui.Options{
+		Settings:   settings,
+		Library:    lib,
+		Touch:      true,
+		ResumeFile: filepath.Join(dataDir, "resume.json"),
+	}))
+	return nil
+}
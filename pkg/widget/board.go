@@ -0,0 +1,68 @@
+//go:build !nogui
+
+package widget
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Board is an Othello board for Ebitengine games: it draws a Driver's game
+// into a rectangle of the screen and plays the squares clicked or touched
+type Board struct {
+	*Driver
+	Rect image.Rectangle // Where the board is drawn; keep it square
+
+	image   *ebiten.Image
+	drawn   string // What the image shows
+	touches []ebiten.TouchID
+}
+
+// NewBoard shows a driver's game in rect
+func NewBoard(d *Driver, rect image.Rectangle) *Board {
+	return &Board{Driver: d, Rect: rect}
+}
+
+// Update plays a square clicked or touched on the board, then updates the
+// driver. Call it from the game's Update.
+func (b *Board) Update() error {
+	var taps []image.Point
+	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+		taps = append(taps, image.Pt(ebiten.CursorPosition()))
+	}
+	b.touches = inpututil.AppendJustPressedTouchIDs(b.touches[:0])
+	for _, id := range b.touches {
+		taps = append(taps, image.Pt(ebiten.TouchPosition(id)))
+	}
+	for _, p := range taps {
+		if row, col, ok := CellAt(b.Rect, p); ok && b.PersonToMove() {
+			b.Play(row, col) // Illegal squares are ignored
+		}
+	}
+
+	b.Driver.Update()
+	return nil
+}
+
+// Draw draws the board onto the screen. Call it from the game's Draw.
+func (b *Board) Draw(screen *ebiten.Image) {
+	size := min(b.Rect.Dx(), b.Rect.Dy())
+	drawn := fmt.Sprintf("%s %d %d %v", b.game.Board.PositionString(), len(b.game.History), size, b.PersonToMove())
+	if b.image == nil || b.drawn != drawn {
+		if b.image != nil {
+			b.image.Dispose()
+		}
+		b.image = ebiten.NewImageFromImage(b.Image(size))
+		b.drawn = drawn
+	}
+
+	op := &ebiten.DrawImageOptions{}
+	bounds := b.image.Bounds()
+	op.GeoM.Scale(float64(b.Rect.Dx())/float64(bounds.Dx()), float64(b.Rect.Dy())/float64(bounds.Dy()))
+	op.GeoM.Translate(float64(b.Rect.Min.X), float64(b.Rect.Min.Y))
+	op.Filter = ebiten.FilterLinear
+	screen.DrawImage(b.image, op)
+}
@@ -0,0 +1,207 @@
+// Package widget embeds an Othello board in other Go programs.
+//
+// A Driver runs a game between people and computer players and tells
+// observers about every move. Its Image and CellAt helpers draw the board and
+// translate clicks into squares for any toolkit, and Board wraps a Driver as
+// a ready-made Ebitengine widget:
+//
+//	driver := widget.NewDriver(nil)
+//	driver.SetPlayer(engine.White, engine.NewAI(engine.Medium, engine.White))
+//	driver.Observe(func(e widget.Event) { fmt.Println(engine.FormatMove(e.Move.Position.Row, e.Move.Position.Col)) })
+//	board := widget.NewBoard(driver, image.Rect(20, 20, 420, 420))
+//
+// then call board.Update and board.Draw from the game's own Update and Draw.
+// See examples/embed for a complete program.
+package widget
+
+import (
+	"errors"
+	"image"
+
+	"github.com/amirhossein-jamali/othello/pkg/engine"
+	"github.com/amirhossein-jamali/othello/pkg/render"
+)
+
+// ErrNotYourTurn is returned by Driver.Play while a computer player is to
+// move
+var ErrNotYourTurn = errors.New("a computer player is to move")
+
+// Event is a move played in a driven game
+type Event struct {
+	Move engine.Move  // A pass when its row is negative
+	Game *engine.Game // The game after the move; read it only during the call
+}
+
+// Observer is told about every move as it is played
+type Observer func(Event)
+
+// search is a computer player's move being chosen in the background
+type search struct {
+	game *engine.Game // The game it was started for
+	ply  int          // And its length then, so stale results are dropped
+	done chan searchResult
+}
+
+// searchResult is the move a search chose
+type searchResult struct {
+	row, col int
+	err      error
+}
+
+// Driver plays a game for an embedding program: people move through Play,
+// computer players think in the background while Update is called. It is
+// meant to be used from one goroutine, such as the UI's.
+type Driver struct {
+	game      *engine.Game
+	players   map[engine.Piece]engine.Player // nil for people
+	observers []Observer
+	search    *search
+}
+
+// NewDriver drives a game, a new one when game is nil, with people on both
+// sides until SetPlayer says otherwise
+func NewDriver(game *engine.Game) *Driver {
+	if game == nil {
+		game = engine.NewGame()
+	}
+	return &Driver{game: game, players: make(map[engine.Piece]engine.Player)}
+}
+
+// Game returns the game being driven; change it only through the Driver
+func (d *Driver) Game() *engine.Game {
+	return d.game
+}
+
+// Reset starts driving another game, a new one when game is nil, keeping the
+// players and observers. A search still running for the old game is ignored.
+func (d *Driver) Reset(game *engine.Game) {
+	if game == nil {
+		game = engine.NewGame()
+	}
+	d.game, d.search = game, nil
+}
+
+// SetPlayer has a computer player, or any other engine.Player, move for a
+// side; nil leaves the side to a person
+func (d *Driver) SetPlayer(side engine.Piece, p engine.Player) {
+	if p == nil {
+		delete(d.players, side)
+	} else {
+		d.players[side] = p
+	}
+	d.search = nil
+}
+
+// Observe adds an observer told about every move from now on
+func (d *Driver) Observe(o Observer) {
+	d.observers = append(d.observers, o)
+}
+
+// PersonToMove reports whether the game waits for a person's move
+func (d *Driver) PersonToMove() bool {
+	return !d.game.GameOver && d.players[d.game.GetCurrentPlayer()] == nil
+}
+
+// Thinking reports whether a computer player is choosing a move
+func (d *Driver) Thinking() bool {
+	return d.search != nil
+}
+
+// Play makes a person's move for the side to move
+func (d *Driver) Play(row, col int) error {
+	if !d.game.GameOver && !d.PersonToMove() {
+		return ErrNotYourTurn
+	}
+	if err := d.game.MakeMove(row, col); err != nil {
+		return err
+	}
+	d.notify()
+	return nil
+}
+
+// Update passes for a side without a legal move, starts a computer player's
+// search when it is to move and plays the move once it is chosen. Call it
+// regularly, e.g. every frame.
+func (d *Driver) Update() {
+	if d.game.GameOver {
+		return
+	}
+	if !d.game.HasValidMove() {
+		if d.game.Pass() == nil {
+			d.notify()
+		}
+		return
+	}
+
+	player := d.players[d.game.GetCurrentPlayer()]
+	if player == nil {
+		return
+	}
+	if d.search == nil {
+		s := &search{game: d.game, ply: len(d.game.History), done: make(chan searchResult, 1)}
+		board := d.game.Board.Clone()
+		go func() {
+			row, col, err := player.GetMove(board)
+			s.done <- searchResult{row, col, err}
+		}()
+		d.search = s
+		return
+	}
+
+	select {
+	case r := <-d.search.done:
+		s := d.search
+		d.search = nil
+		if s.game != d.game || s.ply != len(d.game.History) || r.err != nil {
+			return // Searched again on the next update
+		}
+		var err error
+		if r.row < 0 || r.col < 0 {
+			err = d.game.Pass()
+		} else {
+			err = d.game.MakeMove(r.row, r.col)
+		}
+		if err == nil {
+			d.notify()
+		}
+	default:
+	}
+}
+
+// notify tells the observers about the last move
+func (d *Driver) notify() {
+	e := Event{Move: d.game.History[len(d.game.History)-1], Game: d.game}
+	for _, o := range d.observers {
+		o(e)
+	}
+}
+
+// LastMove returns the last move that was not a pass, and false before one
+func (d *Driver) LastMove() (engine.Position, bool) {
+	for i := len(d.game.History) - 1; i >= 0; i-- {
+		if p := d.game.History[i].Position; p.Row >= 0 {
+			return p, true
+		}
+	}
+	return engine.Position{}, false
+}
+
+// Image draws the board in a square of about size pixels, marking the last
+// move and, while a person is to move, the legal moves
+func (d *Driver) Image(size int) *image.RGBA {
+	opts := render.Options{CellSize: max(size/d.game.Board.Size, 1), ShowMoves: d.PersonToMove()}
+	if last, ok := d.LastMove(); ok {
+		opts.LastMove = &last
+	}
+	return render.Image(d.game.Board, opts)
+}
+
+// CellAt translates a point to the square under it on a board drawn into
+// rect, such as a click on the image returned by Image
+func CellAt(rect image.Rectangle, p image.Point) (row, col int, ok bool) {
+	if !p.In(rect) {
+		return -1, -1, false
+	}
+	const size = 8
+	return (p.Y - rect.Min.Y) * size / rect.Dy(), (p.X - rect.Min.X) * size / rect.Dx(), true
+}
@@ -0,0 +1,40 @@
+package wthor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// FuzzReader checks that any bytes either fail to read or give games whose
+// moves are squares on the board
+func FuzzReader(f *testing.F) {
+	one := make([]byte, HeaderSize+RecordSize)
+	binary.LittleEndian.PutUint32(one[4:8], 1)
+	one[12] = 8
+	copy(one[HeaderSize+8:], []byte{56, 64, 33, 0})
+	f.Add(one)
+	f.Add(one[:HeaderSize+10])
+	f.Add([]byte{})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		r, err := NewReader(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		games, _ := r.ReadAll()
+		if len(games) > len(data)/RecordSize {
+			t.Fatalf("%d games read from %d bytes", len(games), len(data))
+		}
+		for _, g := range games {
+			if len(g.Moves) > MoveCount {
+				t.Fatalf("%d moves in a game", len(g.Moves))
+			}
+			for i, m := range g.Moves {
+				if m.Row < 0 || m.Row >= 8 || m.Col < 0 || m.Col >= 8 {
+					t.Fatalf("move %d = %v: off the board", i+1, m)
+				}
+			}
+			g.Replay() // Must not panic on any moves
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package wthor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/amirhossein-jamali/othello/pkg/model"
+)
+
+// Sizes of the fixed-length parts of a WTHOR game database (.wtb)
+const (
+	HeaderSize = 16
+	RecordSize = 68
+	MoveCount  = 60
+)
+
+// Header is the file header of a WTHOR database
+type Header struct {
+	Created     [4]byte // Century, year, month, day
+	GameCount   uint32
+	RecordCount uint16 // Only used by player and tournament files
+	Year        uint16
+	BoardSize   byte // 0 or 8 for the standard board
+	GameType    byte // 0 for normal games, 1 for solitaires
+	Depth       byte // Depth of the theoretical score
+}
+
+// Game is a single game record
+type Game struct {
+	Tournament       uint16
+	BlackPlayer      uint16
+	WhitePlayer      uint16
+	BlackScore       int // Actual number of black discs at the end
+	TheoreticalScore int // Perfect-play black score at Header.Depth empties
+	Moves            []model.Position
+}
+
+// Reader reads games from a WTHOR database
+type Reader struct {
+	r      io.Reader
+	Header Header
+	read   uint32
+}
+
+// NewReader reads the database header and prepares to read games
+func NewReader(r io.Reader) (*Reader, error) {
+	var buf [HeaderSize]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return nil, fmt.Errorf("reading wthor header: %w", err)
+	}
+
+	h := Header{
+		GameCount:   binary.LittleEndian.Uint32(buf[4:8]),
+		RecordCount: binary.LittleEndian.Uint16(buf[8:10]),
+		Year:        binary.LittleEndian.Uint16(buf[10:12]),
+		BoardSize:   buf[12],
+		GameType:    buf[13],
+		Depth:       buf[14],
+	}
+	copy(h.Created[:], buf[0:4])
+
+	if h.BoardSize != 0 && h.BoardSize != 8 {
+		return nil, fmt.Errorf("unsupported wthor board size %d", h.BoardSize)
+	}
+
+	return &Reader{r: r, Header: h}, nil
+}
+
+// Next returns the next game, or io.EOF after the last one
+func (r *Reader) Next() (*Game, error) {
+	if r.read >= r.Header.GameCount {
+		return nil, io.EOF
+	}
+
+	var buf [RecordSize]byte
+	if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("reading wthor game %d: %w", r.read+1, err)
+	}
+	r.read++
+
+	g := &Game{
+		Tournament:       binary.LittleEndian.Uint16(buf[0:2]),
+		BlackPlayer:      binary.LittleEndian.Uint16(buf[2:4]),
+		WhitePlayer:      binary.LittleEndian.Uint16(buf[4:6]),
+		BlackScore:       int(buf[6]),
+		TheoreticalScore: int(buf[7]),
+	}
+
+	for _, code := range buf[8:] {
+		// Moves are stored as 10*row + column, both 1-based; 0 ends the game
+		if code == 0 {
+			break
+		}
+		row, col := int(code/10)-1, int(code%10)-1
+		if row < 0 || row >= 8 || col < 0 || col >= 8 {
+			return nil, fmt.Errorf("wthor game %d: invalid move code %d", r.read, code)
+		}
+		g.Moves = append(g.Moves, model.Position{Row: row, Col: col})
+	}
+
+	return g, nil
+}
+
+// ReadAll reads every remaining game
+func (r *Reader) ReadAll() ([]*Game, error) {
+	var games []*Game
+	for {
+		g, err := r.Next()
+		if err == io.EOF {
+			return games, nil
+		}
+		if err != nil {
+			return games, err
+		}
+		games = append(games, g)
+	}
+}
+
+// Replay plays the game's moves on a fresh game with full validation
+func (g *Game) Replay() (*model.Game, error) {
+	game := model.NewGame()
+	if ply, err := game.ReplayMoves(g.Moves); err != nil {
+		return game, fmt.Errorf("move %d: %w", ply+1, err)
+	}
+	return game, nil
+}